@@ -2,6 +2,7 @@
 package main
 
 import (
+    "context"
     "log"
     "net/http"
 
@@ -11,7 +12,13 @@ import (
     projectHandler "todo-app/internal/project/handler"
     taskHandler "todo-app/internal/task/handler"
     commentHandler "todo-app/internal/comment/handler"
+    attachmentHandler "todo-app/internal/attachment/handler"
     notificationHandler "todo-app/internal/notification/handler"
+    trashHandler "todo-app/internal/trash/handler"
+    templateHandler "todo-app/internal/template/handler"
+    planningHandler "todo-app/internal/planning/handler"
+    trashUsecase "todo-app/internal/trash/usecase"
+    taskUsecase "todo-app/internal/task/usecase"
     "todo-app/internal/common/logger"
     authMiddleware "todo-app/internal/common/middleware"
     "todo-app/internal/infrastructure/db"
@@ -32,6 +39,15 @@ func main() {
     }
     defer dbConn.Close()
 
+    // ゴミ箱の保持期間を過ぎたデータを定期的に物理削除
+    ctx, cancel := context.WithCancel(context.Background())
+    defer cancel()
+    go trashHandler.NewTrashUseCase(dbConn).RunPurger(ctx, trashUsecase.PurgeIntervalFromEnv())
+
+    // 繰り返しタスクの次の発生を定期的に生成
+    taskUC := taskHandler.NewTaskUseCase(dbConn)
+    go taskUC.RunRecurrenceScheduler(ctx, taskUsecase.RecurrenceIntervalFromEnv())
+
     // Initialize router
     r := chi.NewRouter()
     
@@ -39,11 +55,15 @@ func main() {
     r.Use(chiMiddleware.Logger)
     r.Use(cors.Handler(cors.Options{
         AllowedOrigins:   []string{"http://localhost:5173", "http://localhost:5174", "http://localhost:5175"},
-        AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-        AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
+        AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+        AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "If-Match", "If-None-Match"},
+        ExposedHeaders:   []string{"ETag", "Link", "X-Total-Count"},
         AllowCredentials: true,
     }))
 
+    // 署名付き URL による添付ファイルのダウンロード（URL の署名で認可）
+    attachmentHandler.RegisterFileRoutes(r, dbConn, taskUC)
+
     // 認証必須のルート
     r.Group(func(private chi.Router) {
         private.Use(authMiddleware.JWTMiddleware)
@@ -52,7 +72,11 @@ func main() {
         projectHandler.RegisterProjectRoutes(private, dbConn)
         taskHandler.RegisterTaskRoutes(private, dbConn)
         commentHandler.RegisterCommentRoutes(private, dbConn)
+        attachmentHandler.RegisterAttachmentRoutes(private, dbConn, taskUC)
         notificationHandler.RegisterNotificationRoutes(private, dbConn)
+        trashHandler.RegisterTrashRoutes(private, dbConn)
+        templateHandler.RegisterTemplateRoutes(private, dbConn)
+        planningHandler.RegisterPlanningRoutes(private, dbConn)
     })
 
     zapLogger.Info("Listening on :8080")
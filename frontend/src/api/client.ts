@@ -15,4 +15,9 @@ client.interceptors.request.use((config) => {
   return config;
 });
 
+// 楽観的ロック用の If-Match ヘッダーを生成する
+export const ifMatch = (version?: number) => ({
+  headers: { 'If-Match': version ? `"${version}"` : '*' },
+});
+
 export default client; 
\ No newline at end of file
@@ -12,7 +12,7 @@ import {
 } from '@chakra-ui/react';
 import { useQuery } from '@tanstack/react-query';
 import client from '@/api/client';
-import { Project, Task } from '@/types';
+import { Project, TaskSummary } from '@/types';
 import { useAuth } from '@/contexts/AuthContext';
 
 const Dashboard = () => {
@@ -25,23 +25,17 @@ const Dashboard = () => {
     queryFn: () => client.get('/projects').then((res) => res.data),
   });
 
-  const { data: tasks } = useQuery<Task[]>({
-    queryKey: ['tasks'],
-    queryFn: () => client.get('/tasks').then((res) => res.data),
+  const { data: summary } = useQuery<TaskSummary>({
+    queryKey: ['tasks', 'summary'],
+    queryFn: () => client.get('/tasks/summary').then((res) => res.data),
   });
 
-  const getTaskStats = () => {
-    if (!tasks) return { total: 0, completed: 0, inProgress: 0 };
-
-    return {
-      total: tasks.length,
-      completed: tasks.filter((task) => task.status === 'Done').length,
-      inProgress: tasks.filter((task) => task.status === 'InProgress').length,
-    };
+  const taskStats = {
+    total: summary?.total ?? 0,
+    completed: summary?.by_status.Done ?? 0,
+    inProgress: summary?.by_status.InProgress ?? 0,
   };
 
-  const taskStats = getTaskStats();
-
   return (
     <Box p={6} mt={8}>
       <Box mb={8}>
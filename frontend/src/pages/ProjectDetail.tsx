@@ -32,7 +32,7 @@ import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import { useParams, Link, useNavigate } from 'react-router-dom';
 import { useFormik } from 'formik';
 import * as Yup from 'yup';
-import client from '@/api/client';
+import client, { ifMatch } from '@/api/client';
 import { Project, Task, User } from '@/types';
 import { useAuth } from '@/contexts/AuthContext';
 
@@ -101,7 +101,7 @@ const ProjectDetail = () => {
 
   const deleteProject = useMutation({
     mutationFn: (projectId: string) =>
-      client.delete(`/projects/${projectId}`).then((res) => res.data),
+      client.delete(`/projects/${projectId}`, ifMatch(project?.version)).then((res) => res.data),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ['projects'] });
       toast({
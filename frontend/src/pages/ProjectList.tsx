@@ -23,7 +23,7 @@ import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import { Link } from 'react-router-dom';
 import { useFormik } from 'formik';
 import * as Yup from 'yup';
-import client from '@/api/client';
+import client, { ifMatch } from '@/api/client';
 import { Project } from '@/types';
 
 const validationSchema = Yup.object({
@@ -69,7 +69,7 @@ const ProjectList = () => {
 
   const deleteProject = useMutation({
     mutationFn: (projectId: string) =>
-      client.delete(`/projects/${projectId}`).then((res) => res.data),
+      client.delete(`/projects/${projectId}`, ifMatch(projects?.find((p) => p.id === projectId)?.version)).then((res) => res.data),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ['projects'] });
       toast({
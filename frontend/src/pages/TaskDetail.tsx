@@ -16,7 +16,7 @@ import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import { useParams, useNavigate } from 'react-router-dom';
 import { useFormik } from 'formik';
 import * as Yup from 'yup';
-import client from '@/api/client';
+import client, { ifMatch } from '@/api/client';
 import { Task, Subtask, Comment, User } from '@/types';
 import { useAuth } from '@/contexts/AuthContext';
 
@@ -48,7 +48,7 @@ const TaskDetail = () => {
 
   const updateTask = useMutation({
     mutationFn: (updatedTask: Partial<Task>) =>
-      client.patch(`/tasks/${taskId}`, updatedTask).then((res) => res.data),
+      client.patch(`/tasks/${taskId}`, updatedTask, ifMatch(task?.version)).then((res) => res.data),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ['task', taskId] });
       toast({
@@ -69,6 +69,8 @@ const TaskDetail = () => {
         .then((res) => res.data),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ['subtasks', taskId] });
+      // 進捗の更新やプロジェクト設定による自動完了を反映する
+      queryClient.invalidateQueries({ queryKey: ['task', taskId] });
     },
   });
 
@@ -127,7 +129,7 @@ const TaskDetail = () => {
 
   const deleteTaskMutation = useMutation<string, unknown, string>({
     mutationFn: async (taskId: string) => {
-      const response = await client.delete(`/tasks/${taskId}`);
+      const response = await client.delete(`/tasks/${taskId}`, ifMatch(task?.version));
       return response.data;
     },
     onSuccess: () => {
@@ -191,7 +193,7 @@ const TaskDetail = () => {
 
       <Box mb={6}>
         <Heading size="md" mb={4}>
-          Subtasks
+          Subtasks{task.subtask_progress?.total ? ` (${task.subtask_progress.done}/${task.subtask_progress.total})` : ''}
         </Heading>
         <VStack align="stretch" spacing={2}>
           {subtasks?.map((subtask) => (
@@ -30,7 +30,7 @@ import {
 import { useQuery, useMutation, useQueryClient } from '@tanstack/react-query';
 import { useFormik } from 'formik';
 import * as Yup from 'yup';
-import client from '@/api/client';
+import client, { ifMatch } from '@/api/client';
 import { useAuth } from '@/contexts/AuthContext';
 import { Link } from 'react-router-dom';
 
@@ -107,7 +107,8 @@ const TaskList: React.FC = () => {
   // タスク削除
   const deleteTaskMutation = useMutation({
     mutationFn: async (taskId: string) => {
-      const response = await client.delete(`/tasks/${taskId}`);
+      const version = tasks?.find((t) => t.id === taskId)?.version;
+      const response = await client.delete(`/tasks/${taskId}`, ifMatch(version));
       return response.data;
     },
     onSuccess: () => {
@@ -18,6 +18,24 @@ export interface Project {
   end_date: string;
   created_at: string;
   updated_at: string;
+  version: number;
+  auto_complete_tasks: boolean;
+  status: 'active' | 'archived';
+}
+
+export type ProjectRole = 'owner' | 'maintainer' | 'member' | 'viewer';
+
+export interface ProjectMember {
+  id: string;
+  name: string;
+  email: string;
+  role: ProjectRole;
+}
+
+export interface MemberRemoval {
+  user_id: string;
+  reassigned_to: string;
+  reassigned_tasks: number;
 }
 
 export interface Task {
@@ -31,12 +49,442 @@ export interface Task {
   updated_at: string;
   project_id: string;
   assignee_id: string;
+  assignee_ids: string[];
+  version: number;
+  subtask_progress: SubtaskProgress;
+  recurrence?: string;
+  series_id?: string;
+  occurrence?: number;
+  labels: TaskLabel[];
+  estimate: number;
+  time_spent: number;
+  sprint_id: string;
+  milestone_id: string;
+  start_date: string;
+  duration_days: number;
+  rank: string;
+  custom_fields: Record<string, CustomFieldValue>;
+}
+
+export interface TaskLabel {
+  id: string;
+  name: string;
+  color: string;
+}
+
+export interface Label extends TaskLabel {
+  project_id: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export type CustomFieldType = 'text' | 'number' | 'date' | 'select' | 'multi_select' | 'user';
+
+export type CustomFieldValue = string | number | string[] | null;
+
+export interface CustomField {
+  id: string;
+  project_id: string;
+  key: string;
+  name: string;
+  type: CustomFieldType;
+  options: string[];
+  created_at: string;
+  updated_at: string;
+}
+
+export interface TimeEntry {
+  id: string;
+  task_id: string;
+  user_id: string;
+  started_at: string;
+  ended_at: string | null;
+  minutes: number;
+  note: string;
+  source: 'timer' | 'manual';
+  created_at: string;
+  updated_at: string;
+}
+
+export interface TaskTime {
+  task_id: string;
+  title: string;
+  project_id: string;
+  estimate: number;
+  minutes: number;
+  remaining: number;
+}
+
+export interface TimeSummary {
+  total_minutes: number;
+  estimate: number;
+  by_user: Record<string, number>;
+  by_project: Record<string, number>;
+  by_task: TaskTime[];
+}
+
+export interface QuickAddParsed {
+  title: string;
+  due_date: string | null;
+  due_has_time: boolean;
+  timezone: string;
+  priority?: Task['priority'];
+  assignee?: string;
+  assignee_id?: string;
+  project?: string;
+  project_id?: string;
+  labels: string[];
+  label_ids: string[];
+}
+
+export interface QuickAddResult {
+  task: Task;
+  parsed: QuickAddParsed;
+}
+
+export interface Attachment {
+  id: string;
+  task_id: string;
+  comment_id?: string;
+  filename: string;
+  content_type: string;
+  size: number;
+  hash: string;
+  uploaded_by: string;
+  created_at: string;
+}
+
+export interface AttachmentURL {
+  url: string;
+  expires_at: string;
+}
+
+export interface BulkOperations {
+  status?: string;
+  priority?: Task['priority'];
+  assignee_id?: string;
+  due_date?: string;
+  estimate?: number;
+  project_id?: string;
+  sprint_id?: string;
+  milestone_id?: string;
+  add_labels?: string[];
+  remove_labels?: string[];
+  delete?: boolean;
+}
+
+export interface BulkRequest {
+  task_ids?: string[];
+  filter?: Record<string, string>;
+  operations: BulkOperations;
+  atomic?: boolean;
+}
+
+export interface BulkItemResult {
+  id: string;
+  status: 'ok' | 'failed' | 'rolled_back';
+  error?: string;
+  fields?: Record<string, string>;
+  version?: number;
+}
+
+export interface BulkResult {
+  atomic: boolean;
+  succeeded: number;
+  failed: number;
+  results: BulkItemResult[];
+}
+
+export interface TemplateTask {
+  title: string;
+  description: string;
+  priority: string;
+  estimate: number;
+  due_offset_days?: number;
+  due_time?: string;
+  labels: string[];
+  subtasks: string[];
+}
+
+export interface TemplateContent {
+  project?: {
+    name: string;
+    description: string;
+    duration_days: number;
+    auto_complete_tasks: boolean;
+  };
+  labels: { name: string; color: string }[];
+  tasks: TemplateTask[];
+}
+
+export interface Template {
+  id: string;
+  name: string;
+  description: string;
+  kind: 'project' | 'task';
+  content: TemplateContent;
+  created_by: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface TemplateInstantiateResult {
+  project_id: string;
+  task_ids: string[];
+}
+
+export interface SubtaskProgress {
+  done: number;
+  total: number;
+}
+
+export interface TaskRef {
+  id: string;
+  title: string;
+  status: Task['status'];
+  project_id: string;
+}
+
+export interface DependencyGraph {
+  nodes: TaskRef[];
+  edges: { from: string; to: string }[];
+}
+
+export interface TimelineBar {
+  task_id: string;
+  title: string;
+  status: Task['status'];
+  assignee_id: string;
+  start: string;
+  end: string;
+  duration_days: number;
+  earliest_start: string;
+  earliest_finish: string;
+  latest_start: string;
+  latest_finish: string;
+  shift_days: number;
+  slack: number;
+  critical: boolean;
+  past_project_end: boolean;
+}
+
+export interface Timeline {
+  project_id: string;
+  start_date: string;
+  end_date: string;
+  projected_end: string;
+  overrun_days: number;
+  bars: TimelineBar[];
+  dependencies: { from: string; to: string }[];
+  critical_path: string[];
+  unscheduled: TaskRef[];
+}
+
+export interface RescheduleResult {
+  task: Task;
+  shifted: { task_id: string; title: string; start: string; end: string; shift_days: number }[];
+  skipped: string[];
+}
+
+export type WIPPolicy = 'warn' | 'reject';
+
+export interface BoardColumn {
+  status: string;
+  name: string;
+  position: number;
+  wip_limit: number;
+  wip_policy: WIPPolicy;
+  count: number;
+  over_limit: boolean;
+  tasks: Task[];
+}
+
+export interface Board {
+  project_id: string;
+  columns: BoardColumn[];
+}
+
+export interface TransferOptions {
+  subtasks?: boolean;
+  comments?: boolean;
+  attachments?: boolean;
+  labels?: boolean;
+}
+
+export interface MoveTaskRequest {
+  column?: string;
+  after_id?: string;
+  before_id?: string;
+  project_id?: string;
+  include?: TransferOptions;
+}
+
+export interface CloneTaskRequest {
+  project_id?: string;
+  include?: TransferOptions;
+}
+
+export interface MoveResult {
+  task: Task;
+  warnings: string[];
+}
+
+export interface TaskSummary {
+  total: number;
+  incomplete: number;
+  overdue: number;
+  by_status: Record<string, number>;
+  by_priority: Record<string, number>;
+  by_assignee: Record<string, number>;
+  unassigned: number;
+}
+
+export type SprintStatus = 'planned' | 'active' | 'closed';
+
+export interface Sprint {
+  id: string;
+  project_id: string;
+  name: string;
+  goal: string;
+  start_date: string;
+  end_date: string;
+  status: SprintStatus;
+  started_at?: string;
+  closed_at?: string;
+  rolled_over: number;
+  created_by: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface Milestone {
+  id: string;
+  project_id: string;
+  name: string;
+  description: string;
+  start_date: string;
+  end_date: string;
+  created_by: string;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface PlanningProgress {
+  total: number;
+  completed: number;
+  incomplete: number;
+  by_status: Record<string, number>;
+  estimate: number;
+  completed_estimate: number;
+  time_spent: number;
+  percent: number;
+}
+
+export interface SprintSummary {
+  sprint: Sprint;
+  progress: PlanningProgress;
+  days_total: number;
+  days_remaining: number;
+}
+
+export interface MilestoneSummary {
+  milestone: Milestone;
+  progress: PlanningProgress;
+  days_remaining: number;
+  overdue: boolean;
+}
+
+export interface CloseSprintResult {
+  sprint: Sprint;
+  rolled_over: number;
+  next_sprint_id: string;
+  task_ids: string[];
+}
+
+export interface ReportRange {
+  project_id: string;
+  sprint_id?: string;
+  from: string;
+  to: string;
+}
+
+export interface BurndownReport extends ReportRange {
+  points: {
+    date: string;
+    remaining: number | null;
+    remaining_estimate: number | null;
+    ideal: number;
+    ideal_estimate: number;
+  }[];
+}
+
+export interface BurnupReport extends ReportRange {
+  points: {
+    date: string;
+    scope: number;
+    completed: number;
+    scope_estimate: number;
+    completed_estimate: number;
+  }[];
+}
+
+export interface CumulativeFlowReport extends ReportRange {
+  statuses: Task['status'][];
+  points: { date: string; counts: Record<string, number> }[];
+}
+
+export interface ThroughputReport extends ReportRange {
+  total: number;
+  average_per_day: number;
+  points: { date: string; completed: number; completed_estimate: number }[];
+}
+
+export interface CycleTimeReport extends ReportRange {
+  count: number;
+  average_hours: number;
+  median_hours: number;
+  p85_hours: number;
+  tasks: {
+    task_id: string;
+    title: string;
+    created_at: string;
+    started_at: string;
+    completed_at: string;
+    cycle_time_hours: number;
+    lead_time_hours: number;
+  }[];
+}
+
+export interface VelocityReport {
+  project_id: string;
+  average_tasks: number;
+  average_estimate: number;
+  sprints: {
+    sprint_id: string;
+    name: string;
+    status: Sprint['status'];
+    start_date: string;
+    end_date: string;
+    planned: number;
+    completed_tasks: number;
+    completed_estimate: number;
+    rolled_over: number;
+  }[];
+}
+
+export interface MyTasks {
+  timezone: string;
+  overdue: Task[];
+  today: Task[];
+  this_week: Task[];
+  later: Task[];
 }
 
 export interface Subtask {
   id: string;
   title: string;
   is_complete: boolean;
+  position: number;
   created_at: string;
   updated_at: string;
   task_id: string;
@@ -51,6 +499,31 @@ export interface Comment {
   user_id: string;
 }
 
+export type ActivityEntityType = 'task' | 'subtask' | 'project' | 'comment';
+
+export interface ActivityChange {
+  field: string;
+  old: unknown;
+  new: unknown;
+}
+
+export interface ActivityEntry {
+  id: string;
+  entity_type: ActivityEntityType;
+  entity_id: string;
+  task_id?: string;
+  project_id?: string;
+  action: 'created' | 'updated' | 'deleted' | 'restored';
+  actor_id: string;
+  changes: ActivityChange[];
+  created_at: string;
+}
+
+export interface ActivityPage {
+  entries: ActivityEntry[];
+  next_cursor?: string;
+}
+
 export interface Notification {
   id: string;
   type: string;
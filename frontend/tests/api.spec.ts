@@ -57,6 +57,35 @@ test.describe('TODO API Tests', () => {
       expect(Array.isArray(tasks)).toBe(true);
     });
 
+    test('should filter and paginate tasks', async ({ request }) => {
+      const response = await request.get(`${baseURL}/tasks?view=incomplete&sort=-due_date&page=1&page_size=1`, {
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+
+      expect(response.status()).toBe(200);
+      const tasks = await response.json();
+      expect(tasks.length).toBeLessThanOrEqual(1);
+      for (const task of tasks) {
+        expect(['Open', 'InProgress']).toContain(task.status);
+      }
+      const total = Number(response.headers()['x-total-count']);
+      expect(total).toBeGreaterThanOrEqual(tasks.length);
+      if (total > 1) {
+        expect(response.headers()['link']).toContain('rel="next"');
+      }
+    });
+
+    test('should reject unknown sort columns', async ({ request }) => {
+      const response = await request.get(`${baseURL}/tasks?sort=password`, {
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+      expect(response.status()).toBe(400);
+    });
+
     test('should get task by ID', async ({ request }) => {
       // First create a task
       const taskId = `task-${Date.now()}-2`;
@@ -112,7 +141,8 @@ test.describe('TODO API Tests', () => {
       // Delete the task
       const deleteResponse = await request.delete(`${baseURL}/tasks/${taskId}`, {
         headers: {
-          'Authorization': `Bearer ${authToken}`
+          'Authorization': `Bearer ${authToken}`,
+          'If-Match': '"1"'
         }
       });
       
@@ -128,6 +158,1368 @@ test.describe('TODO API Tests', () => {
       });
       expect(getResponse.status()).toBe(404);
     });
+
+    test('should patch a task with merge patch semantics', async ({ request }) => {
+      const taskId = `task-${Date.now()}-4`;
+      await request.post(`${baseURL}/tasks`, {
+        data: {
+          id: taskId,
+          title: 'Task for Patch Test',
+          description: 'Original description',
+          priority: 'Medium',
+          status: 'Open',
+          due_date: new Date().toISOString().split('T')[0],
+          project_id: testProjectId
+        },
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+
+      const patchResponse = await request.patch(`${baseURL}/tasks/${taskId}`, {
+        data: {
+          priority: 'High',
+          status: 'InProgress'
+        },
+        headers: {
+          'Authorization': `Bearer ${authToken}`,
+          'If-Match': '"1"'
+        }
+      });
+
+      expect(patchResponse.status()).toBe(200);
+      const task = await patchResponse.json();
+      expect(task.priority).toBe('High');
+      expect(task.status).toBe('InProgress');
+      expect(task.description).toBe('Original description');
+    });
+
+    test('should reject invalid priority on update', async ({ request }) => {
+      const taskId = `task-${Date.now()}-5`;
+      await request.post(`${baseURL}/tasks`, {
+        data: {
+          id: taskId,
+          title: 'Task for Validation Test',
+          description: 'Test task for update validation',
+          priority: 'Medium',
+          status: 'Open',
+          due_date: new Date().toISOString().split('T')[0],
+          project_id: testProjectId
+        },
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+
+      const response = await request.put(`${baseURL}/tasks/${taskId}`, {
+        data: {
+          title: 'Task for Validation Test',
+          priority: 'Urgent',
+          status: 'Open'
+        },
+        headers: {
+          'Authorization': `Bearer ${authToken}`,
+          'If-Match': '"1"'
+        }
+      });
+
+      expect(response.status()).toBe(400);
+      const result = await response.json();
+      expect(result.fields.priority).toBeDefined();
+    });
+
+    test('should reject illegal status transitions', async ({ request }) => {
+      const taskId = `task-${Date.now()}-6`;
+      await request.post(`${baseURL}/tasks`, {
+        data: {
+          id: taskId,
+          title: 'Task for Transition Test',
+          description: 'Test task for workflow transitions',
+          priority: 'Medium',
+          status: 'Open',
+          due_date: new Date().toISOString().split('T')[0],
+          project_id: testProjectId
+        },
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+
+      const cancelResponse = await request.post(`${baseURL}/tasks/${taskId}/transitions`, {
+        data: { to: 'Canceled' },
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+      expect(cancelResponse.status()).toBe(200);
+
+      const illegalResponse = await request.post(`${baseURL}/tasks/${taskId}/transitions`, {
+        data: { to: 'InProgress' },
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+      expect(illegalResponse.status()).toBe(409);
+
+      const historyResponse = await request.get(`${baseURL}/tasks/${taskId}/transitions`, {
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+      expect(historyResponse.status()).toBe(200);
+      const history = await historyResponse.json();
+      expect(history).toHaveLength(1);
+      expect(history[0].to_status).toBe('Canceled');
+    });
+
+    test('should reject stale writes with 412 and missing If-Match with 428', async ({ request }) => {
+      const taskId = `task-${Date.now()}-7`;
+      await request.post(`${baseURL}/tasks`, {
+        data: {
+          id: taskId,
+          title: 'Task for Concurrency Test',
+          description: 'Test task for optimistic locking',
+          priority: 'Medium',
+          status: 'Open',
+          due_date: new Date().toISOString().split('T')[0],
+          project_id: testProjectId
+        },
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+
+      const getResponse = await request.get(`${baseURL}/tasks/${taskId}`, {
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+      const etag = getResponse.headers()['etag'];
+      expect(etag).toBe('"1"');
+
+      const notModified = await request.get(`${baseURL}/tasks/${taskId}`, {
+        headers: {
+          'Authorization': `Bearer ${authToken}`,
+          'If-None-Match': etag
+        }
+      });
+      expect(notModified.status()).toBe(304);
+
+      const firstWrite = await request.patch(`${baseURL}/tasks/${taskId}`, {
+        data: { title: 'First writer wins' },
+        headers: {
+          'Authorization': `Bearer ${authToken}`,
+          'If-Match': etag
+        }
+      });
+      expect(firstWrite.status()).toBe(200);
+      expect(firstWrite.headers()['etag']).toBe('"2"');
+
+      const staleWrite = await request.patch(`${baseURL}/tasks/${taskId}`, {
+        data: { title: 'Second writer loses' },
+        headers: {
+          'Authorization': `Bearer ${authToken}`,
+          'If-Match': etag
+        }
+      });
+      expect(staleWrite.status()).toBe(412);
+
+      const missingIfMatch = await request.delete(`${baseURL}/tasks/${taskId}`, {
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+      expect(missingIfMatch.status()).toBe(428);
+    });
+
+    test('should manage subtasks and report progress', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const taskId = `task-${Date.now()}-subtasks`;
+      await request.post(`${baseURL}/tasks`, {
+        data: { id: taskId, title: 'Task with subtasks', project_id: testProjectId },
+        headers
+      });
+
+      const ids: string[] = [];
+      for (const title of ['First', 'Second']) {
+        const created = await request.post(`${baseURL}/tasks/${taskId}/subtasks`, { data: { title }, headers });
+        expect(created.status()).toBe(201);
+        ids.push((await created.json()).id);
+      }
+
+      const toggled = await request.post(`${baseURL}/tasks/${taskId}/subtasks/${ids[0]}/toggle`, { headers });
+      expect(toggled.status()).toBe(200);
+      expect((await toggled.json()).is_complete).toBe(true);
+
+      const task = await (await request.get(`${baseURL}/tasks/${taskId}`, { headers })).json();
+      expect(task.subtask_progress).toEqual({ done: 1, total: 2 });
+
+      const reordered = await request.put(`${baseURL}/tasks/${taskId}/subtasks/order`, {
+        data: { ids: [ids[1], ids[0]] },
+        headers
+      });
+      expect(reordered.status()).toBe(200);
+      const ordered = await reordered.json();
+      expect(ordered.map((st: any) => st.id)).toEqual([ids[1], ids[0]]);
+
+      const invalidOrder = await request.put(`${baseURL}/tasks/${taskId}/subtasks/order`, {
+        data: { ids: [ids[1]] },
+        headers
+      });
+      expect(invalidOrder.status()).toBe(400);
+
+      const deleted = await request.delete(`${baseURL}/tasks/${taskId}/subtasks/${ids[1]}`, { headers });
+      expect(deleted.status()).toBe(200);
+
+      const remaining = await (await request.get(`${baseURL}/tasks/${taskId}/subtasks`, { headers })).json();
+      expect(remaining).toHaveLength(1);
+    });
+
+    test('should manage dependencies and block transitions', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const blockerId = `task-${Date.now()}-blocker`;
+      const blockedId = `task-${Date.now()}-blocked`;
+      for (const id of [blockerId, blockedId]) {
+        await request.post(`${baseURL}/tasks`, {
+          data: { id, title: `Dependency ${id}`, project_id: testProjectId },
+          headers
+        });
+      }
+
+      const added = await request.post(`${baseURL}/tasks/${blockedId}/dependencies`, {
+        data: { blocked_by_id: blockerId },
+        headers
+      });
+      expect(added.status()).toBe(201);
+      const deps = await added.json();
+      expect(deps.blocked_by.map((t: any) => t.id)).toEqual([blockerId]);
+
+      const cycle = await request.post(`${baseURL}/tasks/${blockerId}/dependencies`, {
+        data: { blocked_by_id: blockedId },
+        headers
+      });
+      expect(cycle.status()).toBe(409);
+
+      const blocked = await request.post(`${baseURL}/tasks/${blockedId}/transitions`, {
+        data: { to: 'InProgress' },
+        headers
+      });
+      expect(blocked.status()).toBe(409);
+      expect((await blocked.json()).blockers).toEqual([blockerId]);
+
+      const graph = await request.get(`${baseURL}/projects/${testProjectId}/dependency-graph`, { headers });
+      expect(graph.status()).toBe(200);
+      const { edges } = await graph.json();
+      expect(edges).toContainEqual({ from: blockerId, to: blockedId });
+
+      const removed = await request.delete(`${baseURL}/tasks/${blockedId}/dependencies/${blockerId}`, { headers });
+      expect(removed.status()).toBe(200);
+
+      const unblocked = await request.post(`${baseURL}/tasks/${blockedId}/transitions`, {
+        data: { to: 'InProgress' },
+        headers
+      });
+      expect(unblocked.status()).toBe(200);
+    });
+
+    test('should generate the next occurrence of a recurring task', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const taskId = `task-${Date.now()}-recurring`;
+      const created = await request.post(`${baseURL}/tasks`, {
+        data: {
+          id: taskId,
+          title: 'Weekly report',
+          due_date: '2030-01-07',
+          project_id: testProjectId,
+          recurrence: 'FREQ=WEEKLY;BYDAY=MO'
+        },
+        headers
+      });
+      expect(created.status()).toBe(201);
+
+      const task = await (await request.get(`${baseURL}/tasks/${taskId}`, { headers })).json();
+      expect(task.recurrence).toBe('FREQ=WEEKLY;BYDAY=MO');
+      expect(task.occurrence).toBe(1);
+
+      const thisOnly = await request.patch(`${baseURL}/tasks/${taskId}`, {
+        data: { recurrence: 'FREQ=DAILY' },
+        headers: { ...headers, 'If-Match': `"${task.version}"` }
+      });
+      expect(thisOnly.status()).toBe(400);
+
+      const done = await request.post(`${baseURL}/tasks/${taskId}/transitions`, {
+        data: { to: 'Done' },
+        headers
+      });
+      expect(done.status()).toBe(200);
+
+      const list = await request.get(`${baseURL}/tasks?project_id=${testProjectId}&due_from=2030-01-08&due_to=2030-01-31`, { headers });
+      const next = (await list.json()).find((t: any) => t.series_id === task.series_id);
+      expect(next).toBeDefined();
+      expect(next.occurrence).toBe(2);
+      expect(next.due_date.startsWith('2030-01-14')).toBe(true);
+      expect(next.status).toBe('Open');
+
+      const invalid = await request.post(`${baseURL}/tasks`, {
+        data: { title: 'Invalid', due_date: '2030-01-07', recurrence: 'FREQ=HOURLY' },
+        headers
+      });
+      expect(invalid.status()).toBe(400);
+    });
+
+    test('should manage project labels and filter tasks by label', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const labelName = `bug-${Date.now()}`;
+
+      const created = await request.post(`${baseURL}/projects/${testProjectId}/labels`, {
+        data: { name: labelName, color: '#FF0000' },
+        headers
+      });
+      expect(created.status()).toBe(201);
+      const label = await created.json();
+      expect(label.color).toBe('#ff0000');
+
+      const duplicate = await request.post(`${baseURL}/projects/${testProjectId}/labels`, {
+        data: { name: labelName.toUpperCase() },
+        headers
+      });
+      expect(duplicate.status()).toBe(400);
+      expect((await duplicate.json()).fields.name).toBeDefined();
+
+      const taskId = `task-${Date.now()}-label`;
+      await request.post(`${baseURL}/tasks`, {
+        data: { id: taskId, title: 'Labeled task', project_id: testProjectId },
+        headers
+      });
+
+      const added = await request.post(`${baseURL}/tasks/${taskId}/labels/${label.id}`, { headers });
+      expect(added.status()).toBe(200);
+      const labeled = await added.json();
+      expect(labeled.labels.map((l: any) => l.id)).toEqual([label.id]);
+      expect(labeled.version).toBe(2);
+
+      const filtered = await request.get(`${baseURL}/tasks?label=${labelName}`, { headers });
+      expect(filtered.status()).toBe(200);
+      expect((await filtered.json()).map((t: any) => t.id)).toEqual([taskId]);
+
+      const renamed = await request.patch(`${baseURL}/projects/${testProjectId}/labels/${label.id}`, {
+        data: { name: `${labelName}-renamed` },
+        headers
+      });
+      expect(renamed.status()).toBe(200);
+      expect((await renamed.json()).color).toBe('#ff0000');
+
+      const removed = await request.delete(`${baseURL}/projects/${testProjectId}/labels/${label.id}`, { headers });
+      expect(removed.status()).toBe(200);
+      const task = await (await request.get(`${baseURL}/tasks/${taskId}`, { headers })).json();
+      expect(task.labels).toEqual([]);
+    });
+
+    test('should quick-add a task from a single line', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const labelName = `quick-${Date.now()}`;
+
+      const response = await request.post(`${baseURL}/tasks/quick`, {
+        data: { text: `Review PR tomorrow 3pm !high @test #${labelName} +"Sample Project"` },
+        headers
+      });
+      expect(response.status()).toBe(201);
+      const { task, parsed } = await response.json();
+      expect(parsed.title).toBe('Review PR');
+      expect(parsed.due_has_time).toBe(true);
+      expect(parsed.timezone).toBe('Asia/Tokyo');
+      expect(parsed.due_date).toContain('T15:00:00+09:00');
+      expect(parsed.labels).toEqual([labelName]);
+      expect(task.title).toBe('Review PR');
+      expect(task.priority).toBe('High');
+      expect(task.project_id).toBe(parsed.project_id);
+      expect(task.assignee_id).toBe(parsed.assignee_id);
+      expect(task.labels.map((l: any) => l.name)).toEqual([labelName]);
+
+      const japanese = await request.post(`${baseURL}/tasks/quick`, {
+        data: { text: '明日15時までに資料作成 !高' },
+        headers
+      });
+      expect(japanese.status()).toBe(201);
+      expect((await japanese.json()).parsed.title).toBe('資料作成');
+
+      const unknownProject = await request.post(`${baseURL}/tasks/quick`, {
+        data: { text: 'Something +NoSuchProject' },
+        headers
+      });
+      expect(unknownProject.status()).toBe(400);
+      expect((await unknownProject.json()).fields.project).toBeDefined();
+
+      const empty = await request.post(`${baseURL}/tasks/quick`, {
+        data: { text: 'tomorrow !high' },
+        headers
+      });
+      expect(empty.status()).toBe(400);
+    });
+
+    test('should track time with timers and work logs', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const taskId = `task-${Date.now()}-time`;
+      await request.post(`${baseURL}/tasks`, {
+        data: { id: taskId, title: 'Billable task', project_id: testProjectId, estimate: 90 },
+        headers
+      });
+
+      // 前のテストで残ったタイマーを止める
+      const current = await request.get(`${baseURL}/time/timer`, { headers });
+      if (current.status() === 200) {
+        const running = await current.json();
+        await request.post(`${baseURL}/tasks/${running.task_id}/timer/stop`, { headers });
+      }
+
+      const started = await request.post(`${baseURL}/tasks/${taskId}/timer/start`, { headers });
+      expect(started.status()).toBe(201);
+      expect((await started.json()).ended_at).toBeNull();
+
+      const again = await request.post(`${baseURL}/tasks/${taskId}/timer/start`, { headers });
+      expect(again.status()).toBe(409);
+      expect((await again.json()).task_id).toBe(taskId);
+
+      const stopped = await request.post(`${baseURL}/tasks/${taskId}/timer/stop`, {
+        data: { note: 'Investigation' },
+        headers
+      });
+      expect(stopped.status()).toBe(200);
+      const timerEntry = await stopped.json();
+      expect(timerEntry.ended_at).not.toBeNull();
+      expect(timerEntry.minutes).toBe(1);
+
+      const logged = await request.post(`${baseURL}/tasks/${taskId}/time-entries`, {
+        data: { minutes: 45, note: 'Implementation' },
+        headers
+      });
+      expect(logged.status()).toBe(201);
+
+      const invalid = await request.post(`${baseURL}/tasks/${taskId}/time-entries`, {
+        data: { minutes: 0 },
+        headers
+      });
+      expect(invalid.status()).toBe(400);
+      expect((await invalid.json()).fields.minutes).toBeDefined();
+
+      const entries = await (await request.get(`${baseURL}/tasks/${taskId}/time-entries`, { headers })).json();
+      expect(entries).toHaveLength(2);
+
+      const task = await (await request.get(`${baseURL}/tasks/${taskId}`, { headers })).json();
+      expect(task.estimate).toBe(90);
+      expect(task.time_spent).toBe(46);
+
+      const summary = await (await request.get(`${baseURL}/tasks/${taskId}/time`, { headers })).json();
+      expect(summary.total_minutes).toBe(46);
+      expect(summary.by_task[0].remaining).toBe(44);
+
+      const projectSummary = await request.get(`${baseURL}/projects/${testProjectId}/time?user_id=me`, { headers });
+      expect(projectSummary.status()).toBe(200);
+      expect((await projectSummary.json()).total_minutes).toBeGreaterThanOrEqual(46);
+    });
+
+    test('should upload, deduplicate, download and delete attachments', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const taskId = `task-${Date.now()}-attach`;
+      await request.post(`${baseURL}/tasks`, {
+        data: { id: taskId, title: 'Task with files', project_id: testProjectId },
+        headers
+      });
+      const content = `notes ${Date.now()}\n`;
+      const file = { name: 'notes.txt', mimeType: 'text/plain', buffer: Buffer.from(content) };
+
+      const uploaded = await request.post(`${baseURL}/tasks/${taskId}/attachments`, {
+        multipart: { file },
+        headers
+      });
+      expect(uploaded.status()).toBe(201);
+      const attachment = await uploaded.json();
+      expect(attachment.filename).toBe('notes.txt');
+      expect(attachment.content_type).toBe('text/plain');
+      expect(attachment.size).toBe(content.length);
+
+      // 同じ内容のファイルは本体を共有する
+      const duplicate = await (await request.post(`${baseURL}/tasks/${taskId}/attachments`, {
+        multipart: { file: { ...file, name: 'copy.txt' } },
+        headers
+      })).json();
+      expect(duplicate.hash).toBe(attachment.hash);
+
+      const rejected = await request.post(`${baseURL}/tasks/${taskId}/attachments`, {
+        multipart: { file: { name: 'run.sh', mimeType: 'application/x-sh', buffer: Buffer.from([0x7f, 0x45, 0x4c, 0x46, 0x02, 0x01, 0x01, 0x00]) } },
+        headers
+      });
+      expect(rejected.status()).toBe(415);
+
+      const listed = await (await request.get(`${baseURL}/tasks/${taskId}/attachments`, { headers })).json();
+      expect(listed).toHaveLength(2);
+
+      const downloaded = await request.get(`${baseURL}/attachments/${attachment.id}/download`, { headers });
+      expect(downloaded.status()).toBe(200);
+      expect(downloaded.headers()['content-disposition']).toContain('notes.txt');
+      expect(await downloaded.text()).toBe(content);
+
+      const { url } = await (await request.get(`${baseURL}/attachments/${attachment.id}/url`, { headers })).json();
+      const signed = await request.get(url.startsWith('http') ? url : `${baseURL}${url}`);
+      expect(signed.status()).toBe(200);
+      expect(await signed.text()).toBe(content);
+
+      // プロジェクトのメンバーでないユーザーは一覧・取得・添付できない
+      const email = `outsider-${Date.now()}@example.com`;
+      await request.post(`${baseURL}/users/register`, {
+        data: { name: 'Outsider', email, password: 'password123' }
+      });
+      const login = await (await request.post(`${baseURL}/users/login`, { data: { email, password: 'password123' } })).json();
+      const outsiderHeaders = { 'Authorization': `Bearer ${login.token || login}` };
+      const hiddenList = await request.get(`${baseURL}/tasks/${taskId}/attachments`, { headers: outsiderHeaders });
+      expect(hiddenList.status()).toBe(403);
+      const hiddenFile = await request.get(`${baseURL}/attachments/${attachment.id}/download`, { headers: outsiderHeaders });
+      expect(hiddenFile.status()).toBe(403);
+      const hiddenURL = await request.get(`${baseURL}/attachments/${attachment.id}/url`, { headers: outsiderHeaders });
+      expect(hiddenURL.status()).toBe(403);
+      const foreignUpload = await request.post(`${baseURL}/tasks/${taskId}/attachments`, {
+        multipart: { file },
+        headers: outsiderHeaders
+      });
+      expect(foreignUpload.status()).toBe(403);
+
+      const deleted = await request.delete(`${baseURL}/attachments/${attachment.id}`, { headers });
+      expect(deleted.status()).toBe(204);
+      const gone = await request.get(`${baseURL}/attachments/${attachment.id}`, { headers });
+      expect(gone.status()).toBe(404);
+
+      // ゴミ箱から復元したタスクには添付ファイルが残っている
+      const task = await (await request.get(`${baseURL}/tasks/${taskId}`, { headers })).json();
+      await request.delete(`${baseURL}/tasks/${taskId}`, {
+        headers: { ...headers, 'If-Match': `"${task.version}"` }
+      });
+      const restored = await request.post(`${baseURL}/tasks/${taskId}/restore`, { headers });
+      expect(restored.status()).toBe(200);
+      const kept = await (await request.get(`${baseURL}/tasks/${taskId}/attachments`, { headers })).json();
+      expect(kept.map((a: { id: string }) => a.id)).toEqual([duplicate.id]);
+      const redownloaded = await request.get(`${baseURL}/attachments/${duplicate.id}/download`, { headers });
+      expect(await redownloaded.text()).toBe(content);
+    });
+
+    test('should apply bulk operations with per-item results', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const stamp = Date.now();
+      const ids = [`task-${stamp}-bulk1`, `task-${stamp}-bulk2`];
+      for (const id of ids) {
+        await request.post(`${baseURL}/tasks`, {
+          data: { id, title: `Bulk ${id}`, project_id: testProjectId },
+          headers
+        });
+      }
+
+      const updated = await request.post(`${baseURL}/tasks/bulk`, {
+        data: { task_ids: [...ids, 'non-existent-id'], operations: { priority: 'High', status: 'InProgress' } },
+        headers
+      });
+      expect(updated.status()).toBe(200);
+      const result = await updated.json();
+      expect(result.succeeded).toBe(2);
+      expect(result.failed).toBe(1);
+      expect(result.results[2].status).toBe('failed');
+      const task = await (await request.get(`${baseURL}/tasks/${ids[0]}`, { headers })).json();
+      expect(task.priority).toBe('High');
+      expect(task.status).toBe('InProgress');
+      expect(task.version).toBe(result.results[0].version);
+
+      // atomic の場合は1件でも失敗すればすべて取り消す
+      const atomic = await request.post(`${baseURL}/tasks/bulk`, {
+        data: { task_ids: [...ids, 'non-existent-id'], operations: { priority: 'Low' }, atomic: true },
+        headers
+      });
+      expect(atomic.status()).toBe(409);
+      expect((await atomic.json()).results[0].status).toBe('rolled_back');
+      const unchanged = await (await request.get(`${baseURL}/tasks/${ids[0]}`, { headers })).json();
+      expect(unchanged.priority).toBe('High');
+
+      const invalid = await request.post(`${baseURL}/tasks/bulk`, {
+        data: { task_ids: ids, operations: { delete: true, priority: 'Low' } },
+        headers
+      });
+      expect(invalid.status()).toBe(400);
+
+      const deleted = await request.post(`${baseURL}/tasks/bulk`, {
+        data: { task_ids: ids, operations: { delete: true } },
+        headers
+      });
+      expect((await deleted.json()).succeeded).toBe(2);
+      const gone = await request.get(`${baseURL}/tasks/${ids[1]}`, { headers });
+      expect(gone.status()).toBe(404);
+    });
+
+    test('should save a project as a template and instantiate it with shifted due dates', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const stamp = Date.now();
+      const created = await request.post(`${baseURL}/projects`, {
+        data: { name: `Template Source ${stamp}`, start_date: '2025-01-01', end_date: '2025-01-31' },
+        headers
+      });
+      const projectId = (await created.json()).id;
+      const label = await (await request.post(`${baseURL}/projects/${projectId}/labels`, {
+        data: { name: 'release', color: '#ff0000' },
+        headers
+      })).json();
+      const taskId = `task-${stamp}-template`;
+      await request.post(`${baseURL}/tasks`, {
+        data: { id: taskId, title: 'Write release notes', project_id: projectId, due_date: '2025-01-11', label_ids: [label.id] },
+        headers
+      });
+      await request.post(`${baseURL}/tasks/${taskId}/subtasks`, { data: { title: 'Draft' }, headers });
+
+      const saved = await request.post(`${baseURL}/templates`, {
+        data: { source: 'project', source_id: projectId, name: `Release ${stamp}` },
+        headers
+      });
+      expect(saved.status()).toBe(201);
+      const template = await saved.json();
+      expect(template.kind).toBe('project');
+      expect(template.content.tasks[0].due_offset_days).toBe(10);
+
+      const instantiated = await request.post(`${baseURL}/projects/from-template`, {
+        data: { template_id: template.id, name: `Release copy ${stamp}`, start_date: '2025-03-01' },
+        headers
+      });
+      expect(instantiated.status()).toBe(201);
+      const result = await instantiated.json();
+      expect(result.task_ids.length).toBe(1);
+      const task = await (await request.get(`${baseURL}/tasks/${result.task_ids[0]}`, { headers })).json();
+      expect(task.project_id).toBe(result.project_id);
+      expect(task.due_date.startsWith('2025-03-11')).toBe(true);
+      expect(task.labels[0].name).toBe('release');
+      expect(task.subtask_progress.total).toBe(1);
+
+      // エクスポートした JSON はそのままインポートできる
+      const exported = await (await request.get(`${baseURL}/templates/${template.id}/export`, { headers })).json();
+      expect(exported.format).toBe('todo-app/template');
+      const imported = await request.post(`${baseURL}/templates/import`, { data: exported, headers });
+      expect(imported.status()).toBe(201);
+      expect((await imported.json()).id).not.toBe(template.id);
+
+      const invalid = await request.post(`${baseURL}/templates/import`, { data: { ...exported, version: 99 }, headers });
+      expect(invalid.status()).toBe(400);
+      const missingStart = await request.post(`${baseURL}/projects/from-template`, {
+        data: { template_id: template.id },
+        headers
+      });
+      expect(missingStart.status()).toBe(400);
+    });
+
+    test('should update, archive and unarchive a project', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const created = await request.post(`${baseURL}/projects`, {
+        data: { name: `Lifecycle ${Date.now()}` },
+        headers
+      });
+      const projectId = (await created.json()).id;
+
+      // 更新・アーカイブには If-Match が必要
+      const unconditional = await request.patch(`${baseURL}/projects/${projectId}`, {
+        data: { description: 'Updated description' },
+        headers
+      });
+      expect(unconditional.status()).toBe(428);
+
+      const patched = await request.patch(`${baseURL}/projects/${projectId}`, {
+        data: { description: 'Updated description' },
+        headers: { ...headers, 'If-Match': '"1"' }
+      });
+      expect(patched.status()).toBe(200);
+      const project = await patched.json();
+      expect(project.description).toBe('Updated description');
+      expect(project.status).toBe('active');
+
+      const invalid = await request.put(`${baseURL}/projects/${projectId}`, {
+        data: { name: '' },
+        headers: { ...headers, 'If-Match': `"${project.version}"` }
+      });
+      expect(invalid.status()).toBe(400);
+
+      const stale = await request.post(`${baseURL}/projects/${projectId}/archive`, { headers: { ...headers, 'If-Match': '"1"' } });
+      expect(stale.status()).toBe(412);
+      const archived = await request.post(`${baseURL}/projects/${projectId}/archive`, {
+        headers: { ...headers, 'If-Match': `"${project.version}"` }
+      });
+      expect(archived.status()).toBe(200);
+      expect((await archived.json()).status).toBe('archived');
+
+      // アーカイブ済みのプロジェクトは既定の一覧に含まれず、読み取り専用になる
+      const list = await (await request.get(`${baseURL}/projects`, { headers })).json();
+      expect(list.some((p: any) => p.id === projectId)).toBe(false);
+      const archivedList = await (await request.get(`${baseURL}/projects?status=archived`, { headers })).json();
+      expect(archivedList.some((p: any) => p.id === projectId)).toBe(true);
+      const readOnly = await request.post(`${baseURL}/tasks`, {
+        data: { title: 'Task in archived project', project_id: projectId },
+        headers
+      });
+      expect(readOnly.status()).toBe(409);
+      const update = await request.patch(`${baseURL}/projects/${projectId}`, { data: { name: 'Renamed' }, headers: { ...headers, 'If-Match': '*' } });
+      expect(update.status()).toBe(409);
+
+      const unarchived = await request.post(`${baseURL}/projects/${projectId}/unarchive`, { headers: { ...headers, 'If-Match': '*' } });
+      expect((await unarchived.json()).status).toBe('active');
+    });
+
+    test('should reassign open tasks when removing a project member', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const me = await (await request.get(`${baseURL}/users/me`, { headers })).json();
+      const created = await request.post(`${baseURL}/projects`, {
+        data: { name: `Members ${Date.now()}` },
+        headers
+      });
+      const projectId = (await created.json()).id;
+      const taskId = `task-${Date.now()}-member`;
+      await request.post(`${baseURL}/tasks`, {
+        data: { id: taskId, title: 'Assigned task', project_id: projectId, assignee_id: me.id },
+        headers
+      });
+
+      // 作成者自身を外す場合は割り当て先が必要
+      const missing = await request.delete(`${baseURL}/projects/${projectId}/members/${me.id}`, { headers });
+      expect(missing.status()).toBe(400);
+
+      const notMember = await request.delete(`${baseURL}/projects/${projectId}/members/non-existent-user`, { headers });
+      expect(notMember.status()).toBe(404);
+    });
+
+    test('should enforce project member roles', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const me = await (await request.get(`${baseURL}/users/me`, { headers })).json();
+      const created = await request.post(`${baseURL}/projects`, {
+        data: { name: `Roles ${Date.now()}` },
+        headers
+      });
+      const projectId = (await created.json()).id;
+
+      // 2人目のユーザーを作成してログインする
+      const email = `viewer-${Date.now()}@example.com`;
+      await request.post(`${baseURL}/users/register`, {
+        data: { name: 'Viewer', email, password: 'password123' }
+      });
+      const login = await (await request.post(`${baseURL}/users/login`, { data: { email, password: 'password123' } })).json();
+      const viewerHeaders = { 'Authorization': `Bearer ${login.token || login}` };
+      const viewer = await (await request.get(`${baseURL}/users/me`, { headers: viewerHeaders })).json();
+
+      // メンバーでないユーザーは閲覧できず、一覧にも含まれない
+      const hidden = await request.get(`${baseURL}/projects/${projectId}`, { headers: viewerHeaders });
+      expect(hidden.status()).toBe(403);
+      const before = await (await request.get(`${baseURL}/projects`, { headers: viewerHeaders })).json();
+      expect(before.map((p: { id: string }) => p.id)).not.toContain(projectId);
+
+      const added = await request.post(`${baseURL}/projects/${projectId}/members/${viewer.id}`, { data: { role: 'viewer' }, headers });
+      expect(added.status()).toBe(200);
+      // 既にメンバーのユーザーの追加はロールを変えずに 409
+      const readded = await request.post(`${baseURL}/projects/${projectId}/members/${viewer.id}`, { data: { role: 'maintainer' }, headers });
+      expect(readded.status()).toBe(409);
+      const members = await (await request.get(`${baseURL}/projects/${projectId}/members`, { headers })).json();
+      expect(members.find((m: { id: string }) => m.id === me.id).role).toBe('owner');
+      expect(members.find((m: { id: string }) => m.id === viewer.id).role).toBe('viewer');
+
+      // viewer は閲覧のみ
+      const visible = await request.get(`${baseURL}/projects/${projectId}`, { headers: viewerHeaders });
+      expect(visible.status()).toBe(200);
+      const createTask = await request.post(`${baseURL}/tasks`, {
+        data: { title: 'Viewer task', project_id: projectId },
+        headers: viewerHeaders
+      });
+      expect(createTask.status()).toBe(403);
+      const archive = await request.post(`${baseURL}/projects/${projectId}/archive`, { headers: { ...viewerHeaders, 'If-Match': '*' } });
+      expect(archive.status()).toBe(403);
+
+      // member に変更するとタスクを作成できる
+      const promoted = await request.patch(`${baseURL}/projects/${projectId}/members/${viewer.id}`, { data: { role: 'member' }, headers });
+      expect((await promoted.json()).role).toBe('member');
+      const memberTask = await request.post(`${baseURL}/tasks`, {
+        data: { title: 'Member task', project_id: projectId },
+        headers: viewerHeaders
+      });
+      expect(memberTask.status()).toBe(201);
+
+      // 最後の owner は変更できない
+      const demote = await request.patch(`${baseURL}/projects/${projectId}/members/${me.id}`, { data: { role: 'member' }, headers });
+      expect(demote.status()).toBe(409);
+      const invalid = await request.patch(`${baseURL}/projects/${projectId}/members/${viewer.id}`, { data: { role: 'admin' }, headers });
+      expect(invalid.status()).toBe(400);
+    });
+
+    test('should validate assignees and list my tasks', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const me = await (await request.get(`${baseURL}/users/me`, { headers })).json();
+      const created = await request.post(`${baseURL}/projects`, {
+        data: { name: `Assignees ${Date.now()}` },
+        headers
+      });
+      const projectId = (await created.json()).id;
+
+      const email = `assignee-${Date.now()}@example.com`;
+      await request.post(`${baseURL}/users/register`, {
+        data: { name: 'Assignee', email, password: 'password123' }
+      });
+      const login = await (await request.post(`${baseURL}/users/login`, { data: { email, password: 'password123' } })).json();
+      const otherHeaders = { 'Authorization': `Bearer ${login.token || login}` };
+      const other = await (await request.get(`${baseURL}/users/me`, { headers: otherHeaders })).json();
+
+      // メンバーでないユーザーは担当者にできない
+      const notMember = await request.post(`${baseURL}/tasks`, {
+        data: { title: 'Not a member', project_id: projectId, assignee_id: other.id },
+        headers
+      });
+      expect(notMember.status()).toBe(400);
+      expect((await notMember.json()).fields.assignee_id).toBeDefined();
+
+      // メンバーに追加すると複数の担当者を指定できる
+      await request.post(`${baseURL}/projects/${projectId}/members/${other.id}`, { headers });
+      const yesterday = new Date(Date.now() - 2 * 24 * 60 * 60 * 1000).toISOString();
+      const shared = await request.post(`${baseURL}/tasks`, {
+        data: { title: 'Shared task', project_id: projectId, assignee_ids: [me.id, other.id], due_date: yesterday },
+        headers
+      });
+      expect(shared.status()).toBe(201);
+      const taskId = (await shared.json()).id;
+      const task = await (await request.get(`${baseURL}/tasks/${taskId}`, { headers })).json();
+      expect(task.assignee_id).toBe(me.id);
+      expect(task.assignee_ids).toEqual([me.id, other.id]);
+
+      // 担当者は GET /tasks?assignee_id= と GET /users/me/tasks に含まれる
+      const filtered = await (await request.get(`${baseURL}/tasks?assignee_id=${other.id}`, { headers })).json();
+      expect(filtered.map((t: { id: string }) => t.id)).toContain(taskId);
+      const mine = await request.get(`${baseURL}/users/me/tasks`, { headers: otherHeaders });
+      expect(mine.status()).toBe(200);
+      const buckets = await mine.json();
+      expect(Object.keys(buckets)).toEqual(expect.arrayContaining(['overdue', 'today', 'this_week', 'later']));
+      expect(buckets.overdue.map((t: { id: string }) => t.id)).toContain(taskId);
+    });
+
+    test('should plan sprints and roll unfinished tasks forward', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const created = await request.post(`${baseURL}/projects`, {
+        data: { name: `Sprints ${Date.now()}` },
+        headers
+      });
+      const projectId = (await created.json()).id;
+
+      const invalid = await request.post(`${baseURL}/projects/${projectId}/sprints`, {
+        data: { name: 'Backwards', start_date: '2030-01-14', end_date: '2030-01-01' },
+        headers
+      });
+      expect(invalid.status()).toBe(400);
+
+      const first = await (await request.post(`${baseURL}/projects/${projectId}/sprints`, {
+        data: { name: 'Sprint 1', start_date: '2030-01-01', end_date: '2030-01-14' },
+        headers
+      })).json();
+      const second = await (await request.post(`${baseURL}/projects/${projectId}/sprints`, {
+        data: { name: 'Sprint 2', start_date: '2030-01-15', end_date: '2030-01-28' },
+        headers
+      })).json();
+      expect(first.status).toBe('planned');
+      const milestone = await request.post(`${baseURL}/projects/${projectId}/milestones`, {
+        data: { name: 'Beta', end_date: '2030-01-28' },
+        headers
+      });
+      expect(milestone.status()).toBe(201);
+      const milestoneId = (await milestone.json()).id;
+
+      // タスクをスプリントとマイルストーンに予定する
+      const ids: string[] = [];
+      for (const title of ['Finished', 'Unfinished']) {
+        const task = await request.post(`${baseURL}/tasks`, {
+          data: { title, project_id: projectId, sprint_id: first.id, milestone_id: milestoneId, estimate: 60 },
+          headers
+        });
+        expect(task.status()).toBe(201);
+        ids.push((await task.json()).id);
+      }
+      const other = await request.post(`${baseURL}/tasks`, {
+        data: { title: 'Elsewhere', project_id: testProjectId, sprint_id: first.id },
+        headers
+      });
+      expect(other.status()).toBe(400);
+
+      const started = await request.post(`${baseURL}/sprints/${first.id}/start`, { headers });
+      expect((await started.json()).status).toBe('active');
+      const again = await request.post(`${baseURL}/sprints/${second.id}/start`, { headers });
+      expect(again.status()).toBe(409);
+
+      const done = await request.post(`${baseURL}/tasks/${ids[0]}/transitions`, { data: { to: 'Done' }, headers });
+      expect(done.status()).toBe(200);
+      const summary = await (await request.get(`${baseURL}/sprints/${first.id}/summary`, { headers })).json();
+      expect(summary.progress.total).toBe(2);
+      expect(summary.progress.estimate).toBe(120);
+      expect(summary.days_total).toBe(14);
+
+      // 終了すると未完了のタスクは次の計画中のスプリントに移る
+      const closed = await (await request.post(`${baseURL}/sprints/${first.id}/close`, { headers })).json();
+      expect(closed.sprint.status).toBe('closed');
+      expect(closed.next_sprint_id).toBe(second.id);
+      expect(closed.task_ids).toEqual([ids[1]]);
+      const moved = await (await request.get(`${baseURL}/tasks?sprint_id=${second.id}`, { headers })).json();
+      expect(moved.map((t: { id: string }) => t.id)).toEqual([ids[1]]);
+      const rolled = await (await request.get(`${baseURL}/tasks/${ids[1]}/history?limit=1`, { headers })).json();
+      expect(rolled.entries[0].changes).toEqual([{ field: 'sprint_id', old: first.id, new: second.id }]);
+
+      const milestoneSummary = await (await request.get(`${baseURL}/milestones/${milestoneId}/summary`, { headers })).json();
+      expect(milestoneSummary.progress.total).toBe(2);
+    });
+
+    test('should report burndown, flow and cycle time from status history', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const created = await request.post(`${baseURL}/projects`, {
+        data: { name: `Reports ${Date.now()}` },
+        headers
+      });
+      const projectId = (await created.json()).id;
+
+      const ids: string[] = [];
+      for (const title of ['Ship', 'Drop', 'Keep']) {
+        const task = await request.post(`${baseURL}/tasks`, {
+          data: { title, project_id: projectId, estimate: 30 },
+          headers
+        });
+        expect(task.status()).toBe(201);
+        ids.push((await task.json()).id);
+      }
+      await request.post(`${baseURL}/tasks/${ids[0]}/transitions`, { data: { to: 'InProgress' }, headers });
+      await request.post(`${baseURL}/tasks/${ids[0]}/transitions`, { data: { to: 'Done' }, headers });
+      await request.post(`${baseURL}/tasks/${ids[1]}/transitions`, { data: { to: 'Canceled' }, headers });
+
+      // 日ごとの値はステータス遷移の履歴から導く
+      const burndown = await (await request.get(`${baseURL}/projects/${projectId}/reports/burndown`, { headers })).json();
+      const today = burndown.points[burndown.points.length - 1];
+      expect(today.remaining).toBe(1);
+      expect(today.remaining_estimate).toBe(30);
+
+      const burnup = await (await request.get(`${baseURL}/projects/${projectId}/reports/burnup`, { headers })).json();
+      expect(burnup.points[burnup.points.length - 1]).toMatchObject({ scope: 2, completed: 1 });
+
+      const flow = await (await request.get(`${baseURL}/projects/${projectId}/reports/cumulative-flow`, { headers })).json();
+      expect(flow.points[flow.points.length - 1].counts).toEqual({ Open: 1, InProgress: 0, Done: 1, Canceled: 1 });
+
+      const throughput = await (await request.get(`${baseURL}/projects/${projectId}/reports/throughput`, { headers })).json();
+      expect(throughput.total).toBe(1);
+      const cycle = await (await request.get(`${baseURL}/projects/${projectId}/reports/cycle-time`, { headers })).json();
+      expect(cycle.count).toBe(1);
+      expect(cycle.tasks[0].task_id).toBe(ids[0]);
+
+      const csv = await request.get(`${baseURL}/projects/${projectId}/reports/cumulative-flow?format=csv`, { headers });
+      expect(csv.headers()['content-type']).toContain('text/csv');
+      expect((await csv.text()).split('\n')[0]).toBe('date,Open,InProgress,Done,Canceled');
+
+      const velocity = await (await request.get(`${baseURL}/projects/${projectId}/reports/velocity`, { headers })).json();
+      expect(velocity.sprints).toEqual([]);
+
+      const invalid = await request.get(`${baseURL}/projects/${projectId}/reports/burndown?from=2030-02-01&to=2030-01-01`, { headers });
+      expect(invalid.status()).toBe(400);
+      const unknown = await request.get(`${baseURL}/projects/${projectId}/reports/forecast`, { headers });
+      expect(unknown.status()).toBe(404);
+    });
+
+    test('should compute the timeline critical path and cascade rescheduling', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const created = await request.post(`${baseURL}/projects`, {
+        data: { name: `Timeline ${Date.now()}`, start_date: '2030-01-01', end_date: '2030-01-06' },
+        headers
+      });
+      const projectId = (await created.json()).id;
+
+      const invalid = await request.post(`${baseURL}/tasks`, {
+        data: { title: 'Backwards', project_id: projectId, start_date: '2030-01-05', due_date: '2030-01-01' },
+        headers
+      });
+      expect(invalid.status()).toBe(400);
+
+      const ids: Record<string, string> = {};
+      const plan: [string, string | undefined, number][] = [
+        ['Design', '2030-01-01', 3],
+        ['Build', '2030-01-02', 2],
+        ['Docs', '2030-01-01', 1],
+        ['Release', '2030-01-05', 2],
+        ['Someday', undefined, 0]
+      ];
+      for (const [title, start_date, duration_days] of plan) {
+        const task = await request.post(`${baseURL}/tasks`, {
+          data: { title, project_id: projectId, start_date, duration_days },
+          headers
+        });
+        expect(task.status()).toBe(201);
+        ids[title] = (await task.json()).id;
+      }
+      for (const [blocked, blocker] of [['Build', 'Design'], ['Docs', 'Design'], ['Release', 'Build']]) {
+        const dep = await request.post(`${baseURL}/tasks/${ids[blocked]}/dependencies`, {
+          data: { blocked_by_id: ids[blocker] },
+          headers
+        });
+        expect(dep.status()).toBe(201);
+      }
+
+      // Build は Design の終了を待ち、Release はプロジェクトの終了日を超える
+      const timeline = await (await request.get(`${baseURL}/projects/${projectId}/timeline`, { headers })).json();
+      expect(timeline.critical_path).toEqual([ids.Design, ids.Build, ids.Release]);
+      expect(timeline.unscheduled.map((t: { id: string }) => t.id)).toEqual([ids.Someday]);
+      const bars = Object.fromEntries(timeline.bars.map((b: { task_id: string }) => [b.task_id, b]));
+      expect(bars[ids.Build]).toMatchObject({ earliest_start: '2030-01-04', shift_days: 2, critical: true });
+      expect(bars[ids.Docs]).toMatchObject({ critical: false, slack: 3 });
+      expect(bars[ids.Release]).toMatchObject({ earliest_finish: '2030-01-07', past_project_end: true });
+      expect(timeline.overrun_days).toBe(1);
+
+      const rescheduled = await request.post(`${baseURL}/tasks/${ids.Design}/reschedule`, {
+        data: { start_date: '2030-01-03', cascade: true },
+        headers
+      });
+      expect(rescheduled.status()).toBe(200);
+      const result = await rescheduled.json();
+      expect(result.task.duration_days).toBe(3);
+      const shifted = Object.fromEntries(result.shifted.map((s: { task_id: string }) => [s.task_id, s]));
+      expect(shifted[ids.Build]).toMatchObject({ start: '2030-01-06', shift_days: 4 });
+      expect(shifted[ids.Docs]).toMatchObject({ start: '2030-01-06', shift_days: 5 });
+      expect(shifted[ids.Release]).toMatchObject({ start: '2030-01-08', end: '2030-01-09' });
+    });
+
+    test('should order tasks on the kanban board and enforce WIP limits', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const created = await request.post(`${baseURL}/projects`, { data: { name: `Board ${Date.now()}` }, headers });
+      const projectId = (await created.json()).id;
+
+      const ids: Record<string, string> = {};
+      for (const title of ['A', 'B', 'C']) {
+        const task = await request.post(`${baseURL}/tasks`, { data: { title, project_id: projectId }, headers });
+        expect(task.status()).toBe(201);
+        ids[title] = (await task.json()).id;
+      }
+      const titles = async (status: string) => {
+        const board = await (await request.get(`${baseURL}/projects/${projectId}/board`, { headers })).json();
+        return board.columns.find((c: { status: string }) => c.status === status).tasks.map((t: { title: string }) => t.title);
+      };
+      expect(await titles('Open')).toEqual(['A', 'B', 'C']);
+
+      // C を A と B の間に移す
+      const reordered = await request.post(`${baseURL}/tasks/${ids.C}/move`, {
+        data: { after_id: ids.A, before_id: ids.B },
+        headers
+      });
+      expect(reordered.status()).toBe(200);
+      expect(await titles('Open')).toEqual(['A', 'C', 'B']);
+
+      const columns = await request.put(`${baseURL}/projects/${projectId}/board/columns`, {
+        data: { columns: [{ status: 'Open', name: 'To do' }, { status: 'InProgress', name: 'Doing', wip_limit: 1, wip_policy: 'reject' }] },
+        headers
+      });
+      expect(columns.status()).toBe(200);
+      expect((await columns.json()).columns.map((c: { name: string }) => c.name)).toEqual(['To do', 'Doing', 'Done', 'Canceled']);
+
+      const first = await request.post(`${baseURL}/tasks/${ids.A}/move`, { data: { column: 'InProgress' }, headers });
+      expect(first.status()).toBe(200);
+      expect((await first.json()).task.status).toBe('InProgress');
+      const rejected = await request.post(`${baseURL}/tasks/${ids.B}/move`, { data: { column: 'InProgress' }, headers });
+      expect(rejected.status()).toBe(409);
+      expect(await rejected.json()).toMatchObject({ column: 'InProgress', limit: 1, count: 2 });
+
+      await request.put(`${baseURL}/projects/${projectId}/board/columns`, {
+        data: { columns: [{ status: 'InProgress', wip_limit: 1 }] },
+        headers
+      });
+      const warned = await request.post(`${baseURL}/tasks/${ids.B}/move`, {
+        data: { column: 'InProgress', before_id: ids.A },
+        headers
+      });
+      expect(warned.status()).toBe(200);
+      expect((await warned.json()).warnings).toHaveLength(1);
+      expect(await titles('InProgress')).toEqual(['B', 'A']);
+
+      const invalid = await request.post(`${baseURL}/tasks/${ids.C}/move`, { data: { after_id: ids.A }, headers });
+      expect(invalid.status()).toBe(400);
+    });
+
+    test('should record task and project activity with actors and field changes', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const created = await request.post(`${baseURL}/projects`, { data: { name: `Activity ${Date.now()}` }, headers });
+      const projectId = (await created.json()).id;
+
+      const task = await request.post(`${baseURL}/tasks`, {
+        data: { title: 'Audit me', project_id: projectId, priority: 'Low' },
+        headers
+      });
+      const taskId = (await task.json()).id;
+      const updated = await request.patch(`${baseURL}/tasks/${taskId}`, {
+        data: { priority: 'High' },
+        headers: { ...headers, 'If-Match': '"1"' }
+      });
+      expect(updated.status()).toBe(200);
+      const subtask = await request.post(`${baseURL}/tasks/${taskId}/subtasks`, { data: { title: 'Step' }, headers });
+      expect(subtask.status()).toBe(201);
+
+      const history = await (await request.get(`${baseURL}/tasks/${taskId}/history`, { headers })).json();
+      expect(history.entries.map((e: { entity_type: string; action: string }) => `${e.entity_type}:${e.action}`))
+        .toEqual(['subtask:created', 'task:updated', 'task:created']);
+      const change = history.entries[1];
+      expect(change.actor_id).toBeTruthy();
+      expect(change.project_id).toBe(projectId);
+      expect(change.changes).toEqual([{ field: 'priority', old: 'Low', new: 'High' }]);
+
+      const first = await (await request.get(`${baseURL}/tasks/${taskId}/history?limit=1`, { headers })).json();
+      expect(first.entries).toHaveLength(1);
+      const next = await (await request.get(`${baseURL}/tasks/${taskId}/history?limit=1&cursor=${first.next_cursor}`, { headers })).json();
+      expect(next.entries[0].id).toBe(change.id);
+
+      const activity = await (await request.get(`${baseURL}/projects/${projectId}/activity?entity_type=project`, { headers })).json();
+      expect(activity.entries.map((e: { action: string }) => e.action)).toEqual(['created']);
+      const all = await (await request.get(`${baseURL}/projects/${projectId}/activity`, { headers })).json();
+      expect(all.entries).toHaveLength(4);
+
+      const invalid = await request.get(`${baseURL}/projects/${projectId}/activity?limit=0`, { headers });
+      expect(invalid.status()).toBe(400);
+    });
+
+    test('should validate, filter and sort tasks by project custom fields', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const created = await request.post(`${baseURL}/projects`, { data: { name: `Custom ${Date.now()}` }, headers });
+      const projectId = (await created.json()).id;
+
+      const points = await request.post(`${baseURL}/projects/${projectId}/custom-fields`, {
+        data: { key: 'points', name: 'Story points', type: 'number' },
+        headers
+      });
+      expect(points.status()).toBe(201);
+      const env = await request.post(`${baseURL}/projects/${projectId}/custom-fields`, {
+        data: { key: 'env', name: 'Environment', type: 'select', options: ['dev', 'prod'] },
+        headers
+      });
+      expect(env.status()).toBe(201);
+      const envId = (await env.json()).id;
+      const duplicate = await request.post(`${baseURL}/projects/${projectId}/custom-fields`, {
+        data: { key: 'env', name: 'Env', type: 'text' },
+        headers
+      });
+      expect(duplicate.status()).toBe(400);
+
+      const ids: Record<string, string> = {};
+      for (const [title, values] of [['Small', { points: 1, env: 'prod' }], ['Large', { points: 8, env: 'dev' }], ['None', {}]] as const) {
+        const task = await request.post(`${baseURL}/tasks`, { data: { title, project_id: projectId, custom_fields: values }, headers });
+        expect(task.status()).toBe(201);
+        ids[title] = (await task.json()).id;
+      }
+      const invalid = await request.post(`${baseURL}/tasks`, {
+        data: { title: 'Bad', project_id: projectId, custom_fields: { env: 'qa' } },
+        headers
+      });
+      expect(invalid.status()).toBe(400);
+      expect((await invalid.json()).fields['custom_fields.env']).toBeTruthy();
+
+      const task = await (await request.get(`${baseURL}/tasks/${ids.Small}`, { headers })).json();
+      expect(task.custom_fields).toEqual({ points: 1, env: 'prod' });
+
+      const filtered = await (await request.get(`${baseURL}/tasks?project_id=${projectId}&cf.env=prod`, { headers })).json();
+      expect(filtered.map((t: { id: string }) => t.id)).toEqual([ids.Small]);
+      const sorted = await (await request.get(`${baseURL}/tasks?project_id=${projectId}&sort=-cf.points`, { headers })).json();
+      expect(sorted.map((t: { title: string }) => t.title)).toEqual(['Large', 'Small', 'None']);
+
+      // PATCH は指定したキーのみ変更し、null で値を削除する
+      const patched = await request.patch(`${baseURL}/tasks/${ids.Small}`, {
+        data: { custom_fields: { points: null } },
+        headers: { ...headers, 'If-Match': '"1"' }
+      });
+      expect(patched.status()).toBe(200);
+      expect((await patched.json()).custom_fields).toEqual({ env: 'prod' });
+
+      // 選択肢から外れた値はタスクから取り除かれる
+      const narrowed = await request.patch(`${baseURL}/projects/${projectId}/custom-fields/${envId}`, { data: { options: ['dev'] }, headers });
+      expect(narrowed.status()).toBe(200);
+      const pruned = await (await request.get(`${baseURL}/tasks/${ids.Small}`, { headers })).json();
+      expect(pruned.custom_fields).toEqual({});
+
+      const retyped = await request.patch(`${baseURL}/projects/${projectId}/custom-fields/${envId}`, { data: { type: 'text' }, headers });
+      expect(retyped.status()).toBe(400);
+    });
+
+    test('should move and clone tasks between projects', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const me = await (await request.get(`${baseURL}/users/me`, { headers })).json();
+      const source = (await (await request.post(`${baseURL}/projects`, { data: { name: `Source ${Date.now()}` }, headers })).json()).id;
+      const target = (await (await request.post(`${baseURL}/projects`, { data: { name: `Target ${Date.now()}` }, headers })).json()).id;
+
+      // 移動元にだけ参加しているユーザーは移動先で担当者から外れる
+      const email = `transfer-${Date.now()}@example.com`;
+      await request.post(`${baseURL}/users/register`, { data: { name: 'Transfer', email, password: 'password123' } });
+      const login = await (await request.post(`${baseURL}/users/login`, { data: { email, password: 'password123' } })).json();
+      const otherHeaders = { 'Authorization': `Bearer ${login.token || login}` };
+      const other = await (await request.get(`${baseURL}/users/me`, { headers: otherHeaders })).json();
+      await request.post(`${baseURL}/projects/${source}/members/${other.id}`, { data: { role: 'member' }, headers });
+
+      const label = await (await request.post(`${baseURL}/projects/${source}/labels`, { data: { name: 'backend', color: '#00aa00' }, headers })).json();
+      for (const projectId of [source, target]) {
+        await request.post(`${baseURL}/projects/${projectId}/custom-fields`, { data: { key: 'points', name: 'Points', type: 'number' }, headers });
+      }
+      await request.post(`${baseURL}/projects/${source}/custom-fields`, { data: { key: 'team', name: 'Team', type: 'text' }, headers });
+      const created = await request.post(`${baseURL}/tasks`, {
+        data: { title: 'Transfer me', project_id: source, assignee_ids: [me.id, other.id], label_ids: [label.id], custom_fields: { points: 3, team: 'api' } },
+        headers
+      });
+      const taskId = (await created.json()).id;
+      await request.post(`${baseURL}/tasks/${taskId}/subtasks`, { data: { title: 'Step 1' }, headers });
+      await request.post(`${baseURL}/comments`, { data: { content: 'Note', task_id: taskId }, headers });
+
+      // 複製は元のタスクを残し、指定した内容だけ引き継ぐ
+      const cloned = await request.post(`${baseURL}/tasks/${taskId}/clone`, { data: { include: { subtasks: true, comments: true } }, headers });
+      expect(cloned.status()).toBe(201);
+      const clone = await cloned.json();
+      expect(clone.id).not.toBe(taskId);
+      expect(clone.project_id).toBe(source);
+      expect(clone.labels).toEqual([]);
+      expect(clone.custom_fields).toEqual({ points: 3, team: 'api' });
+      const subtasks = await (await request.get(`${baseURL}/tasks/${clone.id}/subtasks`, { headers })).json();
+      expect(subtasks.map((s: { title: string }) => s.title)).toEqual(['Step 1']);
+
+      // 移動先にないラベルは同じ名前で作成され、移動先にないフィールドの値は外れる
+      const moved = await request.post(`${baseURL}/tasks/${taskId}/move`, {
+        data: { project_id: target, include: { labels: true } },
+        headers: { ...headers, 'If-Match': '"1"' }
+      });
+      expect(moved.status()).toBe(200);
+      const task = (await moved.json()).task;
+      expect(task.project_id).toBe(target);
+      expect(task.assignee_ids).toEqual([me.id]);
+      expect(task.labels.map((l: { name: string; color: string }) => [l.name, l.color])).toEqual([['backend', '#00aa00']]);
+      expect(task.labels[0].id).not.toBe(label.id);
+      expect(task.custom_fields).toEqual({ points: 3 });
+      const stillThere = await (await request.get(`${baseURL}/tasks/${taskId}/subtasks`, { headers })).json();
+      expect(stillThere).toHaveLength(1);
+
+      // 移動先のタスクを編集できないユーザーは移動・複製できない
+      const denied = await request.post(`${baseURL}/tasks/${clone.id}/move`, { data: { project_id: target }, headers: otherHeaders });
+      expect(denied.status()).toBe(403);
+      const deniedClone = await request.post(`${baseURL}/tasks/${clone.id}/clone`, { data: { project_id: target }, headers: otherHeaders });
+      expect(deniedClone.status()).toBe(403);
+      const mixed = await request.post(`${baseURL}/tasks/${clone.id}/move`, { data: { project_id: target, column: 'Todo' }, headers });
+      expect(mixed.status()).toBe(400);
+
+      // 一括操作での移動も同じく付け替え、移動先の列の最後に並べる
+      const bulkMoved = await request.post(`${baseURL}/tasks/bulk`, {
+        data: { task_ids: [clone.id], operations: { project_id: target } },
+        headers
+      });
+      expect((await bulkMoved.json()).succeeded).toBe(1);
+      const movedClone = await (await request.get(`${baseURL}/tasks/${clone.id}`, { headers })).json();
+      expect(movedClone.project_id).toBe(target);
+      expect(movedClone.assignee_ids).toEqual([me.id]);
+      expect(movedClone.custom_fields).toEqual({ points: 3 });
+      expect(movedClone.rank > task.rank).toBe(true);
+    });
+
+    test('should summarize task counts', async ({ request }) => {
+      const response = await request.get(`${baseURL}/tasks/summary`, {
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+      expect(response.status()).toBe(200);
+      const summary = await response.json();
+      expect(summary.total).toBeGreaterThan(0);
+      expect(summary.by_status.Open).toBeDefined();
+      expect(summary.by_priority.High).toBeDefined();
+      expect(summary.incomplete).toBeLessThanOrEqual(summary.total);
+
+      const projectResponse = await request.get(`${baseURL}/projects/${testProjectId}/tasks/summary`, {
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+      expect(projectResponse.status()).toBe(200);
+      const projectSummary = await projectResponse.json();
+      expect(projectSummary.total).toBeLessThanOrEqual(summary.total);
+
+      const invalid = await request.get(`${baseURL}/tasks/summary?status=Unknown`, {
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+      expect(invalid.status()).toBe(400);
+    });
+
+    test('should move a deleted task to trash and restore it', async ({ request }) => {
+      const taskId = `task-${Date.now()}-trash`;
+      await request.post(`${baseURL}/tasks`, {
+        data: {
+          id: taskId,
+          title: 'Task for Trash Test',
+          project_id: testProjectId
+        },
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+
+      const deleteResponse = await request.delete(`${baseURL}/tasks/${taskId}`, {
+        headers: {
+          'Authorization': `Bearer ${authToken}`,
+          'If-Match': '"1"'
+        }
+      });
+      expect(deleteResponse.status()).toBe(200);
+      const deleted = await deleteResponse.json();
+      expect(deleted.restore_url).toBe(`/tasks/${taskId}/restore`);
+
+      const trashResponse = await request.get(`${baseURL}/trash`, {
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+      expect(trashResponse.status()).toBe(200);
+      const trash = await trashResponse.json();
+      expect(trash.tasks.some((t: any) => t.id === taskId)).toBe(true);
+
+      // プロジェクトのメンバーでないユーザーのゴミ箱には表示されず、物理削除もできない
+      const email = `trash-${Date.now()}@example.com`;
+      await request.post(`${baseURL}/users/register`, {
+        data: { name: 'Outsider', email, password: 'password123' }
+      });
+      const login = await (await request.post(`${baseURL}/users/login`, { data: { email, password: 'password123' } })).json();
+      const outsiderHeaders = { 'Authorization': `Bearer ${login.token || login}` };
+      const outsiderTrash = await (await request.get(`${baseURL}/trash`, { headers: outsiderHeaders })).json();
+      expect(outsiderTrash.tasks.some((t: any) => t.id === taskId)).toBe(false);
+      const foreignPurge = await request.delete(`${baseURL}/trash/tasks/${taskId}`, { headers: outsiderHeaders });
+      expect(foreignPurge.status()).toBe(403);
+
+      const restoreResponse = await request.post(`${baseURL}/tasks/${taskId}/restore`, {
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+      expect(restoreResponse.status()).toBe(200);
+      const restored = await restoreResponse.json();
+      expect(restored.id).toBe(taskId);
+
+      const getResponse = await request.get(`${baseURL}/tasks/${taskId}`, {
+        headers: {
+          'Authorization': `Bearer ${authToken}`
+        }
+      });
+      expect(getResponse.status()).toBe(200);
+    });
+
+    test('should purge a task from trash', async ({ request }) => {
+      const headers = { 'Authorization': `Bearer ${authToken}` };
+      const taskId = `task-${Date.now()}-purge`;
+      await request.post(`${baseURL}/tasks`, {
+        data: { id: taskId, title: 'Task to purge', project_id: testProjectId },
+        headers
+      });
+
+      // ゴミ箱にないタスクは物理削除できない
+      const active = await request.delete(`${baseURL}/trash/tasks/${taskId}`, { headers });
+      expect(active.status()).toBe(404);
+
+      await request.delete(`${baseURL}/tasks/${taskId}`, { headers: { ...headers, 'If-Match': '"1"' } });
+      const purged = await request.delete(`${baseURL}/trash/tasks/${taskId}`, { headers });
+      expect(purged.status()).toBe(204);
+
+      const trash = await (await request.get(`${baseURL}/trash`, { headers })).json();
+      expect(trash.tasks.some((t: any) => t.id === taskId)).toBe(false);
+      const restore = await request.post(`${baseURL}/tasks/${taskId}/restore`, { headers });
+      expect(restore.status()).toBe(404);
+    });
   });
 
   test.describe('Project Management API', () => {
@@ -207,7 +1599,8 @@ test.describe('TODO API Tests', () => {
       // Delete the project
       const deleteResponse = await request.delete(`${baseURL}/projects/${projectId}`, {
         headers: {
-          'Authorization': `Bearer ${authToken}`
+          'Authorization': `Bearer ${authToken}`,
+          'If-Match': '"1"'
         }
       });
       
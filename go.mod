@@ -4,6 +4,7 @@ go 1.23.5
 
 require (
 	github.com/go-chi/chi/v5 v5.2.1
+	github.com/go-chi/cors v1.2.1
 	github.com/golang-jwt/jwt/v4 v4.5.2
 	github.com/google/uuid v1.6.0
 	github.com/lib/pq v1.10.9
@@ -12,7 +13,4 @@ require (
 	golang.org/x/crypto v0.39.0
 )
 
-require (
-	github.com/go-chi/cors v1.2.1 // indirect
-	go.uber.org/multierr v1.10.0 // indirect
-)
+require go.uber.org/multierr v1.10.0 // indirect
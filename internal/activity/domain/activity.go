@@ -0,0 +1,102 @@
+package domain
+
+import (
+    "errors"
+    "reflect"
+    "time"
+)
+
+// 変更履歴の対象の種類
+const (
+    EntityTask    = "task"
+    EntitySubtask = "subtask"
+    EntityProject = "project"
+    EntityComment = "comment"
+)
+
+// 変更履歴の操作
+const (
+    ActionCreated  = "created"
+    ActionUpdated  = "updated"
+    ActionDeleted  = "deleted"
+    ActionRestored = "restored"
+)
+
+const (
+    // DefaultLimit は limit 未指定時に返す件数です
+    DefaultLimit = 50
+    // MaxLimit は一度に返す最大の件数です
+    MaxLimit = 200
+)
+
+var (
+    ErrInvalidEntityType = errors.New("entity_type must be one of task, subtask, project, comment")
+    ErrInvalidLimit      = errors.New("limit must be between 1 and 200")
+    ErrInvalidCursor     = errors.New("cursor does not refer to an entry")
+)
+
+// Change はフィールド単位の変更です。作成時の Old、削除時の New は nil です
+type Change struct {
+    Field string      `json:"field"`
+    Old   interface{} `json:"old"`
+    New   interface{} `json:"new"`
+}
+
+// Entry は変更履歴の 1 件です。変更履歴は追記のみで、更新・削除はできません
+// TaskID はタスク・サブタスク・コメントの履歴の対象のタスク、ProjectID は記録した時点のプロジェクトです
+type Entry struct {
+    ID         string    `json:"id"`
+    EntityType string    `json:"entity_type"`
+    EntityID   string    `json:"entity_id"`
+    TaskID     string    `json:"task_id,omitempty"`
+    ProjectID  string    `json:"project_id,omitempty"`
+    Action     string    `json:"action"`
+    ActorID    string    `json:"actor_id"`
+    Changes    []Change  `json:"changes"`
+    CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewEntry は変更のない変更履歴を作成します。変更は Add で追加します
+func NewEntry(entityType, entityID, action, actorID string) *Entry {
+    return &Entry{
+        EntityType: entityType,
+        EntityID:   entityID,
+        Action:     action,
+        ActorID:    actorID,
+        Changes:    []Change{},
+    }
+}
+
+// Add は old と new が異なる場合に変更を追加します
+func (e *Entry) Add(field string, old, new interface{}) {
+    if reflect.DeepEqual(old, new) {
+        return
+    }
+    e.Changes = append(e.Changes, Change{Field: field, Old: old, New: new})
+}
+
+// Time は日時を変更履歴の値（RFC 3339 の UTC）に変換します。ゼロ値は nil です
+func Time(t time.Time) interface{} {
+    if t.IsZero() {
+        return nil
+    }
+    return t.UTC().Format(time.RFC3339)
+}
+
+// ValidEntityType は entityType が変更履歴の対象の種類かを返します
+func ValidEntityType(entityType string) bool {
+    switch entityType {
+    case EntityTask, EntitySubtask, EntityProject, EntityComment:
+        return true
+    }
+    return false
+}
+
+// Query は変更履歴の絞り込みとページングの条件です。新しい順に返します
+// Cursor を指定すると、その ID の変更履歴より古いものを返します
+type Query struct {
+    EntityType string
+    ActorID    string
+    Cursor     string
+    Limit      int
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"todo-app/internal/activity/repository/postgres"
+	"todo-app/internal/activity/usecase"
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/common/utils"
+	projectpolicy "todo-app/internal/project/policy"
+	projectpostgres "todo-app/internal/project/repository/postgres"
+)
+
+// NewActivityUseCase は PostgreSQL のリポジトリを使う ActivityUseCase を返します
+// 変更履歴のエンドポイントはタスクとプロジェクトのハンドラーで登録します
+func NewActivityUseCase(db *sql.DB) *usecase.ActivityUseCase {
+	return usecase.NewActivityUseCase(postgres.NewActivityRepoPg(db), projectpolicy.NewPolicy(projectpostgres.NewProjectRepoPg(db)))
+}
+
+// ParseQuery は entity_type / actor_id / cursor / limit のクエリパラメータを読み取ります
+func ParseQuery(r *http.Request) usecase.ActivityQuery {
+	q := r.URL.Query()
+	return usecase.ActivityQuery{EntityType: q.Get("entity_type"), ActorID: q.Get("actor_id"), Cursor: q.Get("cursor"), Limit: q.Get("limit")}
+}
+
+// WriteError は変更履歴のエラーを HTTP ステータスに変換して返します
+func WriteError(w http.ResponseWriter, err error) {
+	var verr *apperrors.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		utils.JSONResponse(w, http.StatusBadRequest, map[string]interface{}{"error": apperrors.ErrInvalidInput.Error(), "fields": verr.Fields})
+	case errors.Is(err, apperrors.ErrForbidden):
+		utils.JSONResponse(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, apperrors.ErrNotFound):
+		utils.JSONResponse(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	default:
+		utils.JSONResponse(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
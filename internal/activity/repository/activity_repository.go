@@ -0,0 +1,12 @@
+package repository
+
+import "todo-app/internal/activity/domain"
+
+// ActivityRepository は変更履歴を読み出します。変更履歴を更新・削除するメソッドは持ちません
+// 追記は変更と同じトランザクションで、変更を保存する各リポジトリが行います
+type ActivityRepository interface {
+    // ListByTask はタスクと、そのサブタスク・コメントの変更履歴を新しい順に返します
+    ListByTask(taskID string, q domain.Query) ([]*domain.Entry, error)
+    // ListByProject はプロジェクトと、そのタスク・サブタスク・コメントの変更履歴を新しい順に返します
+    ListByProject(projectID string, q domain.Query) ([]*domain.Entry, error)
+}
@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"todo-app/internal/activity/domain"
+	"todo-app/internal/activity/repository"
+)
+
+// activityRepoPg は ActivityRepository の PostgreSQL 実装
+// activity_log はトリガーで UPDATE / DELETE を拒否する追記専用のテーブルです
+type activityRepoPg struct{ db *sql.DB }
+
+// NewActivityRepoPg は PostgreSQL 実装（変更履歴用）を返す
+func NewActivityRepoPg(db *sql.DB) repository.ActivityRepository {
+	return &activityRepoPg{db: db}
+}
+
+// Append は変更履歴を tx の中で追記します。entry が nil の場合は何もしません
+// 変更と同じトランザクションで記録するため、各リポジトリの書き込みから呼び出します
+// ProjectID が空で TaskID がある場合はタスクのプロジェクトを記録します
+func Append(tx *sql.Tx, entry *domain.Entry) error {
+	if entry == nil {
+		return nil
+	}
+	changes, err := json.Marshal(entry.Changes)
+	if err != nil {
+		return err
+	}
+	return tx.QueryRow(`
+        INSERT INTO activity_log (id, entity_type, entity_id, task_id, project_id, action, actor_id, changes, created_at)
+        VALUES ($1, $2, $3, NULLIF($4, ''),
+                COALESCE(NULLIF($5, ''), (SELECT project_id FROM tasks WHERE id = NULLIF($4, ''))),
+                $6, NULLIF($7, ''), $8, $9)
+        RETURNING COALESCE(project_id, '')
+    `, entry.ID, entry.EntityType, entry.EntityID, entry.TaskID, entry.ProjectID, entry.Action, entry.ActorID, changes, entry.CreatedAt).Scan(&entry.ProjectID)
+}
+
+func (r *activityRepoPg) ListByTask(taskID string, q domain.Query) ([]*domain.Entry, error) {
+	return r.list(`task_id = $1`, taskID, q)
+}
+
+func (r *activityRepoPg) ListByProject(projectID string, q domain.Query) ([]*domain.Entry, error) {
+	return r.list(`project_id = $1`, projectID, q)
+}
+
+// list は cond に一致する変更履歴を新しい順（追記の逆順）に q.Limit 件まで返します
+func (r *activityRepoPg) list(cond, id string, q domain.Query) ([]*domain.Entry, error) {
+	var before int64
+	if q.Cursor != "" {
+		err := r.db.QueryRow(`SELECT seq FROM activity_log WHERE id = $1`, q.Cursor).Scan(&before)
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrInvalidCursor
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	rows, err := r.db.Query(fmt.Sprintf(`
+        SELECT id, entity_type, entity_id, COALESCE(task_id, ''), COALESCE(project_id, ''), action, COALESCE(actor_id, ''), changes, created_at
+        FROM activity_log
+        WHERE %s
+          AND ($2 = '' OR entity_type = $2)
+          AND ($3 = '' OR actor_id = $3)
+          AND ($4 = 0 OR seq < $4)
+        ORDER BY seq DESC
+        LIMIT $5
+    `, cond), id, q.EntityType, q.ActorID, before, q.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*domain.Entry
+	for rows.Next() {
+		e := &domain.Entry{}
+		var changes []byte
+		if err := rows.Scan(&e.ID, &e.EntityType, &e.EntityID, &e.TaskID, &e.ProjectID, &e.Action, &e.ActorID, &changes, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(changes, &e.Changes); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
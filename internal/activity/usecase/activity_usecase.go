@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"todo-app/internal/activity/domain"
+	"todo-app/internal/activity/repository"
+	apperrors "todo-app/internal/common/errors"
+	projectdomain "todo-app/internal/project/domain"
+)
+
+// ProjectPolicy はプロジェクトのロールに基づいて操作を許可するかを判定します
+type ProjectPolicy interface {
+	Authorize(projectID, userID string, perm projectdomain.Permission) error
+}
+
+// ActivityUseCase はタスク・サブタスク・プロジェクト・コメントの変更履歴を記録し、読み出します
+// 変更履歴は追記のみで、監査証跡として扱えるように更新・削除の手段を提供しません
+type ActivityUseCase struct {
+	repo   repository.ActivityRepository
+	policy ProjectPolicy
+	now    func() time.Time
+}
+
+func NewActivityUseCase(r repository.ActivityRepository, pp ProjectPolicy) *ActivityUseCase {
+	return &ActivityUseCase{repo: r, policy: pp, now: time.Now}
+}
+
+// Prepare は変更履歴に ID と日時を設定して返します。更新で変更がなかった場合は nil（記録しない）を返します
+// 返した変更履歴は、変更を保存するリポジトリが同じトランザクションで追記します
+func (uc *ActivityUseCase) Prepare(entry *domain.Entry) *domain.Entry {
+	if entry.Action == domain.ActionUpdated && len(entry.Changes) == 0 {
+		return nil
+	}
+	entry.ID = uuid.New().String()
+	entry.CreatedAt = uc.now().UTC()
+	return entry
+}
+
+// TaskHistory はタスクと、そのサブタスク・コメントの変更履歴を返します
+// タスクの閲覧権限は呼び出し元で確認してください
+func (uc *ActivityUseCase) TaskHistory(taskID string, aq ActivityQuery) (*ActivityPageDTO, error) {
+	q, err := parseQuery(aq)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := uc.repo.ListByTask(taskID, q)
+	return page(q, entries, err)
+}
+
+// ProjectActivity はプロジェクトと、そのタスク・サブタスク・コメントの変更履歴を返します。viewer 以上が閲覧できます
+func (uc *ActivityUseCase) ProjectActivity(projectID, actorID string, aq ActivityQuery) (*ActivityPageDTO, error) {
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
+	q, err := parseQuery(aq)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := uc.repo.ListByProject(projectID, q)
+	return page(q, entries, err)
+}
+
+// parseQuery はクエリパラメータを検証します。続きの有無を判定するため 1 件多く読み出します
+func parseQuery(aq ActivityQuery) (domain.Query, error) {
+	verr := apperrors.NewValidationError()
+	q := domain.Query{EntityType: aq.EntityType, ActorID: aq.ActorID, Cursor: aq.Cursor, Limit: domain.DefaultLimit}
+	if q.EntityType != "" && !domain.ValidEntityType(q.EntityType) {
+		verr.Add("entity_type", domain.ErrInvalidEntityType.Error())
+	}
+	if aq.Limit != "" {
+		limit, err := strconv.Atoi(aq.Limit)
+		if err != nil || limit < 1 || limit > domain.MaxLimit {
+			verr.Add("limit", domain.ErrInvalidLimit.Error())
+		}
+		q.Limit = limit
+	}
+	if verr.HasErrors() {
+		return q, verr
+	}
+	q.Limit++
+	return q, nil
+}
+
+// page は読み出した変更履歴を 1 ページ分に切り詰め、続きがあれば次のカーソルを設定します
+func page(q domain.Query, entries []*domain.Entry, err error) (*ActivityPageDTO, error) {
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		verr := apperrors.NewValidationError()
+		verr.Add("cursor", err.Error())
+		return nil, verr
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := &ActivityPageDTO{Entries: []*domain.Entry{}}
+	if limit := q.Limit - 1; len(entries) > limit {
+		entries = entries[:limit]
+		result.NextCursor = entries[limit-1].ID
+	}
+	if entries != nil {
+		result.Entries = entries
+	}
+	return result, nil
+}
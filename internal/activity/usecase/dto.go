@@ -0,0 +1,19 @@
+package usecase
+
+import "todo-app/internal/activity/domain"
+
+// ActivityQuery は変更履歴のエンドポイントのクエリパラメータです
+// Limit は文字列のまま受け取り、ユースケースで検証します（省略時は domain.DefaultLimit）
+type ActivityQuery struct {
+    EntityType string
+    ActorID    string
+    Cursor     string
+    Limit      string
+}
+
+// ActivityPageDTO は変更履歴の 1 ページ分です（新しい順）
+// NextCursor は続きがある場合に cursor に指定する値です
+type ActivityPageDTO struct {
+    Entries    []*domain.Entry `json:"entries"`
+    NextCursor string          `json:"next_cursor,omitempty"`
+}
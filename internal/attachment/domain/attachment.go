@@ -0,0 +1,101 @@
+package domain
+
+import (
+    "errors"
+    "fmt"
+    "path"
+    "strings"
+    "time"
+    "unicode"
+)
+
+// Attachment はタスクまたはコメントに添付されたファイルです
+// 本体は内容の SHA-256（Hash）をキーに BlobStore に保存し、同じ内容のファイルは1つの本体を共有します
+type Attachment struct {
+    ID     string `json:"id"`
+    TaskID string `json:"task_id"`
+    // CommentID はコメントへの添付の場合のみ設定されます（TaskID はコメントのタスク）
+    CommentID   string    `json:"comment_id,omitempty"`
+    Filename    string    `json:"filename"`
+    ContentType string    `json:"content_type"`
+    Size        int64     `json:"size"`
+    Hash        string    `json:"hash"`
+    UploadedBy  string    `json:"uploaded_by"`
+    CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewAttachment(id, taskID, commentID, filename, contentType string, size int64, hash, uploadedBy string) *Attachment {
+    return &Attachment{
+        ID:          id,
+        TaskID:      taskID,
+        CommentID:   commentID,
+        Filename:    filename,
+        ContentType: contentType,
+        Size:        size,
+        Hash:        hash,
+        UploadedBy:  uploadedBy,
+        CreatedAt:   time.Now(),
+    }
+}
+
+// BlobKey は内容のハッシュに対応する BlobStore のキーです
+func BlobKey(hash string) string {
+    return "sha256/" + hash[:2] + "/" + hash
+}
+
+// DefaultMaxSize は添付ファイルの既定の最大サイズ（10MB）です
+const DefaultMaxSize = 10 << 20
+
+// MaxFilenameLength はファイル名の最大文字数です
+const MaxFilenameLength = 255
+
+// DefaultAllowedTypes は既定で添付できる MIME タイプです（内容から判定した値で照合します）
+// Office 文書（docx, xlsx 等）は application/zip として判定されます
+var DefaultAllowedTypes = []string{
+    "image/png", "image/jpeg", "image/gif", "image/webp", "image/bmp",
+    "application/pdf", "application/zip", "application/x-gzip",
+    "text/plain", "text/csv",
+}
+
+var (
+    ErrFileRequired     = errors.New("file is required")
+    ErrEmptyFile        = errors.New("file is empty")
+    ErrFilenameRequired = errors.New("filename is required")
+)
+
+// FileTooLargeError はファイルが最大サイズを超えていることを表します
+type FileTooLargeError struct {
+    MaxSize int64
+}
+
+func (e *FileTooLargeError) Error() string {
+    return fmt.Sprintf("file exceeds the maximum size of %d bytes", e.MaxSize)
+}
+
+// TypeNotAllowedError は添付できない種類のファイルであることを表します
+type TypeNotAllowedError struct {
+    ContentType string
+}
+
+func (e *TypeNotAllowedError) Error() string {
+    return fmt.Sprintf("file type %s is not allowed", e.ContentType)
+}
+
+// SanitizeFilename はパスと制御文字を取り除いたファイル名を返します
+func SanitizeFilename(name string) (string, error) {
+    name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+    name = strings.Map(func(r rune) rune {
+        if unicode.IsControl(r) {
+            return -1
+        }
+        return r
+    }, name)
+    name = strings.TrimSpace(name)
+    if name == "" || name == "." || name == "/" {
+        return "", ErrFilenameRequired
+    }
+    if runes := []rune(name); len(runes) > MaxFilenameLength {
+        name = string(runes[len(runes)-MaxFilenameLength:])
+    }
+    return name, nil
+}
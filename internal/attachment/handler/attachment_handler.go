@@ -0,0 +1,255 @@
+package handler
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+
+	"todo-app/internal/attachment/domain"
+	"todo-app/internal/attachment/repository/postgres"
+	"todo-app/internal/attachment/usecase"
+	commentpostgres "todo-app/internal/comment/repository/postgres"
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/common/utils"
+	"todo-app/internal/infrastructure/blob"
+	taskdomain "todo-app/internal/task/domain"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// multipartOverhead はマルチパートのヘッダー等のため、ファイルの上限に加えて受け付けるバイト数です
+const multipartOverhead = 1 << 20
+
+var (
+	setupOnce sync.Once
+	store     blob.BlobStore
+	urlKey    []byte
+)
+
+// setup はプロセスで共有するストレージとダウンロード URL の署名鍵を初期化します
+// ATTACHMENT_URL_SECRET が未設定の場合は起動ごとに生成するため、再起動すると発行済みの URL は無効になります
+func setup() {
+	setupOnce.Do(func() {
+		var err error
+		if store, err = blob.NewFromEnv(); err != nil {
+			log.Fatalf("failed to initialize blob store: %v", err)
+		}
+		if secret := os.Getenv("ATTACHMENT_URL_SECRET"); secret != "" {
+			urlKey = []byte(secret)
+		} else {
+			urlKey = make([]byte, 32)
+			if _, err := rand.Read(urlKey); err != nil {
+				log.Fatalf("failed to generate attachment URL key: %v", err)
+			}
+		}
+	})
+}
+
+// NewAttachmentUseCase は PostgreSQL のリポジトリと BLOB_STORE のストレージを使う AttachmentUseCase を返します
+// tasks は添付先のタスクの権限で操作を認可します（通常は TaskUseCase）
+func NewAttachmentUseCase(db *sql.DB, tasks usecase.TaskAuthorizer) *usecase.AttachmentUseCase {
+	setup()
+	return usecase.NewAttachmentUseCase(postgres.NewAttachmentRepoPg(db), commentpostgres.NewCommentRepoPg(db), tasks,
+		store, usecase.LimitsFromEnv(), urlKey, usecase.URLTTLFromEnv())
+}
+
+// NewContentCopier は PostgreSQL のリポジトリを使う ContentCopier を返します
+func NewContentCopier(db *sql.DB) *usecase.ContentCopier {
+	return usecase.NewContentCopier(postgres.NewAttachmentRepoPg(db), commentpostgres.NewCommentRepoPg(db))
+}
+
+// RegisterAttachmentRoutes は添付ファイル単体のエンドポイントを登録します
+// タスク・コメントへのアップロードと一覧はそれぞれのハンドラーで登録します
+func RegisterAttachmentRoutes(r chi.Router, db *sql.DB, tasks usecase.TaskAuthorizer) {
+	uc := NewAttachmentUseCase(db, tasks)
+
+	r.Route("/attachments/{attachmentID}", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			attachment, err := uc.Get(chi.URLParam(r, "attachmentID"), userID)
+			if err != nil {
+				WriteError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusOK, attachment)
+		})
+
+		r.Get("/download", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			attachmentID := chi.URLParam(r, "attachmentID")
+			if _, err := uc.Get(attachmentID, userID); err != nil {
+				WriteError(w, err)
+				return
+			}
+			serveContent(w, r, uc, attachmentID)
+		})
+
+		r.Get("/url", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			dto, err := uc.DownloadURL(chi.URLParam(r, "attachmentID"), userID)
+			if err != nil {
+				log.Printf("Failed to issue download URL: %v", err)
+				WriteError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusOK, dto)
+		})
+
+		r.Delete("/", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			attachmentID := chi.URLParam(r, "attachmentID")
+			if err := uc.Delete(r.Context(), attachmentID, userID); err != nil {
+				log.Printf("Failed to delete attachment %s: %v", attachmentID, err)
+				WriteError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	})
+}
+
+// RegisterFileRoutes は GET /attachments/{attachmentID}/url で発行した URL のダウンロードを登録します
+// URL の署名で認可するため、JWT 認証の外に登録します
+func RegisterFileRoutes(r chi.Router, db *sql.DB, tasks usecase.TaskAuthorizer) {
+	uc := NewAttachmentUseCase(db, tasks)
+
+	r.Get("/files/{attachmentID}", func(w http.ResponseWriter, r *http.Request) {
+		attachmentID := chi.URLParam(r, "attachmentID")
+		q := r.URL.Query()
+		if err := uc.VerifyDownloadURL(attachmentID, q.Get("expires"), q.Get("signature")); err != nil {
+			utils.JSONResponse(w, http.StatusForbidden, map[string]string{"error": "invalid or expired download URL"})
+			return
+		}
+		serveContent(w, r, uc, attachmentID)
+	})
+}
+
+// HandleUpload はマルチパートの file フィールドのファイルをタスク（commentID が空の場合）またはコメントに添付します
+func HandleUpload(w http.ResponseWriter, r *http.Request, uc *usecase.AttachmentUseCase, taskID, commentID string) {
+	userID, ok := r.Context().Value("userID").(string)
+	if !ok {
+		utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, uc.MaxSize()+multipartOverhead)
+	part, err := filePart(r)
+	if err != nil {
+		log.Printf("Invalid attachment upload: %v", err)
+		WriteError(w, err)
+		return
+	}
+	defer part.Close()
+
+	attachment, err := uc.Upload(r.Context(), &usecase.UploadRequest{
+		TaskID:     taskID,
+		CommentID:  commentID,
+		Filename:   part.FileName(),
+		Body:       part,
+		UploadedBy: userID,
+	})
+	if err != nil {
+		log.Printf("Failed to upload attachment: %v", err)
+		WriteError(w, err)
+		return
+	}
+	log.Printf("Attachment uploaded successfully: %s (%d bytes)", attachment.ID, attachment.Size)
+	utils.JSONResponse(w, http.StatusCreated, attachment)
+}
+
+// filePart はマルチパートの本文から file フィールドを探します
+func filePart(r *http.Request) (*multipart.Part, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, fieldError(domain.ErrFileRequired.Error())
+	}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, fieldError(domain.ErrFileRequired.Error())
+		}
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return nil, &domain.FileTooLargeError{MaxSize: maxErr.Limit - multipartOverhead}
+		}
+		if err != nil {
+			return nil, fieldError(err.Error())
+		}
+		if part.FormName() == "file" {
+			return part, nil
+		}
+		part.Close()
+	}
+}
+
+// serveContent は添付ファイルの内容を元のファイル名でダウンロードさせます
+func serveContent(w http.ResponseWriter, r *http.Request, uc *usecase.AttachmentUseCase, attachmentID string) {
+	attachment, body, err := uc.Open(r.Context(), attachmentID)
+	if err != nil {
+		log.Printf("Failed to open attachment %s: %v", attachmentID, err)
+		WriteError(w, err)
+		return
+	}
+	defer body.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", blob.ContentDisposition(attachment.Filename))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, body); err != nil {
+		log.Printf("Failed to send attachment %s: %v", attachmentID, err)
+	}
+}
+
+// WriteError は添付ファイルのエラーをステータスコードに変換して返します
+func WriteError(w http.ResponseWriter, err error) {
+	var verr *apperrors.ValidationError
+	var serr *domain.FileTooLargeError
+	var terr *domain.TypeNotAllowedError
+	switch {
+	case errors.As(err, &serr):
+		utils.JSONResponse(w, http.StatusRequestEntityTooLarge, map[string]interface{}{"error": serr.Error(), "max_size": serr.MaxSize})
+	case errors.As(err, &terr):
+		utils.JSONResponse(w, http.StatusUnsupportedMediaType, map[string]interface{}{"error": terr.Error(), "content_type": terr.ContentType})
+	case errors.As(err, &verr):
+		utils.JSONResponse(w, http.StatusBadRequest, map[string]interface{}{"error": apperrors.ErrInvalidInput.Error(), "fields": verr.Fields})
+	case errors.Is(err, apperrors.ErrForbidden):
+		utils.JSONResponse(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, taskdomain.ErrProjectArchived):
+		utils.JSONResponse(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, apperrors.ErrNotFound):
+		utils.JSONResponse(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	default:
+		utils.JSONResponse(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+func fieldError(msg string) error {
+	verr := apperrors.NewValidationError()
+	verr.Add("file", msg)
+	return verr
+}
@@ -0,0 +1,24 @@
+package repository
+
+import (
+    "time"
+
+    "todo-app/internal/attachment/domain"
+)
+
+// AttachmentRepository は添付ファイルと、内容のハッシュごとの本体（blobs）の情報を管理します
+type AttachmentRepository interface {
+    // CreateBlob は本体の情報を登録します。同じハッシュが登録済みの場合は何もせず false を返します
+    CreateBlob(hash string, size int64, contentType string) (bool, error)
+    Create(attachment *domain.Attachment) error
+    FindByID(id string) (*domain.Attachment, error)
+    // ListByTask はタスクとそのコメントの添付ファイルを古い順に返します
+    ListByTask(taskID string) ([]*domain.Attachment, error)
+    ListByComment(commentID string) ([]*domain.Attachment, error)
+    // Delete は添付ファイルを削除し、その本体のハッシュを返します
+    Delete(id string) (string, error)
+    // DeleteOrphanBlobs は hashes のうち、どの添付ファイルからも参照されなくなった本体の情報を削除し、そのハッシュを返します
+    DeleteOrphanBlobs(hashes []string) ([]string, error)
+    // DeleteUnreferencedBlobs は before より前に登録され、どの添付ファイルからも参照されていない本体の情報を削除し、そのハッシュを返します
+    DeleteUnreferencedBlobs(before time.Time) ([]string, error)
+}
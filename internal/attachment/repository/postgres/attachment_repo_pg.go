@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"todo-app/internal/attachment/domain"
+	"todo-app/internal/attachment/repository"
+
+	"github.com/lib/pq"
+)
+
+// attachmentRepoPg は AttachmentRepository の PostgreSQL 実装
+type attachmentRepoPg struct{ db *sql.DB }
+
+// NewAttachmentRepoPg は PostgreSQL 実装（添付ファイル用）を返す
+func NewAttachmentRepoPg(db *sql.DB) repository.AttachmentRepository {
+	return &attachmentRepoPg{db: db}
+}
+
+const attachmentColumns = `id, task_id, COALESCE(comment_id, ''), filename, content_type, size, blob_hash, COALESCE(uploaded_by, ''), created_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanAttachment(row rowScanner) (*domain.Attachment, error) {
+	a := &domain.Attachment{}
+	err := row.Scan(&a.ID, &a.TaskID, &a.CommentID, &a.Filename, &a.ContentType, &a.Size, &a.Hash, &a.UploadedBy, &a.CreatedAt)
+	return a, err
+}
+
+func (r *attachmentRepoPg) CreateBlob(hash string, size int64, contentType string) (bool, error) {
+	result, err := r.db.Exec(`INSERT INTO blobs (hash, size, content_type) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`, hash, size, contentType)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+func (r *attachmentRepoPg) Create(a *domain.Attachment) error {
+	query := `
+        INSERT INTO attachments (id, task_id, comment_id, filename, content_type, size, blob_hash, uploaded_by, created_at)
+        VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, $7, NULLIF($8, ''), $9)
+    `
+	_, err := r.db.Exec(query, a.ID, a.TaskID, a.CommentID, a.Filename, a.ContentType, a.Size, a.Hash, a.UploadedBy, a.CreatedAt)
+	return err
+}
+
+func (r *attachmentRepoPg) FindByID(id string) (*domain.Attachment, error) {
+	a, err := scanAttachment(r.db.QueryRow(`SELECT `+attachmentColumns+` FROM attachments WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("attachment not found")
+		}
+		return nil, err
+	}
+	return a, nil
+}
+
+func (r *attachmentRepoPg) ListByTask(taskID string) ([]*domain.Attachment, error) {
+	return r.list(`SELECT `+attachmentColumns+` FROM attachments WHERE task_id = $1 ORDER BY created_at, id`, taskID)
+}
+
+func (r *attachmentRepoPg) ListByComment(commentID string) ([]*domain.Attachment, error) {
+	return r.list(`SELECT `+attachmentColumns+` FROM attachments WHERE comment_id = $1 ORDER BY created_at, id`, commentID)
+}
+
+func (r *attachmentRepoPg) list(query string, args ...interface{}) ([]*domain.Attachment, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []*domain.Attachment{}
+	for rows.Next() {
+		a, err := scanAttachment(rows)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, a)
+	}
+	return attachments, rows.Err()
+}
+
+func (r *attachmentRepoPg) Delete(id string) (string, error) {
+	var hash string
+	err := r.db.QueryRow(`DELETE FROM attachments WHERE id = $1 RETURNING blob_hash`, id).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("attachment not found")
+	}
+	return hash, err
+}
+
+// DeleteUnreferencedBlobs はタスクやプロジェクトの物理削除（CASCADE）で参照が無くなった本体を削除します
+// 登録直後の本体はアップロード中の添付ファイルから参照される前のため、before で除外します
+func (r *attachmentRepoPg) DeleteUnreferencedBlobs(before time.Time) ([]string, error) {
+	query := `
+        DELETE FROM blobs b
+        WHERE b.created_at < $1 AND NOT EXISTS (SELECT 1 FROM attachments a WHERE a.blob_hash = b.hash)
+        RETURNING b.hash
+    `
+	return r.hashes(query, before)
+}
+
+// DeleteOrphanBlobs は参照が残っている本体を削除しません（attachments.blob_hash の外部キーでも保護されます）
+func (r *attachmentRepoPg) DeleteOrphanBlobs(hashes []string) ([]string, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+	query := `
+        DELETE FROM blobs b
+        WHERE b.hash = ANY($1) AND NOT EXISTS (SELECT 1 FROM attachments a WHERE a.blob_hash = b.hash)
+        RETURNING b.hash
+    `
+	return r.hashes(query, pq.Array(hashes))
+}
+
+func (r *attachmentRepoPg) hashes(query string, args ...interface{}) ([]string, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, rows.Err()
+}
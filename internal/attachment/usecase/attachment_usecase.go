@@ -0,0 +1,401 @@
+package usecase
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"todo-app/internal/attachment/domain"
+	"todo-app/internal/attachment/repository"
+	commentrepo "todo-app/internal/comment/repository"
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/common/utils"
+	"todo-app/internal/infrastructure/blob"
+	projectdomain "todo-app/internal/project/domain"
+
+	"github.com/google/uuid"
+)
+
+// DefaultURLTTL はダウンロード URL の既定の有効期間です
+const DefaultURLTTL = 15 * time.Minute
+
+// Limits は添付できるファイルのサイズと種類の制限です
+type Limits struct {
+	MaxSize      int64
+	AllowedTypes []string
+}
+
+// LimitsFromEnv は ATTACHMENT_MAX_SIZE（バイト）と ATTACHMENT_ALLOWED_TYPES（カンマ区切り）から制限を読み込みます
+func LimitsFromEnv() Limits {
+	limits := Limits{MaxSize: domain.DefaultMaxSize, AllowedTypes: domain.DefaultAllowedTypes}
+	if v := os.Getenv("ATTACHMENT_MAX_SIZE"); v != "" {
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil && n > 0 {
+			limits.MaxSize = n
+		} else {
+			log.Printf("Invalid ATTACHMENT_MAX_SIZE %q, using default %d", v, limits.MaxSize)
+		}
+	}
+	if v := os.Getenv("ATTACHMENT_ALLOWED_TYPES"); v != "" {
+		limits.AllowedTypes = nil
+		for _, t := range strings.Split(v, ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				limits.AllowedTypes = append(limits.AllowedTypes, strings.ToLower(t))
+			}
+		}
+	}
+	return limits
+}
+
+// URLTTLFromEnv は ATTACHMENT_URL_TTL（例: 15m）からダウンロード URL の有効期間を読み込みます
+func URLTTLFromEnv() time.Duration {
+	return utils.DurationFromEnv("ATTACHMENT_URL_TTL", DefaultURLTTL)
+}
+
+type AttachmentUseCase struct {
+	repo        repository.AttachmentRepository
+	commentRepo commentrepo.CommentRepository
+	tasks       TaskAuthorizer
+	store       blob.BlobStore
+	limits      Limits
+	// urlKey はダウンロード URL の署名鍵、urlTTL はその有効期間です
+	urlKey []byte
+	urlTTL time.Duration
+}
+
+// TaskAuthorizer はタスクのプロジェクトのロールに基づいて操作を許可するかを判定します
+// 添付ファイルの閲覧はタスクの閲覧、アップロードはタスクの編集（コメントへの添付はコメント）の権限で認可します
+type TaskAuthorizer interface {
+	AuthorizeTask(taskID, userID string, perm projectdomain.Permission) error
+}
+
+func NewAttachmentUseCase(r repository.AttachmentRepository, cr commentrepo.CommentRepository, ta TaskAuthorizer, store blob.BlobStore, limits Limits, urlKey []byte, urlTTL time.Duration) *AttachmentUseCase {
+	return &AttachmentUseCase{repo: r, commentRepo: cr, tasks: ta, store: store, limits: limits, urlKey: urlKey, urlTTL: urlTTL}
+}
+
+// MaxSize は添付できるファイルの最大サイズ（バイト）です
+func (uc *AttachmentUseCase) MaxSize() int64 {
+	return uc.limits.MaxSize
+}
+
+// Upload はファイルを保存してタスクまたはコメントに添付します
+// 種類は内容から判定し、同じ内容のファイルが保存済みの場合は本体を共有します
+// タスクへの添付はタスクを編集できるユーザー、コメントへの添付はコメントできるユーザーが行えます
+func (uc *AttachmentUseCase) Upload(ctx context.Context, req *UploadRequest) (*domain.Attachment, error) {
+	taskID, perm := req.TaskID, projectdomain.PermEditTasks
+	if req.CommentID != "" {
+		comment, err := uc.commentRepo.FindByID(req.CommentID)
+		if err != nil {
+			return nil, apperrors.ErrNotFound
+		}
+		taskID, perm = comment.TaskID, projectdomain.PermComment
+	}
+	if err := uc.tasks.AuthorizeTask(taskID, req.UploadedBy, perm); err != nil {
+		return nil, err
+	}
+	filename, err := domain.SanitizeFilename(req.Filename)
+	if err != nil {
+		return nil, fieldError("file", err.Error())
+	}
+
+	// 内容のハッシュとサイズを求めるため、一時ファイルに書き出す
+	tmp, err := os.CreateTemp("", "attachment-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), io.LimitReader(req.Body, uc.limits.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if size > uc.limits.MaxSize {
+		return nil, &domain.FileTooLargeError{MaxSize: uc.limits.MaxSize}
+	}
+	if size == 0 {
+		return nil, fieldError("file", domain.ErrEmptyFile.Error())
+	}
+	contentType, err := detectContentType(tmp, filename)
+	if err != nil {
+		return nil, err
+	}
+	if !uc.allowed(contentType) {
+		return nil, &domain.TypeNotAllowedError{ContentType: contentType}
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	key := domain.BlobKey(sum)
+	if _, err := uc.repo.CreateBlob(sum, size, contentType); err != nil {
+		return nil, err
+	}
+	exists, err := uc.store.Exists(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if err := uc.store.Put(ctx, key, tmp, size, contentType); err != nil {
+			uc.removeBlobs(ctx, []string{sum})
+			return nil, err
+		}
+	}
+
+	attachment := domain.NewAttachment(uuid.New().String(), taskID, req.CommentID, filename, contentType, size, sum, req.UploadedBy)
+	if err := uc.repo.Create(attachment); err != nil {
+		uc.removeBlobs(ctx, []string{sum})
+		return nil, err
+	}
+	return attachment, nil
+}
+
+// ListByTask はタスクとそのコメントの添付ファイルを返します。タスクを閲覧できるユーザーのみ取得できます
+func (uc *AttachmentUseCase) ListByTask(taskID, userID string) ([]*domain.Attachment, error) {
+	if err := uc.tasks.AuthorizeTask(taskID, userID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
+	return uc.repo.ListByTask(taskID)
+}
+
+// ListByComment はコメントの添付ファイルを返します。コメントのタスクを閲覧できるユーザーのみ取得できます
+func (uc *AttachmentUseCase) ListByComment(commentID, userID string) ([]*domain.Attachment, error) {
+	comment, err := uc.commentRepo.FindByID(commentID)
+	if err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	if err := uc.tasks.AuthorizeTask(comment.TaskID, userID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
+	return uc.repo.ListByComment(commentID)
+}
+
+// Get は添付ファイルの情報を返します。添付先のタスクを閲覧できるユーザーのみ取得できます
+func (uc *AttachmentUseCase) Get(id, userID string) (*domain.Attachment, error) {
+	attachment, err := uc.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.tasks.AuthorizeTask(attachment.TaskID, userID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (uc *AttachmentUseCase) find(id string) (*domain.Attachment, error) {
+	attachment, err := uc.repo.FindByID(id)
+	if err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	return attachment, nil
+}
+
+// Open は添付ファイルの情報と内容を返します。内容は呼び出し元が閉じます
+// 認可は行わないため、呼び出し元で Get または VerifyDownloadURL により認可してから呼び出します
+func (uc *AttachmentUseCase) Open(ctx context.Context, id string) (*domain.Attachment, io.ReadCloser, error) {
+	attachment, err := uc.find(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := uc.store.Get(ctx, domain.BlobKey(attachment.Hash))
+	if errors.Is(err, blob.ErrNotFound) {
+		return nil, nil, apperrors.ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return attachment, body, nil
+}
+
+// DownloadURL は認証なしでダウンロードできる期限付きの URL を返します。添付先のタスクを閲覧できるユーザーのみ発行できます
+// ストレージが署名付き URL に対応している場合（S3）はその URL、それ以外は GET /files/{id} の署名付き URL です
+func (uc *AttachmentUseCase) DownloadURL(id, userID string) (*DownloadURLDTO, error) {
+	attachment, err := uc.Get(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(uc.urlTTL).Truncate(time.Second)
+	if presigner, ok := uc.store.(blob.Presigner); ok {
+		u, err := presigner.PresignGet(domain.BlobKey(attachment.Hash), attachment.Filename, uc.urlTTL)
+		if err != nil {
+			return nil, err
+		}
+		return &DownloadURLDTO{URL: u, ExpiresAt: expiresAt}, nil
+	}
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	u := fmt.Sprintf("/files/%s?expires=%s&signature=%s", attachment.ID, expires, uc.sign(attachment.ID, expires))
+	return &DownloadURLDTO{URL: u, ExpiresAt: expiresAt}, nil
+}
+
+// VerifyDownloadURL は GET /files/{id} の署名と有効期限を検証します
+func (uc *AttachmentUseCase) VerifyDownloadURL(id, expires, signature string) error {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return apperrors.ErrForbidden
+	}
+	if !hmac.Equal([]byte(signature), []byte(uc.sign(id, expires))) {
+		return apperrors.ErrForbidden
+	}
+	return nil
+}
+
+func (uc *AttachmentUseCase) sign(id, expires string) string {
+	mac := hmac.New(sha256.New, uc.urlKey)
+	mac.Write([]byte(id + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Delete は添付ファイルを削除します。アップロードしたユーザーが、タスクにコメントできる間のみ削除できます
+// 他の添付ファイルと共有していない本体はストレージからも削除します
+func (uc *AttachmentUseCase) Delete(ctx context.Context, id, userID string) error {
+	attachment, err := uc.Get(id, userID)
+	if err != nil {
+		return err
+	}
+	if attachment.UploadedBy != userID {
+		return apperrors.ErrForbidden
+	}
+	if err := uc.tasks.AuthorizeTask(attachment.TaskID, userID, projectdomain.PermComment); err != nil {
+		return err
+	}
+	hash, err := uc.repo.Delete(id)
+	if err != nil {
+		return apperrors.ErrNotFound
+	}
+	uc.removeBlobs(ctx, []string{hash})
+	return nil
+}
+
+// blobGracePeriod は登録した本体を、参照が無くても削除しない期間です（アップロード中の添付ファイルのため）
+const blobGracePeriod = time.Hour
+
+// PurgeUnreferencedBlobs はどの添付ファイルからも参照されなくなった本体をストレージから削除し、件数を返します
+// タスクとプロジェクトの物理削除では添付ファイルが CASCADE で削除されるため、TrashUseCase の物理削除の後に呼ばれます
+func (uc *AttachmentUseCase) PurgeUnreferencedBlobs(ctx context.Context, now time.Time) (int, error) {
+	hashes, err := uc.repo.DeleteUnreferencedBlobs(now.Add(-blobGracePeriod))
+	if err != nil {
+		return 0, err
+	}
+	for _, hash := range hashes {
+		if err := uc.store.Delete(ctx, domain.BlobKey(hash)); err != nil {
+			log.Printf("Failed to delete blob %s: %v", hash, err)
+		}
+	}
+	return len(hashes), nil
+}
+
+// ContentCopier はタスクの複製時にコメントと添付ファイルを複製します。TaskUseCase.CloneTask から使われます
+// 認可は呼び出し元の TaskUseCase で行うため、AttachmentUseCase と異なりタスクの権限を参照しません
+type ContentCopier struct {
+	repo        repository.AttachmentRepository
+	commentRepo commentrepo.CommentRepository
+}
+
+func NewContentCopier(r repository.AttachmentRepository, cr commentrepo.CommentRepository) *ContentCopier {
+	return &ContentCopier{repo: r, commentRepo: cr}
+}
+
+// CopyTaskContent はタスクのコメントと添付ファイルを別のタスクに複製します
+// コメントは投稿者と投稿日時を保ちます。添付ファイルは本体を共有するため、ストレージには書き込みません
+// コメントの添付ファイルは comments と attachments の両方を指定した場合のみ、複製したコメントに付け替えて複製します
+func (uc *ContentCopier) CopyTaskContent(fromTaskID, toTaskID string, comments, attachments bool) error {
+	commentIDs := map[string]string{}
+	if comments {
+		list, err := uc.commentRepo.ListByTask(fromTaskID)
+		if err != nil {
+			return err
+		}
+		for _, c := range list {
+			copied := *c
+			copied.ID = uuid.New().String()
+			copied.TaskID = toTaskID
+			// 複製したコメントは元の投稿として扱い、変更履歴には記録しない
+			if err := uc.commentRepo.Create(&copied, nil); err != nil {
+				return err
+			}
+			commentIDs[c.ID] = copied.ID
+		}
+	}
+	if !attachments {
+		return nil
+	}
+
+	list, err := uc.repo.ListByTask(fromTaskID)
+	if err != nil {
+		return err
+	}
+	for _, a := range list {
+		commentID := ""
+		if a.CommentID != "" {
+			var ok bool
+			if commentID, ok = commentIDs[a.CommentID]; !ok {
+				continue
+			}
+		}
+		copied := domain.NewAttachment(uuid.New().String(), toTaskID, commentID, a.Filename, a.ContentType, a.Size, a.Hash, a.UploadedBy)
+		if err := uc.repo.Create(copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeBlobs は参照されなくなった本体を削除します
+// ストレージからの削除に失敗しても、参照は既に削除されているためエラーにしません
+func (uc *AttachmentUseCase) removeBlobs(ctx context.Context, hashes []string) {
+	orphans, err := uc.repo.DeleteOrphanBlobs(hashes)
+	if err != nil {
+		log.Printf("Failed to delete orphan blobs: %v", err)
+		return
+	}
+	for _, hash := range orphans {
+		if err := uc.store.Delete(ctx, domain.BlobKey(hash)); err != nil {
+			log.Printf("Failed to delete blob %s: %v", hash, err)
+		}
+	}
+}
+
+func (uc *AttachmentUseCase) allowed(contentType string) bool {
+	for _, t := range uc.limits.AllowedTypes {
+		if t == contentType {
+			return true
+		}
+	}
+	return false
+}
+
+// detectContentType はファイルの先頭から MIME タイプを判定します
+// テキストの場合のみ、拡張子から判定できるもの（text/csv 等）を優先します
+func detectContentType(f *os.File, filename string) (string, error) {
+	head := make([]byte, 512)
+	n, err := f.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	detected, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if detected == "text/plain" {
+		if byExt, _, err := mime.ParseMediaType(mime.TypeByExtension(strings.ToLower(filepath.Ext(filename)))); err == nil && strings.HasPrefix(byExt, "text/") {
+			return byExt, nil
+		}
+	}
+	return detected, nil
+}
+
+func fieldError(field, msg string) error {
+	verr := apperrors.NewValidationError()
+	verr.Add(field, msg)
+	return verr
+}
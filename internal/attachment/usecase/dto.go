@@ -0,0 +1,22 @@
+package usecase
+
+import (
+    "io"
+    "time"
+)
+
+// UploadRequest はアップロードするファイルと添付先です
+// CommentID を指定した場合はコメントに添付します（TaskID はコメントのタスクになります）
+type UploadRequest struct {
+    TaskID     string
+    CommentID  string
+    Filename   string
+    Body       io.Reader
+    UploadedBy string
+}
+
+// DownloadURLDTO は GET /attachments/{attachmentID}/url のレスポンスです
+type DownloadURLDTO struct {
+    URL       string    `json:"url"`
+    ExpiresAt time.Time `json:"expires_at"`
+}
@@ -2,18 +2,26 @@ package handler
 
 import (
     "database/sql"
+    "errors"
     "net/http"
 
     "github.com/go-chi/chi/v5"
+    activityhandler "todo-app/internal/activity/handler"
+    attachmenthandler "todo-app/internal/attachment/handler"
+    apperrors "todo-app/internal/common/errors"
     "todo-app/internal/common/utils"
     "todo-app/internal/comment/repository/postgres"
     "todo-app/internal/comment/usecase"
+    taskdomain "todo-app/internal/task/domain"
+    taskhandler "todo-app/internal/task/handler"
 )
 
 // RegisterCommentRoutes はコメント関連のエンドポイントを chi.Router に紐づける
 // (router.go を使わず、ここ一か所で定義します)
 func RegisterCommentRoutes(r chi.Router, db *sql.DB) {
-    uc := usecase.NewCommentUseCase(postgres.NewCommentRepoPg(db))
+    taskUC := taskhandler.NewTaskUseCase(db)
+    uc := usecase.NewCommentUseCase(postgres.NewCommentRepoPg(db), taskUC, activityhandler.NewActivityUseCase(db))
+    attachmentUC := attachmenthandler.NewAttachmentUseCase(db, taskUC)
 
     r.Route("/comments", func(r chi.Router) {
         r.Post("/", func(w http.ResponseWriter, r *http.Request) {
@@ -22,12 +30,46 @@ func RegisterCommentRoutes(r chi.Router, db *sql.DB) {
                 utils.JSONResponse(w, http.StatusBadRequest, err.Error())
                 return
             }
-            id, err := uc.AddComment(&dto)
+            userID, ok := r.Context().Value("userID").(string)
+            if !ok {
+                utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+                return
+            }
+            // 投稿者は常に認証したユーザー
+            dto.AuthorID = userID
+            id, err := uc.AddComment(&dto, userID)
             if err != nil {
-                utils.JSONResponse(w, http.StatusInternalServerError, err.Error())
+                switch {
+                case errors.Is(err, apperrors.ErrForbidden):
+                    utils.JSONResponse(w, http.StatusForbidden, err.Error())
+                case errors.Is(err, apperrors.ErrNotFound):
+                    utils.JSONResponse(w, http.StatusNotFound, "task not found")
+                case errors.Is(err, taskdomain.ErrProjectArchived):
+                    utils.JSONResponse(w, http.StatusConflict, err.Error())
+                default:
+                    utils.JSONResponse(w, http.StatusInternalServerError, err.Error())
+                }
                 return
             }
             utils.JSONResponse(w, http.StatusCreated, map[string]string{"id": id})
         })
+
+        r.Get("/{commentID}/attachments", func(w http.ResponseWriter, r *http.Request) {
+            userID, ok := r.Context().Value("userID").(string)
+            if !ok {
+                utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+                return
+            }
+            attachments, err := attachmentUC.ListByComment(chi.URLParam(r, "commentID"), userID)
+            if err != nil {
+                attachmenthandler.WriteError(w, err)
+                return
+            }
+            utils.JSONResponse(w, http.StatusOK, attachments)
+        })
+
+        r.Post("/{commentID}/attachments", func(w http.ResponseWriter, r *http.Request) {
+            attachmenthandler.HandleUpload(w, r, attachmentUC, "", chi.URLParam(r, "commentID"))
+        })
     })
 }
@@ -1,9 +1,13 @@
 package repository
 
-import "todo-app/internal/comment/domain"
+import (
+    activitydomain "todo-app/internal/activity/domain"
+    "todo-app/internal/comment/domain"
+)
 
 type CommentRepository interface {
-    Create(comment *domain.Comment) error
+    // Create はコメントを登録し、activity（nil の場合は記録しない）を同じトランザクションで変更履歴に追記します
+    Create(comment *domain.Comment, activity *activitydomain.Entry) error
     FindByID(id string) (*domain.Comment, error)
     Delete(id string) error
     ListByTask(taskID string) ([]*domain.Comment, error)
@@ -1,38 +1,92 @@
-// File: internal/comment/repository/postgres/comment_repo_pg.go
-package postgres
-
-import (
-    "database/sql"
-    "todo-app/internal/comment/domain"
-    "todo-app/internal/comment/repository"
-)
-
-// commentRepoPg は CommentRepository の PostgreSQL 実装（スタブ）
-type commentRepoPg struct {
-    db *sql.DB
-}
-
-// NewCommentRepoPg は postgres 用の CommentRepository を返す
-func NewCommentRepoPg(db *sql.DB) repository.CommentRepository {
-    return &commentRepoPg{db: db}
-}
-
-func (r *commentRepoPg) Create(c *domain.Comment) error {
-    // TODO: 実際の SQL INSERT を書く
-    return nil
-}
-
-func (r *commentRepoPg) FindByID(id string) (*domain.Comment, error) {
-    // TODO: 実際の SQL SELECT を書く
-    return &domain.Comment{}, nil
-}
-
-func (r *commentRepoPg) Delete(id string) error {
-    // TODO: 実際の SQL DELETE を書く
-    return nil
-}
-
-func (r *commentRepoPg) ListByTask(taskID string) ([]*domain.Comment, error) {
-    // TODO: 実際の SQL SELECT を書く
-    return []*domain.Comment{}, nil
-}
+// File: internal/comment/repository/postgres/comment_repo_pg.go
+package postgres
+
+import (
+    "database/sql"
+    activitydomain "todo-app/internal/activity/domain"
+    activitypostgres "todo-app/internal/activity/repository/postgres"
+    "todo-app/internal/comment/domain"
+    "todo-app/internal/comment/repository"
+)
+
+// commentRepoPg は CommentRepository の PostgreSQL 実装（スタブ）
+type commentRepoPg struct {
+    db *sql.DB
+}
+
+// NewCommentRepoPg は postgres 用の CommentRepository を返す
+func NewCommentRepoPg(db *sql.DB) repository.CommentRepository {
+    return &commentRepoPg{db: db}
+}
+
+func (r *commentRepoPg) Create(c *domain.Comment, activity *activitydomain.Entry) error {
+    tx, err := r.db.Begin()
+    if err != nil {
+        return err
+    }
+    defer tx.Rollback()
+
+    query := `
+        INSERT INTO comments (id, content, task_id, user_id, created_at, updated_at)
+        VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6)
+    `
+    if _, err := tx.Exec(query, c.ID, c.Content, c.TaskID, c.AuthorID, c.CreatedAt, c.UpdatedAt); err != nil {
+        return err
+    }
+    if err := activitypostgres.Append(tx, activity); err != nil {
+        return err
+    }
+    return tx.Commit()
+}
+
+func (r *commentRepoPg) FindByID(id string) (*domain.Comment, error) {
+    query := `
+        SELECT id, content, task_id, COALESCE(user_id, ''), created_at, updated_at
+        FROM comments
+        WHERE id = $1
+    `
+    c := &domain.Comment{}
+    if err := r.db.QueryRow(query, id).Scan(&c.ID, &c.Content, &c.TaskID, &c.AuthorID, &c.CreatedAt, &c.UpdatedAt); err != nil {
+        return nil, err
+    }
+    return c, nil
+}
+
+func (r *commentRepoPg) Delete(id string) error {
+    result, err := r.db.Exec(`DELETE FROM comments WHERE id = $1`, id)
+    if err != nil {
+        return err
+    }
+    rowsAffected, err := result.RowsAffected()
+    if err != nil {
+        return err
+    }
+    if rowsAffected == 0 {
+        return sql.ErrNoRows
+    }
+    return nil
+}
+
+func (r *commentRepoPg) ListByTask(taskID string) ([]*domain.Comment, error) {
+    query := `
+        SELECT id, content, task_id, COALESCE(user_id, ''), created_at, updated_at
+        FROM comments
+        WHERE task_id = $1
+        ORDER BY created_at
+    `
+    rows, err := r.db.Query(query, taskID)
+    if err != nil {
+        return nil, err
+    }
+    defer rows.Close()
+
+    comments := []*domain.Comment{}
+    for rows.Next() {
+        c := &domain.Comment{}
+        if err := rows.Scan(&c.ID, &c.Content, &c.TaskID, &c.AuthorID, &c.CreatedAt, &c.UpdatedAt); err != nil {
+            return nil, err
+        }
+        comments = append(comments, c)
+    }
+    return comments, rows.Err()
+}
@@ -1,21 +1,49 @@
 package usecase
 
 import (
+    activitydomain "todo-app/internal/activity/domain"
     "todo-app/internal/comment/domain"
     "todo-app/internal/comment/repository"
+    projectdomain "todo-app/internal/project/domain"
+
+    "github.com/google/uuid"
 )
 
 type CommentUseCase struct {
-    repo repository.CommentRepository
+    repo     repository.CommentRepository
+    tasks    TaskAuthorizer
+    activity ActivityRecorder
+}
+
+// TaskAuthorizer はタスクのプロジェクトのロールに基づいて操作を許可するかを判定します
+type TaskAuthorizer interface {
+    AuthorizeTask(taskID, userID string, perm projectdomain.Permission) error
+}
+
+// ActivityRecorder は変更履歴に ID と日時を設定します
+// 変更履歴はリポジトリがコメントと同じトランザクションで追記します
+type ActivityRecorder interface {
+    Prepare(entry *activitydomain.Entry) *activitydomain.Entry
 }
 
-func NewCommentUseCase(r repository.CommentRepository) *CommentUseCase {
-    return &CommentUseCase{repo: r}
+func NewCommentUseCase(r repository.CommentRepository, ta TaskAuthorizer, ar ActivityRecorder) *CommentUseCase {
+    return &CommentUseCase{repo: r, tasks: ta, activity: ar}
 }
 
-func (uc *CommentUseCase) AddComment(dto *CommentDTO) (string, error) {
+// AddComment はタスクにコメントを追加します。actorID はタスクのプロジェクトで member 以上である必要があります
+func (uc *CommentUseCase) AddComment(dto *CommentDTO, actorID string) (string, error) {
+    if err := uc.tasks.AuthorizeTask(dto.TaskID, actorID, projectdomain.PermComment); err != nil {
+        return "", err
+    }
+    if dto.ID == "" {
+        dto.ID = uuid.New().String()
+    }
     comment := domain.NewComment(dto.ID, dto.Content, dto.TaskID, dto.AuthorID)
-    if err := uc.repo.Create(comment); err != nil {
+    // コメントはタスクの履歴として記録する（プロジェクトはタスクから決まる）
+    entry := activitydomain.NewEntry(activitydomain.EntityComment, comment.ID, activitydomain.ActionCreated, actorID)
+    entry.TaskID = comment.TaskID
+    entry.Add("content", nil, comment.Content)
+    if err := uc.repo.Create(comment, uc.activity.Prepare(entry)); err != nil {
         return "", err
     }
     return comment.ID, nil
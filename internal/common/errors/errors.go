@@ -1,10 +1,57 @@
 package errors
 
-import "errors"
+import (
+    "errors"
+    "sort"
+    "strings"
+)
 
 var (
     ErrNotFound      = errors.New("resource not found")
     ErrInvalidInput  = errors.New("invalid input")
     ErrUnauthorized  = errors.New("unauthorized")
+    // ErrForbidden は認証済みだが対象のリソースにアクセスできない場合に返されます
+    ErrForbidden     = errors.New("forbidden")
     ErrInternal      = errors.New("internal server error")
+
+    // ErrVersionMismatch は楽観的ロックで保存済みのバージョンと一致しなかった場合に返されます
+    ErrVersionMismatch = errors.New("resource has been modified by another request")
 )
+
+// ValidationError はフィールド単位の入力エラーをまとめて保持します
+// errors.Is(err, ErrInvalidInput) で判定できます
+type ValidationError struct {
+    Fields map[string]string `json:"fields"`
+}
+
+// NewValidationError は空の ValidationError を返します
+func NewValidationError() *ValidationError {
+    return &ValidationError{Fields: map[string]string{}}
+}
+
+// Add はフィールドのエラーメッセージを追加します
+func (e *ValidationError) Add(field, message string) {
+    e.Fields[field] = message
+}
+
+// HasErrors はエラーが1件以上あるかを返します
+func (e *ValidationError) HasErrors() bool {
+    return len(e.Fields) > 0
+}
+
+func (e *ValidationError) Error() string {
+    keys := make([]string, 0, len(e.Fields))
+    for k := range e.Fields {
+        keys = append(keys, k)
+    }
+    sort.Strings(keys)
+    msgs := make([]string, 0, len(keys))
+    for _, k := range keys {
+        msgs = append(msgs, k+": "+e.Fields[k])
+    }
+    return ErrInvalidInput.Error() + ": " + strings.Join(msgs, ", ")
+}
+
+func (e *ValidationError) Unwrap() error {
+    return ErrInvalidInput
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+    "log"
+    "os"
+    "time"
+)
+
+// DurationFromEnv は環境変数 key を time.Duration（例: 1h, 30m）として読み込みます
+// 未設定または不正な値の場合は def を返します
+func DurationFromEnv(key string, def time.Duration) time.Duration {
+    v := os.Getenv(key)
+    if v == "" {
+        return def
+    }
+    d, err := time.ParseDuration(v)
+    if err != nil || d <= 0 {
+        log.Printf("Invalid %s %q, using default %s", key, v, def)
+        return def
+    }
+    return d
+}
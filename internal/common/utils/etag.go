@@ -0,0 +1,74 @@
+package utils
+
+import (
+    "errors"
+    "net/http"
+    "strconv"
+    "strings"
+)
+
+var (
+    // ErrIfMatchRequired は更新・削除リクエストに If-Match ヘッダーがない場合に返されます
+    ErrIfMatchRequired = errors.New("If-Match header is required")
+    // ErrInvalidIfMatch は If-Match ヘッダーがバージョンとして解釈できない場合に返されます
+    ErrInvalidIfMatch = errors.New("If-Match header must be a quoted version or *")
+)
+
+// ETag はリソースのバージョンから強い ETag を生成します
+func ETag(version int) string {
+    return `"` + strconv.Itoa(version) + `"`
+}
+
+// SetETag はレスポンスに ETag ヘッダーを設定します
+func SetETag(w http.ResponseWriter, version int) {
+    w.Header().Set("ETag", ETag(version))
+}
+
+// RequireIfMatch は If-Match ヘッダーから期待するバージョンを取り出します
+// "*" の場合は 0（任意のバージョン）を返します
+func RequireIfMatch(r *http.Request) (int, error) {
+    header := strings.TrimSpace(r.Header.Get("If-Match"))
+    if header == "" {
+        return 0, ErrIfMatchRequired
+    }
+    return parseIfMatch(header)
+}
+
+// OptionalIfMatch は If-Match ヘッダーがあればバージョンを返し、なければ 0 を返します
+func OptionalIfMatch(r *http.Request) (int, error) {
+    header := strings.TrimSpace(r.Header.Get("If-Match"))
+    if header == "" {
+        return 0, nil
+    }
+    return parseIfMatch(header)
+}
+
+// NotModified は If-None-Match がリソースの現在の ETag と一致するかを返します
+func NotModified(r *http.Request, version int) bool {
+    header := r.Header.Get("If-None-Match")
+    if header == "" {
+        return false
+    }
+    current := ETag(version)
+    for _, tag := range strings.Split(header, ",") {
+        tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+        if tag == "*" || tag == current {
+            return true
+        }
+    }
+    return false
+}
+
+func parseIfMatch(header string) (int, error) {
+    if header == "*" {
+        return 0, nil
+    }
+    // 複数指定は想定しないため先頭のみを使用
+    tag := strings.TrimSpace(strings.Split(header, ",")[0])
+    tag = strings.Trim(strings.TrimPrefix(tag, "W/"), `"`)
+    version, err := strconv.Atoi(tag)
+    if err != nil || version < 1 {
+        return 0, ErrInvalidIfMatch
+    }
+    return version, nil
+}
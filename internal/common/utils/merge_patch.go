@@ -0,0 +1,48 @@
+package utils
+
+import (
+    "encoding/json"
+    "errors"
+)
+
+// ErrInvalidMergePatch はパッチが JSON オブジェクトでない場合に返されます
+var ErrInvalidMergePatch = errors.New("merge patch must be a JSON object")
+
+// MergePatch は RFC 7386 (JSON Merge Patch) に従って target に patch を適用します
+// patch 内の null はフィールドの削除、オブジェクトは再帰的なマージを意味します
+func MergePatch(target, patch []byte) ([]byte, error) {
+    var patchDoc interface{}
+    if err := json.Unmarshal(patch, &patchDoc); err != nil {
+        return nil, err
+    }
+    if _, ok := patchDoc.(map[string]interface{}); !ok {
+        return nil, ErrInvalidMergePatch
+    }
+
+    var targetDoc interface{}
+    if len(target) > 0 {
+        if err := json.Unmarshal(target, &targetDoc); err != nil {
+            return nil, err
+        }
+    }
+    return json.Marshal(mergeValue(targetDoc, patchDoc))
+}
+
+func mergeValue(target, patch interface{}) interface{} {
+    patchObj, ok := patch.(map[string]interface{})
+    if !ok {
+        return patch
+    }
+    targetObj, ok := target.(map[string]interface{})
+    if !ok {
+        targetObj = map[string]interface{}{}
+    }
+    for k, v := range patchObj {
+        if v == nil {
+            delete(targetObj, k)
+            continue
+        }
+        targetObj[k] = mergeValue(targetObj[k], v)
+    }
+    return targetObj
+}
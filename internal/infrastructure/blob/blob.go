@@ -0,0 +1,78 @@
+// Package blob は添付ファイルの本体を保存するストレージです
+//
+// BLOB_STORE で保存先を選択します
+//
+//	local（既定）: BLOB_DIR（既定 ./data/blobs）以下のファイル
+//	s3: S3 互換ストレージ（S3_ENDPOINT, S3_BUCKET, S3_REGION, S3_ACCESS_KEY, S3_SECRET_KEY, S3_PATH_STYLE）
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrNotFound はキーに対応するデータがないことを表します
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore はキーを指定してデータを保存・取得します
+type BlobStore interface {
+	// Put は r の内容（size バイト）を key に保存します。既にある場合は上書きします
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get は key の内容を返します。ない場合は ErrNotFound です
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete は key を削除します。ない場合もエラーにしません
+	Delete(ctx context.Context, key string) error
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+// Presigner は認証なしでダウンロードできる期限付きの URL を発行できるストレージです
+type Presigner interface {
+	// PresignGet は key を filename としてダウンロードさせる URL を返します
+	PresignGet(key, filename string, expires time.Duration) (string, error)
+}
+
+// NewFromEnv は環境変数の設定に従って BlobStore を返します
+func NewFromEnv() (BlobStore, error) {
+	switch kind := os.Getenv("BLOB_STORE"); kind {
+	case "", "local":
+		dir := os.Getenv("BLOB_DIR")
+		if dir == "" {
+			dir = "./data/blobs"
+		}
+		return NewLocalStore(dir)
+	case "s3":
+		return NewS3Store(S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Region:    os.Getenv("S3_REGION"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PathStyle: os.Getenv("S3_PATH_STYLE") == "true",
+		})
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q (must be local or s3)", kind)
+	}
+}
+
+// validateKey はキーが英数字と - _ . / のみからなり、.. や空の区切りを含まないことを確認します
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.HasSuffix(key, "/") {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid blob key %q", key)
+		}
+	}
+	for _, c := range key {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.ContainsRune("-_./", c)) {
+			return fmt.Errorf("invalid blob key %q", key)
+		}
+	}
+	return nil
+}
@@ -10,15 +10,23 @@ func RegisterSearchRoutes(r chi.Router) {
 	r.Get("/search", SearchHandler)
 }
 
+// SearchHandler は query（タイトル・説明・ラベルの部分一致）か label（ラベル名の完全一致）で検索します
 func SearchHandler(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query().Get("query")
-	if query == "" {
+	label := r.URL.Query().Get("label")
+	if query == "" && label == "" {
 		w.WriteHeader(http.StatusBadRequest)
-		w.Write([]byte(`{"error":"query required"}`))
+		w.Write([]byte(`{"error":"query or label required"}`))
 		return
 	}
 	client, _ := NewSolrClient("todoapp")
-	results, err := client.Search(query)
+	var results []map[string]interface{}
+	var err error
+	if label != "" {
+		results, err = client.SearchByLabel(label)
+	} else {
+		results, err = client.Search(query)
+	}
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		w.Write([]byte(`{"error":"search failed"}`))
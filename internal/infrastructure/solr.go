@@ -2,6 +2,7 @@ package infrastructure
 
 import (
 	"fmt"
+	"strings"
 	"github.com/rtt/Go-Solr"
 )
 
@@ -28,15 +29,47 @@ func (s *SolrClient) Add(doc map[string]interface{}) error {
 	return err
 }
 
-// 検索（title, description両方を全文検索）
+// 複数ドキュメントの一括追加（1回のリクエストでまとめてコミット）
+func (s *SolrClient) AddAll(docs []map[string]interface{}) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	adds := make([]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		adds = append(adds, map[string]interface{}{ "doc": doc })
+	}
+	_, err := s.client.Update(map[string]interface{}{"add": adds}, true)
+	return err
+}
+
+// ドキュメント削除
+func (s *SolrClient) Delete(id string) error {
+	deleteDoc := map[string]interface{}{
+		"delete": map[string]interface{}{"id": id},
+	}
+	_, err := s.client.Update(deleteDoc, true)
+	return err
+}
+
+// 検索（title, description, labelsを全文検索）
 func (s *SolrClient) Search(keyword string) ([]map[string]interface{}, error) {
 	// ワイルドカード検索を使用してより柔軟な検索を実現
-	query := fmt.Sprintf("title:*%s* OR description:*%s*", keyword, keyword)
+	query := fmt.Sprintf("title:*%s* OR description:*%s* OR labels:*%s*", keyword, keyword, keyword)
+	return s.selectDocs(query)
+}
+
+// ラベル名が一致するタスクを検索
+func (s *SolrClient) SearchByLabel(label string) ([]map[string]interface{}, error) {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(label)
+	return s.selectDocs(fmt.Sprintf(`type:task AND labels:"%s"`, escaped))
+}
+
+func (s *SolrClient) selectDocs(query string) ([]map[string]interface{}, error) {
 	q := solr.Query{
 		Params: solr.URLParamMap{
 			"q":    []string{query},
 			"rows": []string{"10"},
-			"fl":   []string{"id,type,title,description"}, // 必要なフィールドのみを取得
+			"fl":   []string{"id,type,title,description,labels"}, // 必要なフィールドのみを取得
 		},
 	}
 	res, err := s.client.Select(&q)
@@ -48,8 +81,8 @@ func (s *SolrClient) Search(keyword string) ([]map[string]interface{}, error) {
 		doc := res.Results.Get(i)
 		row := map[string]interface{}{}
 		for k, v := range doc.Fields {
-			// 配列の場合は最初の要素を取得
-			if arr, ok := v.([]interface{}); ok && len(arr) > 0 {
+			// 配列の場合は最初の要素を取得（複数値のlabelsはそのまま）
+			if arr, ok := v.([]interface{}); ok && len(arr) > 0 && k != "labels" {
 				row[k] = arr[0]
 			} else {
 				row[k] = v
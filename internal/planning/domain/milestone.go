@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// Milestone はプロジェクト内の節目です。StartDate は省略可能で、EndDate が期日になります
+type Milestone struct {
+    ID          string    `json:"id"`
+    ProjectID   string    `json:"project_id"`
+    Name        string    `json:"name"`
+    Description string    `json:"description"`
+    StartDate   time.Time `json:"start_date"`
+    EndDate     time.Time `json:"end_date"`
+    CreatedBy   string    `json:"created_by"`
+    CreatedAt   time.Time `json:"created_at"`
+    UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func NewMilestone(id, projectID, name, description string, startDate, endDate time.Time, createdBy string) *Milestone {
+    return &Milestone{
+        ID:          id,
+        ProjectID:   projectID,
+        Name:        name,
+        Description: description,
+        StartDate:   startDate,
+        EndDate:     endDate,
+        CreatedBy:   createdBy,
+        CreatedAt:   time.Now(),
+        UpdatedAt:   time.Now(),
+    }
+}
+
+// Progress はスプリント・マイルストーンに予定されたタスクの集計です（ゴミ箱内のタスクを除く）
+type Progress struct {
+    Total    int
+    ByStatus map[string]int
+    // Estimate は見積もり時間（分）の合計、CompletedEstimate はそのうち Done のタスクの合計です
+    Estimate          int
+    CompletedEstimate int
+    // TimeSpent は記録済みの作業時間（分）の合計です
+    TimeSpent int
+}
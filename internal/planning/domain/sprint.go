@@ -0,0 +1,119 @@
+package domain
+
+import (
+    "errors"
+    "strings"
+    "time"
+)
+
+// スプリントの状態。planned → active → closed の順に進みます
+const (
+    SprintPlanned = "planned"
+    SprintActive  = "active"
+    SprintClosed  = "closed"
+)
+
+const MaxNameLength = 255
+
+var (
+    ErrNameRequired     = errors.New("name is required")
+    ErrNameTooLong      = errors.New("name must be at most 255 characters")
+    ErrStartRequired    = errors.New("start_date is required")
+    ErrEndRequired      = errors.New("end_date is required")
+    ErrInvalidDateRange = errors.New("end_date must not be before start_date")
+    ErrInvalidDate      = errors.New("date must be YYYY-MM-DD or RFC3339")
+    // ErrSprintNotPlanned は開始済みのスプリントを開始しようとした場合に返されます
+    ErrSprintNotPlanned = errors.New("only planned sprints can be started")
+    // ErrSprintNotActive は実行中でないスプリントを終了しようとした場合に返されます
+    ErrSprintNotActive = errors.New("only active sprints can be closed")
+    // ErrActiveSprintExists はプロジェクトに実行中のスプリントがある場合に返されます
+    ErrActiveSprintExists = errors.New("project already has an active sprint")
+    // ErrSprintClosed は終了したスプリントを変更しようとした場合に返されます
+    ErrSprintClosed = errors.New("sprint is closed")
+)
+
+// Sprint はプロジェクト内の期間を区切った作業単位です
+type Sprint struct {
+    ID        string     `json:"id"`
+    ProjectID string     `json:"project_id"`
+    Name      string     `json:"name"`
+    Goal      string     `json:"goal"`
+    StartDate time.Time  `json:"start_date"`
+    EndDate   time.Time  `json:"end_date"`
+    Status    string     `json:"status"`
+    StartedAt *time.Time `json:"started_at,omitempty"`
+    ClosedAt  *time.Time `json:"closed_at,omitempty"`
+    // RolledOver は終了時に次のスプリント（またはバックログ）に移した未完了のタスク数です
+    RolledOver int       `json:"rolled_over"`
+    CreatedBy  string    `json:"created_by"`
+    CreatedAt  time.Time `json:"created_at"`
+    UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func NewSprint(id, projectID, name, goal string, startDate, endDate time.Time, createdBy string) *Sprint {
+    return &Sprint{
+        ID:        id,
+        ProjectID: projectID,
+        Name:      name,
+        Goal:      goal,
+        StartDate: startDate,
+        EndDate:   endDate,
+        Status:    SprintPlanned,
+        CreatedBy: createdBy,
+        CreatedAt: time.Now(),
+        UpdatedAt: time.Now(),
+    }
+}
+
+// Start は計画中のスプリントを実行中にします
+func (s *Sprint) Start(now time.Time) error {
+    if s.Status != SprintPlanned {
+        return ErrSprintNotPlanned
+    }
+    s.Status = SprintActive
+    s.StartedAt = &now
+    s.UpdatedAt = now
+    return nil
+}
+
+// Close は実行中のスプリントを終了します
+func (s *Sprint) Close(now time.Time) error {
+    if s.Status != SprintActive {
+        return ErrSprintNotActive
+    }
+    s.Status = SprintClosed
+    s.ClosedAt = &now
+    s.UpdatedAt = now
+    return nil
+}
+
+// ValidateName は名前を前後の空白を除いて検証し、正規化した名前を返します
+func ValidateName(name string) (string, error) {
+    name = strings.TrimSpace(name)
+    if name == "" {
+        return "", ErrNameRequired
+    }
+    if len([]rune(name)) > MaxNameLength {
+        return "", ErrNameTooLong
+    }
+    return name, nil
+}
+
+// ValidateSprintDates はスプリントの期間を検証します。開始日と終了日はどちらも必須です
+func ValidateSprintDates(start, end time.Time) error {
+    if start.IsZero() {
+        return ErrStartRequired
+    }
+    return ValidateDateRange(start, end)
+}
+
+// ValidateDateRange は終了日が必須で、開始日（省略可能）より前でないことを検証します
+func ValidateDateRange(start, end time.Time) error {
+    if end.IsZero() {
+        return ErrEndRequired
+    }
+    if !start.IsZero() && end.Before(start) {
+        return ErrInvalidDateRange
+    }
+    return nil
+}
@@ -0,0 +1,217 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	activityhandler "todo-app/internal/activity/handler"
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/common/utils"
+	"todo-app/internal/planning/domain"
+	"todo-app/internal/planning/repository/postgres"
+	"todo-app/internal/planning/usecase"
+	projectpolicy "todo-app/internal/project/policy"
+	projectpostgres "todo-app/internal/project/repository/postgres"
+	taskdomain "todo-app/internal/task/domain"
+	taskpostgres "todo-app/internal/task/repository/postgres"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NewPlanningUseCase は PostgreSQL のリポジトリを使う PlanningUseCase を返します
+func NewPlanningUseCase(db *sql.DB) *usecase.PlanningUseCase {
+	return usecase.NewPlanningUseCase(postgres.NewSprintRepoPg(db), postgres.NewMilestoneRepoPg(db),
+		projectpolicy.NewPolicy(projectpostgres.NewProjectRepoPg(db)), taskpostgres.NewProjectSettingsRepoPg(db), activityhandler.NewActivityUseCase(db))
+}
+
+// RegisterPlanningRoutes はスプリント・マイルストーン単体のエンドポイントを登録します
+// プロジェクトのスプリント・マイルストーンの一覧と作成はプロジェクトのハンドラーで登録します
+func RegisterPlanningRoutes(r chi.Router, db *sql.DB) {
+	uc := NewPlanningUseCase(db)
+
+	r.Route("/sprints/{sprintID}", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			sprint, err := uc.GetSprint(chi.URLParam(r, "sprintID"), userID)
+			if err != nil {
+				WriteError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusOK, sprint)
+		})
+
+		r.Patch("/", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			var req usecase.SprintRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			sprint, err := uc.UpdateSprint(chi.URLParam(r, "sprintID"), &req, userID)
+			if err != nil {
+				log.Printf("Failed to update sprint: %v", err)
+				WriteError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusOK, sprint)
+		})
+
+		r.Delete("/", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			if err := uc.DeleteSprint(chi.URLParam(r, "sprintID"), userID); err != nil {
+				log.Printf("Failed to delete sprint: %v", err)
+				WriteError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		r.Post("/start", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			sprint, err := uc.StartSprint(chi.URLParam(r, "sprintID"), userID)
+			if err != nil {
+				log.Printf("Failed to start sprint: %v", err)
+				WriteError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusOK, sprint)
+		})
+
+		// 終了: 未完了のタスクを next_sprint_id（省略時は次の計画中のスプリント）またはバックログに移す
+		r.Post("/close", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			var req usecase.CloseSprintRequest
+			if err := utils.DecodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			result, err := uc.CloseSprint(chi.URLParam(r, "sprintID"), &req, userID)
+			if err != nil {
+				log.Printf("Failed to close sprint: %v", err)
+				WriteError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusOK, result)
+		})
+
+		r.Get("/summary", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			summary, err := uc.SprintSummary(chi.URLParam(r, "sprintID"), userID)
+			if err != nil {
+				WriteError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusOK, summary)
+		})
+	})
+
+	r.Route("/milestones/{milestoneID}", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			milestone, err := uc.GetMilestone(chi.URLParam(r, "milestoneID"), userID)
+			if err != nil {
+				WriteError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusOK, milestone)
+		})
+
+		r.Patch("/", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			var req usecase.MilestoneRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			milestone, err := uc.UpdateMilestone(chi.URLParam(r, "milestoneID"), &req, userID)
+			if err != nil {
+				log.Printf("Failed to update milestone: %v", err)
+				WriteError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusOK, milestone)
+		})
+
+		r.Delete("/", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			if err := uc.DeleteMilestone(chi.URLParam(r, "milestoneID"), userID); err != nil {
+				log.Printf("Failed to delete milestone: %v", err)
+				WriteError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		r.Get("/summary", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			summary, err := uc.MilestoneSummary(chi.URLParam(r, "milestoneID"), userID)
+			if err != nil {
+				WriteError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusOK, summary)
+		})
+	})
+}
+
+// WriteError はスプリント・マイルストーンのエラーを HTTP ステータスに変換して返します
+func WriteError(w http.ResponseWriter, err error) {
+	var verr *apperrors.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		utils.JSONResponse(w, http.StatusBadRequest, map[string]interface{}{"error": apperrors.ErrInvalidInput.Error(), "fields": verr.Fields})
+	case errors.Is(err, domain.ErrSprintNotPlanned), errors.Is(err, domain.ErrSprintNotActive),
+		errors.Is(err, domain.ErrActiveSprintExists), errors.Is(err, domain.ErrSprintClosed),
+		errors.Is(err, taskdomain.ErrProjectArchived):
+		utils.JSONResponse(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, apperrors.ErrForbidden):
+		utils.JSONResponse(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, apperrors.ErrNotFound):
+		utils.JSONResponse(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	default:
+		utils.JSONResponse(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+    activitydomain "todo-app/internal/activity/domain"
+    "todo-app/internal/planning/domain"
+)
+
+// SprintRepository はプロジェクトのスプリントを管理します
+type SprintRepository interface {
+    Create(sprint *domain.Sprint) error
+    FindByID(id string) (*domain.Sprint, error)
+    // ListByProject はプロジェクトのスプリントを開始日の順に返します
+    ListByProject(projectID string) ([]*domain.Sprint, error)
+    Update(sprint *domain.Sprint) error
+    // Delete はスプリントを削除します。予定されていたタスクはバックログ（スプリントなし）に戻ります
+    // 戻したタスクごとに activity(id) を同じトランザクションで変更履歴に追記します
+    Delete(id string, activity func(taskID string) *activitydomain.Entry) error
+    // Close は終了したスプリントを保存し、未完了（Done / Canceled 以外）のタスクを nextSprintID（空の場合はバックログ）に移して、
+    // 移したタスクの ID を返します。sprint.RolledOver には移したタスク数を設定します
+    // 移したタスクごとに activity(id) を同じトランザクションで変更履歴に追記します
+    Close(sprint *domain.Sprint, nextSprintID string, activity func(taskID string) *activitydomain.Entry) ([]string, error)
+    // Progress はスプリントに予定されたタスクを集計します
+    Progress(id string) (*domain.Progress, error)
+}
+
+// MilestoneRepository はプロジェクトのマイルストーンを管理します
+type MilestoneRepository interface {
+    Create(milestone *domain.Milestone) error
+    FindByID(id string) (*domain.Milestone, error)
+    // ListByProject はプロジェクトのマイルストーンを期日の順に返します
+    ListByProject(projectID string) ([]*domain.Milestone, error)
+    Update(milestone *domain.Milestone) error
+    // Delete はマイルストーンを削除します。予定されていたタスクはマイルストーンなしになります
+    // 外したタスクごとに activity(id) を同じトランザクションで変更履歴に追記します
+    Delete(id string, activity func(taskID string) *activitydomain.Entry) error
+    // Progress はマイルストーンに予定されたタスクを集計します
+    Progress(id string) (*domain.Progress, error)
+}
@@ -0,0 +1,112 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	activitydomain "todo-app/internal/activity/domain"
+	"todo-app/internal/planning/domain"
+	"todo-app/internal/planning/repository"
+)
+
+// milestoneRepoPg は MilestoneRepository の PostgreSQL 実装
+type milestoneRepoPg struct{ db *sql.DB }
+
+// NewMilestoneRepoPg は PostgreSQL 実装（マイルストーン用）を返す
+func NewMilestoneRepoPg(db *sql.DB) repository.MilestoneRepository {
+	return &milestoneRepoPg{db: db}
+}
+
+const milestoneColumns = `id, project_id, name, description, start_date, end_date, COALESCE(created_by, ''), created_at, updated_at`
+
+func scanMilestone(row rowScanner) (*domain.Milestone, error) {
+	m := &domain.Milestone{}
+	var start sql.NullTime
+	err := row.Scan(&m.ID, &m.ProjectID, &m.Name, &m.Description, &start, &m.EndDate, &m.CreatedBy, &m.CreatedAt, &m.UpdatedAt)
+	m.StartDate = start.Time
+	return m, err
+}
+
+// nullTime は未設定（ゼロ値）の日時を NULL として保存します
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func (r *milestoneRepoPg) Create(m *domain.Milestone) error {
+	query := `
+        INSERT INTO milestones (id, project_id, name, description, start_date, end_date, created_by, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, ''), $8, $9)
+    `
+	_, err := r.db.Exec(query, m.ID, m.ProjectID, m.Name, m.Description, nullTime(m.StartDate), m.EndDate, m.CreatedBy, m.CreatedAt, m.UpdatedAt)
+	return err
+}
+
+func (r *milestoneRepoPg) FindByID(id string) (*domain.Milestone, error) {
+	m, err := scanMilestone(r.db.QueryRow(`SELECT `+milestoneColumns+` FROM milestones WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("milestone not found")
+		}
+		return nil, err
+	}
+	return m, nil
+}
+
+func (r *milestoneRepoPg) ListByProject(projectID string) ([]*domain.Milestone, error) {
+	rows, err := r.db.Query(`SELECT `+milestoneColumns+` FROM milestones WHERE project_id = $1 ORDER BY end_date, created_at`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	milestones := []*domain.Milestone{}
+	for rows.Next() {
+		m, err := scanMilestone(rows)
+		if err != nil {
+			return nil, err
+		}
+		milestones = append(milestones, m)
+	}
+	return milestones, rows.Err()
+}
+
+func (r *milestoneRepoPg) Update(m *domain.Milestone) error {
+	query := `
+        UPDATE milestones
+        SET name = $2, description = $3, start_date = $4, end_date = $5, updated_at = $6
+        WHERE id = $1
+    `
+	result, err := r.db.Exec(query, m.ID, m.Name, m.Description, nullTime(m.StartDate), m.EndDate, m.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("milestone not found")
+	}
+	return nil
+}
+
+func (r *milestoneRepoPg) Delete(id string, activity func(taskID string) *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := unscheduleTasks(tx, "milestone_id", id, activity); err != nil {
+		return err
+	}
+	result, err := tx.Exec(`DELETE FROM milestones WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("milestone not found")
+	}
+	return tx.Commit()
+}
+
+func (r *milestoneRepoPg) Progress(id string) (*domain.Progress, error) {
+	return progress(r.db, "milestone_id", id)
+}
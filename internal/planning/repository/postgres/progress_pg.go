@@ -0,0 +1,53 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"todo-app/internal/planning/domain"
+	taskdomain "todo-app/internal/task/domain"
+
+	"github.com/lib/pq"
+)
+
+// progress は column（sprint_id または milestone_id）が id のタスクをステータスごとに集計します
+func progress(db *sql.DB, column, id string) (*domain.Progress, error) {
+	query := fmt.Sprintf(`
+        SELECT t.status, COUNT(*), COALESCE(SUM(t.estimate), 0), COALESCE(SUM(te.minutes), 0)
+        FROM tasks t
+        LEFT JOIN (
+            SELECT task_id, SUM(minutes) AS minutes FROM time_entries WHERE ended_at IS NOT NULL GROUP BY task_id
+        ) te ON te.task_id = t.id
+        WHERE t.%s = $1 AND t.deleted_at IS NULL
+        GROUP BY t.status
+    `, column)
+	rows, err := db.Query(query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	p := &domain.Progress{ByStatus: map[string]int{}}
+	for rows.Next() {
+		var status string
+		var count, estimate, spent int
+		if err := rows.Scan(&status, &count, &estimate, &spent); err != nil {
+			return nil, err
+		}
+		p.Total += count
+		p.ByStatus[status] = count
+		p.Estimate += estimate
+		p.TimeSpent += spent
+		if status == taskdomain.StatusDone {
+			p.CompletedEstimate += estimate
+		}
+	}
+	return p, rows.Err()
+}
+
+// isUniqueViolation は一意制約違反のエラーかを返します
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	activitydomain "todo-app/internal/activity/domain"
+	activitypostgres "todo-app/internal/activity/repository/postgres"
+	"todo-app/internal/planning/domain"
+	"todo-app/internal/planning/repository"
+	taskdomain "todo-app/internal/task/domain"
+)
+
+// sprintRepoPg は SprintRepository の PostgreSQL 実装
+type sprintRepoPg struct{ db *sql.DB }
+
+// NewSprintRepoPg は PostgreSQL 実装（スプリント用）を返す
+func NewSprintRepoPg(db *sql.DB) repository.SprintRepository {
+	return &sprintRepoPg{db: db}
+}
+
+const sprintColumns = `id, project_id, name, goal, start_date, end_date, status, started_at, closed_at, rolled_over,
+        COALESCE(created_by, ''), created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanSprint(row rowScanner) (*domain.Sprint, error) {
+	s := &domain.Sprint{}
+	err := row.Scan(&s.ID, &s.ProjectID, &s.Name, &s.Goal, &s.StartDate, &s.EndDate, &s.Status, &s.StartedAt, &s.ClosedAt, &s.RolledOver,
+		&s.CreatedBy, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
+
+func (r *sprintRepoPg) Create(s *domain.Sprint) error {
+	query := `
+        INSERT INTO sprints (id, project_id, name, goal, start_date, end_date, status, created_by, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), $9, $10)
+    `
+	_, err := r.db.Exec(query, s.ID, s.ProjectID, s.Name, s.Goal, s.StartDate, s.EndDate, s.Status, s.CreatedBy, s.CreatedAt, s.UpdatedAt)
+	return err
+}
+
+func (r *sprintRepoPg) FindByID(id string) (*domain.Sprint, error) {
+	s, err := scanSprint(r.db.QueryRow(`SELECT `+sprintColumns+` FROM sprints WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("sprint not found")
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *sprintRepoPg) ListByProject(projectID string) ([]*domain.Sprint, error) {
+	rows, err := r.db.Query(`SELECT `+sprintColumns+` FROM sprints WHERE project_id = $1 ORDER BY start_date, created_at`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sprints := []*domain.Sprint{}
+	for rows.Next() {
+		s, err := scanSprint(rows)
+		if err != nil {
+			return nil, err
+		}
+		sprints = append(sprints, s)
+	}
+	return sprints, rows.Err()
+}
+
+// Update はスプリントを保存します。実行中のスプリントが同じプロジェクトに既にある場合は ErrActiveSprintExists です
+func (r *sprintRepoPg) Update(s *domain.Sprint) error {
+	query := `
+        UPDATE sprints
+        SET name = $2, goal = $3, start_date = $4, end_date = $5, status = $6, started_at = $7, closed_at = $8, updated_at = $9
+        WHERE id = $1
+    `
+	result, err := r.db.Exec(query, s.ID, s.Name, s.Goal, s.StartDate, s.EndDate, s.Status, s.StartedAt, s.ClosedAt, s.UpdatedAt)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return domain.ErrActiveSprintExists
+		}
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("sprint not found")
+	}
+	return nil
+}
+
+func (r *sprintRepoPg) Delete(id string, activity func(taskID string) *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := unscheduleTasks(tx, "sprint_id", id, activity); err != nil {
+		return err
+	}
+	result, err := tx.Exec(`DELETE FROM sprints WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("sprint not found")
+	}
+	return tx.Commit()
+}
+
+// Close はタスクの移動とスプリントの終了を 1 つのトランザクションで行います
+// 移したタスクはバージョンを 1 つ進めます。ゴミ箱内のタスクは移しません
+func (r *sprintRepoPg) Close(s *domain.Sprint, nextSprintID string, activity func(taskID string) *activitydomain.Entry) ([]string, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE tasks
+        SET sprint_id = NULLIF($2, ''), updated_at = NOW(), version = version + 1
+        WHERE sprint_id = $1 AND status NOT IN ($3, $4) AND deleted_at IS NULL
+        RETURNING id
+    `
+	rows, err := tx.Query(query, s.ID, nextSprintID, taskdomain.StatusDone, taskdomain.StatusCanceled)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if err := activitypostgres.Append(tx, activity(id)); err != nil {
+			return nil, err
+		}
+	}
+
+	s.RolledOver = len(ids)
+	result, err := tx.Exec(`
+        UPDATE sprints SET status = $2, closed_at = $3, rolled_over = $4, updated_at = $5
+        WHERE id = $1 AND status = $6
+    `, s.ID, s.Status, s.ClosedAt, s.RolledOver, s.UpdatedAt, domain.SprintActive)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil, domain.ErrSprintNotActive
+	}
+	return ids, tx.Commit()
+}
+
+// unscheduleTasks は field（sprint_id / milestone_id）が id のタスクから予定を外し、タスクごとの変更履歴を追記します
+// 外部キーの ON DELETE SET NULL に任せると変更履歴が残らないため、削除の前に同じトランザクションで外します
+// Close と同じくゴミ箱内のタスクは対象外で、それらの予定は外部キーによって外れます
+func unscheduleTasks(tx *sql.Tx, field, id string, activity func(taskID string) *activitydomain.Entry) error {
+	query := `
+        UPDATE tasks
+        SET ` + field + ` = NULL, updated_at = NOW(), version = version + 1
+        WHERE ` + field + ` = $1 AND deleted_at IS NULL
+        RETURNING id
+    `
+	rows, err := tx.Query(query, id)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for rows.Next() {
+		var taskID string
+		if err := rows.Scan(&taskID); err != nil {
+			rows.Close()
+			return err
+		}
+		ids = append(ids, taskID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for _, taskID := range ids {
+		if err := activitypostgres.Append(tx, activity(taskID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *sprintRepoPg) Progress(id string) (*domain.Progress, error) {
+	return progress(r.db, "sprint_id", id)
+}
@@ -0,0 +1,68 @@
+package usecase
+
+import "todo-app/internal/planning/domain"
+
+// SprintRequest は POST /projects/{projectID}/sprints と PATCH /sprints/{sprintID} のリクエストボディです
+// 日付は YYYY-MM-DD または RFC3339 です。PATCH では指定した項目のみ変更します
+type SprintRequest struct {
+    Name      *string `json:"name"`
+    Goal      *string `json:"goal"`
+    StartDate *string `json:"start_date"`
+    EndDate   *string `json:"end_date"`
+}
+
+// MilestoneRequest は POST /projects/{projectID}/milestones と PATCH /milestones/{milestoneID} のリクエストボディです
+// start_date は省略可能で、空文字列を指定すると外します
+type MilestoneRequest struct {
+    Name        *string `json:"name"`
+    Description *string `json:"description"`
+    StartDate   *string `json:"start_date"`
+    EndDate     *string `json:"end_date"`
+}
+
+// CloseSprintRequest は POST /sprints/{sprintID}/close のリクエストボディです
+// 未完了のタスクは next_sprint_id のスプリントに移します。省略した場合は次の計画中のスプリント（なければバックログ）、
+// to_backlog が true の場合はバックログ（スプリントなし）に移します
+type CloseSprintRequest struct {
+    NextSprintID string `json:"next_sprint_id"`
+    ToBacklog    bool   `json:"to_backlog"`
+}
+
+// CloseSprintResultDTO はスプリントの終了結果です。NextSprintID が空の場合はバックログに移しました
+type CloseSprintResultDTO struct {
+    Sprint       *domain.Sprint `json:"sprint"`
+    RolledOver   int            `json:"rolled_over"`
+    NextSprintID string         `json:"next_sprint_id"`
+    TaskIDs      []string       `json:"task_ids"`
+}
+
+// ProgressDTO は予定されたタスクの進捗です。時間の単位は分です
+type ProgressDTO struct {
+    Total      int            `json:"total"`
+    Completed  int            `json:"completed"`
+    Incomplete int            `json:"incomplete"`
+    ByStatus   map[string]int `json:"by_status"`
+    Estimate          int `json:"estimate"`
+    CompletedEstimate int `json:"completed_estimate"`
+    TimeSpent         int `json:"time_spent"`
+    // Percent は完了率（%）です。Canceled のタスクは分母に含めません
+    Percent int `json:"percent"`
+}
+
+// SprintSummaryDTO は GET /sprints/{sprintID}/summary のレスポンスです
+// DaysTotal はスプリントの日数、DaysRemaining は今日（UTC）から終了日までの残り日数です
+type SprintSummaryDTO struct {
+    Sprint        *domain.Sprint `json:"sprint"`
+    Progress      ProgressDTO    `json:"progress"`
+    DaysTotal     int            `json:"days_total"`
+    DaysRemaining int            `json:"days_remaining"`
+}
+
+// MilestoneSummaryDTO は GET /milestones/{milestoneID}/summary のレスポンスです
+// Overdue は期日を過ぎて未完了のタスクが残っている場合に true です
+type MilestoneSummaryDTO struct {
+    Milestone     *domain.Milestone `json:"milestone"`
+    Progress      ProgressDTO       `json:"progress"`
+    DaysRemaining int               `json:"days_remaining"`
+    Overdue       bool              `json:"overdue"`
+}
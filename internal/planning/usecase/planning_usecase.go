@@ -0,0 +1,463 @@
+package usecase
+
+import (
+	"time"
+
+	activitydomain "todo-app/internal/activity/domain"
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/planning/domain"
+	"todo-app/internal/planning/repository"
+	projectdomain "todo-app/internal/project/domain"
+	taskdomain "todo-app/internal/task/domain"
+
+	"github.com/google/uuid"
+)
+
+// ProjectPolicy はプロジェクトのロールに基づいて操作を許可するかを判定します
+type ProjectPolicy interface {
+	Authorize(projectID, userID string, perm projectdomain.Permission) error
+}
+
+// ProjectSettings はプロジェクトがアーカイブ済み（読み取り専用）かを返します
+type ProjectSettings interface {
+	IsArchived(projectID string) (bool, error)
+}
+
+// ActivityRecorder は変更履歴に ID と日時を設定します。更新で変更がなかった場合は nil を返します
+// 変更履歴はリポジトリが変更と同じトランザクションで追記します
+type ActivityRecorder interface {
+	Prepare(entry *activitydomain.Entry) *activitydomain.Entry
+}
+
+// PlanningUseCase はプロジェクトのスプリントとマイルストーンを扱います
+// 閲覧は viewer 以上、作成・変更・開始・終了・削除は maintainer 以上が行えます
+type PlanningUseCase struct {
+	sprints    repository.SprintRepository
+	milestones repository.MilestoneRepository
+	policy     ProjectPolicy
+	settings   ProjectSettings
+	activity   ActivityRecorder
+}
+
+func NewPlanningUseCase(sr repository.SprintRepository, mr repository.MilestoneRepository, pp ProjectPolicy, ps ProjectSettings, ar ActivityRecorder) *PlanningUseCase {
+	return &PlanningUseCase{sprints: sr, milestones: mr, policy: pp, settings: ps, activity: ar}
+}
+
+// rescheduleEntry はスプリント・マイルストーンの終了や削除で予定が変わったタスクの変更履歴を返す関数を返します
+// field は sprint_id / milestone_id で、toID が空の場合は予定なしです
+func (uc *PlanningUseCase) rescheduleEntry(projectID, field, fromID, toID, actorID string) func(taskID string) *activitydomain.Entry {
+	return func(taskID string) *activitydomain.Entry {
+		entry := activitydomain.NewEntry(activitydomain.EntityTask, taskID, activitydomain.ActionUpdated, actorID)
+		entry.TaskID, entry.ProjectID = taskID, projectID
+		var to interface{}
+		if toID != "" {
+			to = toID
+		}
+		entry.Add(field, fromID, to)
+		return uc.activity.Prepare(entry)
+	}
+}
+
+// authorize は actorID がプロジェクトで perm を行えるかを判定します。変更の場合はアーカイブ済みのプロジェクトを拒否します
+func (uc *PlanningUseCase) authorize(projectID, actorID string, perm projectdomain.Permission) error {
+	if err := uc.policy.Authorize(projectID, actorID, perm); err != nil {
+		return err
+	}
+	if perm == projectdomain.PermView {
+		return nil
+	}
+	archived, err := uc.settings.IsArchived(projectID)
+	if err != nil {
+		return err
+	}
+	if archived {
+		return taskdomain.ErrProjectArchived
+	}
+	return nil
+}
+
+// ListSprints はプロジェクトのスプリントを開始日の順に返します
+func (uc *PlanningUseCase) ListSprints(projectID, actorID string) ([]*domain.Sprint, error) {
+	if err := uc.authorize(projectID, actorID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
+	return uc.sprints.ListByProject(projectID)
+}
+
+// CreateSprint は計画中のスプリントを作成します
+func (uc *PlanningUseCase) CreateSprint(projectID string, req *SprintRequest, actorID string) (*domain.Sprint, error) {
+	if err := uc.authorize(projectID, actorID, projectdomain.PermManageProject); err != nil {
+		return nil, err
+	}
+	sprint := domain.NewSprint(uuid.New().String(), projectID, "", "", time.Time{}, time.Time{}, actorID)
+	if err := applySprintRequest(sprint, req); err != nil {
+		return nil, err
+	}
+	if err := uc.sprints.Create(sprint); err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+// GetSprint はスプリントを返します
+func (uc *PlanningUseCase) GetSprint(id, actorID string) (*domain.Sprint, error) {
+	return uc.findSprint(id, actorID, projectdomain.PermView)
+}
+
+// UpdateSprint はスプリントの名前・目標・期間を変更します。終了したスプリントは変更できません
+func (uc *PlanningUseCase) UpdateSprint(id string, req *SprintRequest, actorID string) (*domain.Sprint, error) {
+	sprint, err := uc.findSprint(id, actorID, projectdomain.PermManageProject)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.Status == domain.SprintClosed {
+		return nil, domain.ErrSprintClosed
+	}
+	if err := applySprintRequest(sprint, req); err != nil {
+		return nil, err
+	}
+	sprint.UpdatedAt = time.Now()
+	if err := uc.sprints.Update(sprint); err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+// DeleteSprint はスプリントを削除します。予定されていたタスクはバックログに戻ります
+func (uc *PlanningUseCase) DeleteSprint(id, actorID string) error {
+	sprint, err := uc.findSprint(id, actorID, projectdomain.PermManageProject)
+	if err != nil {
+		return err
+	}
+	return uc.sprints.Delete(id, uc.rescheduleEntry(sprint.ProjectID, "sprint_id", id, "", actorID))
+}
+
+// StartSprint は計画中のスプリントを開始します。実行中のスプリントはプロジェクトごとに 1 つまでです
+func (uc *PlanningUseCase) StartSprint(id, actorID string) (*domain.Sprint, error) {
+	sprint, err := uc.findSprint(id, actorID, projectdomain.PermManageProject)
+	if err != nil {
+		return nil, err
+	}
+	sprints, err := uc.sprints.ListByProject(sprint.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range sprints {
+		if s.ID != sprint.ID && s.Status == domain.SprintActive {
+			return nil, domain.ErrActiveSprintExists
+		}
+	}
+	if err := sprint.Start(time.Now()); err != nil {
+		return nil, err
+	}
+	if err := uc.sprints.Update(sprint); err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+// CloseSprint は実行中のスプリントを終了し、未完了のタスクを次のスプリント（またはバックログ）に移します
+func (uc *PlanningUseCase) CloseSprint(id string, req *CloseSprintRequest, actorID string) (*CloseSprintResultDTO, error) {
+	sprint, err := uc.findSprint(id, actorID, projectdomain.PermManageProject)
+	if err != nil {
+		return nil, err
+	}
+	if sprint.Status != domain.SprintActive {
+		return nil, domain.ErrSprintNotActive
+	}
+	nextID, err := uc.nextSprint(sprint, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := sprint.Close(time.Now()); err != nil {
+		return nil, err
+	}
+	ids, err := uc.sprints.Close(sprint, nextID, uc.rescheduleEntry(sprint.ProjectID, "sprint_id", sprint.ID, nextID, actorID))
+	if err != nil {
+		return nil, err
+	}
+	return &CloseSprintResultDTO{Sprint: sprint, RolledOver: len(ids), NextSprintID: nextID, TaskIDs: ids}, nil
+}
+
+// nextSprint は未完了のタスクの移動先のスプリントを返します。空文字列はバックログです
+func (uc *PlanningUseCase) nextSprint(sprint *domain.Sprint, req *CloseSprintRequest) (string, error) {
+	if req.ToBacklog {
+		if req.NextSprintID != "" {
+			return "", fieldError("next_sprint_id", "next_sprint_id cannot be combined with to_backlog")
+		}
+		return "", nil
+	}
+	if req.NextSprintID != "" {
+		next, err := uc.sprints.FindByID(req.NextSprintID)
+		if err != nil || next.ProjectID != sprint.ProjectID || next.ID == sprint.ID {
+			return "", fieldError("next_sprint_id", "sprint not found in this project")
+		}
+		if next.Status == domain.SprintClosed {
+			return "", fieldError("next_sprint_id", domain.ErrSprintClosed.Error())
+		}
+		return next.ID, nil
+	}
+	// ListByProject は開始日の順のため、最初に見つかった計画中のスプリントが次のスプリント
+	sprints, err := uc.sprints.ListByProject(sprint.ProjectID)
+	if err != nil {
+		return "", err
+	}
+	for _, s := range sprints {
+		if s.ID != sprint.ID && s.Status == domain.SprintPlanned {
+			return s.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// SprintSummary はスプリントに予定されたタスクの進捗と残り日数を返します
+func (uc *PlanningUseCase) SprintSummary(id, actorID string) (*SprintSummaryDTO, error) {
+	sprint, err := uc.findSprint(id, actorID, projectdomain.PermView)
+	if err != nil {
+		return nil, err
+	}
+	p, err := uc.sprints.Progress(id)
+	if err != nil {
+		return nil, err
+	}
+	return &SprintSummaryDTO{
+		Sprint:        sprint,
+		Progress:      toProgressDTO(p),
+		DaysTotal:     daysBetween(sprint.StartDate, sprint.EndDate) + 1,
+		DaysRemaining: daysRemaining(sprint.EndDate, time.Now()),
+	}, nil
+}
+
+// findSprint はスプリントを返します。存在しない場合は ErrNotFound、権限がない場合は ErrForbidden です
+func (uc *PlanningUseCase) findSprint(id, actorID string, perm projectdomain.Permission) (*domain.Sprint, error) {
+	sprint, err := uc.sprints.FindByID(id)
+	if err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	if err := uc.authorize(sprint.ProjectID, actorID, perm); err != nil {
+		return nil, err
+	}
+	return sprint, nil
+}
+
+// applySprintRequest は指定された項目をスプリントに反映して検証します
+func applySprintRequest(sprint *domain.Sprint, req *SprintRequest) error {
+	verr := apperrors.NewValidationError()
+	if req.Name != nil {
+		sprint.Name = *req.Name
+	}
+	if name, err := domain.ValidateName(sprint.Name); err != nil {
+		verr.Add("name", err.Error())
+	} else {
+		sprint.Name = name
+	}
+	if req.Goal != nil {
+		sprint.Goal = *req.Goal
+	}
+	if req.StartDate != nil {
+		if t, err := parseDate(*req.StartDate); err != nil {
+			verr.Add("start_date", err.Error())
+		} else {
+			sprint.StartDate = t
+		}
+	}
+	if req.EndDate != nil {
+		if t, err := parseDate(*req.EndDate); err != nil {
+			verr.Add("end_date", err.Error())
+		} else {
+			sprint.EndDate = t
+		}
+	}
+	if !verr.HasErrors() {
+		if err := domain.ValidateSprintDates(sprint.StartDate, sprint.EndDate); err != nil {
+			verr.Add(dateField(err), err.Error())
+		}
+	}
+	if verr.HasErrors() {
+		return verr
+	}
+	return nil
+}
+
+// ListMilestones はプロジェクトのマイルストーンを期日の順に返します
+func (uc *PlanningUseCase) ListMilestones(projectID, actorID string) ([]*domain.Milestone, error) {
+	if err := uc.authorize(projectID, actorID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
+	return uc.milestones.ListByProject(projectID)
+}
+
+// CreateMilestone はマイルストーンを作成します
+func (uc *PlanningUseCase) CreateMilestone(projectID string, req *MilestoneRequest, actorID string) (*domain.Milestone, error) {
+	if err := uc.authorize(projectID, actorID, projectdomain.PermManageProject); err != nil {
+		return nil, err
+	}
+	milestone := domain.NewMilestone(uuid.New().String(), projectID, "", "", time.Time{}, time.Time{}, actorID)
+	if err := applyMilestoneRequest(milestone, req); err != nil {
+		return nil, err
+	}
+	if err := uc.milestones.Create(milestone); err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+// GetMilestone はマイルストーンを返します
+func (uc *PlanningUseCase) GetMilestone(id, actorID string) (*domain.Milestone, error) {
+	return uc.findMilestone(id, actorID, projectdomain.PermView)
+}
+
+// UpdateMilestone はマイルストーンの名前・説明・期間を変更します
+func (uc *PlanningUseCase) UpdateMilestone(id string, req *MilestoneRequest, actorID string) (*domain.Milestone, error) {
+	milestone, err := uc.findMilestone(id, actorID, projectdomain.PermManageProject)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyMilestoneRequest(milestone, req); err != nil {
+		return nil, err
+	}
+	milestone.UpdatedAt = time.Now()
+	if err := uc.milestones.Update(milestone); err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+// DeleteMilestone はマイルストーンを削除します。予定されていたタスクはマイルストーンなしになります
+func (uc *PlanningUseCase) DeleteMilestone(id, actorID string) error {
+	milestone, err := uc.findMilestone(id, actorID, projectdomain.PermManageProject)
+	if err != nil {
+		return err
+	}
+	return uc.milestones.Delete(id, uc.rescheduleEntry(milestone.ProjectID, "milestone_id", id, "", actorID))
+}
+
+// MilestoneSummary はマイルストーンに予定されたタスクの進捗と期日までの残り日数を返します
+func (uc *PlanningUseCase) MilestoneSummary(id, actorID string) (*MilestoneSummaryDTO, error) {
+	milestone, err := uc.findMilestone(id, actorID, projectdomain.PermView)
+	if err != nil {
+		return nil, err
+	}
+	p, err := uc.milestones.Progress(id)
+	if err != nil {
+		return nil, err
+	}
+	progress := toProgressDTO(p)
+	now := time.Now()
+	return &MilestoneSummaryDTO{
+		Milestone:     milestone,
+		Progress:      progress,
+		DaysRemaining: daysRemaining(milestone.EndDate, now),
+		Overdue:       progress.Incomplete > 0 && daysBetween(milestone.EndDate, now) > 0,
+	}, nil
+}
+
+func (uc *PlanningUseCase) findMilestone(id, actorID string, perm projectdomain.Permission) (*domain.Milestone, error) {
+	milestone, err := uc.milestones.FindByID(id)
+	if err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	if err := uc.authorize(milestone.ProjectID, actorID, perm); err != nil {
+		return nil, err
+	}
+	return milestone, nil
+}
+
+// applyMilestoneRequest は指定された項目をマイルストーンに反映して検証します
+func applyMilestoneRequest(milestone *domain.Milestone, req *MilestoneRequest) error {
+	verr := apperrors.NewValidationError()
+	if req.Name != nil {
+		milestone.Name = *req.Name
+	}
+	if name, err := domain.ValidateName(milestone.Name); err != nil {
+		verr.Add("name", err.Error())
+	} else {
+		milestone.Name = name
+	}
+	if req.Description != nil {
+		milestone.Description = *req.Description
+	}
+	if req.StartDate != nil {
+		if *req.StartDate == "" {
+			milestone.StartDate = time.Time{}
+		} else if t, err := parseDate(*req.StartDate); err != nil {
+			verr.Add("start_date", err.Error())
+		} else {
+			milestone.StartDate = t
+		}
+	}
+	if req.EndDate != nil {
+		if t, err := parseDate(*req.EndDate); err != nil {
+			verr.Add("end_date", err.Error())
+		} else {
+			milestone.EndDate = t
+		}
+	}
+	if !verr.HasErrors() {
+		if err := domain.ValidateDateRange(milestone.StartDate, milestone.EndDate); err != nil {
+			verr.Add(dateField(err), err.Error())
+		}
+	}
+	if verr.HasErrors() {
+		return verr
+	}
+	return nil
+}
+
+func toProgressDTO(p *domain.Progress) ProgressDTO {
+	done := p.ByStatus[taskdomain.StatusDone]
+	canceled := p.ByStatus[taskdomain.StatusCanceled]
+	dto := ProgressDTO{
+		Total:             p.Total,
+		Completed:         done,
+		Incomplete:        p.Total - done - canceled,
+		ByStatus:          p.ByStatus,
+		Estimate:          p.Estimate,
+		CompletedEstimate: p.CompletedEstimate,
+		TimeSpent:         p.TimeSpent,
+	}
+	if n := p.Total - canceled; n > 0 {
+		dto.Percent = done * 100 / n
+	}
+	return dto
+}
+
+// daysBetween は from の日付から to の日付までの日数（UTC）を返します
+func daysBetween(from, to time.Time) int {
+	from, to = from.UTC(), to.UTC()
+	a := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	b := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// daysRemaining は now から終了日までの残り日数です。終了日を過ぎた場合は 0 です
+func daysRemaining(end, now time.Time) int {
+	if n := daysBetween(now, end); n > 0 {
+		return n
+	}
+	return 0
+}
+
+// dateField は期間の検証エラーに対応するフィールド名です
+func dateField(err error) string {
+	if err == domain.ErrStartRequired {
+		return "start_date"
+	}
+	return "end_date"
+}
+
+// parseDate は YYYY-MM-DD または RFC3339 の日付を読み取ります
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, domain.ErrInvalidDate
+}
+
+func fieldError(field, message string) error {
+	verr := apperrors.NewValidationError()
+	verr.Add(field, message)
+	return verr
+}
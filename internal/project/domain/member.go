@@ -0,0 +1,87 @@
+package domain
+
+import "errors"
+
+// プロジェクト内のロール。owner > maintainer > member > viewer の順に権限が強くなります
+const (
+    RoleOwner      = "owner"
+    RoleMaintainer = "maintainer"
+    RoleMember     = "member"
+    RoleViewer     = "viewer"
+)
+
+// Permission はロールによって許可されるプロジェクト内の操作です
+type Permission int
+
+const (
+    // PermView はプロジェクトとタスク・サブタスク・コメントの閲覧です
+    PermView Permission = iota
+    // PermComment はタスクへのコメントです
+    PermComment
+    // PermEditTasks はタスク・サブタスクの作成・更新・削除です
+    PermEditTasks
+    // PermManageProject はプロジェクトの更新・設定・アーカイブとラベルの管理です
+    PermManageProject
+    // PermManageMembers はメンバーの追加・削除とロールの変更です。owner の付与・変更は owner のみ行えます
+    PermManageMembers
+    // PermDeleteProject はプロジェクトの削除と復元です
+    PermDeleteProject
+)
+
+var (
+    ErrInvalidRole = errors.New("role must be one of owner, maintainer, member, viewer")
+    // ErrLastOwner は最後の owner を外そうとした場合に返されます
+    ErrLastOwner = errors.New("project must have at least one owner")
+    // ErrAlreadyMember は既にメンバーのユーザーを追加しようとした場合に返されます。ロールの変更は UpdateMemberRole で行います
+    ErrAlreadyMember = errors.New("user is already a member of the project")
+)
+
+// Member はプロジェクトのメンバーとロールです
+type Member struct {
+    ID    string `json:"id"`
+    Name  string `json:"name"`
+    Email string `json:"email"`
+    Role  string `json:"role"`
+}
+
+var roleRanks = map[string]int{
+    RoleViewer:     1,
+    RoleMember:     2,
+    RoleMaintainer: 3,
+    RoleOwner:      4,
+}
+
+var requiredRoles = map[Permission]string{
+    PermView:          RoleViewer,
+    PermComment:       RoleMember,
+    PermEditTasks:     RoleMember,
+    PermManageProject: RoleMaintainer,
+    PermManageMembers: RoleMaintainer,
+    PermDeleteProject: RoleOwner,
+}
+
+// ValidateRole はロール名が有効かを検証します
+func ValidateRole(role string) error {
+    if _, ok := roleRanks[role]; !ok {
+        return ErrInvalidRole
+    }
+    return nil
+}
+
+// RolesAllowing は perm を許可するロールを権限の強い順に返します
+func RolesAllowing(perm Permission) []string {
+    var roles []string
+    for _, role := range []string{RoleOwner, RoleMaintainer, RoleMember, RoleViewer} {
+        if RoleAllows(role, perm) {
+            roles = append(roles, role)
+        }
+    }
+    return roles
+}
+
+// RoleAllows は role が perm を許可するかを返します。空のロール（メンバーでない）は何も許可しません
+func RoleAllows(role string, perm Permission) bool {
+    rank, ok := roleRanks[role]
+    required, known := requiredRoles[perm]
+    return ok && known && rank >= roleRanks[required]
+}
@@ -11,6 +11,12 @@ type Project struct {
     CreatedBy   string    `json:"created_by"`
     CreatedAt   time.Time `json:"created_at"`
     UpdatedAt   time.Time `json:"updated_at"`
+    Version     int       `json:"version"`
+    DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+    // AutoCompleteTasks が true の場合、サブタスクがすべて完了したタスクを自動的に Done にします
+    AutoCompleteTasks bool `json:"auto_complete_tasks"`
+    // Status は active または archived です。アーカイブ済みのプロジェクトは読み取り専用になります
+    Status      string    `json:"status"`
 }
 
 func NewProject(id, name, description string, startDate, endDate time.Time, createdBy string) *Project {
@@ -23,5 +29,12 @@ func NewProject(id, name, description string, startDate, endDate time.Time, crea
         CreatedBy:   createdBy,
         CreatedAt:   time.Now(),
         UpdatedAt:   time.Now(),
+        Version:     1,
+        Status:      StatusActive,
     }
 }
+
+// IsArchived はプロジェクトがアーカイブ済みかを返します
+func (p *Project) IsArchived() bool {
+    return p.Status == StatusArchived
+}
@@ -1,6 +1,15 @@
 package domain
 
+import "errors"
+
 const (
     StatusActive   = "active"
     StatusArchived = "archived"
 )
+
+var (
+    // ErrArchived はアーカイブ済み（読み取り専用）のプロジェクトを変更しようとした場合に返されます
+    ErrArchived         = errors.New("project is archived and read-only")
+    ErrNameRequired     = errors.New("name is required")
+    ErrInvalidDateRange = errors.New("end_date must not be before start_date")
+)
@@ -2,31 +2,52 @@ package handler
 
 import (
 	"database/sql"
+	"errors"
+	"io"
 	"log"
 	"net/http"
 
+	activityhandler "todo-app/internal/activity/handler"
+	apperrors "todo-app/internal/common/errors"
 	"todo-app/internal/common/utils"
+	planninghandler "todo-app/internal/planning/handler"
+	planningusecase "todo-app/internal/planning/usecase"
+	"todo-app/internal/project/domain"
 	"todo-app/internal/project/repository/postgres"
 	"todo-app/internal/project/usecase"
-	taskpostgres "todo-app/internal/task/repository/postgres"
+	reporthandler "todo-app/internal/report/handler"
+	taskdomain "todo-app/internal/task/domain"
+	taskhandler "todo-app/internal/task/handler"
 	taskusecase "todo-app/internal/task/usecase"
+	templatehandler "todo-app/internal/template/handler"
+	templateusecase "todo-app/internal/template/usecase"
 
 	"github.com/go-chi/chi/v5"
 )
 
 func RegisterProjectRoutes(r chi.Router, db *sql.DB) {
-	uc := usecase.NewProjectUseCase(postgres.NewProjectRepoPg(db))
-	taskRepo := taskpostgres.NewTaskRepoPg(db)
-	taskUC := taskusecase.NewTaskUseCase(taskRepo, taskpostgres.NewSubtaskRepoPg(db))
+	taskUC := taskhandler.NewTaskUseCase(db)
+	activityUC := activityhandler.NewActivityUseCase(db)
+	uc := usecase.NewProjectUseCase(postgres.NewProjectRepoPg(db), taskUC, activityUC)
+	templateUC := templatehandler.NewTemplateUseCase(db)
+	planningUC := planninghandler.NewPlanningUseCase(db)
+	reportUC := reporthandler.NewReportUseCase(db)
 
 	r.Route("/projects", func(r chi.Router) {
 		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 			log.Printf("Get projects request received")
 
-			projects, err := uc.GetAll()
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			projects, err := uc.GetAll(userID, r.URL.Query().Get("status"))
 			if err != nil {
 				log.Printf("Failed to get projects: %v", err)
-				utils.JSONResponse(w, http.StatusInternalServerError, err.Error())
+				writeProjectError(w, err)
 				return
 			}
 
@@ -66,14 +87,55 @@ func RegisterProjectRoutes(r chi.Router, db *sql.DB) {
 			utils.JSONResponse(w, http.StatusCreated, map[string]string{"id": id})
 		})
 
+		r.Post("/from-template", func(w http.ResponseWriter, r *http.Request) {
+			log.Printf("Create project from template request received")
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			var req templateusecase.InstantiateProjectRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode template request: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			result, err := templateUC.InstantiateProject(&req, userID)
+			if err != nil {
+				log.Printf("Failed to create project from template %s: %v", req.TemplateID, err)
+				templatehandler.WriteError(w, err)
+				return
+			}
+
+			log.Printf("Project created from template %s with ID: %s (%d tasks)", req.TemplateID, result.ProjectID, len(result.TaskIDs))
+			utils.JSONResponse(w, http.StatusCreated, result)
+		})
+
 		r.Get("/{projectID}", func(w http.ResponseWriter, r *http.Request) {
 			projectID := chi.URLParam(r, "projectID")
 			log.Printf("Get project request received for projectID: %s", projectID)
 
-			project, err := uc.GetByID(projectID)
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			project, err := uc.GetByID(projectID, userID)
 			if err != nil {
 				log.Printf("Failed to get project %s: %v", projectID, err)
-				utils.JSONResponse(w, http.StatusNotFound, "project not found")
+				writeProjectError(w, err)
+				return
+			}
+
+			utils.SetETag(w, project.Version)
+			if utils.NotModified(r, project.Version) {
+				w.WriteHeader(http.StatusNotModified)
 				return
 			}
 
@@ -81,14 +143,166 @@ func RegisterProjectRoutes(r chi.Router, db *sql.DB) {
 			utils.JSONResponse(w, http.StatusOK, project)
 		})
 
+		r.Put("/{projectID}", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Update project request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			version, err := utils.RequireIfMatch(r)
+			if err != nil {
+				status := http.StatusBadRequest
+				if errors.Is(err, utils.ErrIfMatchRequired) {
+					status = http.StatusPreconditionRequired
+				}
+				utils.JSONResponse(w, status, err.Error())
+				return
+			}
+
+			var dto usecase.ProjectDTO
+			if err := utils.DecodeJSON(r, &dto); err != nil {
+				log.Printf("Failed to decode project data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			project, err := uc.Update(projectID, &dto, version, userID)
+			if err != nil {
+				log.Printf("Failed to update project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			log.Printf("Project updated successfully: %s", projectID)
+			utils.SetETag(w, project.Version)
+			utils.JSONResponse(w, http.StatusOK, project)
+		})
+
+		r.Patch("/{projectID}", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Patch project request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			version, err := utils.RequireIfMatch(r)
+			if err != nil {
+				status := http.StatusBadRequest
+				if errors.Is(err, utils.ErrIfMatchRequired) {
+					status = http.StatusPreconditionRequired
+				}
+				utils.JSONResponse(w, status, err.Error())
+				return
+			}
+
+			patch, err := io.ReadAll(r.Body)
+			if err != nil {
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			project, err := uc.Patch(projectID, patch, version, userID)
+			if err != nil {
+				log.Printf("Failed to patch project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			log.Printf("Project patched successfully: %s", projectID)
+			utils.SetETag(w, project.Version)
+			utils.JSONResponse(w, http.StatusOK, project)
+		})
+
+		r.Post("/{projectID}/archive", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Archive project request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			version, err := utils.RequireIfMatch(r)
+			if err != nil {
+				status := http.StatusBadRequest
+				if errors.Is(err, utils.ErrIfMatchRequired) {
+					status = http.StatusPreconditionRequired
+				}
+				utils.JSONResponse(w, status, err.Error())
+				return
+			}
+
+			project, err := uc.Archive(projectID, version, userID)
+			if err != nil {
+				log.Printf("Failed to archive project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			log.Printf("Project archived successfully: %s", projectID)
+			utils.SetETag(w, project.Version)
+			utils.JSONResponse(w, http.StatusOK, project)
+		})
+
+		r.Post("/{projectID}/unarchive", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Unarchive project request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			version, err := utils.RequireIfMatch(r)
+			if err != nil {
+				status := http.StatusBadRequest
+				if errors.Is(err, utils.ErrIfMatchRequired) {
+					status = http.StatusPreconditionRequired
+				}
+				utils.JSONResponse(w, status, err.Error())
+				return
+			}
+
+			project, err := uc.Unarchive(projectID, version, userID)
+			if err != nil {
+				log.Printf("Failed to unarchive project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			log.Printf("Project unarchived successfully: %s", projectID)
+			utils.SetETag(w, project.Version)
+			utils.JSONResponse(w, http.StatusOK, project)
+		})
+
 		r.Get("/{projectID}/members", func(w http.ResponseWriter, r *http.Request) {
 			projectID := chi.URLParam(r, "projectID")
 			log.Printf("Get project members request received for projectID: %s", projectID)
 
-			members, err := uc.GetMembers(projectID)
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			members, err := uc.GetMembers(projectID, userID)
 			if err != nil {
 				log.Printf("Failed to get project members %s: %v", projectID, err)
-				utils.JSONResponse(w, http.StatusInternalServerError, err.Error())
+				writeProjectError(w, err)
 				return
 			}
 
@@ -100,10 +314,17 @@ func RegisterProjectRoutes(r chi.Router, db *sql.DB) {
 			projectID := chi.URLParam(r, "projectID")
 			log.Printf("Get project tasks request received for projectID: %s", projectID)
 
-			tasks, err := taskUC.GetTasksByProject(projectID)
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			tasks, err := taskUC.GetTasksByProject(projectID, userID)
 			if err != nil {
 				log.Printf("Failed to get project tasks %s: %v", projectID, err)
-				utils.JSONResponse(w, http.StatusInternalServerError, err.Error())
+				writeProjectError(w, err)
 				return
 			}
 
@@ -111,34 +332,834 @@ func RegisterProjectRoutes(r chi.Router, db *sql.DB) {
 			utils.JSONResponse(w, http.StatusOK, tasks)
 		})
 
-		r.Post("/{projectID}/members/{userID}", func(w http.ResponseWriter, r *http.Request) {
+		r.Get("/{projectID}/tasks/summary", func(w http.ResponseWriter, r *http.Request) {
 			projectID := chi.URLParam(r, "projectID")
-			userID := chi.URLParam(r, "userID")
-			log.Printf("Add member request received: projectID=%s, userID=%s", projectID, userID)
+			log.Printf("Get project task summary request received for projectID: %s", projectID)
 
-			if err := uc.AddMember(projectID, userID); err != nil {
-				log.Printf("Failed to add member: %v", err)
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			q, err := taskhandler.ParseTaskSummaryQuery(r)
+			if err != nil {
+				log.Printf("Invalid project task summary query %s: %v", projectID, err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			q.ProjectID = projectID
+			q.VisibleTo = userID
+
+			summary, err := taskUC.SummarizeTasks(r.Context(), q)
+			if err != nil {
+				log.Printf("Failed to summarize project tasks %s: %v", projectID, err)
 				utils.JSONResponse(w, http.StatusInternalServerError, err.Error())
 				return
 			}
 
-			log.Printf("Member added successfully: projectID=%s, userID=%s", projectID, userID)
-			utils.JSONResponse(w, http.StatusOK, map[string]string{"status": "member added"})
+			log.Printf("Project task summary retrieved successfully: %s", projectID)
+			utils.JSONResponse(w, http.StatusOK, summary)
 		})
 
-		r.Delete("/{projectID}", func(w http.ResponseWriter, r *http.Request) {
+		r.Get("/{projectID}/time", func(w http.ResponseWriter, r *http.Request) {
 			projectID := chi.URLParam(r, "projectID")
-			log.Printf("Delete project request received for projectID: %s", projectID)
+			log.Printf("Get project time summary request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
 
-			err := uc.Delete(projectID)
+			q, err := taskhandler.ParseTimeQuery(r)
 			if err != nil {
-				log.Printf("Failed to delete project %s: %v", projectID, err)
-				utils.JSONResponse(w, http.StatusNotFound, "project not found")
+				log.Printf("Invalid project time summary query %s: %v", projectID, err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			if q.UserID == "me" {
+				q.UserID = userID
+			}
+			q.Tasks.ProjectID = projectID
+			q.Tasks.VisibleTo = userID
+
+			summary, err := taskUC.SummarizeTime(r.Context(), q)
+			if err != nil {
+				log.Printf("Failed to summarize project time %s: %v", projectID, err)
+				utils.JSONResponse(w, http.StatusInternalServerError, err.Error())
 				return
 			}
 
-			log.Printf("Project deleted successfully: %s", projectID)
-			utils.JSONResponse(w, http.StatusOK, map[string]string{"message": "project deleted successfully"})
+			log.Printf("Project time summary retrieved successfully: %s (%d minutes)", projectID, summary.TotalMinutes)
+			utils.JSONResponse(w, http.StatusOK, summary)
 		})
-	})
+
+		r.Get("/{projectID}/dependency-graph", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Get dependency graph request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			graph, err := taskUC.GetDependencyGraph(r.Context(), projectID, userID)
+			if err != nil {
+				log.Printf("Failed to get dependency graph %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			log.Printf("Dependency graph retrieved successfully: %s (%d nodes, %d edges)", projectID, len(graph.Nodes), len(graph.Edges))
+			utils.JSONResponse(w, http.StatusOK, graph)
+		})
+
+		r.Get("/{projectID}/timeline", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Get timeline request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if _, err := uc.GetByID(projectID, userID); err != nil {
+				log.Printf("Failed to get project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			timeline, err := taskUC.Timeline(projectID, userID)
+			if err != nil {
+				log.Printf("Failed to get timeline %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			log.Printf("Timeline retrieved successfully: %s (%d bars, %d on critical path)", projectID, len(timeline.Bars), len(timeline.CriticalPath))
+			utils.JSONResponse(w, http.StatusOK, timeline)
+		})
+
+		r.Get("/{projectID}/activity", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Get activity request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if _, err := uc.GetByID(projectID, userID); err != nil {
+				log.Printf("Failed to get project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			activity, err := activityUC.ProjectActivity(projectID, userID, activityhandler.ParseQuery(r))
+			if err != nil {
+				log.Printf("Failed to get activity for project %s: %v", projectID, err)
+				activityhandler.WriteError(w, err)
+				return
+			}
+
+			log.Printf("Activity retrieved successfully: %s (%d entries)", projectID, len(activity.Entries))
+			utils.JSONResponse(w, http.StatusOK, activity)
+		})
+
+		r.Get("/{projectID}/board", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Get board request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if _, err := uc.GetByID(projectID, userID); err != nil {
+				log.Printf("Failed to get project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			board, err := taskUC.GetBoard(projectID, userID)
+			if err != nil {
+				log.Printf("Failed to get board %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			log.Printf("Board retrieved successfully: %s (%d columns)", projectID, len(board.Columns))
+			utils.JSONResponse(w, http.StatusOK, board)
+		})
+
+		r.Put("/{projectID}/board/columns", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Update board columns request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if _, err := uc.GetByID(projectID, userID); err != nil {
+				log.Printf("Failed to get project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			var req taskusecase.BoardColumnsRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode board columns data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			board, err := taskUC.UpdateBoardColumns(projectID, &req, userID)
+			if err != nil {
+				log.Printf("Failed to update board columns for project %s: %v", projectID, err)
+				writeLabelError(w, err)
+				return
+			}
+
+			log.Printf("Board columns updated successfully: %s", projectID)
+			utils.JSONResponse(w, http.StatusOK, board)
+		})
+
+		r.Get("/{projectID}/sprints", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Get sprints request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if _, err := uc.GetByID(projectID, userID); err != nil {
+				log.Printf("Failed to get project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			sprints, err := planningUC.ListSprints(projectID, userID)
+			if err != nil {
+				log.Printf("Failed to get sprints for project %s: %v", projectID, err)
+				planninghandler.WriteError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, sprints)
+		})
+
+		r.Post("/{projectID}/sprints", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Create sprint request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if _, err := uc.GetByID(projectID, userID); err != nil {
+				log.Printf("Failed to get project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			var req planningusecase.SprintRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode sprint data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			sprint, err := planningUC.CreateSprint(projectID, &req, userID)
+			if err != nil {
+				log.Printf("Failed to create sprint for project %s: %v", projectID, err)
+				planninghandler.WriteError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusCreated, sprint)
+		})
+
+		r.Get("/{projectID}/milestones", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Get milestones request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if _, err := uc.GetByID(projectID, userID); err != nil {
+				log.Printf("Failed to get project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			milestones, err := planningUC.ListMilestones(projectID, userID)
+			if err != nil {
+				log.Printf("Failed to get milestones for project %s: %v", projectID, err)
+				planninghandler.WriteError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, milestones)
+		})
+
+		r.Post("/{projectID}/milestones", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Create milestone request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if _, err := uc.GetByID(projectID, userID); err != nil {
+				log.Printf("Failed to get project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			var req planningusecase.MilestoneRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode milestone data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			milestone, err := planningUC.CreateMilestone(projectID, &req, userID)
+			if err != nil {
+				log.Printf("Failed to create milestone for project %s: %v", projectID, err)
+				planninghandler.WriteError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusCreated, milestone)
+		})
+
+		// レポート: burndown / burnup / cumulative-flow / throughput / cycle-time / velocity
+		// format=csv（または Accept: text/csv）で CSV を返す
+		r.Get("/{projectID}/reports/{report}", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			name := chi.URLParam(r, "report")
+			log.Printf("Get %s report request received for projectID: %s", name, projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if _, err := uc.GetByID(projectID, userID); err != nil {
+				log.Printf("Failed to get project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			report, err := reportUC.Report(projectID, name, reporthandler.ParseQuery(r), userID)
+			if err != nil {
+				log.Printf("Failed to get %s report for project %s: %v", name, projectID, err)
+				reporthandler.WriteError(w, err)
+				return
+			}
+
+			reporthandler.WriteReport(w, r, name, report)
+		})
+
+		r.Get("/{projectID}/labels", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Get labels request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if _, err := uc.GetByID(projectID, userID); err != nil {
+				log.Printf("Failed to get project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			labels, err := taskUC.ListLabels(projectID, userID)
+			if err != nil {
+				log.Printf("Failed to get labels for project %s: %v", projectID, err)
+				writeLabelError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, labels)
+		})
+
+		r.Post("/{projectID}/labels", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Create label request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if _, err := uc.GetByID(projectID, userID); err != nil {
+				log.Printf("Failed to get project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			var req taskusecase.LabelRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode label data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			label, err := taskUC.CreateLabel(projectID, &req, userID)
+			if err != nil {
+				log.Printf("Failed to create label for project %s: %v", projectID, err)
+				writeLabelError(w, err)
+				return
+			}
+
+			log.Printf("Label created successfully with ID: %s", label.ID)
+			utils.JSONResponse(w, http.StatusCreated, label)
+		})
+
+		r.Patch("/{projectID}/labels/{labelID}", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			labelID := chi.URLParam(r, "labelID")
+			log.Printf("Update label request received: projectID=%s, labelID=%s", projectID, labelID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			var req taskusecase.LabelRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode label data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			label, err := taskUC.UpdateLabel(projectID, labelID, &req, userID)
+			if err != nil {
+				log.Printf("Failed to update label %s: %v", labelID, err)
+				writeLabelError(w, err)
+				return
+			}
+
+			log.Printf("Label updated successfully: %s", labelID)
+			utils.JSONResponse(w, http.StatusOK, label)
+		})
+
+		r.Delete("/{projectID}/labels/{labelID}", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			labelID := chi.URLParam(r, "labelID")
+			log.Printf("Delete label request received: projectID=%s, labelID=%s", projectID, labelID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if err := taskUC.DeleteLabel(projectID, labelID, userID); err != nil {
+				log.Printf("Failed to delete label %s: %v", labelID, err)
+				writeLabelError(w, err)
+				return
+			}
+
+			log.Printf("Label deleted successfully: %s", labelID)
+			utils.JSONResponse(w, http.StatusOK, map[string]string{"message": "label deleted successfully"})
+		})
+
+		r.Get("/{projectID}/custom-fields", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Get custom fields request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if _, err := uc.GetByID(projectID, userID); err != nil {
+				log.Printf("Failed to get project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			fields, err := taskUC.ListCustomFields(projectID, userID)
+			if err != nil {
+				log.Printf("Failed to get custom fields for project %s: %v", projectID, err)
+				writeCustomFieldError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, fields)
+		})
+
+		r.Post("/{projectID}/custom-fields", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Create custom field request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if _, err := uc.GetByID(projectID, userID); err != nil {
+				log.Printf("Failed to get project %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			var req taskusecase.CustomFieldRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode custom field data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			field, err := taskUC.CreateCustomField(projectID, &req, userID)
+			if err != nil {
+				log.Printf("Failed to create custom field for project %s: %v", projectID, err)
+				writeCustomFieldError(w, err)
+				return
+			}
+
+			log.Printf("Custom field created successfully with ID: %s", field.ID)
+			utils.JSONResponse(w, http.StatusCreated, field)
+		})
+
+		r.Patch("/{projectID}/custom-fields/{fieldID}", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			fieldID := chi.URLParam(r, "fieldID")
+			log.Printf("Update custom field request received: projectID=%s, fieldID=%s", projectID, fieldID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			var req taskusecase.CustomFieldRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode custom field data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			field, err := taskUC.UpdateCustomField(projectID, fieldID, &req, userID)
+			if err != nil {
+				log.Printf("Failed to update custom field %s: %v", fieldID, err)
+				writeCustomFieldError(w, err)
+				return
+			}
+
+			log.Printf("Custom field updated successfully: %s", fieldID)
+			utils.JSONResponse(w, http.StatusOK, field)
+		})
+
+		r.Delete("/{projectID}/custom-fields/{fieldID}", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			fieldID := chi.URLParam(r, "fieldID")
+			log.Printf("Delete custom field request received: projectID=%s, fieldID=%s", projectID, fieldID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			if err := taskUC.DeleteCustomField(projectID, fieldID, userID); err != nil {
+				log.Printf("Failed to delete custom field %s: %v", fieldID, err)
+				writeCustomFieldError(w, err)
+				return
+			}
+
+			log.Printf("Custom field deleted successfully: %s", fieldID)
+			utils.JSONResponse(w, http.StatusOK, map[string]string{"message": "custom field deleted successfully"})
+		})
+
+		// ボディの role を省略した場合は member として追加する
+		r.Post("/{projectID}/members/{userID}", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			userID := chi.URLParam(r, "userID")
+			log.Printf("Add member request received: projectID=%s, userID=%s", projectID, userID)
+
+			actorID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			var req usecase.MemberRoleRequest
+			if err := utils.DecodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+				log.Printf("Failed to decode member role: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			if err := uc.AddMember(projectID, userID, req.Role, actorID); err != nil {
+				log.Printf("Failed to add member: %v", err)
+				writeProjectError(w, err)
+				return
+			}
+
+			log.Printf("Member added successfully: projectID=%s, userID=%s", projectID, userID)
+			utils.JSONResponse(w, http.StatusOK, map[string]string{"status": "member added"})
+		})
+
+		r.Patch("/{projectID}/members/{userID}", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			userID := chi.URLParam(r, "userID")
+			log.Printf("Update member role request received: projectID=%s, userID=%s", projectID, userID)
+
+			actorID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			var req usecase.MemberRoleRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode member role: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			member, err := uc.UpdateMemberRole(projectID, userID, req.Role, actorID)
+			if err != nil {
+				log.Printf("Failed to update member role: %v", err)
+				if errors.Is(err, apperrors.ErrNotFound) {
+					utils.JSONResponse(w, http.StatusNotFound, "member not found")
+					return
+				}
+				writeProjectError(w, err)
+				return
+			}
+
+			log.Printf("Member role updated successfully: projectID=%s, userID=%s, role=%s", projectID, userID, member.Role)
+			utils.JSONResponse(w, http.StatusOK, member)
+		})
+
+		r.Delete("/{projectID}/members/{userID}", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			userID := chi.URLParam(r, "userID")
+			log.Printf("Remove member request received: projectID=%s, userID=%s", projectID, userID)
+
+			actorID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			result, err := uc.RemoveMember(r.Context(), projectID, userID, r.URL.Query().Get("reassign_to"), actorID)
+			if err != nil {
+				log.Printf("Failed to remove member: %v", err)
+				if errors.Is(err, apperrors.ErrNotFound) {
+					utils.JSONResponse(w, http.StatusNotFound, "member not found")
+					return
+				}
+				writeProjectError(w, err)
+				return
+			}
+
+			log.Printf("Member removed successfully: projectID=%s, userID=%s (%d tasks reassigned to %s)", projectID, userID, result.ReassignedTasks, result.ReassignedTo)
+			utils.JSONResponse(w, http.StatusOK, result)
+		})
+
+		r.Delete("/{projectID}", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Delete project request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			version, err := utils.RequireIfMatch(r)
+			if err != nil {
+				status := http.StatusBadRequest
+				if errors.Is(err, utils.ErrIfMatchRequired) {
+					status = http.StatusPreconditionRequired
+				}
+				utils.JSONResponse(w, status, err.Error())
+				return
+			}
+
+			err = uc.Delete(projectID, version, userID)
+			if err != nil {
+				log.Printf("Failed to delete project %s: %v", projectID, err)
+				if errors.Is(err, apperrors.ErrVersionMismatch) || errors.Is(err, apperrors.ErrForbidden) {
+					writeProjectError(w, err)
+					return
+				}
+				utils.JSONResponse(w, http.StatusNotFound, "project not found")
+				return
+			}
+
+			log.Printf("Project moved to trash: %s", projectID)
+			utils.JSONResponse(w, http.StatusOK, map[string]string{
+				"message":     "project deleted successfully",
+				"restore_url": "/projects/" + projectID + "/restore",
+			})
+		})
+
+		r.Patch("/{projectID}/settings", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Update project settings request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			version, err := utils.RequireIfMatch(r)
+			if err != nil {
+				status := http.StatusBadRequest
+				if errors.Is(err, utils.ErrIfMatchRequired) {
+					status = http.StatusPreconditionRequired
+				}
+				utils.JSONResponse(w, status, err.Error())
+				return
+			}
+
+			var dto usecase.ProjectSettingsDTO
+			if err := utils.DecodeJSON(r, &dto); err != nil {
+				log.Printf("Failed to decode project settings: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			project, err := uc.UpdateSettings(projectID, &dto, version, userID)
+			if err != nil {
+				log.Printf("Failed to update project settings %s: %v", projectID, err)
+				writeProjectError(w, err)
+				return
+			}
+
+			log.Printf("Project settings updated successfully: %s", projectID)
+			utils.SetETag(w, project.Version)
+			utils.JSONResponse(w, http.StatusOK, project)
+		})
+
+		r.Post("/{projectID}/restore", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Restore project request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+				return
+			}
+
+			project, err := uc.Restore(projectID, userID)
+			if err != nil {
+				log.Printf("Failed to restore project %s: %v", projectID, err)
+				if errors.Is(err, apperrors.ErrForbidden) {
+					writeProjectError(w, err)
+					return
+				}
+				utils.JSONResponse(w, http.StatusNotFound, "project not found in trash")
+				return
+			}
+
+			log.Printf("Project restored successfully: %s", projectID)
+			utils.SetETag(w, project.Version)
+			utils.JSONResponse(w, http.StatusOK, project)
+		})
+	})
+}
+
+// writeProjectError はプロジェクト操作のエラーを HTTP ステータスに変換して返します
+func writeProjectError(w http.ResponseWriter, err error) {
+	var verr *apperrors.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		utils.JSONResponse(w, http.StatusBadRequest, map[string]interface{}{"error": apperrors.ErrInvalidInput.Error(), "fields": verr.Fields})
+	case errors.Is(err, apperrors.ErrInvalidInput):
+		utils.JSONResponse(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, domain.ErrArchived), errors.Is(err, domain.ErrLastOwner), errors.Is(err, domain.ErrAlreadyMember):
+		utils.JSONResponse(w, http.StatusConflict, err.Error())
+	case errors.Is(err, apperrors.ErrForbidden):
+		utils.JSONResponse(w, http.StatusForbidden, err.Error())
+	case errors.Is(err, apperrors.ErrVersionMismatch):
+		utils.JSONResponse(w, http.StatusPreconditionFailed, err.Error())
+	case errors.Is(err, apperrors.ErrNotFound):
+		utils.JSONResponse(w, http.StatusNotFound, "project not found")
+	default:
+		utils.JSONResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// writeLabelError はラベル操作のエラーを HTTP ステータスに変換して返します
+func writeLabelError(w http.ResponseWriter, err error) {
+	var verr *apperrors.ValidationError
+	switch {
+	case errors.Is(err, taskdomain.ErrProjectArchived):
+		utils.JSONResponse(w, http.StatusConflict, err.Error())
+	case errors.Is(err, apperrors.ErrForbidden):
+		utils.JSONResponse(w, http.StatusForbidden, err.Error())
+	case errors.As(err, &verr):
+		utils.JSONResponse(w, http.StatusBadRequest, map[string]interface{}{"error": apperrors.ErrInvalidInput.Error(), "fields": verr.Fields})
+	case errors.Is(err, apperrors.ErrNotFound):
+		utils.JSONResponse(w, http.StatusNotFound, "label not found")
+	default:
+		utils.JSONResponse(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// writeCustomFieldError はカスタムフィールドのエラーを HTTP ステータスに変換して返します
+func writeCustomFieldError(w http.ResponseWriter, err error) {
+	if errors.Is(err, apperrors.ErrNotFound) {
+		utils.JSONResponse(w, http.StatusNotFound, "custom field not found")
+		return
+	}
+	writeLabelError(w, err)
 }
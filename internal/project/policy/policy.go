@@ -0,0 +1,41 @@
+// Package policy はプロジェクトのロールに基づいて操作を許可するかを判定します
+// プロジェクト・タスク・サブタスク・コメントのユースケースから参照されます
+package policy
+
+import (
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/project/domain"
+)
+
+// RoleSource はユーザーのプロジェクト内のロールを返します。メンバーでない場合は空文字列を返します
+type RoleSource interface {
+	MemberRole(projectID, userID string) (string, error)
+}
+
+type Policy struct {
+	roles RoleSource
+}
+
+func NewPolicy(roles RoleSource) *Policy {
+	return &Policy{roles: roles}
+}
+
+// Role は userID のプロジェクト内のロールを返します
+func (p *Policy) Role(projectID, userID string) (string, error) {
+	if projectID == "" || userID == "" {
+		return "", nil
+	}
+	return p.roles.MemberRole(projectID, userID)
+}
+
+// Authorize は userID が projectID で perm を行えない場合に ErrForbidden を返します
+func (p *Policy) Authorize(projectID, userID string, perm domain.Permission) error {
+	role, err := p.Role(projectID, userID)
+	if err != nil {
+		return err
+	}
+	if !domain.RoleAllows(role, perm) {
+		return apperrors.ErrForbidden
+	}
+	return nil
+}
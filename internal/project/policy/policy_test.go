@@ -0,0 +1,63 @@
+package policy
+
+import (
+	"testing"
+
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/project/domain"
+)
+
+type roles map[string]string
+
+func (r roles) MemberRole(projectID, userID string) (string, error) {
+	return r[userID], nil
+}
+
+func TestAuthorize(t *testing.T) {
+	p := NewPolicy(roles{
+		"owner":      domain.RoleOwner,
+		"maintainer": domain.RoleMaintainer,
+		"member":     domain.RoleMember,
+		"viewer":     domain.RoleViewer,
+	})
+	perms := []struct {
+		name string
+		perm domain.Permission
+		// allowed は許可されるユーザー（ロール）です。それ以外と非メンバーは ErrForbidden になります
+		allowed []string
+	}{
+		{"view", domain.PermView, []string{"owner", "maintainer", "member", "viewer"}},
+		{"comment", domain.PermComment, []string{"owner", "maintainer", "member"}},
+		{"edit tasks", domain.PermEditTasks, []string{"owner", "maintainer", "member"}},
+		{"manage project", domain.PermManageProject, []string{"owner", "maintainer"}},
+		{"manage members", domain.PermManageMembers, []string{"owner", "maintainer"}},
+		{"delete project", domain.PermDeleteProject, []string{"owner"}},
+	}
+	for _, pp := range perms {
+		allowed := map[string]bool{}
+		for _, user := range pp.allowed {
+			allowed[user] = true
+		}
+		for _, user := range []string{"owner", "maintainer", "member", "viewer", "stranger"} {
+			t.Run(pp.name+"/"+user, func(t *testing.T) {
+				err := p.Authorize("p1", user, pp.perm)
+				if allowed[user] && err != nil {
+					t.Errorf("Authorize() error = %v, want nil", err)
+				}
+				if !allowed[user] && err != apperrors.ErrForbidden {
+					t.Errorf("Authorize() error = %v, want ErrForbidden", err)
+				}
+			})
+		}
+	}
+}
+
+func TestAuthorizeWithoutProjectOrUser(t *testing.T) {
+	p := NewPolicy(roles{"owner": domain.RoleOwner})
+	if err := p.Authorize("", "owner", domain.PermView); err != apperrors.ErrForbidden {
+		t.Errorf("Authorize() without a project error = %v, want ErrForbidden", err)
+	}
+	if err := p.Authorize("p1", "", domain.PermView); err != apperrors.ErrForbidden {
+		t.Errorf("Authorize() without a user error = %v, want ErrForbidden", err)
+	}
+}
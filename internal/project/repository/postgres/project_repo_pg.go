@@ -1,11 +1,18 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
+	activitydomain "todo-app/internal/activity/domain"
+	activitypostgres "todo-app/internal/activity/repository/postgres"
+	apperrors "todo-app/internal/common/errors"
 	"todo-app/internal/project/domain"
 	"todo-app/internal/project/repository"
-	userdomain "todo-app/internal/user/domain"
+	taskpostgres "todo-app/internal/task/repository/postgres"
+
+	"github.com/lib/pq"
 )
 
 type projectRepoPg struct {
@@ -16,22 +23,53 @@ func NewProjectRepoPg(db *sql.DB) repository.ProjectRepository {
 	return &projectRepoPg{db: db}
 }
 
-func (r *projectRepoPg) Create(project *domain.Project) error {
+// Create はプロジェクトを作成し、作成者を owner としてメンバーに追加します
+func (r *projectRepoPg) Create(project *domain.Project, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
-        INSERT INTO projects (id, name, description, start_date, end_date, created_by, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())
+        INSERT INTO projects (id, name, description, start_date, end_date, created_by, created_at, updated_at, version, auto_complete_tasks, status)
+        VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW(), $7, $8, $9)
     `
-	_, err := r.db.Exec(query, project.ID, project.Name, project.Description, project.StartDate, project.EndDate, project.CreatedBy)
-	return err
+	if _, err := tx.Exec(query, project.ID, project.Name, project.Description, project.StartDate, project.EndDate, project.CreatedBy, project.Version, project.AutoCompleteTasks, project.Status); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`INSERT INTO project_members (project_id, user_id, role) VALUES ($1, $2, $3)`, project.ID, project.CreatedBy, domain.RoleOwner); err != nil {
+		return err
+	}
+	if err := activitypostgres.Append(tx, activity); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *projectRepoPg) GetAll() ([]*domain.Project, error) {
 	query := `
-        SELECT id, name, description, start_date, end_date, created_by, created_at, updated_at
+        SELECT id, name, description, start_date, end_date, created_by, created_at, updated_at, version, auto_complete_tasks, status
         FROM projects
+        WHERE deleted_at IS NULL
         ORDER BY created_at DESC
     `
-	rows, err := r.db.Query(query)
+	return r.queryProjects(query)
+}
+
+func (r *projectRepoPg) ListByMember(userID string) ([]*domain.Project, error) {
+	query := `
+        SELECT id, name, description, start_date, end_date, created_by, created_at, updated_at, version, auto_complete_tasks, status
+        FROM projects p
+        WHERE deleted_at IS NULL
+          AND EXISTS (SELECT 1 FROM project_members pm WHERE pm.project_id = p.id AND pm.user_id = $1)
+        ORDER BY created_at DESC
+    `
+	return r.queryProjects(query, userID)
+}
+
+func (r *projectRepoPg) queryProjects(query string, args ...interface{}) ([]*domain.Project, error) {
+	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -40,7 +78,7 @@ func (r *projectRepoPg) GetAll() ([]*domain.Project, error) {
 	var projects []*domain.Project
 	for rows.Next() {
 		project := &domain.Project{}
-		err := rows.Scan(&project.ID, &project.Name, &project.Description, &project.StartDate, &project.EndDate, &project.CreatedBy, &project.CreatedAt, &project.UpdatedAt)
+		err := rows.Scan(&project.ID, &project.Name, &project.Description, &project.StartDate, &project.EndDate, &project.CreatedBy, &project.CreatedAt, &project.UpdatedAt, &project.Version, &project.AutoCompleteTasks, &project.Status)
 		if err != nil {
 			return nil, err
 		}
@@ -51,12 +89,12 @@ func (r *projectRepoPg) GetAll() ([]*domain.Project, error) {
 
 func (r *projectRepoPg) GetByID(id string) (*domain.Project, error) {
 	query := `
-        SELECT id, name, description, start_date, end_date, created_by, created_at, updated_at
+        SELECT id, name, description, start_date, end_date, created_by, created_at, updated_at, version, auto_complete_tasks, status
         FROM projects
-        WHERE id = $1
+        WHERE id = $1 AND deleted_at IS NULL
     `
 	project := &domain.Project{}
-	err := r.db.QueryRow(query, id).Scan(&project.ID, &project.Name, &project.Description, &project.StartDate, &project.EndDate, &project.CreatedBy, &project.CreatedAt, &project.UpdatedAt)
+	err := r.db.QueryRow(query, id).Scan(&project.ID, &project.Name, &project.Description, &project.StartDate, &project.EndDate, &project.CreatedBy, &project.CreatedAt, &project.UpdatedAt, &project.Version, &project.AutoCompleteTasks, &project.Status)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("project not found")
@@ -70,11 +108,10 @@ func (r *projectRepoPg) FindByID(id string) (*domain.Project, error) {
 	return r.GetByID(id)
 }
 
-func (r *projectRepoPg) GetMembers(projectID string) ([]*userdomain.User, error) {
+func (r *projectRepoPg) GetMembers(projectID string) ([]*domain.Member, error) {
 	query := `
-        SELECT u.id, u.name, u.email, u.role_id, r.name as role_name, u.timezone, u.language, u.created_at, u.updated_at
+        SELECT u.id, u.name, u.email, pm.role
         FROM users u
-        LEFT JOIN roles r ON u.role_id = r.id
         INNER JOIN project_members pm ON u.id = pm.user_id
         WHERE pm.project_id = $1
         ORDER BY u.name
@@ -85,26 +122,69 @@ func (r *projectRepoPg) GetMembers(projectID string) ([]*userdomain.User, error)
 	}
 	defer rows.Close()
 
-	var users []*userdomain.User
+	var members []*domain.Member
 	for rows.Next() {
-		user := &userdomain.User{}
-		err := rows.Scan(&user.ID, &user.Name, &user.Email, &user.RoleID, &user.RoleName, &user.Timezone, &user.Language, &user.CreatedAt, &user.UpdatedAt)
-		if err != nil {
+		member := &domain.Member{}
+		if err := rows.Scan(&member.ID, &member.Name, &member.Email, &member.Role); err != nil {
 			return nil, err
 		}
-		users = append(users, user)
+		members = append(members, member)
 	}
-	return users, nil
+	return members, nil
 }
 
-func (r *projectRepoPg) Update(project *domain.Project) error {
-	// TODO: UPDATE
+// Update は project.Version が保存済みのバージョンと一致する場合のみ更新し、バージョンを 1 つ進めます
+func (r *projectRepoPg) Update(project *domain.Project, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE projects
+        SET name = $2, description = $3, start_date = $4, end_date = $5, updated_at = $6, version = version + 1, auto_complete_tasks = $8, status = $9
+        WHERE id = $1 AND version = $7 AND deleted_at IS NULL
+    `
+	result, err := tx.Exec(query, project.ID, project.Name, project.Description, project.StartDate, project.EndDate, project.UpdatedAt, project.Version, project.AutoCompleteTasks, project.Status)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return r.missingOrStale(project.ID)
+	}
+	if err := activitypostgres.Append(tx, activity); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	project.Version++
 	return nil
 }
 
-func (r *projectRepoPg) Delete(id string) error {
-	query := `DELETE FROM projects WHERE id = $1`
-	result, err := r.db.Exec(query, id)
+// Delete はプロジェクトをゴミ箱に移動（論理削除）します
+// version が 0 の場合はバージョンを問わず、それ以外は一致する場合のみ削除します
+func (r *projectRepoPg) Delete(id string, version int, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE projects
+        SET deleted_at = NOW(), version = version + 1
+        WHERE id = $1 AND ($2 = 0 OR version = $2) AND deleted_at IS NULL
+    `
+	result, err := tx.Exec(query, id, version)
 	if err != nil {
 		return err
 	}
@@ -115,18 +195,217 @@ func (r *projectRepoPg) Delete(id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("project not found")
+		return r.missingOrStale(id)
+	}
+	if err := activitypostgres.Append(tx, activity); err != nil {
+		return err
 	}
+	return tx.Commit()
+}
 
-	return nil
+// missingOrStale は更新・削除が 0 件だった理由（存在しない／バージョン不一致）を判定します
+func (r *projectRepoPg) missingOrStale(id string) error {
+	var exists bool
+	if err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM projects WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return apperrors.ErrVersionMismatch
+	}
+	return fmt.Errorf("project not found")
 }
 
-func (r *projectRepoPg) AddMember(projectID, userID string) error {
-	// TODO: INSERT into project_members
-	return nil
+// Restore はゴミ箱内のプロジェクトを元に戻します
+func (r *projectRepoPg) Restore(id string, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE projects
+        SET deleted_at = NULL, version = version + 1
+        WHERE id = $1 AND deleted_at IS NOT NULL
+    `
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("project not found in trash")
+	}
+	if err := activitypostgres.Append(tx, activity); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (r *projectRepoPg) RemoveMember(projectID, userID string) error {
-	// TODO: DELETE from project_members
-	return nil
+// ListDeleted はゴミ箱内のプロジェクトのうち userID が roles のいずれかのロールを持つものを削除日時の新しい順に返します
+func (r *projectRepoPg) ListDeleted(userID string, roles []string) ([]*domain.Project, error) {
+	query := `
+        SELECT id, name, description, start_date, end_date, created_by, created_at, updated_at, version, deleted_at
+        FROM projects p
+        WHERE deleted_at IS NOT NULL
+          AND EXISTS (SELECT 1 FROM project_members pm WHERE pm.project_id = p.id AND pm.user_id = $1 AND pm.role = ANY($2))
+        ORDER BY deleted_at DESC
+    `
+	rows, err := r.db.Query(query, userID, pq.Array(roles))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var projects []*domain.Project
+	for rows.Next() {
+		project := &domain.Project{}
+		err := rows.Scan(&project.ID, &project.Name, &project.Description, &project.StartDate, &project.EndDate, &project.CreatedBy, &project.CreatedAt, &project.UpdatedAt, &project.Version, &project.DeletedAt)
+		if err != nil {
+			return nil, err
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// PurgeDeletedBefore は cutoff より前にゴミ箱に入ったプロジェクトを物理削除し、削除した ID を返します
+func (r *projectRepoPg) PurgeDeletedBefore(cutoff time.Time) ([]string, error) {
+	rows, err := r.db.Query(`DELETE FROM projects WHERE deleted_at < $1 RETURNING id`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// PurgeDeleted はゴミ箱内のプロジェクトをタスクとともに物理削除し、削除したタスクの ID を返します
+func (r *projectRepoPg) PurgeDeleted(id string) ([]string, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var exists bool
+	if err := tx.QueryRow(`SELECT EXISTS (SELECT 1 FROM projects WHERE id = $1 AND deleted_at IS NOT NULL)`, id).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("project not found in trash")
+	}
+
+	// Solr から削除するため、CASCADE に任せずタスクの ID を返す
+	rows, err := tx.Query(`DELETE FROM tasks WHERE project_id = $1 RETURNING id`, id)
+	if err != nil {
+		return nil, err
+	}
+	var taskIDs []string
+	for rows.Next() {
+		var taskID string
+		if err := rows.Scan(&taskID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		taskIDs = append(taskIDs, taskID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`DELETE FROM projects WHERE id = $1`, id); err != nil {
+		return nil, err
+	}
+	return taskIDs, tx.Commit()
+}
+
+// AddMember はメンバーを追加します。既にメンバーの場合はロールを変えずに ErrAlreadyMember を返します
+func (r *projectRepoPg) AddMember(projectID, userID, role string, activity *activitydomain.Entry) error {
+	return r.changeMember(activity, domain.ErrAlreadyMember,
+		`INSERT INTO project_members (project_id, user_id, role) VALUES ($1, $2, $3) ON CONFLICT (project_id, user_id) DO NOTHING`, projectID, userID, role)
+}
+
+func (r *projectRepoPg) UpdateMemberRole(projectID, userID, role string, activity *activitydomain.Entry) error {
+	return r.changeMember(activity, apperrors.ErrNotFound,
+		`UPDATE project_members SET role = $3 WHERE project_id = $1 AND user_id = $2`, projectID, userID, role)
+}
+
+func (r *projectRepoPg) RemoveMember(ctx context.Context, projectID, userID, reassignTo string, activity *activitydomain.Entry, taskActivity func(taskID string) *activitydomain.Entry) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM project_members WHERE project_id = $1 AND user_id = $2`, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, apperrors.ErrNotFound
+	}
+	if err := activitypostgres.Append(tx, activity); err != nil {
+		return nil, err
+	}
+	ids, err := taskpostgres.ReassignOpen(ctx, tx, projectID, userID, reassignTo, taskActivity)
+	if err != nil {
+		return nil, err
+	}
+	return ids, tx.Commit()
+}
+
+// MemberRole はメンバーのロールを返します。ゴミ箱内のプロジェクトも対象にします（復元の権限判定のため）
+func (r *projectRepoPg) MemberRole(projectID, userID string) (string, error) {
+	var role string
+	err := r.db.QueryRow(`SELECT role FROM project_members WHERE project_id = $1 AND user_id = $2`, projectID, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return role, err
+}
+
+// changeMember はメンバーの追加・更新・削除を行い、activity を同じトランザクションで変更履歴に追記します
+// 対象が 0 件の場合は unaffected を返します
+func (r *projectRepoPg) changeMember(activity *activitydomain.Entry, unaffected error, query string, args ...interface{}) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return unaffected
+	}
+	if err := activitypostgres.Append(tx, activity); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
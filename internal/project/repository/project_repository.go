@@ -1,18 +1,37 @@
 package repository
 
 import (
+	"context"
+	"time"
+
+	activitydomain "todo-app/internal/activity/domain"
 	"todo-app/internal/project/domain"
-	userdomain "todo-app/internal/user/domain"
 )
 
+// 書き込みのメソッドは activity（nil の場合は記録しない）を変更と同じトランザクションで変更履歴に追記します
 type ProjectRepository interface {
-	Create(project *domain.Project) error
+	Create(project *domain.Project, activity *activitydomain.Entry) error
 	GetAll() ([]*domain.Project, error)
+	// ListByMember は userID がメンバーのプロジェクトを返します
+	ListByMember(userID string) ([]*domain.Project, error)
 	GetByID(id string) (*domain.Project, error)
 	FindByID(id string) (*domain.Project, error)
-	Update(project *domain.Project) error
-	Delete(id string) error
-	GetMembers(projectID string) ([]*userdomain.User, error)
-	AddMember(projectID, userID string) error
-	RemoveMember(projectID, userID string) error
+	Update(project *domain.Project, activity *activitydomain.Entry) error
+	Delete(id string, version int, activity *activitydomain.Entry) error
+	GetMembers(projectID string) ([]*domain.Member, error)
+	// AddMember はメンバーを追加します。既にメンバーの場合は domain.ErrAlreadyMember を返します
+	AddMember(projectID, userID, role string, activity *activitydomain.Entry) error
+	// UpdateMemberRole はメンバーのロールを変更します。メンバーでない場合は apperrors.ErrNotFound を返します
+	UpdateMemberRole(projectID, userID, role string, activity *activitydomain.Entry) error
+	// RemoveMember はメンバーを外し、そのメンバーが担当している未完了のタスクを reassignTo に割り当て直して、タスクの ID を返します
+	// 割り当て直しとタスクごとの変更履歴 taskActivity(id) も同じトランザクションで行います。メンバーでない場合は apperrors.ErrNotFound を返します
+	RemoveMember(ctx context.Context, projectID, userID, reassignTo string, activity *activitydomain.Entry, taskActivity func(taskID string) *activitydomain.Entry) ([]string, error)
+	// MemberRole は userID のプロジェクト内のロールを返します。メンバーでない場合は空文字列です
+	MemberRole(projectID, userID string) (string, error)
+	Restore(id string, activity *activitydomain.Entry) error
+	// ListDeleted はゴミ箱内のプロジェクトのうち、userID が roles のいずれかのロールを持つものを返します
+	ListDeleted(userID string, roles []string) ([]*domain.Project, error)
+	PurgeDeletedBefore(cutoff time.Time) ([]string, error)
+	// PurgeDeleted はゴミ箱内のプロジェクトをタスクとともに物理削除し、削除したタスクの ID を返します
+	PurgeDeleted(id string) ([]string, error)
 }
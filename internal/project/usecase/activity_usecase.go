@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	activitydomain "todo-app/internal/activity/domain"
+	"todo-app/internal/project/domain"
+)
+
+// ActivityRecorder は変更履歴に ID と日時を設定します。更新で変更がなかった場合は nil を返します
+// 変更履歴はリポジトリが変更と同じトランザクションで追記します
+type ActivityRecorder interface {
+	Prepare(entry *activitydomain.Entry) *activitydomain.Entry
+}
+
+// projectActivityFields は変更履歴に記録するプロジェクトのフィールドです（記録する順）
+var projectActivityFields = []string{"name", "description", "start_date", "end_date", "auto_complete_tasks", "status"}
+
+// projectValues は変更履歴に記録するプロジェクトのフィールドの値を返します
+// プロジェクトを変更する前に呼び出し、変更後の値と比べます
+func projectValues(project *domain.Project) map[string]interface{} {
+	if project == nil {
+		return map[string]interface{}{}
+	}
+	values := map[string]interface{}{
+		"name":                project.Name,
+		"description":         project.Description,
+		"start_date":          activitydomain.Time(project.StartDate),
+		"end_date":            activitydomain.Time(project.EndDate),
+		"auto_complete_tasks": project.AutoCompleteTasks,
+		"status":              project.Status,
+	}
+	if project.Description == "" {
+		values["description"] = nil
+	}
+	return values
+}
+
+// projectEntry はプロジェクトの変更履歴を返します。before は変更前の projectValues（作成時は nil）です
+// 削除・復元は変更を記録しません
+func (uc *ProjectUseCase) projectEntry(before map[string]interface{}, project *domain.Project, action, actorID string) *activitydomain.Entry {
+	entry := activitydomain.NewEntry(activitydomain.EntityProject, project.ID, action, actorID)
+	entry.ProjectID = project.ID
+	if action == activitydomain.ActionCreated || action == activitydomain.ActionUpdated {
+		after := projectValues(project)
+		for _, field := range projectActivityFields {
+			entry.Add(field, before[field], after[field])
+		}
+	}
+	return uc.prepare(entry)
+}
+
+// memberEntry はメンバーの追加・ロールの変更・削除をプロジェクトの変更として返します。oldRole / newRole が空の場合は追加・削除です
+func (uc *ProjectUseCase) memberEntry(projectID, userID, oldRole, newRole, actorID string) *activitydomain.Entry {
+	entry := activitydomain.NewEntry(activitydomain.EntityProject, projectID, activitydomain.ActionUpdated, actorID)
+	entry.ProjectID = projectID
+	entry.Add("member", memberValue(userID, oldRole), memberValue(userID, newRole))
+	return uc.prepare(entry)
+}
+
+// reassignEntry はメンバーを外したときに割り当て直したタスクの変更履歴を返す関数を返します。toUserID が空の場合は未割り当てです
+func (uc *ProjectUseCase) reassignEntry(projectID, fromUserID, toUserID, actorID string) func(taskID string) *activitydomain.Entry {
+	return func(taskID string) *activitydomain.Entry {
+		entry := activitydomain.NewEntry(activitydomain.EntityTask, taskID, activitydomain.ActionUpdated, actorID)
+		entry.TaskID, entry.ProjectID = taskID, projectID
+		var to interface{}
+		if toUserID != "" {
+			to = toUserID
+		}
+		entry.Add("assignee_id", fromUserID, to)
+		return uc.prepare(entry)
+	}
+}
+
+func memberValue(userID, role string) interface{} {
+	if role == "" {
+		return nil
+	}
+	return map[string]string{"user_id": userID, "role": role}
+}
+
+// prepare は変更履歴を記録できる状態にして返します。記録しない場合は nil です
+func (uc *ProjectUseCase) prepare(entry *activitydomain.Entry) *activitydomain.Entry {
+	if uc.activity == nil {
+		return nil
+	}
+	return uc.activity.Prepare(entry)
+}
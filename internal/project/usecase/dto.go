@@ -12,12 +12,39 @@ type ProjectDTO struct {
     StartDate   time.Time `json:"start_date"`
     EndDate     time.Time `json:"end_date"`
     CreatedBy   string    `json:"created_by"`
+    Version     int       `json:"version"`
+    AutoCompleteTasks bool `json:"auto_complete_tasks"`
+    // Status は active または archived です（読み取り専用。変更は /archive と /unarchive で行います）
+    Status      string    `json:"status"`
+}
+
+// StatusFilterAll は GET /projects?status=all でアーカイブ済みを含むすべてのプロジェクトを返す指定です
+const StatusFilterAll = "all"
+
+// ProjectSettingsDTO は PATCH /projects/{projectID}/settings のリクエストボディです
+// 指定された設定のみ更新します
+type ProjectSettingsDTO struct {
+    AutoCompleteTasks *bool `json:"auto_complete_tasks"`
+}
+
+// MemberRemovalDTO は DELETE /projects/{projectID}/members/{userID} のレスポンスです
+type MemberRemovalDTO struct {
+    UserID          string `json:"user_id"`
+    ReassignedTo    string `json:"reassigned_to"`
+    ReassignedTasks int    `json:"reassigned_tasks"`
 }
 
 type MemberDTO struct {
     ID    string `json:"id"`
     Name  string `json:"name"`
     Email string `json:"email"`
+    // Role は owner・maintainer・member・viewer のいずれかです
+    Role  string `json:"role"`
+}
+
+// MemberRoleRequest は POST・PATCH /projects/{projectID}/members/{userID} のリクエストボディです
+type MemberRoleRequest struct {
+    Role string `json:"role"`
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for ProjectDTO
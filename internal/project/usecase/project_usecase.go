@@ -1,9 +1,18 @@
 package usecase
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	activitydomain "todo-app/internal/activity/domain"
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/common/utils"
+	"todo-app/internal/infrastructure"
 	"todo-app/internal/project/domain"
+	"todo-app/internal/project/policy"
 	"todo-app/internal/project/repository"
-	"todo-app/internal/infrastructure"
 
 	"github.com/google/uuid"
 )
@@ -11,69 +20,99 @@ import (
 var solrClient, _ = infrastructure.NewSolrClient("todoapp")
 
 type ProjectUseCase struct {
-	repo repository.ProjectRepository
+	repo     repository.ProjectRepository
+	tasks    TaskIndexer
+	activity ActivityRecorder
+	policy   *policy.Policy
+}
+
+// TaskIndexer はメンバーを外したときに、割り当て直したタスクを Solr に登録し直します
+type TaskIndexer interface {
+	ReindexTasks(taskIDs []string)
 }
 
-func NewProjectUseCase(r repository.ProjectRepository) *ProjectUseCase {
-	return &ProjectUseCase{repo: r}
+func NewProjectUseCase(r repository.ProjectRepository, ti TaskIndexer, ar ActivityRecorder) *ProjectUseCase {
+	return &ProjectUseCase{repo: r, tasks: ti, activity: ar, policy: policy.NewPolicy(r)}
 }
 
+// Create はプロジェクトを作成します。作成者はプロジェクトの owner になります
 func (uc *ProjectUseCase) Create(dto *ProjectDTO) (string, error) {
 	// Use provided ID if it exists, otherwise generate a new UUID
 	if dto.ID == "" {
 		dto.ID = uuid.New().String()
 	}
 	project := domain.NewProject(dto.ID, dto.Name, dto.Description, dto.StartDate, dto.EndDate, dto.CreatedBy)
-	if err := uc.repo.Create(project); err != nil {
+	project.AutoCompleteTasks = dto.AutoCompleteTasks
+	if err := uc.repo.Create(project, uc.projectEntry(nil, project, activitydomain.ActionCreated, dto.CreatedBy)); err != nil {
 		return "", err
 	}
 	// Solrにも投入
+	indexProject(project)
+	return project.ID, nil
+}
+
+// indexProject はプロジェクトを Solr に登録します（同じ ID なら上書き）
+func indexProject(project *domain.Project) {
 	solrClient.Add(map[string]interface{}{
-		"id":        project.ID,
-		"type":      "project",
-		"title":     project.Name,
+		"id":          project.ID,
+		"type":        "project",
+		"title":       project.Name,
 		"description": project.Description,
 	})
-	return project.ID, nil
 }
 
-func (uc *ProjectUseCase) GetAll() ([]*ProjectDTO, error) {
-	projects, err := uc.repo.GetAll()
+// GetAll は userID がメンバー（ロールは問わない）のプロジェクトの一覧を返します
+// status が空または active の場合はアーカイブ済みのプロジェクトを含めず、archived の場合はアーカイブ済みのみ、all の場合はすべて返します
+func (uc *ProjectUseCase) GetAll(userID, status string) ([]*ProjectDTO, error) {
+	switch status {
+	case "":
+		status = domain.StatusActive
+	case domain.StatusActive, domain.StatusArchived, StatusFilterAll:
+	default:
+		return nil, fieldError("status", "status must be one of active, archived, all")
+	}
+	all, err := uc.repo.ListByMember(userID)
 	if err != nil {
 		return nil, err
 	}
+	projects := all[:0]
+	for _, project := range all {
+		if status == StatusFilterAll || project.Status == status {
+			projects = append(projects, project)
+		}
+	}
 
 	dtos := make([]*ProjectDTO, len(projects))
 	for i, project := range projects {
 		dtos[i] = &ProjectDTO{
-			ID:          project.ID,
-			Name:        project.Name,
-			Description: project.Description,
-			StartDate:   project.StartDate,
-			EndDate:     project.EndDate,
-			CreatedBy:   project.CreatedBy,
+			ID:                project.ID,
+			Name:              project.Name,
+			Description:       project.Description,
+			StartDate:         project.StartDate,
+			EndDate:           project.EndDate,
+			CreatedBy:         project.CreatedBy,
+			Version:           project.Version,
+			AutoCompleteTasks: project.AutoCompleteTasks,
+			Status:            project.Status,
 		}
 	}
 	return dtos, nil
 }
 
-func (uc *ProjectUseCase) GetByID(id string) (*ProjectDTO, error) {
-	project, err := uc.repo.GetByID(id)
+// GetByID はプロジェクトを返します。userID が閲覧できない場合は ErrForbidden です
+func (uc *ProjectUseCase) GetByID(id, userID string) (*ProjectDTO, error) {
+	project, err := uc.authorize(id, userID, domain.PermView)
 	if err != nil {
 		return nil, err
 	}
 
-	return &ProjectDTO{
-		ID:          project.ID,
-		Name:        project.Name,
-		Description: project.Description,
-		StartDate:   project.StartDate,
-		EndDate:     project.EndDate,
-		CreatedBy:   project.CreatedBy,
-	}, nil
+	return toProjectDTO(project), nil
 }
 
-func (uc *ProjectUseCase) GetMembers(projectID string) ([]*MemberDTO, error) {
+func (uc *ProjectUseCase) GetMembers(projectID, userID string) ([]*MemberDTO, error) {
+	if _, err := uc.authorize(projectID, userID, domain.PermView); err != nil {
+		return nil, err
+	}
 	members, err := uc.repo.GetMembers(projectID)
 	if err != nil {
 		return nil, err
@@ -85,22 +124,336 @@ func (uc *ProjectUseCase) GetMembers(projectID string) ([]*MemberDTO, error) {
 			ID:    member.ID,
 			Name:  member.Name,
 			Email: member.Email,
+			Role:  member.Role,
 		}
 	}
 	return dtos, nil
 }
 
-func (uc *ProjectUseCase) AddMember(projectID, userID string) error {
-	return uc.repo.AddMember(projectID, userID)
+// AddMember は userID を role でメンバーに追加します。role を省略した場合は member です
+// メンバーの管理には maintainer 以上、owner の付与には owner のロールが必要です
+// 既にメンバーの場合は ErrAlreadyMember を返し、ロールも活動履歴も変更しません（ロールの変更は UpdateMemberRole）
+func (uc *ProjectUseCase) AddMember(projectID, userID, role, actorID string) error {
+	if role == "" {
+		role = domain.RoleMember
+	}
+	if err := domain.ValidateRole(role); err != nil {
+		return fieldError("role", err.Error())
+	}
+	if _, err := uc.writable(projectID); err != nil {
+		return err
+	}
+	if err := uc.authorizeMembers(projectID, actorID, role); err != nil {
+		return err
+	}
+	return uc.repo.AddMember(projectID, userID, role, uc.memberEntry(projectID, userID, "", role, actorID))
+}
+
+// UpdateMemberRole はメンバーのロールを変更します。最後の owner のロールは変更できません
+func (uc *ProjectUseCase) UpdateMemberRole(projectID, userID, role, actorID string) (*MemberDTO, error) {
+	if err := domain.ValidateRole(role); err != nil {
+		return nil, fieldError("role", err.Error())
+	}
+	if _, err := uc.writable(projectID); err != nil {
+		return nil, err
+	}
+	members, err := uc.repo.GetMembers(projectID)
+	if err != nil {
+		return nil, err
+	}
+	member := findMember(members, userID)
+	if member == nil {
+		return nil, apperrors.ErrNotFound
+	}
+	if err := uc.authorizeMembers(projectID, actorID, role, member.Role); err != nil {
+		return nil, err
+	}
+	if member.Role == domain.RoleOwner && role != domain.RoleOwner && countOwners(members) == 1 {
+		return nil, domain.ErrLastOwner
+	}
+
+	if err := uc.repo.UpdateMemberRole(projectID, userID, role, uc.memberEntry(projectID, userID, member.Role, role, actorID)); err != nil {
+		return nil, err
+	}
+	return &MemberDTO{ID: member.ID, Name: member.Name, Email: member.Email, Role: role}, nil
+}
+
+// RemoveMember はメンバーをプロジェクトから外し、そのメンバーが担当している未完了のタスクを reassignTo に割り当て直します
+// reassignTo を省略した場合はプロジェクトの作成者に割り当てます。reassignTo はメンバーである必要があります
+// 自分自身はロールに関係なく外れることができます。最後の owner は外せません
+func (uc *ProjectUseCase) RemoveMember(ctx context.Context, projectID, userID, reassignTo, actorID string) (*MemberRemovalDTO, error) {
+	project, err := uc.writable(projectID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := uc.repo.GetMembers(projectID)
+	if err != nil {
+		return nil, err
+	}
+	isMember := func(id string) bool {
+		return findMember(members, id) != nil
+	}
+	if err := uc.policy.Authorize(projectID, actorID, domain.PermView); err != nil {
+		return nil, err
+	}
+	member := findMember(members, userID)
+	if member == nil {
+		return nil, apperrors.ErrNotFound
+	}
+	if userID != actorID {
+		if err := uc.authorizeMembers(projectID, actorID, member.Role); err != nil {
+			return nil, err
+		}
+	}
+	if reassignTo == "" {
+		reassignTo = project.CreatedBy
+	}
+	switch {
+	case reassignTo == "" || reassignTo == userID:
+		return nil, fieldError("reassign_to", "reassign_to is required when removing the project creator")
+	case !isMember(reassignTo):
+		return nil, fieldError("reassign_to", "reassign_to must be a project member")
+	}
+	if member.Role == domain.RoleOwner && countOwners(members) == 1 {
+		return nil, domain.ErrLastOwner
+	}
+
+	// 割り当て直しとメンバーの削除は同じトランザクションで行い、外したメンバーが担当するタスクが残らないようにする
+	reassigned, err := uc.repo.RemoveMember(ctx, projectID, userID, reassignTo,
+		uc.memberEntry(projectID, userID, member.Role, "", actorID), uc.reassignEntry(projectID, userID, reassignTo, actorID))
+	if err != nil {
+		return nil, err
+	}
+	uc.tasks.ReindexTasks(reassigned)
+	return &MemberRemovalDTO{UserID: userID, ReassignedTo: reassignTo, ReassignedTasks: len(reassigned)}, nil
+}
+
+// Update はプロジェクトの編集可能なフィールドを dto の内容で置き換えます (PUT)
+// version が 0 以外の場合は一致する場合のみ更新します
+func (uc *ProjectUseCase) Update(id string, dto *ProjectDTO, version int, actorID string) (*ProjectDTO, error) {
+	project, err := uc.authorize(id, actorID, domain.PermManageProject)
+	if err != nil {
+		return nil, err
+	}
+	return uc.applyUpdate(project, dto, version, actorID)
 }
 
-func (uc *ProjectUseCase) Delete(id string) error {
+// Patch は JSON Merge Patch (RFC 7386) をプロジェクトに適用します (PATCH)
+func (uc *ProjectUseCase) Patch(id string, patch []byte, version int, actorID string) (*ProjectDTO, error) {
+	project, err := uc.authorize(id, actorID, domain.PermManageProject)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(toProjectDTO(project))
+	if err != nil {
+		return nil, err
+	}
+	merged, err := utils.MergePatch(data, patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrInvalidInput, err)
+	}
+	var dto ProjectDTO
+	if err := json.Unmarshal(merged, &dto); err != nil {
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrInvalidInput, err)
+	}
+	return uc.applyUpdate(project, &dto, version, actorID)
+}
+
+// applyUpdate は名前・説明・期間・自動完了の設定を更新します。ID・作成者・ステータスは変更しません
+func (uc *ProjectUseCase) applyUpdate(project *domain.Project, dto *ProjectDTO, version int, actorID string) (*ProjectDTO, error) {
+	if version != 0 && version != project.Version {
+		return nil, apperrors.ErrVersionMismatch
+	}
+	if project.IsArchived() {
+		return nil, domain.ErrArchived
+	}
+	verr := apperrors.NewValidationError()
+	if dto.Name == "" {
+		verr.Add("name", domain.ErrNameRequired.Error())
+	}
+	if !dto.StartDate.IsZero() && !dto.EndDate.IsZero() && dto.EndDate.Before(dto.StartDate) {
+		verr.Add("end_date", domain.ErrInvalidDateRange.Error())
+	}
+	if verr.HasErrors() {
+		return nil, verr
+	}
+
+	before := projectValues(project)
+	project.Name = dto.Name
+	project.Description = dto.Description
+	project.StartDate = dto.StartDate
+	project.EndDate = dto.EndDate
+	project.AutoCompleteTasks = dto.AutoCompleteTasks
+	project.UpdatedAt = time.Now()
+	if err := uc.repo.Update(project, uc.projectEntry(before, project, activitydomain.ActionUpdated, actorID)); err != nil {
+		return nil, err
+	}
+	indexProject(project)
+	return toProjectDTO(project), nil
+}
+
+// Archive はプロジェクトをアーカイブします。アーカイブ済みのプロジェクトは読み取り専用になり、既定の一覧に表示されません
+func (uc *ProjectUseCase) Archive(id string, version int, actorID string) (*ProjectDTO, error) {
+	return uc.setStatus(id, domain.StatusArchived, version, actorID)
+}
+
+// Unarchive はアーカイブ済みのプロジェクトを元に戻します
+func (uc *ProjectUseCase) Unarchive(id string, version int, actorID string) (*ProjectDTO, error) {
+	return uc.setStatus(id, domain.StatusActive, version, actorID)
+}
+
+// setStatus はプロジェクトのステータスを変更します。既に同じステータスの場合は何もしません
+func (uc *ProjectUseCase) setStatus(id, status string, version int, actorID string) (*ProjectDTO, error) {
+	project, err := uc.authorize(id, actorID, domain.PermManageProject)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && version != project.Version {
+		return nil, apperrors.ErrVersionMismatch
+	}
+	if project.Status != status {
+		before := projectValues(project)
+		project.Status = status
+		project.UpdatedAt = time.Now()
+		if err := uc.repo.Update(project, uc.projectEntry(before, project, activitydomain.ActionUpdated, actorID)); err != nil {
+			return nil, err
+		}
+	}
+	return toProjectDTO(project), nil
+}
+
+// writable はアーカイブされていないプロジェクトを返します
+func (uc *ProjectUseCase) writable(id string) (*domain.Project, error) {
+	project, err := uc.repo.GetByID(id)
+	if err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	if project.IsArchived() {
+		return nil, domain.ErrArchived
+	}
+	return project, nil
+}
+
+// authorize はプロジェクトを返します。存在しない場合は ErrNotFound、actorID に perm がない場合は ErrForbidden です
+func (uc *ProjectUseCase) authorize(id, actorID string, perm domain.Permission) (*domain.Project, error) {
+	project, err := uc.repo.GetByID(id)
+	if err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	if err := uc.policy.Authorize(id, actorID, perm); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// authorizeMembers はメンバーの管理を許可するかを判定します
+// roles（付与するロールや変更前のロール）に owner が含まれる場合は actorID が owner である必要があります
+func (uc *ProjectUseCase) authorizeMembers(projectID, actorID string, roles ...string) error {
+	actorRole, err := uc.policy.Role(projectID, actorID)
+	if err != nil {
+		return err
+	}
+	if !domain.RoleAllows(actorRole, domain.PermManageMembers) {
+		return apperrors.ErrForbidden
+	}
+	for _, role := range roles {
+		if role == domain.RoleOwner && actorRole != domain.RoleOwner {
+			return apperrors.ErrForbidden
+		}
+	}
+	return nil
+}
+
+// UpdateSettings はプロジェクトの設定を更新します。version が 0 以外の場合は一致する場合のみ更新します
+func (uc *ProjectUseCase) UpdateSettings(id string, dto *ProjectSettingsDTO, version int, actorID string) (*ProjectDTO, error) {
+	project, err := uc.authorize(id, actorID, domain.PermManageProject)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && version != project.Version {
+		return nil, apperrors.ErrVersionMismatch
+	}
+	if project.IsArchived() {
+		return nil, domain.ErrArchived
+	}
+
+	before := projectValues(project)
+	if dto.AutoCompleteTasks != nil {
+		project.AutoCompleteTasks = *dto.AutoCompleteTasks
+	}
+	project.UpdatedAt = time.Now()
+	if err := uc.repo.Update(project, uc.projectEntry(before, project, activitydomain.ActionUpdated, actorID)); err != nil {
+		return nil, err
+	}
+	return toProjectDTO(project), nil
+}
+
+// Restore はゴミ箱内のプロジェクトを元に戻します。owner のみ行えます
+func (uc *ProjectUseCase) Restore(id, actorID string) (*ProjectDTO, error) {
+	if err := uc.policy.Authorize(id, actorID, domain.PermDeleteProject); err != nil {
+		return nil, err
+	}
+	entry := activitydomain.NewEntry(activitydomain.EntityProject, id, activitydomain.ActionRestored, actorID)
+	entry.ProjectID = id
+	if err := uc.repo.Restore(id, uc.prepare(entry)); err != nil {
+		return nil, err
+	}
+	project, err := uc.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return toProjectDTO(project), nil
+}
+
+// Delete はプロジェクトをゴミ箱に移動します。version が 0 以外の場合は一致する場合のみ削除します
+// owner のみ削除できます
+func (uc *ProjectUseCase) Delete(id string, version int, actorID string) error {
 	// First check if project exists
-	_, err := uc.repo.GetByID(id)
+	project, err := uc.authorize(id, actorID, domain.PermDeleteProject)
 	if err != nil {
 		return err
 	}
 
 	// Delete the project
-	return uc.repo.Delete(id)
+	return uc.repo.Delete(id, version, uc.projectEntry(nil, project, activitydomain.ActionDeleted, actorID))
+}
+
+func toProjectDTO(project *domain.Project) *ProjectDTO {
+	return &ProjectDTO{
+		ID:                project.ID,
+		Name:              project.Name,
+		Description:       project.Description,
+		StartDate:         project.StartDate,
+		EndDate:           project.EndDate,
+		CreatedBy:         project.CreatedBy,
+		Version:           project.Version,
+		AutoCompleteTasks: project.AutoCompleteTasks,
+		Status:            project.Status,
+	}
+}
+
+func findMember(members []*domain.Member, userID string) *domain.Member {
+	for _, m := range members {
+		if m.ID == userID {
+			return m
+		}
+	}
+	return nil
+}
+
+func countOwners(members []*domain.Member) int {
+	n := 0
+	for _, m := range members {
+		if m.Role == domain.RoleOwner {
+			n++
+		}
+	}
+	return n
+}
+
+func fieldError(field, msg string) error {
+	verr := apperrors.NewValidationError()
+	verr.Add(field, msg)
+	return verr
 }
@@ -0,0 +1,106 @@
+package domain
+
+import (
+    "errors"
+    "time"
+
+    taskdomain "todo-app/internal/task/domain"
+)
+
+// 日別のレポートの種類
+const (
+    ReportBurndown       = "burndown"
+    ReportBurnup         = "burnup"
+    ReportCumulativeFlow = "cumulative-flow"
+    ReportThroughput     = "throughput"
+    ReportCycleTime      = "cycle-time"
+    ReportVelocity       = "velocity"
+)
+
+// MaxDays はレポートの期間として指定できる最大の日数です
+const MaxDays = 366
+
+var (
+    ErrUnknownReport    = errors.New("unknown report")
+    ErrInvalidDate      = errors.New("date must be YYYY-MM-DD")
+    ErrInvalidDateRange = errors.New("from must not be after to")
+    ErrRangeTooLong     = errors.New("range must not exceed 366 days")
+)
+
+// Transition はタスクのステータス変更の履歴です
+type Transition struct {
+    From string
+    To   string
+    At   time.Time
+}
+
+// TaskHistory はタスクと、作成からのステータス変更の履歴（古い順）です
+// タスクはステータスを現在の値しか持たないため、過去のある時点のステータスは履歴から導きます
+type TaskHistory struct {
+    ID          string
+    Title       string
+    Status      string
+    Estimate    int
+    CreatedAt   time.Time
+    Transitions []Transition
+}
+
+// InitialStatus は作成時のステータスです。変更の履歴がなければ現在のステータスです
+func (h *TaskHistory) InitialStatus() string {
+    if len(h.Transitions) > 0 {
+        return h.Transitions[0].From
+    }
+    return h.Status
+}
+
+// StatusAt は時刻 t の直前のステータスを返します。t の時点でまだ作成されていなければ false を返します
+func (h *TaskHistory) StatusAt(t time.Time) (string, bool) {
+    if !h.CreatedAt.Before(t) {
+        return "", false
+    }
+    status := h.InitialStatus()
+    for _, tr := range h.Transitions {
+        if !tr.At.Before(t) {
+            break
+        }
+        status = tr.To
+    }
+    return status, true
+}
+
+// StartedAt は最初に InProgress になった時刻です。InProgress を経ずに完了した場合は作成日時です
+func (h *TaskHistory) StartedAt() time.Time {
+    for _, tr := range h.Transitions {
+        if tr.To == taskdomain.StatusInProgress {
+            return tr.At
+        }
+    }
+    return h.CreatedAt
+}
+
+// CompletedAt は現在 Done のタスクが最後に Done になった時刻です。完了していなければ false を返します
+func (h *TaskHistory) CompletedAt() (time.Time, bool) {
+    if h.Status != taskdomain.StatusDone {
+        return time.Time{}, false
+    }
+    for i := len(h.Transitions) - 1; i >= 0; i-- {
+        if h.Transitions[i].To == taskdomain.StatusDone {
+            return h.Transitions[i].At, true
+        }
+    }
+    // Done のまま作成されたタスク
+    return h.CreatedAt, true
+}
+
+// SprintVelocity は開始済みのスプリントで完了したタスクの集計です
+type SprintVelocity struct {
+    SprintID          string
+    Name              string
+    Status            string
+    StartDate         time.Time
+    EndDate           time.Time
+    Planned           int
+    CompletedTasks    int
+    CompletedEstimate int
+    RolledOver        int
+}
@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/common/utils"
+	planningpostgres "todo-app/internal/planning/repository/postgres"
+	projectpolicy "todo-app/internal/project/policy"
+	projectpostgres "todo-app/internal/project/repository/postgres"
+	"todo-app/internal/report/domain"
+	"todo-app/internal/report/repository/postgres"
+	"todo-app/internal/report/usecase"
+)
+
+// NewReportUseCase は PostgreSQL のリポジトリを使う ReportUseCase を返します
+// プロジェクトのレポートのエンドポイントはプロジェクトのハンドラーで登録します
+func NewReportUseCase(db *sql.DB) *usecase.ReportUseCase {
+	projectRepo := projectpostgres.NewProjectRepoPg(db)
+	return usecase.NewReportUseCase(postgres.NewReportRepoPg(db), projectRepo, planningpostgres.NewSprintRepoPg(db),
+		projectpolicy.NewPolicy(projectRepo))
+}
+
+// ParseQuery は from / to / sprint_id のクエリパラメータを読み取ります
+func ParseQuery(r *http.Request) usecase.ReportQuery {
+	q := r.URL.Query()
+	return usecase.ReportQuery{From: q.Get("from"), To: q.Get("to"), SprintID: q.Get("sprint_id")}
+}
+
+// WantsCSV は format=csv または Accept: text/csv で CSV が要求されているかを返します
+func WantsCSV(r *http.Request) bool {
+	if format := r.URL.Query().Get("format"); format != "" {
+		return format == "csv"
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/csv")
+}
+
+// WriteReport はレポートを JSON、CSV が要求されている場合は name.csv として返します
+func WriteReport(w http.ResponseWriter, r *http.Request, name string, report usecase.Report) {
+	if !WantsCSV(r) {
+		utils.JSONResponse(w, http.StatusOK, report)
+		return
+	}
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+name+`.csv"`)
+	w.WriteHeader(http.StatusOK)
+	// ヘッダーは送信済みのため、書き込みに失敗した場合はログに残すだけにする
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(report.CSV()); err != nil {
+		log.Printf("Failed to write %s report CSV: %v", name, err)
+	}
+}
+
+// WriteError はレポートのエラーを HTTP ステータスに変換して返します
+func WriteError(w http.ResponseWriter, err error) {
+	var verr *apperrors.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		utils.JSONResponse(w, http.StatusBadRequest, map[string]interface{}{"error": apperrors.ErrInvalidInput.Error(), "fields": verr.Fields})
+	case errors.Is(err, apperrors.ErrForbidden):
+		utils.JSONResponse(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrUnknownReport), errors.Is(err, apperrors.ErrNotFound):
+		utils.JSONResponse(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	default:
+		utils.JSONResponse(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
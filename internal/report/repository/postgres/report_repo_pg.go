@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"todo-app/internal/report/domain"
+	"todo-app/internal/report/repository"
+)
+
+// reportRepoPg は ReportRepository の PostgreSQL 実装
+type reportRepoPg struct{ db *sql.DB }
+
+// NewReportRepoPg は PostgreSQL 実装（レポート用）を返す
+func NewReportRepoPg(db *sql.DB) repository.ReportRepository {
+	return &reportRepoPg{db: db}
+}
+
+func (r *reportRepoPg) TaskHistories(projectID, sprintID string) ([]*domain.TaskHistory, error) {
+	rows, err := r.db.Query(`
+        SELECT id, title, COALESCE(status, 'Open'), estimate, COALESCE(created_at, CURRENT_TIMESTAMP)
+        FROM tasks
+        WHERE project_id = $1 AND deleted_at IS NULL AND ($2 = '' OR sprint_id = $2)
+        ORDER BY created_at, id
+    `, projectID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var histories []*domain.TaskHistory
+	byID := map[string]*domain.TaskHistory{}
+	for rows.Next() {
+		h := &domain.TaskHistory{}
+		if err := rows.Scan(&h.ID, &h.Title, &h.Status, &h.Estimate, &h.CreatedAt); err != nil {
+			return nil, err
+		}
+		histories = append(histories, h)
+		byID[h.ID] = h
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(histories) == 0 {
+		return histories, nil
+	}
+
+	trows, err := r.db.Query(`
+        SELECT tr.task_id, tr.from_status, tr.to_status, tr.created_at
+        FROM task_transitions tr
+        JOIN tasks t ON t.id = tr.task_id
+        WHERE t.project_id = $1 AND t.deleted_at IS NULL AND ($2 = '' OR t.sprint_id = $2)
+        ORDER BY tr.created_at, tr.id
+    `, projectID, sprintID)
+	if err != nil {
+		return nil, err
+	}
+	defer trows.Close()
+
+	for trows.Next() {
+		var taskID string
+		var tr domain.Transition
+		if err := trows.Scan(&taskID, &tr.From, &tr.To, &tr.At); err != nil {
+			return nil, err
+		}
+		if h, ok := byID[taskID]; ok {
+			h.Transitions = append(h.Transitions, tr)
+		}
+	}
+	return histories, trows.Err()
+}
+
+func (r *reportRepoPg) SprintVelocities(projectID string) ([]*domain.SprintVelocity, error) {
+	rows, err := r.db.Query(`
+        SELECT s.id, s.name, s.status, s.start_date, s.end_date, s.rolled_over,
+            COUNT(t.id),
+            COUNT(t.id) FILTER (WHERE t.status = 'Done'),
+            COALESCE(SUM(t.estimate) FILTER (WHERE t.status = 'Done'), 0)
+        FROM sprints s
+        LEFT JOIN tasks t ON t.sprint_id = s.id AND t.deleted_at IS NULL
+        WHERE s.project_id = $1 AND s.status <> 'planned'
+        GROUP BY s.id
+        ORDER BY s.start_date, s.created_at
+    `, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var velocities []*domain.SprintVelocity
+	for rows.Next() {
+		v := &domain.SprintVelocity{}
+		if err := rows.Scan(&v.SprintID, &v.Name, &v.Status, &v.StartDate, &v.EndDate, &v.RolledOver,
+			&v.Planned, &v.CompletedTasks, &v.CompletedEstimate); err != nil {
+			return nil, err
+		}
+		// 終了時に次のスプリントへ移したタスクも計画に含める
+		v.Planned += v.RolledOver
+		velocities = append(velocities, v)
+	}
+	return velocities, rows.Err()
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "todo-app/internal/report/domain"
+
+// ReportRepository はレポートの集計に使うタスクの履歴を読み出します
+type ReportRepository interface {
+    // TaskHistories はプロジェクトのタスク（ゴミ箱内を除く）とステータス変更の履歴を返します
+    // sprintID を指定した場合はそのスプリントに予定されたタスクに絞り込みます
+    TaskHistories(projectID, sprintID string) ([]*domain.TaskHistory, error)
+    // SprintVelocities はプロジェクトの開始済み（active / closed）のスプリントを開始日の順に集計します
+    SprintVelocities(projectID string) ([]*domain.SprintVelocity, error)
+}
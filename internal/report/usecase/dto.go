@@ -0,0 +1,203 @@
+package usecase
+
+import (
+    "strconv"
+    "time"
+
+    taskdomain "todo-app/internal/task/domain"
+)
+
+// Report は JSON と CSV のどちらでも返せるレポートです
+type Report interface {
+    // CSV は見出し行と日ごと（サイクルタイムはタスクごと、ベロシティはスプリントごと）の行を返します
+    CSV() [][]string
+}
+
+// ReportQuery は GET /projects/{projectID}/reports/{report} のクエリパラメータです
+// 日付は YYYY-MM-DD（UTC）です。省略した場合、from はスプリントの開始日・プロジェクトの開始日・最初のタスクの作成日のいずれか、
+// to はスプリントの終了日または今日になります
+type ReportQuery struct {
+    From     string
+    To       string
+    SprintID string
+}
+
+// ReportRange はレポートの対象です。SprintID が空の場合はプロジェクト全体です
+type ReportRange struct {
+    ProjectID string `json:"project_id"`
+    SprintID  string `json:"sprint_id,omitempty"`
+    From      string `json:"from"`
+    To        string `json:"to"`
+}
+
+// BurndownPoint は日ごとの残り（Done / Canceled 以外）のタスク数と見積もり時間（分）です
+// Ideal は初日の残りから最終日に 0 になる理想線です。今日より後の日は Remaining が null になります
+type BurndownPoint struct {
+    Date              string  `json:"date"`
+    Remaining         *int    `json:"remaining"`
+    RemainingEstimate *int    `json:"remaining_estimate"`
+    Ideal             float64 `json:"ideal"`
+    IdealEstimate     float64 `json:"ideal_estimate"`
+}
+
+// BurndownDTO は GET /projects/{projectID}/reports/burndown のレスポンスです
+type BurndownDTO struct {
+    ReportRange
+    Points []BurndownPoint `json:"points"`
+}
+
+func (d *BurndownDTO) CSV() [][]string {
+    rows := [][]string{{"date", "remaining", "remaining_estimate", "ideal", "ideal_estimate"}}
+    for _, p := range d.Points {
+        rows = append(rows, []string{p.Date, optionalInt(p.Remaining), optionalInt(p.RemainingEstimate), formatFloat(p.Ideal), formatFloat(p.IdealEstimate)})
+    }
+    return rows
+}
+
+// BurnupPoint は日ごとの全体（Canceled 以外）と完了（Done）のタスク数・見積もり時間（分）です
+type BurnupPoint struct {
+    Date              string `json:"date"`
+    Scope             int    `json:"scope"`
+    Completed         int    `json:"completed"`
+    ScopeEstimate     int    `json:"scope_estimate"`
+    CompletedEstimate int    `json:"completed_estimate"`
+}
+
+// BurnupDTO は GET /projects/{projectID}/reports/burnup のレスポンスです
+type BurnupDTO struct {
+    ReportRange
+    Points []BurnupPoint `json:"points"`
+}
+
+func (d *BurnupDTO) CSV() [][]string {
+    rows := [][]string{{"date", "scope", "completed", "scope_estimate", "completed_estimate"}}
+    for _, p := range d.Points {
+        rows = append(rows, []string{p.Date, strconv.Itoa(p.Scope), strconv.Itoa(p.Completed), strconv.Itoa(p.ScopeEstimate), strconv.Itoa(p.CompletedEstimate)})
+    }
+    return rows
+}
+
+// FlowStatuses は累積フロー図に含めるステータスの順序（標準ワークフローの順）です
+var FlowStatuses = taskdomain.DefaultWorkflow().States
+
+// FlowPoint は日ごとのステータス別のタスク数です
+type FlowPoint struct {
+    Date   string         `json:"date"`
+    Counts map[string]int `json:"counts"`
+}
+
+// CumulativeFlowDTO は GET /projects/{projectID}/reports/cumulative-flow のレスポンスです
+type CumulativeFlowDTO struct {
+    ReportRange
+    Statuses []string    `json:"statuses"`
+    Points   []FlowPoint `json:"points"`
+}
+
+func (d *CumulativeFlowDTO) CSV() [][]string {
+    rows := [][]string{append([]string{"date"}, d.Statuses...)}
+    for _, p := range d.Points {
+        row := []string{p.Date}
+        for _, status := range d.Statuses {
+            row = append(row, strconv.Itoa(p.Counts[status]))
+        }
+        rows = append(rows, row)
+    }
+    return rows
+}
+
+// ThroughputPoint は日ごとに完了したタスク数と見積もり時間（分）です
+type ThroughputPoint struct {
+    Date              string `json:"date"`
+    Completed         int    `json:"completed"`
+    CompletedEstimate int    `json:"completed_estimate"`
+}
+
+// ThroughputDTO は GET /projects/{projectID}/reports/throughput のレスポンスです
+type ThroughputDTO struct {
+    ReportRange
+    Total         int               `json:"total"`
+    AveragePerDay float64           `json:"average_per_day"`
+    Points        []ThroughputPoint `json:"points"`
+}
+
+func (d *ThroughputDTO) CSV() [][]string {
+    rows := [][]string{{"date", "completed", "completed_estimate"}}
+    for _, p := range d.Points {
+        rows = append(rows, []string{p.Date, strconv.Itoa(p.Completed), strconv.Itoa(p.CompletedEstimate)})
+    }
+    return rows
+}
+
+// CycleTimeEntry は期間内に完了したタスクの所要時間です
+// サイクルタイムは最初に InProgress になってから（InProgress を経ていない場合は作成から）完了まで、リードタイムは作成から完了までです
+type CycleTimeEntry struct {
+    TaskID         string    `json:"task_id"`
+    Title          string    `json:"title"`
+    CreatedAt      time.Time `json:"created_at"`
+    StartedAt      time.Time `json:"started_at"`
+    CompletedAt    time.Time `json:"completed_at"`
+    CycleTimeHours float64   `json:"cycle_time_hours"`
+    LeadTimeHours  float64   `json:"lead_time_hours"`
+}
+
+// CycleTimeDTO は GET /projects/{projectID}/reports/cycle-time のレスポンスです。Tasks は完了日時の順です
+type CycleTimeDTO struct {
+    ReportRange
+    Count        int              `json:"count"`
+    AverageHours float64          `json:"average_hours"`
+    MedianHours  float64          `json:"median_hours"`
+    P85Hours     float64          `json:"p85_hours"`
+    Tasks        []CycleTimeEntry `json:"tasks"`
+}
+
+func (d *CycleTimeDTO) CSV() [][]string {
+    rows := [][]string{{"task_id", "title", "created_at", "started_at", "completed_at", "cycle_time_hours", "lead_time_hours"}}
+    for _, e := range d.Tasks {
+        rows = append(rows, []string{e.TaskID, e.Title, e.CreatedAt.UTC().Format(time.RFC3339), e.StartedAt.UTC().Format(time.RFC3339),
+            e.CompletedAt.UTC().Format(time.RFC3339), formatFloat(e.CycleTimeHours), formatFloat(e.LeadTimeHours)})
+    }
+    return rows
+}
+
+// VelocityPoint はスプリントで完了したタスク数と見積もり時間（分）です
+// Planned はスプリントに予定されたタスク数（終了時に次へ移したタスクを含む）です
+type VelocityPoint struct {
+    SprintID          string `json:"sprint_id"`
+    Name              string `json:"name"`
+    Status            string `json:"status"`
+    StartDate         string `json:"start_date"`
+    EndDate           string `json:"end_date"`
+    Planned           int    `json:"planned"`
+    CompletedTasks    int    `json:"completed_tasks"`
+    CompletedEstimate int    `json:"completed_estimate"`
+    RolledOver        int    `json:"rolled_over"`
+}
+
+// VelocityDTO は GET /projects/{projectID}/reports/velocity のレスポンスです
+// 平均は終了済みのスプリントのみで計算します
+type VelocityDTO struct {
+    ProjectID       string          `json:"project_id"`
+    AverageTasks    float64         `json:"average_tasks"`
+    AverageEstimate float64         `json:"average_estimate"`
+    Sprints         []VelocityPoint `json:"sprints"`
+}
+
+func (d *VelocityDTO) CSV() [][]string {
+    rows := [][]string{{"sprint_id", "name", "status", "start_date", "end_date", "planned", "completed_tasks", "completed_estimate", "rolled_over"}}
+    for _, s := range d.Sprints {
+        rows = append(rows, []string{s.SprintID, s.Name, s.Status, s.StartDate, s.EndDate, strconv.Itoa(s.Planned),
+            strconv.Itoa(s.CompletedTasks), strconv.Itoa(s.CompletedEstimate), strconv.Itoa(s.RolledOver)})
+    }
+    return rows
+}
+
+func optionalInt(v *int) string {
+    if v == nil {
+        return ""
+    }
+    return strconv.Itoa(*v)
+}
+
+func formatFloat(v float64) string {
+    return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,349 @@
+package usecase
+
+import (
+	"sort"
+	"time"
+
+	apperrors "todo-app/internal/common/errors"
+	planningdomain "todo-app/internal/planning/domain"
+	projectdomain "todo-app/internal/project/domain"
+	"todo-app/internal/report/domain"
+	"todo-app/internal/report/repository"
+	taskdomain "todo-app/internal/task/domain"
+)
+
+// ProjectPolicy はプロジェクトのロールに基づいて操作を許可するかを判定します
+type ProjectPolicy interface {
+	Authorize(projectID, userID string, perm projectdomain.Permission) error
+}
+
+// ProjectFinder はレポートの既定の期間を決めるためにプロジェクトを返します
+type ProjectFinder interface {
+	FindByID(id string) (*projectdomain.Project, error)
+}
+
+// SprintFinder はスプリントで絞り込むレポートのためにスプリントを返します
+type SprintFinder interface {
+	FindByID(id string) (*planningdomain.Sprint, error)
+}
+
+// ReportUseCase はプロジェクトの推移をステータス変更の履歴から日ごと（UTC）に集計します
+// レポートは viewer 以上が閲覧できます
+type ReportUseCase struct {
+	reports  repository.ReportRepository
+	projects ProjectFinder
+	sprints  SprintFinder
+	policy   ProjectPolicy
+	now      func() time.Time
+}
+
+func NewReportUseCase(rr repository.ReportRepository, pf ProjectFinder, sf SprintFinder, pp ProjectPolicy) *ReportUseCase {
+	return &ReportUseCase{reports: rr, projects: pf, sprints: sf, policy: pp, now: time.Now}
+}
+
+// Report は kind のレポートを返します。kind が定義済みでない場合は domain.ErrUnknownReport を返します
+func (uc *ReportUseCase) Report(projectID, kind string, q ReportQuery, actorID string) (Report, error) {
+	switch kind {
+	case domain.ReportBurndown, domain.ReportBurnup, domain.ReportCumulativeFlow, domain.ReportThroughput, domain.ReportCycleTime, domain.ReportVelocity:
+	default:
+		return nil, domain.ErrUnknownReport
+	}
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
+	if kind == domain.ReportVelocity {
+		return uc.velocity(projectID)
+	}
+
+	rng, from, to, err := uc.resolveRange(projectID, q)
+	if err != nil {
+		return nil, err
+	}
+	histories, err := uc.reports.TaskHistories(projectID, q.SprintID)
+	if err != nil {
+		return nil, err
+	}
+	if from.IsZero() {
+		// プロジェクトの開始日がなければ最初のタスクの作成日から
+		from = startOfDay(uc.now())
+		for _, h := range histories {
+			if d := startOfDay(h.CreatedAt); d.Before(from) {
+				from = d
+			}
+		}
+		if from.After(to) {
+			from = to
+		} else if days := daysBetween(from, to) + 1; days > domain.MaxDays {
+			from = to.AddDate(0, 0, 1-domain.MaxDays)
+		}
+		rng.From = from.Format(dateLayout)
+	}
+
+	switch kind {
+	case domain.ReportBurndown:
+		return uc.burndown(rng, histories, from, to), nil
+	case domain.ReportBurnup:
+		return uc.burnup(rng, histories, from, to), nil
+	case domain.ReportCumulativeFlow:
+		return uc.cumulativeFlow(rng, histories, from, to), nil
+	case domain.ReportThroughput:
+		return uc.throughput(rng, histories, from, to), nil
+	default:
+		return uc.cycleTime(rng, histories, from, to), nil
+	}
+}
+
+const dateLayout = "2006-01-02"
+
+// resolveRange はクエリから期間を決めます。from を決められない（プロジェクトの開始日がない）場合はゼロ値を返します
+func (uc *ReportUseCase) resolveRange(projectID string, q ReportQuery) (ReportRange, time.Time, time.Time, error) {
+	rng := ReportRange{ProjectID: projectID, SprintID: q.SprintID}
+	project, err := uc.projects.FindByID(projectID)
+	if err != nil {
+		return rng, time.Time{}, time.Time{}, apperrors.ErrNotFound
+	}
+
+	var from, to time.Time
+	if q.SprintID != "" {
+		sprint, err := uc.sprints.FindByID(q.SprintID)
+		if err != nil || sprint.ProjectID != projectID {
+			return rng, time.Time{}, time.Time{}, fieldError("sprint_id", "sprint not found in the project")
+		}
+		from, to = startOfDay(sprint.StartDate), startOfDay(sprint.EndDate)
+	} else {
+		if !project.StartDate.IsZero() {
+			from = startOfDay(project.StartDate)
+		}
+		to = startOfDay(uc.now())
+	}
+
+	verr := apperrors.NewValidationError()
+	if q.From != "" {
+		t, err := time.Parse(dateLayout, q.From)
+		if err != nil {
+			verr.Add("from", domain.ErrInvalidDate.Error())
+		}
+		from = t
+	}
+	if q.To != "" {
+		t, err := time.Parse(dateLayout, q.To)
+		if err != nil {
+			verr.Add("to", domain.ErrInvalidDate.Error())
+		}
+		to = t
+	}
+	if verr.HasErrors() {
+		return rng, time.Time{}, time.Time{}, verr
+	}
+	if !from.IsZero() {
+		if from.After(to) {
+			return rng, time.Time{}, time.Time{}, fieldError("from", domain.ErrInvalidDateRange.Error())
+		}
+		if daysBetween(from, to)+1 > domain.MaxDays {
+			return rng, time.Time{}, time.Time{}, fieldError("to", domain.ErrRangeTooLong.Error())
+		}
+		rng.From = from.Format(dateLayout)
+	}
+	rng.To = to.Format(dateLayout)
+	return rng, from, to, nil
+}
+
+// days は from から to までの日付を返します。limit より後の日は含めません
+func days(from, to, limit time.Time) []time.Time {
+	var result []time.Time
+	for d := from; !d.After(to) && !d.After(limit); d = d.AddDate(0, 0, 1) {
+		result = append(result, d)
+	}
+	return result
+}
+
+func (uc *ReportUseCase) burndown(rng ReportRange, histories []*domain.TaskHistory, from, to time.Time) *BurndownDTO {
+	today := startOfDay(uc.now())
+	dto := &BurndownDTO{ReportRange: rng, Points: []BurndownPoint{}}
+	span := float64(daysBetween(from, to))
+	var startCount, startEstimate float64
+	for i, day := range days(from, to, to) {
+		point := BurndownPoint{Date: day.Format(dateLayout)}
+		if !day.After(today) {
+			remaining, estimate := 0, 0
+			end := day.AddDate(0, 0, 1)
+			for _, h := range histories {
+				status, ok := h.StatusAt(end)
+				if ok && status != taskdomain.StatusDone && status != taskdomain.StatusCanceled {
+					remaining++
+					estimate += h.Estimate
+				}
+			}
+			point.Remaining, point.RemainingEstimate = &remaining, &estimate
+			if i == 0 {
+				startCount, startEstimate = float64(remaining), float64(estimate)
+			}
+		}
+		point.Ideal, point.IdealEstimate = startCount, startEstimate
+		if span > 0 {
+			left := 1 - float64(i)/span
+			point.Ideal, point.IdealEstimate = startCount*left, startEstimate*left
+		}
+		dto.Points = append(dto.Points, point)
+	}
+	return dto
+}
+
+func (uc *ReportUseCase) burnup(rng ReportRange, histories []*domain.TaskHistory, from, to time.Time) *BurnupDTO {
+	dto := &BurnupDTO{ReportRange: rng, Points: []BurnupPoint{}}
+	for _, day := range days(from, to, startOfDay(uc.now())) {
+		point := BurnupPoint{Date: day.Format(dateLayout)}
+		end := day.AddDate(0, 0, 1)
+		for _, h := range histories {
+			status, ok := h.StatusAt(end)
+			if !ok || status == taskdomain.StatusCanceled {
+				continue
+			}
+			point.Scope++
+			point.ScopeEstimate += h.Estimate
+			if status == taskdomain.StatusDone {
+				point.Completed++
+				point.CompletedEstimate += h.Estimate
+			}
+		}
+		dto.Points = append(dto.Points, point)
+	}
+	return dto
+}
+
+func (uc *ReportUseCase) cumulativeFlow(rng ReportRange, histories []*domain.TaskHistory, from, to time.Time) *CumulativeFlowDTO {
+	dto := &CumulativeFlowDTO{ReportRange: rng, Statuses: FlowStatuses, Points: []FlowPoint{}}
+	for _, day := range days(from, to, startOfDay(uc.now())) {
+		point := FlowPoint{Date: day.Format(dateLayout), Counts: map[string]int{}}
+		for _, status := range FlowStatuses {
+			point.Counts[status] = 0
+		}
+		end := day.AddDate(0, 0, 1)
+		for _, h := range histories {
+			if status, ok := h.StatusAt(end); ok {
+				point.Counts[status]++
+			}
+		}
+		dto.Points = append(dto.Points, point)
+	}
+	return dto
+}
+
+func (uc *ReportUseCase) throughput(rng ReportRange, histories []*domain.TaskHistory, from, to time.Time) *ThroughputDTO {
+	dto := &ThroughputDTO{ReportRange: rng, Points: []ThroughputPoint{}}
+	index := map[string]int{}
+	for _, day := range days(from, to, startOfDay(uc.now())) {
+		index[day.Format(dateLayout)] = len(dto.Points)
+		dto.Points = append(dto.Points, ThroughputPoint{Date: day.Format(dateLayout)})
+	}
+	for _, h := range histories {
+		completedAt, ok := h.CompletedAt()
+		if !ok {
+			continue
+		}
+		if i, ok := index[completedAt.UTC().Format(dateLayout)]; ok {
+			dto.Points[i].Completed++
+			dto.Points[i].CompletedEstimate += h.Estimate
+			dto.Total++
+		}
+	}
+	if len(dto.Points) > 0 {
+		dto.AveragePerDay = float64(dto.Total) / float64(len(dto.Points))
+	}
+	return dto
+}
+
+func (uc *ReportUseCase) cycleTime(rng ReportRange, histories []*domain.TaskHistory, from, to time.Time) *CycleTimeDTO {
+	dto := &CycleTimeDTO{ReportRange: rng, Tasks: []CycleTimeEntry{}}
+	end := to.AddDate(0, 0, 1)
+	var hours []float64
+	for _, h := range histories {
+		completedAt, ok := h.CompletedAt()
+		if !ok || completedAt.Before(from) || !completedAt.Before(end) {
+			continue
+		}
+		startedAt := h.StartedAt()
+		entry := CycleTimeEntry{
+			TaskID:         h.ID,
+			Title:          h.Title,
+			CreatedAt:      h.CreatedAt,
+			StartedAt:      startedAt,
+			CompletedAt:    completedAt,
+			CycleTimeHours: completedAt.Sub(startedAt).Hours(),
+			LeadTimeHours:  completedAt.Sub(h.CreatedAt).Hours(),
+		}
+		dto.Tasks = append(dto.Tasks, entry)
+		hours = append(hours, entry.CycleTimeHours)
+	}
+	sort.SliceStable(dto.Tasks, func(i, j int) bool { return dto.Tasks[i].CompletedAt.Before(dto.Tasks[j].CompletedAt) })
+
+	dto.Count = len(hours)
+	if dto.Count > 0 {
+		sort.Float64s(hours)
+		var sum float64
+		for _, h := range hours {
+			sum += h
+		}
+		dto.AverageHours = sum / float64(dto.Count)
+		dto.MedianHours = percentile(hours, 50)
+		dto.P85Hours = percentile(hours, 85)
+	}
+	return dto
+}
+
+func (uc *ReportUseCase) velocity(projectID string) (*VelocityDTO, error) {
+	velocities, err := uc.reports.SprintVelocities(projectID)
+	if err != nil {
+		return nil, err
+	}
+	dto := &VelocityDTO{ProjectID: projectID, Sprints: []VelocityPoint{}}
+	closed := 0
+	for _, v := range velocities {
+		dto.Sprints = append(dto.Sprints, VelocityPoint{
+			SprintID:          v.SprintID,
+			Name:              v.Name,
+			Status:            v.Status,
+			StartDate:         v.StartDate.UTC().Format(dateLayout),
+			EndDate:           v.EndDate.UTC().Format(dateLayout),
+			Planned:           v.Planned,
+			CompletedTasks:    v.CompletedTasks,
+			CompletedEstimate: v.CompletedEstimate,
+			RolledOver:        v.RolledOver,
+		})
+		if v.Status == planningdomain.SprintClosed {
+			closed++
+			dto.AverageTasks += float64(v.CompletedTasks)
+			dto.AverageEstimate += float64(v.CompletedEstimate)
+		}
+	}
+	if closed > 0 {
+		dto.AverageTasks /= float64(closed)
+		dto.AverageEstimate /= float64(closed)
+	}
+	return dto, nil
+}
+
+// percentile は昇順に並んだ values の p パーセンタイル（線形補間）です
+func percentile(values []float64, p float64) float64 {
+	pos := p / 100 * float64(len(values)-1)
+	lower := int(pos)
+	if lower+1 >= len(values) {
+		return values[len(values)-1]
+	}
+	return values[lower] + (values[lower+1]-values[lower])*(pos-float64(lower))
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(startOfDay(to).Sub(startOfDay(from)).Hours() / 24)
+}
+
+func fieldError(field, message string) error {
+	verr := apperrors.NewValidationError()
+	verr.Add(field, message)
+	return verr
+}
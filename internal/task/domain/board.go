@@ -0,0 +1,71 @@
+package domain
+
+import (
+    "errors"
+    "fmt"
+)
+
+// WIP 制限を超える移動の扱い
+const (
+    // WIPWarn は移動を許可し、警告を返します
+    WIPWarn = "warn"
+    // WIPReject は移動を拒否します
+    WIPReject = "reject"
+)
+
+// MaxColumnNameLength はかんばんボードの列名の最大文字数です
+const MaxColumnNameLength = 50
+
+var (
+    ErrInvalidWIPPolicy = errors.New("wip_policy must be one of warn, reject")
+    ErrInvalidWIPLimit  = errors.New("wip_limit must not be negative")
+    ErrColumnNameLength = errors.New("name must be 50 characters or less")
+)
+
+// BoardColumn はかんばんボードの列です。列はワークフローのステータスに 1 対 1 で対応します
+// WIPLimit は列に置けるタスク数の上限（0 は無制限）、WIPPolicy は上限を超える移動の扱いです
+type BoardColumn struct {
+    ProjectID string `json:"project_id"`
+    Status    string `json:"status"`
+    Name      string `json:"name"`
+    Position  int    `json:"position"`
+    WIPLimit  int    `json:"wip_limit"`
+    WIPPolicy string `json:"wip_policy"`
+}
+
+// DefaultColumns はワークフローのステータスの順に、ステータス名を列名とする列を返します
+func DefaultColumns(projectID string, wf *Workflow) []*BoardColumn {
+    columns := make([]*BoardColumn, 0, len(wf.States))
+    for i, status := range wf.States {
+        columns = append(columns, &BoardColumn{ProjectID: projectID, Status: status, Name: status, Position: i, WIPPolicy: WIPWarn})
+    }
+    return columns
+}
+
+// ValidateWIP は WIP 制限の設定を検証します
+func ValidateWIP(limit int, policy string) error {
+    if limit < 0 {
+        return ErrInvalidWIPLimit
+    }
+    if policy != WIPWarn && policy != WIPReject {
+        return ErrInvalidWIPPolicy
+    }
+    return nil
+}
+
+// WIPLimitError は移動すると列のタスク数が WIP 制限を超えることを表します
+type WIPLimitError struct {
+    Column string `json:"column"`
+    Limit  int    `json:"limit"`
+    Count  int    `json:"count"`
+}
+
+func (e *WIPLimitError) Error() string {
+    return fmt.Sprintf("column %s would exceed its WIP limit (%d/%d)", e.Column, e.Count, e.Limit)
+}
+
+// RankedTask はかんばんボードの列に並ぶタスクと順位です。Rank が空のタスクは列の最後（作成順）に並びます
+type RankedTask struct {
+    ID   string
+    Rank string
+}
@@ -0,0 +1,210 @@
+package domain
+
+import (
+    "errors"
+    "fmt"
+    "math"
+    "regexp"
+    "strings"
+    "time"
+)
+
+// カスタムフィールドの型
+const (
+    CustomFieldText        = "text"
+    CustomFieldNumber      = "number"
+    CustomFieldDate        = "date"
+    CustomFieldSelect      = "select"
+    CustomFieldMultiSelect = "multi_select"
+    CustomFieldUser        = "user"
+)
+
+const (
+    // MaxCustomFieldNameLength はカスタムフィールド名の最大文字数です
+    MaxCustomFieldNameLength = 50
+    // MaxCustomFieldOptions は選択肢の最大数、MaxCustomFieldOptionLength は選択肢の最大文字数です
+    MaxCustomFieldOptions      = 100
+    MaxCustomFieldOptionLength = 100
+    // MaxCustomTextLength は text 型の値の最大文字数です
+    MaxCustomTextLength = 1000
+    // CustomDateFormat は date 型の値の書式です
+    CustomDateFormat = "2006-01-02"
+)
+
+var (
+    ErrCustomFieldKeyInvalid    = errors.New("key must start with a lowercase letter and contain only lowercase letters, digits and underscores (at most 40 characters)")
+    ErrCustomFieldNameRequired  = errors.New("name is required")
+    ErrCustomFieldNameTooLong   = errors.New("name must be at most 50 characters")
+    ErrCustomFieldTypeInvalid   = errors.New("type must be one of text, number, date, select, multi_select, user")
+    ErrCustomFieldOptionsNeeded = errors.New("options are required for select and multi_select fields")
+    ErrCustomFieldOptionsUnused = errors.New("options can only be set for select and multi_select fields")
+    ErrCustomFieldOptionInvalid = errors.New("options must be unique non-empty strings of at most 100 characters (at most 100 options)")
+)
+
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+// CustomField はプロジェクトごとに定義するタスクの追加の項目です
+// Key はタスクの値・絞り込み・並び替え・Solr のフィールド名に使うため、Type と同じく作成後は変更できません
+type CustomField struct {
+    ID        string    `json:"id"`
+    ProjectID string    `json:"project_id"`
+    Key       string    `json:"key"`
+    Name      string    `json:"name"`
+    Type      string    `json:"type"`
+    // Options は select・multi_select 型の選択肢です（表示順）
+    Options   []string  `json:"options"`
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"`
+}
+
+func NewCustomField(id, projectID, key, name, fieldType string, options []string) *CustomField {
+    if options == nil {
+        options = []string{}
+    }
+    return &CustomField{
+        ID:        id,
+        ProjectID: projectID,
+        Key:       key,
+        Name:      name,
+        Type:      fieldType,
+        Options:   options,
+        CreatedAt: time.Now(),
+        UpdatedAt: time.Now(),
+    }
+}
+
+// ValidCustomFieldKey は key がカスタムフィールドのキーとして使えるかを返します
+func ValidCustomFieldKey(key string) bool {
+    return customFieldKeyPattern.MatchString(key)
+}
+
+// ValidateCustomFieldType はカスタムフィールドの型を検証します
+func ValidateCustomFieldType(fieldType string) error {
+    switch fieldType {
+    case CustomFieldText, CustomFieldNumber, CustomFieldDate, CustomFieldSelect, CustomFieldMultiSelect, CustomFieldUser:
+        return nil
+    }
+    return ErrCustomFieldTypeInvalid
+}
+
+// NormalizeCustomFieldName は前後の空白を取り除いたカスタムフィールド名を検証して返します
+func NormalizeCustomFieldName(name string) (string, error) {
+    name = strings.TrimSpace(name)
+    if name == "" {
+        return "", ErrCustomFieldNameRequired
+    }
+    if len([]rune(name)) > MaxCustomFieldNameLength {
+        return "", ErrCustomFieldNameTooLong
+    }
+    return name, nil
+}
+
+// NormalizeCustomFieldOptions は前後の空白を取り除いた選択肢を検証して返します
+// select・multi_select 型は 1 つ以上の選択肢が必要で、それ以外の型は選択肢を持てません
+func NormalizeCustomFieldOptions(fieldType string, options []string) ([]string, error) {
+    if fieldType != CustomFieldSelect && fieldType != CustomFieldMultiSelect {
+        if len(options) > 0 {
+            return nil, ErrCustomFieldOptionsUnused
+        }
+        return []string{}, nil
+    }
+    if len(options) == 0 {
+        return nil, ErrCustomFieldOptionsNeeded
+    }
+    if len(options) > MaxCustomFieldOptions {
+        return nil, ErrCustomFieldOptionInvalid
+    }
+    normalized := make([]string, 0, len(options))
+    seen := map[string]bool{}
+    for _, o := range options {
+        o = strings.TrimSpace(o)
+        if o == "" || seen[o] || len([]rune(o)) > MaxCustomFieldOptionLength {
+            return nil, ErrCustomFieldOptionInvalid
+        }
+        seen[o] = true
+        normalized = append(normalized, o)
+    }
+    return normalized, nil
+}
+
+// HasOption は option が選択肢に含まれるかを返します
+func (f *CustomField) HasOption(option string) bool {
+    for _, o := range f.Options {
+        if o == option {
+            return true
+        }
+    }
+    return false
+}
+
+// NormalizeValue は JSON から読み取ったタスクの値を型に合わせて検証し、保存する値を返します
+// nil・空文字列・空の配列は値の削除として nil を返します。user 型はユーザー ID で、メンバーかどうかは検証しません
+func (f *CustomField) NormalizeValue(value interface{}) (interface{}, error) {
+    if value == nil {
+        return nil, nil
+    }
+    switch f.Type {
+    case CustomFieldNumber:
+        n, ok := value.(float64)
+        if !ok || math.IsNaN(n) || math.IsInf(n, 0) {
+            return nil, errors.New("value must be a number")
+        }
+        return n, nil
+    case CustomFieldMultiSelect:
+        items, ok := value.([]interface{})
+        if !ok {
+            return nil, errors.New("value must be an array of options")
+        }
+        selected := []interface{}{}
+        seen := map[string]bool{}
+        for _, item := range items {
+            s, ok := item.(string)
+            if !ok || !f.HasOption(s) {
+                return nil, fmt.Errorf("value must be one of %s", strings.Join(f.Options, ", "))
+            }
+            if !seen[s] {
+                seen[s] = true
+                selected = append(selected, s)
+            }
+        }
+        if len(selected) == 0 {
+            return nil, nil
+        }
+        return selected, nil
+    }
+
+    s, ok := value.(string)
+    if !ok {
+        return nil, errors.New("value must be a string")
+    }
+    if s == "" {
+        return nil, nil
+    }
+    switch f.Type {
+    case CustomFieldText:
+        if len([]rune(s)) > MaxCustomTextLength {
+            return nil, errors.New("value must be at most 1000 characters")
+        }
+    case CustomFieldDate:
+        if _, err := time.Parse(CustomDateFormat, s); err != nil {
+            return nil, errors.New("value must be a date (YYYY-MM-DD)")
+        }
+    case CustomFieldSelect:
+        if !f.HasOption(s) {
+            return nil, fmt.Errorf("value must be one of %s", strings.Join(f.Options, ", "))
+        }
+    }
+    return s, nil
+}
+
+// CustomFieldSolrName はカスタムフィールドの値を Solr に登録する動的フィールドの名前を返します
+// 文字列は cf_<key>_s、数値は cf_<key>_d、複数選択は cf_<key>_ss です
+func CustomFieldSolrName(key string, value interface{}) string {
+    switch value.(type) {
+    case float64:
+        return "cf_" + key + "_d"
+    case []interface{}:
+        return "cf_" + key + "_ss"
+    }
+    return "cf_" + key + "_s"
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCustomFieldNormalizeValue(t *testing.T) {
+	options := []string{"dev", "staging", "prod"}
+	tests := []struct {
+		name      string
+		fieldType string
+		value     interface{}
+		want      interface{}
+		wantErr   bool
+	}{
+		{name: "text", fieldType: CustomFieldText, value: "Acme", want: "Acme"},
+		{name: "empty text clears", fieldType: CustomFieldText, value: "", want: nil},
+		{name: "null clears", fieldType: CustomFieldNumber, value: nil, want: nil},
+		{name: "number", fieldType: CustomFieldNumber, value: 5.0, want: 5.0},
+		{name: "number as string", fieldType: CustomFieldNumber, value: "5", wantErr: true},
+		{name: "date", fieldType: CustomFieldDate, value: "2024-03-01", want: "2024-03-01"},
+		{name: "timestamp is not a date", fieldType: CustomFieldDate, value: "2024-03-01T00:00:00Z", wantErr: true},
+		{name: "select", fieldType: CustomFieldSelect, value: "prod", want: "prod"},
+		{name: "unknown option", fieldType: CustomFieldSelect, value: "qa", wantErr: true},
+		{name: "multi select dedupes", fieldType: CustomFieldMultiSelect, value: []interface{}{"prod", "dev", "prod"}, want: []interface{}{"prod", "dev"}},
+		{name: "empty multi select clears", fieldType: CustomFieldMultiSelect, value: []interface{}{}, want: nil},
+		{name: "multi select needs array", fieldType: CustomFieldMultiSelect, value: "prod", wantErr: true},
+		{name: "user", fieldType: CustomFieldUser, value: "user-1", want: "user-1"},
+	}
+	for _, tt := range tests {
+		field := NewCustomField("f1", "p1", "env", "Environment", tt.fieldType, options)
+		got, err := field.NormalizeValue(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: NormalizeValue(%v) error = %v, wantErr %v", tt.name, tt.value, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: NormalizeValue(%v) = %v, want %v", tt.name, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeCustomFieldOptions(t *testing.T) {
+	got, err := NormalizeCustomFieldOptions(CustomFieldSelect, []string{" dev ", "prod"})
+	if err != nil || !reflect.DeepEqual(got, []string{"dev", "prod"}) {
+		t.Errorf("NormalizeCustomFieldOptions = %v, %v", got, err)
+	}
+	if _, err := NormalizeCustomFieldOptions(CustomFieldSelect, nil); err != ErrCustomFieldOptionsNeeded {
+		t.Errorf("select without options: err = %v", err)
+	}
+	if _, err := NormalizeCustomFieldOptions(CustomFieldSelect, []string{"dev", "dev"}); err != ErrCustomFieldOptionInvalid {
+		t.Errorf("duplicate options: err = %v", err)
+	}
+	if _, err := NormalizeCustomFieldOptions(CustomFieldText, []string{"dev"}); err != ErrCustomFieldOptionsUnused {
+		t.Errorf("text with options: err = %v", err)
+	}
+}
+
+func TestValidCustomFieldKey(t *testing.T) {
+	for key, want := range map[string]bool{
+		"story_points": true,
+		"env2":         true,
+		"2env":         false,
+		"Env":          false,
+		"":             false,
+		"a-b":          false,
+	} {
+		if got := ValidCustomFieldKey(key); got != want {
+			t.Errorf("ValidCustomFieldKey(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+)
+
+// TaskDependency は TaskID のタスクが DependsOnID のタスクの完了を待つ（blocked by）関係を表します
+type TaskDependency struct {
+    TaskID      string    `json:"task_id"`
+    DependsOnID string    `json:"depends_on_id"`
+    CreatedBy   string    `json:"created_by"`
+    CreatedAt   time.Time `json:"created_at"`
+}
+
+func NewTaskDependency(taskID, dependsOnID, createdBy string) *TaskDependency {
+    return &TaskDependency{
+        TaskID:      taskID,
+        DependsOnID: dependsOnID,
+        CreatedBy:   createdBy,
+        CreatedAt:   time.Now(),
+    }
+}
+
+var (
+    ErrSelfDependency  = errors.New("a task cannot depend on itself")
+    ErrDependencyCycle = errors.New("dependency would create a cycle")
+)
+
+// BlockedError は未完了のブロッカーがあるためにステータスを遷移できないことを表します
+type BlockedError struct {
+    To       string   `json:"to"`
+    Blockers []string `json:"blockers"`
+}
+
+func (e *BlockedError) Error() string {
+    return fmt.Sprintf("cannot move to %s while blocked by open tasks: %s", e.To, strings.Join(e.Blockers, ", "))
+}
+
+// RequiresUnblocked は to への遷移にブロッカーの完了が必要かを返します
+func RequiresUnblocked(to string) bool {
+    return to == StatusInProgress || to == StatusDone
+}
@@ -0,0 +1,74 @@
+package domain
+
+import (
+    "errors"
+    "regexp"
+    "strings"
+    "time"
+)
+
+// Label はプロジェクトごとに定義するタスクのラベル（タグ）です
+type Label struct {
+    ID        string    `json:"id"`
+    ProjectID string    `json:"project_id"`
+    Name      string    `json:"name"`
+    Color     string    `json:"color"`
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TaskLabel はタスクに付いているラベルの表示用の情報です
+type TaskLabel struct {
+    ID    string `json:"id"`
+    Name  string `json:"name"`
+    Color string `json:"color"`
+}
+
+// DefaultLabelColor は色が指定されなかった場合のラベルの色です
+const DefaultLabelColor = "#9e9e9e"
+
+// MaxLabelNameLength はラベル名の最大文字数です
+const MaxLabelNameLength = 50
+
+var (
+    ErrLabelNameRequired = errors.New("name is required")
+    ErrLabelNameTooLong  = errors.New("name must be at most 50 characters")
+    ErrInvalidLabelColor = errors.New("color must be a hex color such as #ff8800")
+)
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+
+func NewLabel(id, projectID, name, color string) *Label {
+    return &Label{
+        ID:        id,
+        ProjectID: projectID,
+        Name:      name,
+        Color:     color,
+        CreatedAt: time.Now(),
+        UpdatedAt: time.Now(),
+    }
+}
+
+// NormalizeLabelName は前後の空白を取り除いたラベル名を検証して返します
+func NormalizeLabelName(name string) (string, error) {
+    name = strings.TrimSpace(name)
+    if name == "" {
+        return "", ErrLabelNameRequired
+    }
+    if len([]rune(name)) > MaxLabelNameLength {
+        return "", ErrLabelNameTooLong
+    }
+    return name, nil
+}
+
+// NormalizeLabelColor は #RRGGBB 形式の色を小文字にそろえて返します（空の場合は既定の色）
+func NormalizeLabelColor(color string) (string, error) {
+    color = strings.ToLower(strings.TrimSpace(color))
+    if color == "" {
+        return DefaultLabelColor, nil
+    }
+    if !labelColorPattern.MatchString(color) {
+        return "", ErrInvalidLabelColor
+    }
+    return color, nil
+}
@@ -0,0 +1,54 @@
+package domain
+
+import "strings"
+
+// rankDigits は順位に使う文字です。ASCII（PostgreSQL の COLLATE "C"）の順に並んでいます
+const rankDigits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// RankBetween は a と b の間に並ぶ順位を返します（a < 結果 < b）
+// a が空の場合は先頭、b が空の場合は末尾に並ぶ順位です。a と b がどちらも空でなければ a < b である必要があります
+// 順位は辞書順で比較する文字列で、前後の順位を変えずにいくらでも間に挿入できます（末尾が "0" になることはありません）
+func RankBetween(a, b string) string {
+    if b != "" {
+        // 共通の接頭辞（a は足りない桁を "0" とみなす）はそのまま使う
+        n := 0
+        for n < len(b) && rankDigitAt(a, n) == b[n] {
+            n++
+        }
+        if n > 0 {
+            rest := ""
+            if n < len(a) {
+                rest = a[n:]
+            }
+            return b[:n] + RankBetween(rest, b[n:])
+        }
+    }
+
+    lo := 0
+    if a != "" {
+        lo = strings.IndexByte(rankDigits, a[0])
+    }
+    hi := len(rankDigits)
+    if b != "" {
+        hi = strings.IndexByte(rankDigits, b[0])
+    }
+    if hi-lo > 1 {
+        return string(rankDigits[(lo+hi+1)/2])
+    }
+    // 隣り合う桁: b の先頭の 1 文字が b より前に並ぶか、a の次の桁で間を探す
+    if len(b) > 1 {
+        return b[:1]
+    }
+    rest := ""
+    if len(a) > 1 {
+        rest = a[1:]
+    }
+    return string(rankDigits[lo]) + RankBetween(rest, "")
+}
+
+func rankDigitAt(s string, i int) byte {
+    if i < len(s) {
+        return s[i]
+    }
+    return rankDigits[0]
+}
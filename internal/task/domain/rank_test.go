@@ -0,0 +1,44 @@
+package domain
+
+import "testing"
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{a: "", b: "", want: "V"},
+		{a: "V", b: "", want: "l"},
+		{a: "", b: "V", want: "G"},
+		{a: "V", b: "W", want: "VV"},
+		{a: "V", b: "V1", want: "V0V"},
+		{a: "z", b: "", want: "zV"},
+		{a: "Vz", b: "W", want: "VzV"},
+		{a: "000001V", b: "000002V", want: "000002"},
+	}
+	for _, tt := range tests {
+		got := RankBetween(tt.a, tt.b)
+		if got != tt.want {
+			t.Errorf("RankBetween(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+		if got <= tt.a || (tt.b != "" && got >= tt.b) {
+			t.Errorf("RankBetween(%q, %q) = %q is not between", tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestRankBetweenRepeatedInserts(t *testing.T) {
+	// 同じ位置への挿入を繰り返しても順序が保たれる
+	lo, hi := "", ""
+	for i := 0; i < 200; i++ {
+		mid := RankBetween(lo, hi)
+		if mid <= lo || (hi != "" && mid >= hi) || mid[len(mid)-1] == '0' {
+			t.Fatalf("step %d: RankBetween(%q, %q) = %q", i, lo, hi, mid)
+		}
+		if i%2 == 0 {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package domain
+
+import (
+    "errors"
+    "fmt"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// 繰り返しの頻度（RFC 5545 の FREQ のうち対応しているもの）
+const (
+    FreqDaily   = "DAILY"
+    FreqWeekly  = "WEEKLY"
+    FreqMonthly = "MONTHLY"
+    FreqYearly  = "YEARLY"
+)
+
+var ErrInvalidRecurrence = errors.New("recurrence must be an RRULE with FREQ=DAILY|WEEKLY|MONTHLY|YEARLY and optional INTERVAL, BYDAY, UNTIL or COUNT")
+
+var weekdayCodes = map[string]time.Weekday{
+    "SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+    "TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+var untilFormats = []string{"20060102T150405Z", "20060102T150405", "20060102"}
+
+// maxRecurrenceSteps は次の発生日時を探す際の反復回数の上限です
+const maxRecurrenceSteps = 20000
+
+// RecurrenceRule は RFC 5545 の RRULE のサブセットです
+//
+//  FREQ=DAILY|WEEKLY|MONTHLY|YEARLY（必須）, INTERVAL=n, BYDAY=MO,WE,...,
+//  UNTIL=YYYYMMDD[THHMMSS[Z]], COUNT=n（UNTIL と COUNT は同時に指定できません）
+//
+// 発生日時は系列の最初の発生日時（DTSTART）を起点に計算し、時刻は DTSTART の時刻を維持します
+type RecurrenceRule struct {
+    Freq     string
+    Interval int
+    ByDay    []time.Weekday
+    Until    *time.Time
+    Count    int
+}
+
+// ParseRecurrenceRule は "FREQ=WEEKLY;BYDAY=MO,WE" 形式（先頭の "RRULE:" は省略可）を解析します
+func ParseRecurrenceRule(s string) (*RecurrenceRule, error) {
+    s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+    rule := &RecurrenceRule{Interval: 1}
+    for _, part := range strings.Split(s, ";") {
+        if part == "" {
+            continue
+        }
+        kv := strings.SplitN(part, "=", 2)
+        if len(kv) != 2 {
+            return nil, ErrInvalidRecurrence
+        }
+        key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+        switch key {
+        case "FREQ":
+            switch value {
+            case FreqDaily, FreqWeekly, FreqMonthly, FreqYearly:
+                rule.Freq = value
+            default:
+                return nil, ErrInvalidRecurrence
+            }
+        case "INTERVAL":
+            n, err := strconv.Atoi(value)
+            if err != nil || n < 1 {
+                return nil, ErrInvalidRecurrence
+            }
+            rule.Interval = n
+        case "BYDAY":
+            for _, code := range strings.Split(value, ",") {
+                day, ok := weekdayCodes[code]
+                if !ok {
+                    return nil, ErrInvalidRecurrence
+                }
+                rule.ByDay = append(rule.ByDay, day)
+            }
+        case "UNTIL":
+            until, ok := parseUntil(value)
+            if !ok {
+                return nil, ErrInvalidRecurrence
+            }
+            rule.Until = &until
+        case "COUNT":
+            n, err := strconv.Atoi(value)
+            if err != nil || n < 1 {
+                return nil, ErrInvalidRecurrence
+            }
+            rule.Count = n
+        default:
+            return nil, ErrInvalidRecurrence
+        }
+    }
+    if rule.Freq == "" || (rule.Until != nil && rule.Count > 0) {
+        return nil, ErrInvalidRecurrence
+    }
+    return rule, nil
+}
+
+func parseUntil(v string) (time.Time, bool) {
+    for _, format := range untilFormats {
+        if t, err := time.Parse(format, v); err == nil {
+            // 日付のみの場合はその日の終わりまでを含める
+            if format == "20060102" {
+                t = t.Add(24*time.Hour - time.Second)
+            }
+            return t, true
+        }
+    }
+    return time.Time{}, false
+}
+
+// String は正規化した RRULE 文字列を返します
+func (r *RecurrenceRule) String() string {
+    parts := []string{"FREQ=" + r.Freq}
+    if r.Interval > 1 {
+        parts = append(parts, fmt.Sprintf("INTERVAL=%d", r.Interval))
+    }
+    if len(r.ByDay) > 0 {
+        codes := make([]string, len(r.ByDay))
+        for i, day := range r.ByDay {
+            codes[i] = strings.ToUpper(day.String()[:2])
+        }
+        parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+    }
+    if r.Until != nil {
+        parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+    }
+    if r.Count > 0 {
+        parts = append(parts, fmt.Sprintf("COUNT=%d", r.Count))
+    }
+    return strings.Join(parts, ";")
+}
+
+// NextAfter は after より後の最初の発生日時と、それが系列の何回目の発生かを返します
+// start は系列の最初の発生日時、last は index 回目の発生日時です。after までの発生は飛ばしますが、COUNT の回数には数えます
+// 日時の計算は start のロケーションで行うため、夏時間をまたいでも現地の時刻が維持されます
+// COUNT・UNTIL により次の発生がない場合は false を返します
+func (r *RecurrenceRule) NextAfter(start, last time.Time, index int, after time.Time) (time.Time, int, bool) {
+    loc := start.Location()
+    cur := last.In(loc)
+    for i := 0; i < maxRecurrenceSteps; i++ {
+        next, ok := r.next(start, cur)
+        if !ok {
+            return time.Time{}, 0, false
+        }
+        index++
+        if r.Count > 0 && index > r.Count {
+            return time.Time{}, 0, false
+        }
+        if r.Until != nil && next.After(*r.Until) {
+            return time.Time{}, 0, false
+        }
+        if next.After(after) {
+            return next, index, true
+        }
+        cur = next
+    }
+    return time.Time{}, 0, false
+}
+
+// next は prev より後の最初の発生日時を返します
+func (r *RecurrenceRule) next(start, prev time.Time) (time.Time, bool) {
+    if len(r.ByDay) == 0 {
+        for n := 1; n < maxRecurrenceSteps; n++ {
+            if t, ok := r.nth(start, n); ok && t.After(prev) {
+                return t, true
+            }
+        }
+        return time.Time{}, false
+    }
+
+    // BYDAY 指定時は翌日から1日ずつ、曜日と INTERVAL の周期が一致する日を探す
+    day := time.Date(prev.Year(), prev.Month(), prev.Day()+1, start.Hour(), start.Minute(), start.Second(), 0, start.Location())
+    for i := 0; i < maxRecurrenceSteps; i++ {
+        t := day.AddDate(0, 0, i)
+        if r.hasDay(t.Weekday()) && r.period(start, t)%r.Interval == 0 {
+            return t, true
+        }
+    }
+    return time.Time{}, false
+}
+
+// nth は BYDAY なしの場合の n 回目の周期の日時を返します
+// 月末や閏日のように該当日が存在しない周期は false を返します（RFC 5545 と同様に飛ばす）
+func (r *RecurrenceRule) nth(start time.Time, n int) (time.Time, bool) {
+    step := n * r.Interval
+    switch r.Freq {
+    case FreqDaily:
+        return start.AddDate(0, 0, step), true
+    case FreqWeekly:
+        return start.AddDate(0, 0, 7*step), true
+    case FreqMonthly:
+        t := start.AddDate(0, step, 0)
+        return t, t.Day() == start.Day()
+    default:
+        t := start.AddDate(step, 0, 0)
+        return t, t.Day() == start.Day()
+    }
+}
+
+func (r *RecurrenceRule) hasDay(day time.Weekday) bool {
+    for _, d := range r.ByDay {
+        if d == day {
+            return true
+        }
+    }
+    return false
+}
+
+// period は start から t までに経過した周期（日・週・月・年）の数です。週は月曜始まりで数えます
+func (r *RecurrenceRule) period(start, t time.Time) int {
+    switch r.Freq {
+    case FreqDaily:
+        return daysBetween(start, t)
+    case FreqWeekly:
+        return daysBetween(weekStart(start), weekStart(t)) / 7
+    case FreqMonthly:
+        return (t.Year()-start.Year())*12 + int(t.Month()-start.Month())
+    default:
+        return t.Year() - start.Year()
+    }
+}
+
+func daysBetween(a, b time.Time) int {
+    da := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+    db := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+    return int(db.Sub(da).Hours() / 24)
+}
+
+func weekStart(t time.Time) time.Time {
+    offset := (int(t.Weekday()) + 6) % 7
+    return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,137 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func at(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 9, 0, 0, 0, time.UTC)
+}
+
+func TestParseRecurrenceRule(t *testing.T) {
+	until := time.Date(2030, 3, 31, 23, 59, 59, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want *RecurrenceRule
+		str  string
+	}{
+		{in: "FREQ=DAILY", want: &RecurrenceRule{Freq: FreqDaily, Interval: 1}, str: "FREQ=DAILY"},
+		{in: "RRULE:freq=weekly;interval=2;byday=MO,WE", want: &RecurrenceRule{Freq: FreqWeekly, Interval: 2, ByDay: []time.Weekday{time.Monday, time.Wednesday}}, str: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE"},
+		{in: "FREQ=MONTHLY;UNTIL=20300331", want: &RecurrenceRule{Freq: FreqMonthly, Interval: 1, Until: &until}, str: "FREQ=MONTHLY;UNTIL=20300331T235959Z"},
+		{in: "FREQ=YEARLY;COUNT=3;", want: &RecurrenceRule{Freq: FreqYearly, Interval: 1, Count: 3}, str: "FREQ=YEARLY;COUNT=3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRecurrenceRule(tt.in)
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRule(%q) error = %v", tt.in, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRecurrenceRule(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+			if s := got.String(); s != tt.str {
+				t.Errorf("String() = %q, want %q", s, tt.str)
+			}
+		})
+	}
+
+	for _, in := range []string{"", "INTERVAL=2", "FREQ=HOURLY", "FREQ=DAILY;INTERVAL=0", "FREQ=WEEKLY;BYDAY=XX", "FREQ=DAILY;COUNT=2;UNTIL=20300101", "FREQ=DAILY;UNTIL=tomorrow", "FREQ=DAILY;BYMONTH=1", "FREQ"} {
+		if _, err := ParseRecurrenceRule(in); err != ErrInvalidRecurrence {
+			t.Errorf("ParseRecurrenceRule(%q) error = %v, want ErrInvalidRecurrence", in, err)
+		}
+	}
+}
+
+func TestNextAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		rule  string
+		start time.Time
+		want  []time.Time
+		// ends は want の後に発生がないことを表します
+		ends bool
+	}{
+		{
+			name:  "month end skips short months",
+			rule:  "FREQ=MONTHLY",
+			start: at(2030, 1, 31),
+			want:  []time.Time{at(2030, 3, 31), at(2030, 5, 31), at(2030, 7, 31)},
+		},
+		{
+			name:  "leap day",
+			rule:  "FREQ=YEARLY",
+			start: at(2028, 2, 29),
+			want:  []time.Time{at(2032, 2, 29)},
+		},
+		{
+			name:  "byday every other week",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR",
+			start: at(2030, 1, 7), // 月曜日
+			want:  []time.Time{at(2030, 1, 11), at(2030, 1, 21), at(2030, 1, 25), at(2030, 2, 4)},
+		},
+		{
+			name:  "count",
+			rule:  "FREQ=DAILY;COUNT=3",
+			start: at(2030, 1, 1),
+			want:  []time.Time{at(2030, 1, 2), at(2030, 1, 3)},
+			ends:  true,
+		},
+		{
+			name:  "until",
+			rule:  "FREQ=WEEKLY;UNTIL=20300115",
+			start: at(2030, 1, 1),
+			want:  []time.Time{at(2030, 1, 8), at(2030, 1, 15)},
+			ends:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRecurrenceRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRecurrenceRule(%q) error = %v", tt.rule, err)
+			}
+			last, index := tt.start, 1
+			for i, want := range tt.want {
+				next, n, ok := rule.NextAfter(tt.start, last, index, last)
+				if !ok || !next.Equal(want) || n != index+1 {
+					t.Fatalf("occurrence %d = %v, %d, %v; want %v, %d, true", i+2, next, n, ok, want, index+1)
+				}
+				last, index = next, n
+			}
+			if next, _, ok := rule.NextAfter(tt.start, last, index, last); tt.ends && ok {
+				t.Errorf("NextAfter after the last occurrence = %v, want none", next)
+			}
+		})
+	}
+}
+
+func TestNextAfterSkipsPastOccurrences(t *testing.T) {
+	rule, err := ParseRecurrenceRule("FREQ=DAILY;COUNT=10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := at(2030, 1, 1)
+	// 期限切れの発生は飛ばすが COUNT には数える
+	next, index, ok := rule.NextAfter(start, start, 1, at(2030, 1, 5))
+	if !ok || !next.Equal(at(2030, 1, 6)) || index != 6 {
+		t.Errorf("NextAfter = %v, %d, %v; want %v, 6, true", next, index, ok, at(2030, 1, 6))
+	}
+	if _, _, ok := rule.NextAfter(start, start, 1, at(2030, 1, 10)); ok {
+		t.Error("NextAfter beyond COUNT should report no occurrence")
+	}
+}
+
+func TestNextAfterKeepsLocalTimeAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+	rule, _ := ParseRecurrenceRule("FREQ=WEEKLY")
+	start := time.Date(2030, 3, 4, 9, 0, 0, 0, loc)
+	next, _, ok := rule.NextAfter(start, start, 1, start)
+	if !ok || next.Hour() != 9 || next.Day() != 11 {
+		t.Errorf("NextAfter = %v, want 2030-03-11 09:00 local time", next)
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+    "time"
+)
+
+// TaskSeries は繰り返しタスクの系列です
+// 次の発生（タスク）はこの系列の内容から生成されるため、「以降すべて」の編集は系列に反映します
+type TaskSeries struct {
+    ID          string    `json:"id"`
+    Rule        string    `json:"rule"`
+    // Start は繰り返しの起点（DTSTART）です
+    Start       time.Time `json:"start"`
+    // LastDue は最後に生成した発生の期限で、Occurrences はその発生が起点から何回目かを表します
+    LastDue     time.Time `json:"last_due"`
+    Occurrences int       `json:"occurrences"`
+    // Ended は COUNT・UNTIL に達したか繰り返しが解除され、これ以上発生を生成しないことを表します
+    Ended       bool      `json:"ended"`
+    Title       string    `json:"title"`
+    Description string    `json:"description"`
+    Priority    string    `json:"priority"`
+    ProjectID   string    `json:"project_id"`
+    AssigneeID  string    `json:"assignee_id"`
+    CreatedBy   string    `json:"created_by"`
+    CreatedAt   time.Time `json:"created_at"`
+    UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// NewTaskSeries は task を最初の発生とする系列を作成します
+func NewTaskSeries(id, rule string, task *Task) *TaskSeries {
+    return &TaskSeries{
+        ID:          id,
+        Rule:        rule,
+        Start:       task.DueDate,
+        LastDue:     task.DueDate,
+        Occurrences: 1,
+        Title:       task.Title,
+        Description: task.Description,
+        Priority:    task.Priority,
+        ProjectID:   task.ProjectID,
+        AssigneeID:  task.AssigneeID,
+        CreatedBy:   task.CreatedBy,
+        CreatedAt:   time.Now(),
+        UpdatedAt:   time.Now(),
+    }
+}
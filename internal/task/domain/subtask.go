@@ -11,6 +11,19 @@ type Subtask struct {
     CreatedAt  time.Time `json:"created_at"`
     UpdatedAt  time.Time `json:"updated_at"`
     TaskID     string    `json:"task_id"`
+    // Position は手動並び替え用の表示順（0 始まり）です
+    Position   int       `json:"position"`
+}
+
+// SubtaskProgress はタスクのサブタスク完了数と総数です
+type SubtaskProgress struct {
+    Done  int `json:"done"`
+    Total int `json:"total"`
+}
+
+// Complete はサブタスクが1件以上あり、すべて完了しているかを返します
+func (p SubtaskProgress) Complete() bool {
+    return p.Total > 0 && p.Done == p.Total
 }
 
 func NewSubtask(id, title, taskID string) *Subtask {
@@ -16,10 +16,37 @@ type Task struct {
     UpdatedAt   time.Time `json:"updated_at"`
     ProjectID   string    `json:"project_id"`
     AssigneeID  string    `json:"assignee_id"`
+    // AssigneeIDs はタスクの担当者です。先頭が主担当者で、AssigneeID と一致します
+    AssigneeIDs []string `json:"assignee_ids"`
+    Version     int       `json:"version"`
+    DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+    // SubtaskProgress は保存されず、取得時にサブタスクから集計されます
+    SubtaskProgress SubtaskProgress `json:"subtask_progress"`
+    // SeriesID は繰り返しタスクの系列、Occurrence は系列の起点から何回目の発生かを表します
+    SeriesID   string `json:"series_id,omitempty"`
+    Occurrence int    `json:"occurrence,omitempty"`
+    // Recurrence は系列の RRULE です（保存されず、取得時に系列から読み込みます）
+    Recurrence string `json:"recurrence,omitempty"`
+    // Labels は保存されず、取得時にタスクのラベルから読み込みます
+    Labels []TaskLabel `json:"labels"`
+    // Estimate は見積もり時間（分）で、0 は未見積もりです
+    Estimate int `json:"estimate"`
+    // TimeSpent は記録済みの作業時間（分）の合計です（保存されず、取得時に作業時間の記録から集計します）
+    TimeSpent int `json:"time_spent"`
+    // SprintID と MilestoneID はタスクを予定したスプリント・マイルストーンです（空の場合は未定）
+    SprintID    string `json:"sprint_id"`
+    MilestoneID string `json:"milestone_id"`
+    // StartDate はタイムライン上の開始日、DurationDays は日数です（ゼロ値は未設定。Span を参照）
+    StartDate    time.Time `json:"start_date"`
+    DurationDays int       `json:"duration_days"`
+    // Rank はかんばんボードの列内の順位です（辞書順。空の場合は列の最後に作成順で並びます）
+    Rank string `json:"rank"`
+    // CustomFields はプロジェクトのカスタムフィールドの値です（キーはフィールドの Key。値のない項目は含みません）
+    CustomFields map[string]interface{} `json:"custom_fields"`
 }
 
 func NewTask(id, title, description, projectID, assigneeID string, dueDate time.Time, priority, status string, createdBy string) *Task {
-    return &Task{
+    task := &Task{
         ID:          id,
         Title:       title,
         Description: description,
@@ -30,6 +57,43 @@ func NewTask(id, title, description, projectID, assigneeID string, dueDate time.
         CreatedAt:   time.Now(),
         UpdatedAt:   time.Now(),
         ProjectID:   projectID,
-        AssigneeID:  assigneeID,
+        Version:     1,
+        CustomFields: map[string]interface{}{},
     }
+    task.SetAssignees(nil)
+    if assigneeID != "" {
+        task.SetAssignees([]string{assigneeID})
+    }
+    return task
+}
+
+// SetAssignees は担当者を重複と空文字列を除いて設定し、先頭を主担当者（AssigneeID）にします
+func (t *Task) SetAssignees(ids []string) {
+    assignees := []string{}
+    seen := map[string]bool{}
+    for _, id := range ids {
+        if id == "" || seen[id] {
+            continue
+        }
+        seen[id] = true
+        assignees = append(assignees, id)
+    }
+    t.AssigneeIDs = assignees
+    t.AssigneeID = ""
+    if len(assignees) > 0 {
+        t.AssigneeID = assignees[0]
+    }
+}
+
+// HasAssignee は userID がタスクの担当者かを返します
+func (t *Task) HasAssignee(userID string) bool {
+    if userID == "" {
+        return false
+    }
+    for _, id := range t.AssigneeIDs {
+        if id == userID {
+            return true
+        }
+    }
+    return t.AssigneeID == userID
 }
@@ -0,0 +1,114 @@
+package domain
+
+import (
+    "errors"
+    "fmt"
+    "time"
+)
+
+// TimeEntry はタスクの作業時間の記録です
+// タイマーで計測中の記録は EndedAt が nil で、停止時に Minutes が確定します
+type TimeEntry struct {
+    ID        string     `json:"id"`
+    TaskID    string     `json:"task_id"`
+    UserID    string     `json:"user_id"`
+    StartedAt time.Time  `json:"started_at"`
+    EndedAt   *time.Time `json:"ended_at"`
+    // Minutes は作業時間（分）です
+    Minutes   int        `json:"minutes"`
+    Note      string     `json:"note"`
+    // Source は記録の方法（timer / manual）です
+    Source    string     `json:"source"`
+    CreatedAt time.Time  `json:"created_at"`
+    UpdatedAt time.Time  `json:"updated_at"`
+}
+
+const (
+    TimeEntrySourceTimer  = "timer"
+    TimeEntrySourceManual = "manual"
+)
+
+// MaxTimeEntryMinutes は手入力の記録1件あたりの作業時間の上限（24時間）です
+const MaxTimeEntryMinutes = 24 * 60
+
+// MaxTimeEntryNoteLength はメモの最大文字数です
+const MaxTimeEntryNoteLength = 1000
+
+var (
+    ErrInvalidMinutes   = fmt.Errorf("minutes must be between 1 and %d", MaxTimeEntryMinutes)
+    ErrNoteTooLong      = fmt.Errorf("note must be at most %d characters", MaxTimeEntryNoteLength)
+    ErrInvalidEstimate  = errors.New("estimate must not be negative")
+    ErrTimerNotRunning  = errors.New("no timer is running for this task")
+    ErrTimeEntryRunning = errors.New("a running timer cannot be edited; stop it first")
+)
+
+// TimerRunningError はユーザーのタイマーが既に計測中であることを表します（タイマーはユーザーごとに1つ）
+type TimerRunningError struct {
+    TaskID  string `json:"task_id"`
+    EntryID string `json:"entry_id"`
+}
+
+func (e *TimerRunningError) Error() string {
+    return fmt.Sprintf("a timer is already running for task %s", e.TaskID)
+}
+
+// StartTimer は now から計測を始めるタイマーの記録を作成します
+func StartTimer(id, taskID, userID string, now time.Time) *TimeEntry {
+    return &TimeEntry{
+        ID:        id,
+        TaskID:    taskID,
+        UserID:    userID,
+        StartedAt: now,
+        Source:    TimeEntrySourceTimer,
+        CreatedAt: now,
+        UpdatedAt: now,
+    }
+}
+
+// NewManualTimeEntry は手入力の記録を作成します。終了日時は startedAt + minutes です
+func NewManualTimeEntry(id, taskID, userID string, startedAt time.Time, minutes int, note string) *TimeEntry {
+    endedAt := startedAt.Add(time.Duration(minutes) * time.Minute)
+    return &TimeEntry{
+        ID:        id,
+        TaskID:    taskID,
+        UserID:    userID,
+        StartedAt: startedAt,
+        EndedAt:   &endedAt,
+        Minutes:   minutes,
+        Note:      note,
+        Source:    TimeEntrySourceManual,
+        CreatedAt: time.Now(),
+        UpdatedAt: time.Now(),
+    }
+}
+
+// Running はタイマーが計測中かを返します
+func (e *TimeEntry) Running() bool {
+    return e.EndedAt == nil
+}
+
+// Stop はタイマーを now で停止します。作業時間は分単位に切り上げます
+func (e *TimeEntry) Stop(now time.Time) {
+    if now.Before(e.StartedAt) {
+        now = e.StartedAt
+    }
+    e.EndedAt = &now
+    e.Minutes = int((now.Sub(e.StartedAt) + time.Minute - 1) / time.Minute)
+    e.UpdatedAt = now
+}
+
+// ValidateMinutes は手入力の作業時間を検証します
+func ValidateMinutes(minutes int) error {
+    if minutes < 1 || minutes > MaxTimeEntryMinutes {
+        return ErrInvalidMinutes
+    }
+    return nil
+}
+
+// ValidateEstimate はタスクの見積もり時間（分）を検証します。0 は未見積もりです
+func ValidateEstimate(estimate int) error {
+    if estimate < 0 {
+        return ErrInvalidEstimate
+    }
+    return nil
+}
@@ -0,0 +1,215 @@
+package domain
+
+import (
+    "errors"
+    "sort"
+    "time"
+)
+
+// MaxDurationDays はタスクの日数として指定できる最大値です
+const MaxDurationDays = 3650
+
+var (
+    ErrInvalidDuration = errors.New("duration_days must be between 0 and 3650")
+    ErrStartAfterDue   = errors.New("start_date must not be after due_date")
+)
+
+// ValidateSchedule はタスクの開始日・日数・期限の組み合わせを検証します
+func ValidateSchedule(start, due time.Time, durationDays int) error {
+    if durationDays < 0 || durationDays > MaxDurationDays {
+        return ErrInvalidDuration
+    }
+    if !start.IsZero() && !due.IsZero() && Day(start).After(Day(due)) {
+        return ErrStartAfterDue
+    }
+    return nil
+}
+
+// Day は t の日付（UTC の 0 時）を返します
+func Day(t time.Time) time.Time {
+    t = t.UTC()
+    return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// DaysBetween は from の日付から to の日付までの日数です
+func DaysBetween(from, to time.Time) int {
+    return int(Day(to).Sub(Day(from)).Hours() / 24)
+}
+
+// Span はタイムライン上のタスクの期間（両端の日付を含む）を返します
+// 開始日があれば開始日から DurationDays 日（未設定なら期限まで、期限もなければ 1 日）、
+// 開始日がなく期限があれば期限に終わる DurationDays 日（未設定なら 1 日）です。どちらもなければ false を返します
+func (t *Task) Span() (start, end time.Time, ok bool) {
+    switch {
+    case !t.StartDate.IsZero():
+        start = Day(t.StartDate)
+        switch {
+        case t.DurationDays > 0:
+            end = start.AddDate(0, 0, t.DurationDays-1)
+        case !t.DueDate.IsZero() && !Day(t.DueDate).Before(start):
+            end = Day(t.DueDate)
+        default:
+            end = start
+        }
+    case !t.DueDate.IsZero():
+        end = Day(t.DueDate)
+        start = end
+        if t.DurationDays > 0 {
+            start = end.AddDate(0, 0, 1-t.DurationDays)
+        }
+    default:
+        return time.Time{}, time.Time{}, false
+    }
+    return start, end, true
+}
+
+// Shift はタスクの開始日と期限（設定されている場合）を days 日ずらします
+func (t *Task) Shift(days int) {
+    if !t.StartDate.IsZero() {
+        t.StartDate = t.StartDate.AddDate(0, 0, days)
+    }
+    if !t.DueDate.IsZero() {
+        t.DueDate = t.DueDate.AddDate(0, 0, days)
+    }
+}
+
+// ScheduleItem はクリティカルパスを計算するタスクの予定の期間です（両端の日付を含む）
+type ScheduleItem struct {
+    ID    string
+    Start time.Time
+    End   time.Time
+}
+
+// ScheduleResult は依存関係を考慮した最早・最遅の開始日と終了日です
+// Slack は全体の終了日を遅らせずにずらせる日数で、0 のタスクがクリティカルです
+type ScheduleResult struct {
+    EarliestStart  time.Time
+    EarliestFinish time.Time
+    LatestStart    time.Time
+    LatestFinish   time.Time
+    Slack          int
+    Critical       bool
+}
+
+// Schedule は items の予定と依存関係からクリティカルパス法で日程を計算します
+// 各タスクは予定の開始日より前には始めず、依存先（DependsOnID）の最早終了日の翌日以降に始めます
+// 戻り値は ID ごとの結果、最早開始日の順に並んだクリティカルパス、全体の終了日です
+// items に含まれないタスクとの依存関係は無視し、循環している場合は依存関係を考慮せず予定の日程のままにします
+func Schedule(items []ScheduleItem, deps []*TaskDependency) (map[string]*ScheduleResult, []string, time.Time) {
+    byID := map[string]ScheduleItem{}
+    for _, item := range items {
+        byID[item.ID] = item
+    }
+    preds := map[string][]string{}
+    succs := map[string][]string{}
+    indegree := map[string]int{}
+    for _, dep := range deps {
+        if _, ok := byID[dep.TaskID]; !ok {
+            continue
+        }
+        if _, ok := byID[dep.DependsOnID]; !ok {
+            continue
+        }
+        preds[dep.TaskID] = append(preds[dep.TaskID], dep.DependsOnID)
+        succs[dep.DependsOnID] = append(succs[dep.DependsOnID], dep.TaskID)
+        indegree[dep.TaskID]++
+    }
+
+    // トポロジカル順（予定の開始日、ID の順で安定させる）
+    sorted := append([]ScheduleItem(nil), items...)
+    sort.SliceStable(sorted, func(i, j int) bool {
+        if !sorted[i].Start.Equal(sorted[j].Start) {
+            return sorted[i].Start.Before(sorted[j].Start)
+        }
+        return sorted[i].ID < sorted[j].ID
+    })
+    var order []string
+    var queue []string
+    for _, item := range sorted {
+        if indegree[item.ID] == 0 {
+            queue = append(queue, item.ID)
+        }
+    }
+    for len(queue) > 0 {
+        id := queue[0]
+        queue = queue[1:]
+        order = append(order, id)
+        for _, next := range succs[id] {
+            if indegree[next]--; indegree[next] == 0 {
+                queue = append(queue, next)
+            }
+        }
+    }
+    inOrder := map[string]bool{}
+    for _, id := range order {
+        inOrder[id] = true
+    }
+    for _, item := range sorted {
+        if !inOrder[item.ID] {
+            // 循環している依存関係
+            order = append(order, item.ID)
+            preds[item.ID], succs[item.ID] = nil, nil
+        }
+    }
+
+    results := map[string]*ScheduleResult{}
+    var finish time.Time
+    for _, id := range order {
+        item := byID[id]
+        duration := DaysBetween(item.Start, item.End)
+        es := Day(item.Start)
+        for _, p := range preds[id] {
+            if pr := results[p]; pr != nil {
+                if next := pr.EarliestFinish.AddDate(0, 0, 1); next.After(es) {
+                    es = next
+                }
+            }
+        }
+        r := &ScheduleResult{EarliestStart: es, EarliestFinish: es.AddDate(0, 0, duration)}
+        results[id] = r
+        if r.EarliestFinish.After(finish) {
+            finish = r.EarliestFinish
+        }
+    }
+    for i := len(order) - 1; i >= 0; i-- {
+        id := order[i]
+        r := results[id]
+        lf := finish
+        for _, s := range succs[id] {
+            if sr := results[s]; sr != nil && sr.LatestStart.AddDate(0, 0, -1).Before(lf) {
+                lf = sr.LatestStart.AddDate(0, 0, -1)
+            }
+        }
+        r.LatestFinish = lf
+        r.LatestStart = lf.AddDate(0, 0, -DaysBetween(r.EarliestStart, r.EarliestFinish))
+        r.Slack = DaysBetween(r.EarliestStart, r.LatestStart)
+        r.Critical = r.Slack <= 0
+    }
+
+    // 全体の終了日に終わるクリティカルなタスクから、間を空けずに続く依存先をたどる
+    var path []string
+    for _, id := range order {
+        if r := results[id]; r.Critical && r.EarliestFinish.Equal(finish) {
+            path = append(path, id)
+            break
+        }
+    }
+    for len(path) > 0 {
+        current := results[path[len(path)-1]]
+        next := ""
+        for _, p := range preds[path[len(path)-1]] {
+            if pr := results[p]; pr.Critical && pr.EarliestFinish.AddDate(0, 0, 1).Equal(current.EarliestStart) {
+                next = p
+                break
+            }
+        }
+        if next == "" {
+            break
+        }
+        path = append(path, next)
+    }
+    for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+        path[i], path[j] = path[j], path[i]
+    }
+    return results, path, finish
+}
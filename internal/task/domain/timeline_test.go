@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func day(month time.Month, d int) time.Time {
+	return time.Date(2030, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSpan(t *testing.T) {
+	tests := []struct {
+		name      string
+		task      Task
+		wantStart time.Time
+		wantEnd   time.Time
+		wantOK    bool
+	}{
+		{name: "unscheduled", task: Task{}},
+		{name: "start and duration", task: Task{StartDate: day(1, 1), DurationDays: 3, DueDate: day(1, 10)}, wantStart: day(1, 1), wantEnd: day(1, 3), wantOK: true},
+		{name: "start until due", task: Task{StartDate: day(1, 1), DueDate: day(1, 5)}, wantStart: day(1, 1), wantEnd: day(1, 5), wantOK: true},
+		{name: "start only", task: Task{StartDate: day(1, 1).Add(15 * time.Hour)}, wantStart: day(1, 1), wantEnd: day(1, 1), wantOK: true},
+		{name: "due and duration", task: Task{DueDate: day(1, 10), DurationDays: 4}, wantStart: day(1, 7), wantEnd: day(1, 10), wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := tt.task.Span()
+			if ok != tt.wantOK || !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("Span() = %v, %v, %v; want %v, %v, %v", start, end, ok, tt.wantStart, tt.wantEnd, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	// a(1/1-1/3) -> b(1/2-1/3) -> d(1/5)
+	// a -> c(1/1-1/1) -> d
+	items := []ScheduleItem{
+		{ID: "a", Start: day(1, 1), End: day(1, 3)},
+		{ID: "b", Start: day(1, 2), End: day(1, 3)},
+		{ID: "c", Start: day(1, 1), End: day(1, 1)},
+		{ID: "d", Start: day(1, 5), End: day(1, 5)},
+	}
+	deps := []*TaskDependency{
+		{TaskID: "b", DependsOnID: "a"},
+		{TaskID: "c", DependsOnID: "a"},
+		{TaskID: "d", DependsOnID: "b"},
+		{TaskID: "d", DependsOnID: "c"},
+		{TaskID: "d", DependsOnID: "outside"},
+	}
+
+	results, path, finish := Schedule(items, deps)
+	if !finish.Equal(day(1, 6)) {
+		t.Errorf("finish = %v, want %v", finish, day(1, 6))
+	}
+	if want := []string{"a", "b", "d"}; !reflect.DeepEqual(path, want) {
+		t.Errorf("path = %v, want %v", path, want)
+	}
+
+	want := map[string]ScheduleResult{
+		"a": {EarliestStart: day(1, 1), EarliestFinish: day(1, 3), LatestStart: day(1, 1), LatestFinish: day(1, 3), Slack: 0, Critical: true},
+		"b": {EarliestStart: day(1, 4), EarliestFinish: day(1, 5), LatestStart: day(1, 4), LatestFinish: day(1, 5), Slack: 0, Critical: true},
+		"c": {EarliestStart: day(1, 4), EarliestFinish: day(1, 4), LatestStart: day(1, 5), LatestFinish: day(1, 5), Slack: 1},
+		"d": {EarliestStart: day(1, 6), EarliestFinish: day(1, 6), LatestStart: day(1, 6), LatestFinish: day(1, 6), Slack: 0, Critical: true},
+	}
+	for id, w := range want {
+		if got := *results[id]; !reflect.DeepEqual(got, w) {
+			t.Errorf("%s = %+v, want %+v", id, got, w)
+		}
+	}
+}
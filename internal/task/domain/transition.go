@@ -0,0 +1,26 @@
+package domain
+
+import (
+    "time"
+)
+
+// TaskTransition はタスクのステータス遷移の履歴（誰が・いつ）を表します
+type TaskTransition struct {
+    ID         string    `json:"id"`
+    TaskID     string    `json:"task_id"`
+    FromStatus string    `json:"from_status"`
+    ToStatus   string    `json:"to_status"`
+    ActorID    string    `json:"actor_id"`
+    CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewTaskTransition(id, taskID, fromStatus, toStatus, actorID string) *TaskTransition {
+    return &TaskTransition{
+        ID:         id,
+        TaskID:     taskID,
+        FromStatus: fromStatus,
+        ToStatus:   toStatus,
+        ActorID:    actorID,
+        CreatedAt:  time.Now(),
+    }
+}
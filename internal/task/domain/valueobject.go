@@ -1,5 +1,7 @@
 package domain
 
+import "errors"
+
 var (
     PriorityHigh   = "High"
     PriorityMedium = "Medium"
@@ -10,3 +12,28 @@ var (
     StatusDone       = "Done"
     StatusCanceled   = "Canceled"
 )
+
+var (
+    ErrInvalidPriority = errors.New("priority must be one of High, Medium, Low")
+    ErrInvalidStatus   = errors.New("status must be one of Open, InProgress, Done, Canceled")
+    // ErrProjectArchived はアーカイブ済み（読み取り専用）のプロジェクトのタスクを変更しようとした場合に返されます
+    ErrProjectArchived = errors.New("project is archived and read-only")
+)
+
+// ValidatePriority は優先度が定義済みの値かどうかを検証します
+func ValidatePriority(priority string) error {
+    switch priority {
+    case PriorityHigh, PriorityMedium, PriorityLow:
+        return nil
+    }
+    return ErrInvalidPriority
+}
+
+// ValidateStatus はステータスが定義済みの値かどうかを検証します
+func ValidateStatus(status string) error {
+    switch status {
+    case StatusOpen, StatusInProgress, StatusDone, StatusCanceled:
+        return nil
+    }
+    return ErrInvalidStatus
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+    "fmt"
+    "strings"
+)
+
+// Workflow はタスクのステータスとその間の遷移を定義するステートマシンです
+// 将来的にはプロジェクトごとに独自の定義を持てるようにします
+type Workflow struct {
+    Name        string              `json:"name"`
+    Initial     string              `json:"initial"`
+    States      []string            `json:"states"`
+    Transitions map[string][]string `json:"transitions"`
+}
+
+// TransitionError は許可されていないステータス遷移を表します
+type TransitionError struct {
+    From    string   `json:"from"`
+    To      string   `json:"to"`
+    Allowed []string `json:"allowed"`
+}
+
+func (e *TransitionError) Error() string {
+    return fmt.Sprintf("transition from %s to %s is not allowed (allowed: %s)", e.From, e.To, strings.Join(e.Allowed, ", "))
+}
+
+// DefaultWorkflow は valueobject.go のステータス定数に対応する標準ワークフローを返します
+//
+//  Open       -> InProgress, Done, Canceled
+//  InProgress -> Open, Done, Canceled
+//  Done       -> Open, InProgress
+//  Canceled   -> Open
+func DefaultWorkflow() *Workflow {
+    return &Workflow{
+        Name:    "default",
+        Initial: StatusOpen,
+        States:  []string{StatusOpen, StatusInProgress, StatusDone, StatusCanceled},
+        Transitions: map[string][]string{
+            StatusOpen:       {StatusInProgress, StatusDone, StatusCanceled},
+            StatusInProgress: {StatusOpen, StatusDone, StatusCanceled},
+            StatusDone:       {StatusOpen, StatusInProgress},
+            StatusCanceled:   {StatusOpen},
+        },
+    }
+}
+
+// HasState は status がこのワークフローに定義されているかを返します
+func (w *Workflow) HasState(status string) bool {
+    for _, s := range w.States {
+        if s == status {
+            return true
+        }
+    }
+    return false
+}
+
+// AllowedTransitions は from から遷移可能なステータスの一覧を返します
+func (w *Workflow) AllowedTransitions(from string) []string {
+    return w.Transitions[from]
+}
+
+// CanTransition は from から to への遷移が許可されているかを返します
+func (w *Workflow) CanTransition(from, to string) bool {
+    for _, s := range w.Transitions[from] {
+        if s == to {
+            return true
+        }
+    }
+    return false
+}
+
+// Validate は遷移を検証し、許可されていなければ *TransitionError を返します
+func (w *Workflow) Validate(from, to string) error {
+    if !w.HasState(to) {
+        return ErrInvalidStatus
+    }
+    if !w.CanTransition(from, to) {
+        return &TransitionError{From: from, To: to, Allowed: w.AllowedTransitions(from)}
+    }
+    return nil
+}
@@ -2,44 +2,164 @@ package handler
 
 import (
 	"database/sql"
+	"errors"
+	"io"
 	"log"
 	"net/http"
 
+	activityhandler "todo-app/internal/activity/handler"
+	attachmenthandler "todo-app/internal/attachment/handler"
+	apperrors "todo-app/internal/common/errors"
 	"todo-app/internal/common/utils"
+	projectdomain "todo-app/internal/project/domain"
+	projectpolicy "todo-app/internal/project/policy"
+	projectpostgres "todo-app/internal/project/repository/postgres"
+	"todo-app/internal/task/domain"
 	"todo-app/internal/task/repository/postgres"
 	"todo-app/internal/task/usecase"
+	userpostgres "todo-app/internal/user/repository/postgres"
 
 	"github.com/go-chi/chi/v5"
 )
 
-func RegisterTaskRoutes(r chi.Router, db *sql.DB) {
+// NewTaskUseCase は PostgreSQL の各リポジトリを使う TaskUseCase を返します
+func NewTaskUseCase(db *sql.DB) *usecase.TaskUseCase {
 	taskRepo := postgres.NewTaskRepoPg(db)
 	subtaskRepo := postgres.NewSubtaskRepoPg(db) // ← こちらを呼び出す
-	uc := usecase.NewTaskUseCase(taskRepo, subtaskRepo)
+	projectRepo := projectpostgres.NewProjectRepoPg(db)
+	return usecase.NewTaskUseCase(taskRepo, subtaskRepo, postgres.NewTaskTransitionRepoPg(db), postgres.NewProjectSettingsRepoPg(db),
+		postgres.NewTaskDependencyRepoPg(db), postgres.NewTaskSeriesRepoPg(db), postgres.NewLabelRepoPg(db), postgres.NewTimeEntryRepoPg(db), postgres.NewBoardRepoPg(db), postgres.NewCustomFieldRepoPg(db), userpostgres.NewUserRepoPg(db),
+		attachmenthandler.NewContentCopier(db), activityhandler.NewActivityUseCase(db), projectpolicy.NewPolicy(projectRepo), projectRepo)
+}
+
+func RegisterTaskRoutes(r chi.Router, db *sql.DB) {
+	uc := NewTaskUseCase(db)
+	attachmentUC := attachmenthandler.NewAttachmentUseCase(db, uc)
+	activityUC := activityhandler.NewActivityUseCase(db)
 
 	r.Route("/tasks", func(r chi.Router) {
 		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
 			log.Printf("Get tasks request received")
 
-			tasks, err := uc.GetAllTasks()
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			req, err := parseTaskListRequest(r)
+			if err != nil {
+				log.Printf("Invalid task list query: %v", err)
+				writeTaskError(w, err)
+				return
+			}
+			// 呼び出し元が閲覧できるタスクのみ返す
+			req.Query.VisibleTo = userID
+
+			result, err := uc.ListTasks(r.Context(), req)
 			if err != nil {
 				log.Printf("Failed to get tasks: %v", err)
-				utils.JSONResponse(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Tasks retrieved successfully: %d of %d tasks", len(result.Tasks), result.Total)
+			setPaginationHeaders(w, r, result)
+			utils.JSONResponse(w, http.StatusOK, result.Tasks)
+		})
+
+		r.Get("/summary", func(w http.ResponseWriter, r *http.Request) {
+			log.Printf("Get task summary request received")
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			q, err := ParseTaskSummaryQuery(r)
+			if err != nil {
+				log.Printf("Invalid task summary query: %v", err)
+				writeTaskError(w, err)
+				return
+			}
+			q.VisibleTo = userID
+
+			summary, err := uc.SummarizeTasks(r.Context(), q)
+			if err != nil {
+				log.Printf("Failed to summarize tasks: %v", err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Task summary retrieved successfully: %d tasks", summary.Total)
+			utils.JSONResponse(w, http.StatusOK, summary)
+		})
+
+		r.Post("/bulk", func(w http.ResponseWriter, r *http.Request) {
+			log.Printf("Bulk task operation request received")
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			var req usecase.BulkRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode bulk request: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+			if req.Filter != nil {
+				q, err := ParseBulkFilter(req.Filter)
+				if err != nil {
+					writeTaskError(w, err)
+					return
+				}
+				req.Query = &q
+			}
+
+			result, err := uc.BulkTasks(r.Context(), &req, userID)
+			if err != nil {
+				log.Printf("Failed to apply bulk operation: %v", err)
+				writeTaskError(w, err)
 				return
 			}
 
-			log.Printf("Tasks retrieved successfully: %d tasks", len(tasks))
-			utils.JSONResponse(w, http.StatusOK, tasks)
+			log.Printf("Bulk operation finished: %d succeeded, %d failed", result.Succeeded, result.Failed)
+			// atomic で取り消した場合は 409
+			status := http.StatusOK
+			if result.Atomic && result.Failed > 0 {
+				status = http.StatusConflict
+			}
+			utils.JSONResponse(w, status, result)
 		})
 
 		r.Get("/{taskID}", func(w http.ResponseWriter, r *http.Request) {
 			taskID := chi.URLParam(r, "taskID")
 			log.Printf("Get task request received for taskID: %s", taskID)
 
-			task, err := uc.GetTaskByID(taskID)
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			task, err := uc.GetTaskByID(taskID, userID)
 			if err != nil {
 				log.Printf("Failed to get task %s: %v", taskID, err)
-				utils.JSONResponse(w, http.StatusNotFound, map[string]string{"error": "task not found"})
+				writeTaskError(w, err)
+				return
+			}
+
+			utils.SetETag(w, task.Version)
+			if utils.NotModified(r, task.Version) {
+				w.WriteHeader(http.StatusNotModified)
 				return
 			}
 
@@ -76,7 +196,7 @@ func RegisterTaskRoutes(r chi.Router, db *sql.DB) {
 			id, err := uc.CreateTask(&dto)
 			if err != nil {
 				log.Printf("Failed to create task: %v", err)
-				utils.JSONResponse(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				writeTaskError(w, err)
 				return
 			}
 
@@ -84,42 +204,991 @@ func RegisterTaskRoutes(r chi.Router, db *sql.DB) {
 			utils.JSONResponse(w, http.StatusCreated, map[string]string{"id": id})
 		})
 
-		r.Post("/{taskID}/subtasks", func(w http.ResponseWriter, r *http.Request) {
+		// クイック追加: "Review PR tomorrow 3pm !high @alice #backend +ProjectX" のような一行からタスクを作成
+		r.Post("/quick", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			var req usecase.QuickAddRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			result, err := uc.QuickAddTask(req.Text, userID)
+			if err != nil {
+				log.Printf("Failed to quick-add task: %v", err)
+				writeTaskError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusCreated, result)
+		})
+
+		r.Put("/{taskID}", func(w http.ResponseWriter, r *http.Request) {
 			taskID := chi.URLParam(r, "taskID")
-			log.Printf("Create subtask request received for taskID: %s", taskID)
+			log.Printf("Update task request received for taskID: %s", taskID)
 
-			var dto usecase.SubtaskDTO
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			version, ok := requireIfMatch(w, r)
+			if !ok {
+				return
+			}
+
+			scope, err := usecase.ParseEditScope(r.URL.Query().Get("scope"))
+			if err != nil {
+				writeTaskError(w, err)
+				return
+			}
+
+			var dto usecase.TaskDTO
 			if err := utils.DecodeJSON(r, &dto); err != nil {
-				log.Printf("Failed to decode subtask data: %v", err)
+				log.Printf("Failed to decode task data: %v", err)
 				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
 				return
 			}
 
-			dto.TaskID = taskID
-			id, err := uc.CreateSubtask(&dto)
+			task, err := uc.UpdateTask(taskID, &dto, userID, version, scope)
 			if err != nil {
-				log.Printf("Failed to create subtask: %v", err)
-				utils.JSONResponse(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				log.Printf("Failed to update task %s: %v", taskID, err)
+				writeTaskError(w, err)
 				return
 			}
 
-			log.Printf("Subtask created successfully with ID: %s", id)
-			utils.JSONResponse(w, http.StatusCreated, map[string]string{"id": id})
+			log.Printf("Task updated successfully: %s", taskID)
+			utils.SetETag(w, task.Version)
+			utils.JSONResponse(w, http.StatusOK, task)
 		})
 
-		r.Delete("/{taskID}", func(w http.ResponseWriter, r *http.Request) {
+		r.Patch("/{taskID}", func(w http.ResponseWriter, r *http.Request) {
 			taskID := chi.URLParam(r, "taskID")
-			log.Printf("Delete task request received for taskID: %s", taskID)
+			log.Printf("Patch task request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			version, ok := requireIfMatch(w, r)
+			if !ok {
+				return
+			}
 
-			err := uc.DeleteTask(taskID)
+			scope, err := usecase.ParseEditScope(r.URL.Query().Get("scope"))
 			if err != nil {
-				log.Printf("Failed to delete task %s: %v", taskID, err)
-				utils.JSONResponse(w, http.StatusNotFound, map[string]string{"error": "task not found"})
+				writeTaskError(w, err)
+				return
+			}
+
+			defer r.Body.Close()
+			patch, err := io.ReadAll(r.Body)
+			if err != nil {
+				log.Printf("Failed to read patch body: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			task, err := uc.PatchTask(taskID, patch, userID, version, scope)
+			if err != nil {
+				log.Printf("Failed to patch task %s: %v", taskID, err)
+				writeTaskError(w, err)
 				return
 			}
 
-			log.Printf("Task deleted successfully: %s", taskID)
-			utils.JSONResponse(w, http.StatusOK, map[string]string{"message": "task deleted successfully"})
+			log.Printf("Task patched successfully: %s", taskID)
+			utils.SetETag(w, task.Version)
+			utils.JSONResponse(w, http.StatusOK, task)
 		})
-	})
+
+		r.Post("/{taskID}/transitions", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Transition task request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			version, err := utils.OptionalIfMatch(r)
+			if err != nil {
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			var req usecase.TransitionRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode transition data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			task, err := uc.TransitionTask(taskID, req.To, userID, version)
+			if err != nil {
+				log.Printf("Failed to transition task %s to %s: %v", taskID, req.To, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Task transitioned successfully: %s -> %s", taskID, req.To)
+			utils.SetETag(w, task.Version)
+			utils.JSONResponse(w, http.StatusOK, task)
+		})
+
+		// 日程の変更: cascade が true の場合は依存元のタスクを後ろにずらす
+		r.Post("/{taskID}/reschedule", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Reschedule task request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			version, err := utils.OptionalIfMatch(r)
+			if err != nil {
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			var req usecase.RescheduleRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode reschedule data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			result, err := uc.Reschedule(taskID, &req, userID, version)
+			if err != nil {
+				log.Printf("Failed to reschedule task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Task rescheduled successfully: %s (%d dependents shifted)", taskID, len(result.Shifted))
+			utils.SetETag(w, result.Task.Version)
+			utils.JSONResponse(w, http.StatusOK, result)
+		})
+
+		r.Post("/{taskID}/move", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Move task request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			version, err := utils.OptionalIfMatch(r)
+			if err != nil {
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			var req usecase.MoveTaskRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode move data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			result, err := uc.MoveTask(taskID, &req, userID, version)
+			if err != nil {
+				log.Printf("Failed to move task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Task moved successfully: %s (%s, %d warnings)", taskID, result.Task.Status, len(result.Warnings))
+			utils.SetETag(w, result.Task.Version)
+			utils.JSONResponse(w, http.StatusOK, result)
+		})
+
+		r.Post("/{taskID}/clone", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Clone task request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			// ボディを省略すると同じプロジェクトにタスクのみ複製する
+			var req usecase.CloneTaskRequest
+			if err := utils.DecodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+				log.Printf("Failed to decode clone data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			task, err := uc.CloneTask(taskID, &req, userID)
+			if err != nil {
+				log.Printf("Failed to clone task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Task cloned successfully: %s -> %s", taskID, task.ID)
+			utils.SetETag(w, task.Version)
+			utils.JSONResponse(w, http.StatusCreated, task)
+		})
+
+		r.Get("/{taskID}/transitions", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Get task transitions request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			transitions, err := uc.GetTransitions(taskID, userID)
+			if err != nil {
+				log.Printf("Failed to get transitions for task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, transitions)
+		})
+
+		r.Get("/{taskID}/history", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Get task history request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			if err := uc.AuthorizeTask(taskID, userID, projectdomain.PermView); err != nil {
+				log.Printf("Failed to authorize task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			history, err := activityUC.TaskHistory(taskID, activityhandler.ParseQuery(r))
+			if err != nil {
+				log.Printf("Failed to get history for task %s: %v", taskID, err)
+				activityhandler.WriteError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, history)
+		})
+
+		r.Get("/{taskID}/workflow", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Get task workflow request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			workflow, err := uc.GetWorkflow(taskID, userID)
+			if err != nil {
+				log.Printf("Failed to get workflow for task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, workflow)
+		})
+
+		r.Post("/{taskID}/subtasks", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Create subtask request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			var dto usecase.SubtaskDTO
+			if err := utils.DecodeJSON(r, &dto); err != nil {
+				log.Printf("Failed to decode subtask data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			dto.TaskID = taskID
+			id, err := uc.CreateSubtask(&dto, userID)
+			if err != nil {
+				log.Printf("Failed to create subtask: %v", err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Subtask created successfully with ID: %s", id)
+			utils.JSONResponse(w, http.StatusCreated, map[string]string{"id": id})
+		})
+
+		r.Get("/{taskID}/subtasks", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Get subtasks request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			subtasks, err := uc.ListSubtasks(taskID, userID)
+			if err != nil {
+				log.Printf("Failed to get subtasks for task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, subtasks)
+		})
+
+		r.Put("/{taskID}/subtasks/order", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Reorder subtasks request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			var req usecase.SubtaskOrderRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode subtask order: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			subtasks, err := uc.ReorderSubtasks(taskID, req.IDs, userID)
+			if err != nil {
+				log.Printf("Failed to reorder subtasks for task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Subtasks reordered successfully for task: %s", taskID)
+			utils.JSONResponse(w, http.StatusOK, subtasks)
+		})
+
+		r.Patch("/{taskID}/subtasks/{subtaskID}", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			subtaskID := chi.URLParam(r, "subtaskID")
+			log.Printf("Update subtask request received for subtaskID: %s", subtaskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			var req usecase.SubtaskUpdateRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode subtask data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			subtask, err := uc.UpdateSubtask(taskID, subtaskID, &req, userID)
+			if err != nil {
+				log.Printf("Failed to update subtask %s: %v", subtaskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Subtask updated successfully: %s", subtaskID)
+			utils.JSONResponse(w, http.StatusOK, subtask)
+		})
+
+		r.Post("/{taskID}/subtasks/{subtaskID}/toggle", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			subtaskID := chi.URLParam(r, "subtaskID")
+			log.Printf("Toggle subtask request received for subtaskID: %s", subtaskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			subtask, err := uc.ToggleSubtask(taskID, subtaskID, userID)
+			if err != nil {
+				log.Printf("Failed to toggle subtask %s: %v", subtaskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Subtask toggled successfully: %s (complete=%t)", subtaskID, subtask.IsComplete)
+			utils.JSONResponse(w, http.StatusOK, subtask)
+		})
+
+		r.Delete("/{taskID}/subtasks/{subtaskID}", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			subtaskID := chi.URLParam(r, "subtaskID")
+			log.Printf("Delete subtask request received for subtaskID: %s", subtaskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			if err := uc.DeleteSubtask(taskID, subtaskID, userID); err != nil {
+				log.Printf("Failed to delete subtask %s: %v", subtaskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Subtask deleted successfully: %s", subtaskID)
+			utils.JSONResponse(w, http.StatusOK, map[string]string{"message": "subtask deleted successfully"})
+		})
+
+		r.Get("/{taskID}/dependencies", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Get dependencies request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			deps, err := uc.GetDependencies(r.Context(), taskID, userID)
+			if err != nil {
+				log.Printf("Failed to get dependencies for task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, deps)
+		})
+
+		r.Post("/{taskID}/dependencies", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Add dependency request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			var req usecase.DependencyRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode dependency data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			deps, err := uc.AddDependency(r.Context(), taskID, &req, userID)
+			if err != nil {
+				log.Printf("Failed to add dependency for task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Dependency added successfully for task: %s", taskID)
+			utils.JSONResponse(w, http.StatusCreated, deps)
+		})
+
+		r.Delete("/{taskID}/dependencies/{dependsOnID}", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			dependsOnID := chi.URLParam(r, "dependsOnID")
+			log.Printf("Remove dependency request received: taskID=%s, dependsOnID=%s", taskID, dependsOnID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			if err := uc.RemoveDependency(taskID, dependsOnID, userID); err != nil {
+				log.Printf("Failed to remove dependency %s -> %s: %v", taskID, dependsOnID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Dependency removed successfully: taskID=%s, dependsOnID=%s", taskID, dependsOnID)
+			utils.JSONResponse(w, http.StatusOK, map[string]string{"message": "dependency removed successfully"})
+		})
+
+		r.Put("/{taskID}/labels", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Set task labels request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			var req usecase.TaskLabelsRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode task labels: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			task, err := uc.SetTaskLabels(taskID, req.LabelIDs, userID)
+			if err != nil {
+				log.Printf("Failed to set labels for task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Task labels set successfully: %s (%d labels)", taskID, len(task.Labels))
+			utils.SetETag(w, task.Version)
+			utils.JSONResponse(w, http.StatusOK, task)
+		})
+
+		r.Post("/{taskID}/labels/{labelID}", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			labelID := chi.URLParam(r, "labelID")
+			log.Printf("Add task label request received: taskID=%s, labelID=%s", taskID, labelID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			task, err := uc.AddTaskLabel(taskID, labelID, userID)
+			if err != nil {
+				log.Printf("Failed to add label %s to task %s: %v", labelID, taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Task label added successfully: taskID=%s, labelID=%s", taskID, labelID)
+			utils.SetETag(w, task.Version)
+			utils.JSONResponse(w, http.StatusOK, task)
+		})
+
+		r.Delete("/{taskID}/labels/{labelID}", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			labelID := chi.URLParam(r, "labelID")
+			log.Printf("Remove task label request received: taskID=%s, labelID=%s", taskID, labelID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			task, err := uc.RemoveTaskLabel(taskID, labelID, userID)
+			if err != nil {
+				log.Printf("Failed to remove label %s from task %s: %v", labelID, taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Task label removed successfully: taskID=%s, labelID=%s", taskID, labelID)
+			utils.SetETag(w, task.Version)
+			utils.JSONResponse(w, http.StatusOK, task)
+		})
+
+		r.Post("/{taskID}/timer/start", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Start timer request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			entry, err := uc.StartTimer(taskID, userID)
+			if err != nil {
+				log.Printf("Failed to start timer for task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Timer started: taskID=%s, entryID=%s", taskID, entry.ID)
+			utils.JSONResponse(w, http.StatusCreated, entry)
+		})
+
+		r.Post("/{taskID}/timer/stop", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Stop timer request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			// ボディ（メモ）は省略可
+			var req usecase.TimerStopRequest
+			if r.ContentLength != 0 {
+				if err := utils.DecodeJSON(r, &req); err != nil {
+					log.Printf("Failed to decode timer data: %v", err)
+					utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+					return
+				}
+			}
+
+			entry, err := uc.StopTimer(taskID, userID, &req)
+			if err != nil {
+				log.Printf("Failed to stop timer for task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Timer stopped: taskID=%s, entryID=%s, minutes=%d", taskID, entry.ID, entry.Minutes)
+			utils.JSONResponse(w, http.StatusOK, entry)
+		})
+
+		r.Get("/{taskID}/time-entries", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Get time entries request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			entries, err := uc.ListTimeEntries(taskID, userID)
+			if err != nil {
+				log.Printf("Failed to get time entries for task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, entries)
+		})
+
+		r.Post("/{taskID}/time-entries", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Log time request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			var req usecase.TimeEntryRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode time entry data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			entry, err := uc.LogTime(taskID, userID, &req)
+			if err != nil {
+				log.Printf("Failed to log time for task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Time logged: taskID=%s, minutes=%d", taskID, entry.Minutes)
+			utils.JSONResponse(w, http.StatusCreated, entry)
+		})
+
+		r.Patch("/{taskID}/time-entries/{entryID}", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			entryID := chi.URLParam(r, "entryID")
+			log.Printf("Update time entry request received for entryID: %s", entryID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			var req usecase.TimeEntryRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				log.Printf("Failed to decode time entry data: %v", err)
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			entry, err := uc.UpdateTimeEntry(taskID, entryID, userID, &req)
+			if err != nil {
+				log.Printf("Failed to update time entry %s: %v", entryID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, entry)
+		})
+
+		r.Delete("/{taskID}/time-entries/{entryID}", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			entryID := chi.URLParam(r, "entryID")
+			log.Printf("Delete time entry request received for entryID: %s", entryID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			if err := uc.DeleteTimeEntry(taskID, entryID, userID); err != nil {
+				log.Printf("Failed to delete time entry %s: %v", entryID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, map[string]string{"message": "time entry deleted successfully"})
+		})
+
+		r.Get("/{taskID}/time", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Get task time summary request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			q, err := ParseTimeQuery(r)
+			if err != nil {
+				writeTaskError(w, err)
+				return
+			}
+			if _, err := uc.GetTaskByID(taskID, userID); err != nil {
+				writeTaskError(w, err)
+				return
+			}
+			q.Tasks.IDs = []string{taskID}
+
+			summary, err := uc.SummarizeTime(r.Context(), q)
+			if err != nil {
+				log.Printf("Failed to summarize time for task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, summary)
+		})
+
+		r.Get("/{taskID}/attachments", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+			attachments, err := attachmentUC.ListByTask(taskID, userID)
+			if err != nil {
+				log.Printf("Failed to list attachments for task %s: %v", taskID, err)
+				attachmenthandler.WriteError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusOK, attachments)
+		})
+
+		r.Post("/{taskID}/attachments", func(w http.ResponseWriter, r *http.Request) {
+			attachmenthandler.HandleUpload(w, r, attachmentUC, chi.URLParam(r, "taskID"), "")
+		})
+
+		r.Delete("/{taskID}", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Delete task request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			version, ok := requireIfMatch(w, r)
+			if !ok {
+				return
+			}
+
+			err := uc.DeleteTask(taskID, version, userID)
+			if err != nil {
+				log.Printf("Failed to delete task %s: %v", taskID, err)
+				if errors.Is(err, apperrors.ErrVersionMismatch) || errors.Is(err, apperrors.ErrForbidden) || errors.Is(err, domain.ErrProjectArchived) {
+					writeTaskError(w, err)
+					return
+				}
+				utils.JSONResponse(w, http.StatusNotFound, map[string]string{"error": "task not found"})
+				return
+			}
+
+			log.Printf("Task moved to trash: %s", taskID)
+			utils.JSONResponse(w, http.StatusOK, map[string]string{
+				"message":     "task deleted successfully",
+				"restore_url": "/tasks/" + taskID + "/restore",
+			})
+		})
+
+		r.Post("/{taskID}/restore", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Restore task request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			task, err := uc.RestoreTask(taskID, userID)
+			if err != nil {
+				log.Printf("Failed to restore task %s: %v", taskID, err)
+				writeTaskError(w, err)
+				return
+			}
+
+			log.Printf("Task restored successfully: %s", taskID)
+			utils.SetETag(w, task.Version)
+			utils.JSONResponse(w, http.StatusOK, task)
+		})
+	})
+
+	// 作業時間（タスク・プロジェクトをまたいだ集計と、計測中のタイマー）
+	r.Route("/time", func(r chi.Router) {
+		r.Get("/summary", func(w http.ResponseWriter, r *http.Request) {
+			log.Printf("Get time summary request received")
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			q, err := ParseTimeQuery(r)
+			if err != nil {
+				log.Printf("Invalid time summary query: %v", err)
+				writeTaskError(w, err)
+				return
+			}
+			if q.UserID == "me" {
+				q.UserID = userID
+			}
+			q.Tasks.VisibleTo = userID
+
+			summary, err := uc.SummarizeTime(r.Context(), q)
+			if err != nil {
+				log.Printf("Failed to summarize time: %v", err)
+				writeTaskError(w, err)
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, summary)
+		})
+
+		r.Get("/timer", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			entry, err := uc.CurrentTimer(userID)
+			if err != nil {
+				utils.JSONResponse(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, entry)
+		})
+	})
+}
+
+// writeTaskError はユースケースのエラーを HTTP ステータスに変換して返します
+func writeTaskError(w http.ResponseWriter, err error) {
+	var verr *apperrors.ValidationError
+	var terr *domain.TransitionError
+	var berr *domain.BlockedError
+	var rerr *domain.TimerRunningError
+	var werr *domain.WIPLimitError
+	switch {
+	case errors.As(err, &terr):
+		utils.JSONResponse(w, http.StatusConflict, map[string]interface{}{"error": terr.Error(), "from": terr.From, "to": terr.To, "allowed": terr.Allowed})
+	case errors.As(err, &berr):
+		utils.JSONResponse(w, http.StatusConflict, map[string]interface{}{"error": berr.Error(), "to": berr.To, "blockers": berr.Blockers})
+	case errors.As(err, &rerr):
+		utils.JSONResponse(w, http.StatusConflict, map[string]interface{}{"error": rerr.Error(), "task_id": rerr.TaskID, "entry_id": rerr.EntryID})
+	case errors.As(err, &werr):
+		utils.JSONResponse(w, http.StatusConflict, map[string]interface{}{"error": werr.Error(), "column": werr.Column, "limit": werr.Limit, "count": werr.Count})
+	case errors.Is(err, domain.ErrTimerNotRunning), errors.Is(err, domain.ErrTimeEntryRunning):
+		utils.JSONResponse(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrDependencyCycle), errors.Is(err, domain.ErrProjectArchived):
+		utils.JSONResponse(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, apperrors.ErrForbidden):
+		utils.JSONResponse(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidStatus):
+		utils.JSONResponse(w, http.StatusBadRequest, map[string]interface{}{"error": apperrors.ErrInvalidInput.Error(), "fields": map[string]string{"status": err.Error()}})
+	case errors.As(err, &verr):
+		utils.JSONResponse(w, http.StatusBadRequest, map[string]interface{}{"error": apperrors.ErrInvalidInput.Error(), "fields": verr.Fields})
+	case errors.Is(err, apperrors.ErrInvalidInput):
+		utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+	case errors.Is(err, apperrors.ErrVersionMismatch):
+		utils.JSONResponse(w, http.StatusPreconditionFailed, map[string]string{"error": err.Error()})
+	case errors.Is(err, apperrors.ErrNotFound):
+		utils.JSONResponse(w, http.StatusNotFound, map[string]string{"error": "task not found"})
+	default:
+		utils.JSONResponse(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
+
+// requireIfMatch は If-Match ヘッダーを検証し、期待するバージョンを返します
+// ヘッダーがない場合は 428、不正な場合は 400 を返して false を返します
+func requireIfMatch(w http.ResponseWriter, r *http.Request) (int, bool) {
+	version, err := utils.RequireIfMatch(r)
+	switch {
+	case errors.Is(err, utils.ErrIfMatchRequired):
+		utils.JSONResponse(w, http.StatusPreconditionRequired, map[string]string{"error": err.Error()})
+		return 0, false
+	case err != nil:
+		utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return 0, false
+	}
+	return version, true
 }
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+	"todo-app/internal/task/usecase"
+)
+
+// 一覧のビュー切替（F-3: 全件 / 未完 / 完了）
+const (
+	viewAll        = "all"
+	viewIncomplete = "incomplete"
+	viewCompleted  = "completed"
+)
+
+var queryDateFormats = []string{
+	"2006-01-02T15:04:05Z07:00", // RFC3339
+	"2006-01-02T15:04:05",       // ISO without timezone
+	"2006-01-02",                // Date only
+}
+
+// parseTaskListRequest は GET /tasks のクエリパラメータを TaskListRequest に変換します
+//
+//	view=all|incomplete|completed, status=Open,InProgress, priority=High,
+//	assignee_id, project_id, created_by, sprint_id, milestone_id, label=bug,urgent, due_from, due_to, overdue=true,
+//	cf.<key>=value1,value2, sort=-due_date（カスタムフィールドは sort=cf.<key>）, page, page_size, pagination=cursor, cursor
+func parseTaskListRequest(r *http.Request) (*usecase.TaskListRequest, error) {
+	params := r.URL.Query()
+	verr := apperrors.NewValidationError()
+	req := &usecase.TaskListRequest{}
+	q := &req.Query
+	parseTaskFilter(params, q, verr)
+
+	if sort := params.Get("sort"); sort != "" {
+		q.SortDesc = strings.HasPrefix(sort, "-")
+		q.SortBy = strings.TrimPrefix(sort, "-")
+		if !repository.IsTaskSortField(q.SortBy) {
+			verr.Add("sort", "sort must be one of "+strings.Join(repository.TaskSortFields, ", ")+" or cf.<key> (prefix with - for descending)")
+		}
+	}
+
+	req.Page = parsePositiveInt(params.Get("page"), "page", verr)
+	req.PageSize = parsePositiveInt(params.Get("page_size"), "page_size", verr)
+	req.Cursor = params.Get("cursor")
+	req.CursorMode = req.Cursor != "" || params.Get("pagination") == "cursor"
+
+	if verr.HasErrors() {
+		return nil, verr
+	}
+	return req, nil
+}
+
+// ParseTaskSummaryQuery は GET /tasks/summary のクエリパラメータ（一覧と同じ絞り込み条件）を TaskQuery に変換します
+func ParseTaskSummaryQuery(r *http.Request) (repository.TaskQuery, error) {
+	verr := apperrors.NewValidationError()
+	var q repository.TaskQuery
+	parseTaskFilter(r.URL.Query(), &q, verr)
+	if verr.HasErrors() {
+		return q, verr
+	}
+	return q, nil
+}
+
+// ParseTimeQuery は作業時間の集計のクエリパラメータを TimeQuery に変換します
+//
+//	user_id, from, to（日付のみの場合はその日を含む）と、一覧と同じタスクの絞り込み条件
+func ParseTimeQuery(r *http.Request) (repository.TimeQuery, error) {
+	params := r.URL.Query()
+	verr := apperrors.NewValidationError()
+	var q repository.TimeQuery
+	parseTaskFilter(params, &q.Tasks, verr)
+	q.UserID = params.Get("user_id")
+
+	if v := params.Get("from"); v != "" {
+		if t, ok := parseQueryDate(v); ok {
+			q.From = &t
+		} else {
+			verr.Add("from", "from must be a date (YYYY-MM-DD) or RFC3339 timestamp")
+		}
+	}
+	if v := params.Get("to"); v != "" {
+		if t, ok := parseQueryDate(v); ok {
+			if len(v) == len("2006-01-02") {
+				t = t.AddDate(0, 0, 1)
+			}
+			q.To = &t
+		} else {
+			verr.Add("to", "to must be a date (YYYY-MM-DD) or RFC3339 timestamp")
+		}
+	}
+	if verr.HasErrors() {
+		return q, verr
+	}
+	return q, nil
+}
+
+// ParseBulkFilter は POST /tasks/bulk の filter（GET /tasks と同じクエリパラメータ名と値）を TaskQuery に変換します
+func ParseBulkFilter(filter map[string]string) (repository.TaskQuery, error) {
+	params := url.Values{}
+	for k, v := range filter {
+		params.Set(k, v)
+	}
+	var q repository.TaskQuery
+	verr := apperrors.NewValidationError()
+	// 空の filter ですべてのタスクが対象になるのを防ぐ
+	if len(filter) == 0 {
+		verr.Add("filter", "filter must have at least one condition")
+	}
+	parseTaskFilter(params, &q, verr)
+	if verr.HasErrors() {
+		return q, verr
+	}
+	return q, nil
+}
+
+// parseTaskFilter は一覧・集計で共通の絞り込み条件を q に設定します
+func parseTaskFilter(params url.Values, q *repository.TaskQuery, verr *apperrors.ValidationError) {
+	switch params.Get("view") {
+	case "", viewAll:
+	case viewIncomplete:
+		q.Statuses = []string{domain.StatusOpen, domain.StatusInProgress}
+	case viewCompleted:
+		q.Statuses = []string{domain.StatusDone}
+	default:
+		verr.Add("view", "view must be one of all, incomplete, completed")
+	}
+
+	// status が明示された場合は view より優先する
+	if statuses := splitList(params.Get("status")); len(statuses) > 0 {
+		for _, s := range statuses {
+			if err := domain.ValidateStatus(s); err != nil {
+				verr.Add("status", err.Error())
+			}
+		}
+		q.Statuses = statuses
+	}
+	if priorities := splitList(params.Get("priority")); len(priorities) > 0 {
+		for _, p := range priorities {
+			if err := domain.ValidatePriority(p); err != nil {
+				verr.Add("priority", err.Error())
+			}
+		}
+		q.Priorities = priorities
+	}
+
+	q.AssigneeID = params.Get("assignee_id")
+	q.ProjectID = params.Get("project_id")
+	q.CreatedBy = params.Get("created_by")
+	// sprint_id・milestone_id に none を指定すると予定先のないタスク（バックログ）に絞り込む
+	q.SprintID = params.Get("sprint_id")
+	q.MilestoneID = params.Get("milestone_id")
+	// label はラベルの ID または名前（いずれかが付いているタスクに絞り込む）
+	q.Labels = splitList(params.Get("label"))
+	// cf.<key> はカスタムフィールドの値（いずれかと一致するタスクに絞り込む）
+	for name := range params {
+		if !strings.HasPrefix(name, repository.CustomFieldSortPrefix) {
+			continue
+		}
+		key, ok := repository.CustomFieldSortKey(name)
+		if !ok {
+			verr.Add(name, domain.ErrCustomFieldKeyInvalid.Error())
+			continue
+		}
+		if values := splitList(params.Get(name)); len(values) > 0 {
+			if q.CustomFields == nil {
+				q.CustomFields = map[string][]string{}
+			}
+			q.CustomFields[key] = values
+		}
+	}
+
+	if v := params.Get("due_from"); v != "" {
+		if t, ok := parseQueryDate(v); ok {
+			q.DueFrom = &t
+		} else {
+			verr.Add("due_from", "due_from must be a date (YYYY-MM-DD) or RFC3339 timestamp")
+		}
+	}
+	if v := params.Get("due_to"); v != "" {
+		if t, ok := parseQueryDate(v); ok {
+			q.DueTo = &t
+		} else {
+			verr.Add("due_to", "due_to must be a date (YYYY-MM-DD) or RFC3339 timestamp")
+		}
+	}
+	if v := params.Get("overdue"); v != "" {
+		overdue, err := strconv.ParseBool(v)
+		if err != nil {
+			verr.Add("overdue", "overdue must be true or false")
+		}
+		q.Overdue = overdue
+	}
+}
+
+// setPaginationHeaders は総件数を X-Total-Count に、前後のページを Link ヘッダー (RFC 8288) に設定します
+func setPaginationHeaders(w http.ResponseWriter, r *http.Request, result *usecase.TaskListResult) {
+	w.Header().Set("X-Total-Count", strconv.Itoa(result.Total))
+
+	link := func(rel string, set map[string]string) string {
+		params := r.URL.Query()
+		for k, v := range set {
+			params.Set(k, v)
+		}
+		u := url.URL{Path: r.URL.Path, RawQuery: params.Encode()}
+		return fmt.Sprintf(`<%s>; rel="%s"`, u.String(), rel)
+	}
+
+	var links []string
+	if result.NextCursor != "" || result.PrevCursor != "" {
+		if result.NextCursor != "" {
+			links = append(links, link("next", map[string]string{"cursor": result.NextCursor}))
+		}
+		if result.PrevCursor != "" {
+			links = append(links, link("prev", map[string]string{"cursor": result.PrevCursor}))
+		}
+	} else if result.Page > 0 {
+		size := strconv.Itoa(result.PageSize)
+		if result.HasNext {
+			links = append(links, link("next", map[string]string{"page": strconv.Itoa(result.Page + 1), "page_size": size}))
+		}
+		if result.HasPrev {
+			links = append(links, link("prev", map[string]string{"page": strconv.Itoa(result.Page - 1), "page_size": size}))
+		}
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func parseQueryDate(v string) (time.Time, bool) {
+	for _, format := range queryDateFormats {
+		if t, err := time.Parse(format, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func parsePositiveInt(v, field string, verr *apperrors.ValidationError) int {
+	if v == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		verr.Add(field, field+" must be a positive integer")
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,262 @@
+package quickadd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type datePattern struct {
+	re *regexp.Regexp
+	// resolve は一致した部分（m[0] が全体）から日付（その日の 0 時）を返します。日付として不正な場合は false を返します
+	resolve func(m []string, today time.Time) (time.Time, bool)
+}
+
+type timePattern struct {
+	re      *regexp.Regexp
+	resolve func(m []string) (hour, min int, ok bool)
+}
+
+const (
+	// enDatePrefix は日付の前の前置詞（due tomorrow, by friday など）
+	enDatePrefix = `(?i)\b(?:(?:on|by|due|before)\s+)?`
+	// enTimePrefix は時刻の前の前置詞（at 3pm など）
+	enTimePrefix = `(?i)\b(?:(?:at|by)\s+)?`
+	// jaSuffix は日付・時刻の後の助詞（明日までに、15時に など）
+	jaSuffix = `(?:までに|まで|に|の)?`
+)
+
+func enDate(expr string) *regexp.Regexp {
+	return regexp.MustCompile(enDatePrefix + `(?:` + expr + `)\b` + jaSuffix)
+}
+
+func enTime(expr string) *regexp.Regexp {
+	return regexp.MustCompile(enTimePrefix + `(?:` + expr + `)\b` + jaSuffix)
+}
+
+func ja(expr string) *regexp.Regexp {
+	return regexp.MustCompile(`(?:` + expr + `)` + jaSuffix)
+}
+
+const (
+	enMonths   = `jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sep(?:t(?:ember)?)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?`
+	enWeekdays = `monday|tuesday|wednesday|thursday|friday|saturday|sunday`
+	// enWeekdayAbbrs は単語として紛らわしいため、next / this / on などの後でのみ曜日として扱います
+	enWeekdayAbbrs = `mon|tues?|wed|thu(?:rs?)?|fri|sat|sun`
+)
+
+var monthNumbers = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var enWeekdayNumbers = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+var jaWeekdayNumbers = map[string]time.Weekday{
+	"日": time.Sunday, "月": time.Monday, "火": time.Tuesday, "水": time.Wednesday,
+	"木": time.Thursday, "金": time.Friday, "土": time.Saturday,
+}
+
+// jaWeekOffsets は今週を 0 とした週の差
+var jaWeekOffsets = map[string]int{"今週": 0, "来週": 1, "再来週": 2}
+
+// datePatterns は優先順（より具体的な表現が先）に並べた日付の表現
+var datePatterns = []datePattern{
+	// 2024-05-01, 2024/5/1
+	{enDate(`(\d{4})[-/](\d{1,2})[-/](\d{1,2})`), func(m []string, today time.Time) (time.Time, bool) {
+		return ymd(atoi(m[1]), atoi(m[2]), atoi(m[3]), today.Location())
+	}},
+	// 2024年5月1日
+	{ja(`(\d{4})年(\d{1,2})月(\d{1,2})日`), func(m []string, today time.Time) (time.Time, bool) {
+		return ymd(atoi(m[1]), atoi(m[2]), atoi(m[3]), today.Location())
+	}},
+	// 5月1日
+	{ja(`(\d{1,2})月(\d{1,2})日`), func(m []string, today time.Time) (time.Time, bool) {
+		return upcoming(today, atoi(m[1]), atoi(m[2]))
+	}},
+	// 5/1/2024
+	{enDate(`(\d{1,2})/(\d{1,2})/(\d{4})`), func(m []string, today time.Time) (time.Time, bool) {
+		return ymd(atoi(m[3]), atoi(m[1]), atoi(m[2]), today.Location())
+	}},
+	// 5/1
+	{enDate(`(\d{1,2})/(\d{1,2})`), func(m []string, today time.Time) (time.Time, bool) {
+		return upcoming(today, atoi(m[1]), atoi(m[2]))
+	}},
+	// May 1, May 1st 2024
+	{enDate(`(` + enMonths + `)\.?\s+(\d{1,2})(?:st|nd|rd|th)?(?:,?\s+(\d{4}))?`), func(m []string, today time.Time) (time.Time, bool) {
+		return monthDay(today, m[1], m[2], m[3])
+	}},
+	// 1 May, 1st May 2024
+	{enDate(`(\d{1,2})(?:st|nd|rd|th)?\s+(` + enMonths + `)\.?(?:,?\s+(\d{4}))?`), func(m []string, today time.Time) (time.Time, bool) {
+		return monthDay(today, m[2], m[1], m[3])
+	}},
+	{enDate(`day\s+after\s+tomorrow`), relativeDays(2)},
+	{ja(`明後日|あさって`), relativeDays(2)},
+	{enDate(`today|tonight`), relativeDays(0)},
+	{ja(`今日中?|本日中?|きょう|今夜`), relativeDays(0)},
+	{enDate(`tomorrow|tmrw?`), relativeDays(1)},
+	{ja(`明日|あした|あす`), relativeDays(1)},
+	// in 3 days, in 2 weeks, in 1 month
+	{enDate(`in\s+(\d{1,3})\s+(days?|weeks?|months?)`), func(m []string, today time.Time) (time.Time, bool) {
+		return addUnit(today, atoi(m[1]), strings.ToLower(m[2])[:1]), true
+	}},
+	// 3日後, 2週間後, 1か月後
+	{ja(`(\d{1,3})\s*(日|週間|か月|ヶ月|ヵ月|カ月|ケ月)後`), func(m []string, today time.Time) (time.Time, bool) {
+		unit := map[string]string{"日": "d", "週間": "w"}[m[2]]
+		if unit == "" {
+			unit = "m"
+		}
+		return addUnit(today, atoi(m[1]), unit), true
+	}},
+	// next friday, this fri
+	{enDate(`(next|this)\s+(` + enWeekdays + `|` + enWeekdayAbbrs + `)`), func(m []string, today time.Time) (time.Time, bool) {
+		weeks := 0
+		if strings.EqualFold(m[1], "next") {
+			weeks = 1
+		}
+		return weekdayInWeek(today, enWeekday(m[2]), weeks), true
+	}},
+	// 来週金曜, 今週の水曜日
+	{ja(`(再来週|来週|今週)の?([月火水木金土日])曜日?`), func(m []string, today time.Time) (time.Time, bool) {
+		return weekdayInWeek(today, jaWeekdayNumbers[m[2]], jaWeekOffsets[m[1]]), true
+	}},
+	// friday, on fri
+	{enDate(`(` + enWeekdays + `)`), func(m []string, today time.Time) (time.Time, bool) {
+		return weekdayOnOrAfter(today, enWeekday(m[1])), true
+	}},
+	{regexp.MustCompile(`(?i)\b(?:on|by|due|before)\s+(` + enWeekdayAbbrs + `)\b` + jaSuffix), func(m []string, today time.Time) (time.Time, bool) {
+		return weekdayOnOrAfter(today, enWeekday(m[1])), true
+	}},
+	// 金曜, 金曜日
+	{ja(`([月火水木金土日])曜日?`), func(m []string, today time.Time) (time.Time, bool) {
+		return weekdayOnOrAfter(today, jaWeekdayNumbers[m[1]]), true
+	}},
+	// next week（来週の月曜日）
+	{enDate(`next\s+week`), func(m []string, today time.Time) (time.Time, bool) {
+		return weekdayInWeek(today, time.Monday, 1), true
+	}},
+	{ja(`(再来週|来週)`), func(m []string, today time.Time) (time.Time, bool) {
+		return weekdayInWeek(today, time.Monday, jaWeekOffsets[m[1]]), true
+	}},
+	// next month（来月の1日）
+	{enDate(`next\s+month`), firstOfNextMonth},
+	{ja(`来月`), firstOfNextMonth},
+}
+
+// timePatterns は優先順に並べた時刻の表現
+var timePatterns = []timePattern{
+	// 3pm, 3:30 pm
+	{enTime(`(\d{1,2})(?::([0-5]\d))?\s*(am|pm)`), func(m []string) (int, int, bool) {
+		h := atoi(m[1])
+		if h < 1 || h > 12 {
+			return 0, 0, false
+		}
+		h %= 12
+		if strings.EqualFold(m[3], "pm") {
+			h += 12
+		}
+		return h, atoi(m[2]), true
+	}},
+	// 15:00
+	{enTime(`([01]?\d|2[0-3]):([0-5]\d)`), func(m []string) (int, int, bool) {
+		return atoi(m[1]), atoi(m[2]), true
+	}},
+	// 15時, 午後3時半, 午前10時15分（「3時間」は時刻として扱わない）
+	{ja(`(午前|午後)?(\d{1,2})時(間)?(?:(半)|([0-5]?\d)分)?`), func(m []string) (int, int, bool) {
+		h := atoi(m[2])
+		if m[3] != "" || h > 23 || (m[1] != "" && h > 12) {
+			return 0, 0, false
+		}
+		if m[1] == "午後" && h < 12 {
+			h += 12
+		}
+		if m[1] == "午前" && h == 12 {
+			h = 0
+		}
+		min := atoi(m[5])
+		if m[4] != "" {
+			min = 30
+		}
+		return h, min, true
+	}},
+	{enTime(`noon|midday`), noon},
+	{ja(`正午`), noon},
+}
+
+func noon(m []string) (int, int, bool) {
+	return 12, 0, true
+}
+
+func relativeDays(days int) func(m []string, today time.Time) (time.Time, bool) {
+	return func(m []string, today time.Time) (time.Time, bool) {
+		return today.AddDate(0, 0, days), true
+	}
+}
+
+func firstOfNextMonth(m []string, today time.Time) (time.Time, bool) {
+	return time.Date(today.Year(), today.Month()+1, 1, 0, 0, 0, 0, today.Location()), true
+}
+
+// addUnit は unit（d: 日, w: 週, m: 月）単位で n 進めた日付を返します
+func addUnit(today time.Time, n int, unit string) time.Time {
+	switch unit {
+	case "w":
+		return today.AddDate(0, 0, 7*n)
+	case "m":
+		return today.AddDate(0, n, 0)
+	default:
+		return today.AddDate(0, 0, n)
+	}
+}
+
+// weekdayOnOrAfter は today 以降（today を含む）で最初の曜日 wd の日付を返します
+func weekdayOnOrAfter(today time.Time, wd time.Weekday) time.Time {
+	return today.AddDate(0, 0, (int(wd)-int(today.Weekday())+7)%7)
+}
+
+// weekdayInWeek は today の週から weeks 週後の曜日 wd の日付を返します（週は月曜始まり）
+func weekdayInWeek(today time.Time, wd time.Weekday, weeks int) time.Time {
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	return monday.AddDate(0, 0, 7*weeks+(int(wd)+6)%7)
+}
+
+// monthDay は英語の月名と日（年は省略可）から日付を返します
+func monthDay(today time.Time, month, day, year string) (time.Time, bool) {
+	m := monthNumbers[strings.ToLower(month)[:3]]
+	if year != "" {
+		return ymd(atoi(year), m, atoi(day), today.Location())
+	}
+	return upcoming(today, m, atoi(day))
+}
+
+// upcoming は年が省略された月日を、今日以降で最も近い日付にします（過ぎていれば来年）
+func upcoming(today time.Time, month, day int) (time.Time, bool) {
+	t, ok := ymd(today.Year(), month, day, today.Location())
+	if ok && t.Before(today) {
+		t, ok = ymd(today.Year()+1, month, day, today.Location())
+	}
+	return t, ok
+}
+
+// ymd は存在する日付の場合のみ true を返します（2月30日などは false）
+func ymd(year, month, day int, loc *time.Location) (time.Time, bool) {
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
+	return t, t.Year() == year && int(t.Month()) == month && t.Day() == day
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func enWeekday(name string) time.Weekday {
+	return enWeekdayNumbers[strings.ToLower(name)[:3]]
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,195 @@
+// Package quickadd は1行の自然文からタスクの内容を読み取ります（F-1 のクイック追加）
+//
+//	Review PR tomorrow 3pm !high @alice #backend +ProjectX
+//	明日15時までに資料作成 !高 @alice #仕事 +営業
+//
+// !優先度, @担当者, #ラベル, +プロジェクト は空白で区切って指定し、空白を含む値は +"Project X" のように引用符で囲みます
+// 日付・時刻は英語と日本語の表現に対応し、残りの文字列がタイトルになります
+package quickadd
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"todo-app/internal/task/domain"
+)
+
+// Result は入力の解釈です
+type Result struct {
+	Title string
+	// Due は期限で、now と同じタイムゾーンで表します。日付・時刻の指定がない場合は nil です
+	Due *time.Time
+	// DueHasTime は時刻まで指定されたかを表します。日付のみの場合、Due はその日の 0 時です
+	DueHasTime bool
+	Priority   string
+	Assignee   string
+	Labels     []string
+	Project    string
+}
+
+var (
+	ErrEmptyTitle        = errors.New("title is required")
+	ErrMultiplePriority  = errors.New("only one !priority can be specified")
+	ErrMultipleAssignees = errors.New("only one @assignee can be specified")
+	ErrMultipleProjects  = errors.New("only one +project can be specified")
+)
+
+// tokenPattern は空白の後の !priority, @assignee, #label, +project（値は引用符で囲むこともできる）
+var tokenPattern = regexp.MustCompile(`(?:^|\s)([!@#+])(?:"([^"]+)"|([^\s"]+))`)
+
+var priorityWords = map[string]string{
+	"high": domain.PriorityHigh, "h": domain.PriorityHigh, "1": domain.PriorityHigh, "p1": domain.PriorityHigh, "高": domain.PriorityHigh,
+	"medium": domain.PriorityMedium, "med": domain.PriorityMedium, "m": domain.PriorityMedium, "2": domain.PriorityMedium, "p2": domain.PriorityMedium, "中": domain.PriorityMedium,
+	"low": domain.PriorityLow, "l": domain.PriorityLow, "3": domain.PriorityLow, "p3": domain.PriorityLow, "低": domain.PriorityLow,
+}
+
+// fullWidth は全角の記号・数字・空白を半角にそろえます
+var fullWidth = strings.NewReplacer(
+	"！", "!", "＠", "@", "＃", "#", "＋", "+", "：", ":", "／", "/", "　", " ",
+	"０", "0", "１", "1", "２", "2", "３", "3", "４", "4", "５", "5", "６", "6", "７", "7", "８", "8", "９", "9",
+)
+
+// Parse は input を解析します。相対的な日付・時刻は now（呼び出し元のタイムゾーンの現在時刻）を基準にします
+func Parse(input string, now time.Time) (*Result, error) {
+	text := fullWidth.Replace(input)
+	r := &Result{Labels: []string{}}
+
+	text, err := r.extractTokens(text)
+	if err != nil {
+		return nil, err
+	}
+	text = r.extractDue(text, now)
+
+	r.Title = strings.Join(strings.Fields(text), " ")
+	if r.Title == "" {
+		return nil, ErrEmptyTitle
+	}
+	return r, nil
+}
+
+// extractTokens は !priority, @assignee, #label, +project を読み取り、それ以外の文字列を返します
+// 優先度として解釈できない !xxx はタイトルの一部として残します
+func (r *Result) extractTokens(text string) (string, error) {
+	var rest strings.Builder
+	last := 0
+	for _, m := range tokenPattern.FindAllStringSubmatchIndex(text, -1) {
+		sigil := text[m[2]:m[3]]
+		value := ""
+		if m[4] >= 0 {
+			value = strings.TrimSpace(text[m[4]:m[5]])
+		} else {
+			value = text[m[6]:m[7]]
+		}
+
+		switch sigil {
+		case "!":
+			priority, ok := priorityWords[strings.ToLower(value)]
+			if !ok {
+				continue
+			}
+			if r.Priority != "" && r.Priority != priority {
+				return "", ErrMultiplePriority
+			}
+			r.Priority = priority
+		case "@":
+			if r.Assignee != "" && !strings.EqualFold(r.Assignee, value) {
+				return "", ErrMultipleAssignees
+			}
+			r.Assignee = value
+		case "#":
+			if !containsFold(r.Labels, value) {
+				r.Labels = append(r.Labels, value)
+			}
+		case "+":
+			if r.Project != "" && !strings.EqualFold(r.Project, value) {
+				return "", ErrMultipleProjects
+			}
+			r.Project = value
+		}
+		rest.WriteString(text[last:m[0]])
+		rest.WriteString(" ")
+		last = m[1]
+	}
+	rest.WriteString(text[last:])
+	return rest.String(), nil
+}
+
+// extractDue は日付・時刻の表現を1つずつ読み取って期限を設定し、それ以外の文字列を返します
+func (r *Result) extractDue(text string, now time.Time) string {
+	today := dateOf(now)
+
+	var date *time.Time
+	for _, p := range datePatterns {
+		m := p.re.FindStringSubmatchIndex(text)
+		if m == nil {
+			continue
+		}
+		if d, ok := p.resolve(submatches(text, m), today); ok {
+			date = &d
+			text = cut(text, m[0], m[1])
+			break
+		}
+	}
+
+	hour, min, hasTime := 0, 0, false
+	for _, p := range timePatterns {
+		m := p.re.FindStringSubmatchIndex(text)
+		if m == nil {
+			continue
+		}
+		if h, mi, ok := p.resolve(submatches(text, m)); ok {
+			hour, min, hasTime = h, mi, true
+			text = cut(text, m[0], m[1])
+			break
+		}
+	}
+
+	switch {
+	case date != nil:
+		due := time.Date(date.Year(), date.Month(), date.Day(), hour, min, 0, 0, date.Location())
+		r.Due, r.DueHasTime = &due, hasTime
+	case hasTime:
+		// 時刻のみの場合は、今日のその時刻が過ぎていれば明日にする
+		due := time.Date(now.Year(), now.Month(), now.Day(), hour, min, 0, 0, now.Location())
+		if !due.After(now) {
+			due = time.Date(now.Year(), now.Month(), now.Day()+1, hour, min, 0, 0, now.Location())
+		}
+		r.Due, r.DueHasTime = &due, true
+	}
+	return text
+}
+
+func submatches(text string, m []int) []string {
+	s := make([]string, len(m)/2)
+	for i := range s {
+		if m[2*i] >= 0 {
+			s[i] = text[m[2*i]:m[2*i+1]]
+		}
+	}
+	return s
+}
+
+// cut は text から [start, end) を取り除きます
+// 前後がどちらも英数字の場合のみ空白を挟み、日本語の文章には空白を入れません
+func cut(text string, start, end int) string {
+	left, right := text[:start], text[end:]
+	l, _ := utf8.DecodeLastRuneInString(left)
+	rr, _ := utf8.DecodeRuneInString(right)
+	if l < utf8.RuneSelf && rr < utf8.RuneSelf && !unicode.IsSpace(l) && !unicode.IsSpace(rr) && left != "" && right != "" {
+		return left + " " + right
+	}
+	return left + right
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
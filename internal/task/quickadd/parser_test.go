@@ -0,0 +1,224 @@
+package quickadd
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"todo-app/internal/task/domain"
+)
+
+// 基準日時は 2024-05-15（水）10:00 JST
+var (
+	tokyo = time.FixedZone("JST", 9*60*60)
+	now   = time.Date(2024, 5, 15, 10, 0, 0, 0, tokyo)
+)
+
+func at(year int, month time.Month, day, hour, min int) *time.Time {
+	t := time.Date(year, month, day, hour, min, 0, 0, tokyo)
+	return &t
+}
+
+func TestParseTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Result
+	}{
+		{
+			name:  "all tokens",
+			input: "Review PR tomorrow 3pm !high @alice #backend +ProjectX",
+			want:  Result{Title: "Review PR", Due: at(2024, 5, 16, 15, 0), DueHasTime: true, Priority: domain.PriorityHigh, Assignee: "alice", Labels: []string{"backend"}, Project: "ProjectX"},
+		},
+		{
+			name:  "title only",
+			input: "  Write   release notes ",
+			want:  Result{Title: "Write release notes", Labels: []string{}},
+		},
+		{
+			name:  "tokens in the middle",
+			input: "Fix #bug login !1 crash",
+			want:  Result{Title: "Fix login crash", Priority: domain.PriorityHigh, Labels: []string{"bug"}},
+		},
+		{
+			name:  "priority aliases",
+			input: "Tidy desk !p3",
+			want:  Result{Title: "Tidy desk", Priority: domain.PriorityLow, Labels: []string{}},
+		},
+		{
+			name:  "unknown priority stays in title",
+			input: "Ship it !now",
+			want:  Result{Title: "Ship it !now", Labels: []string{}},
+		},
+		{
+			name:  "quoted project and assignee",
+			input: `Plan offsite +"Team Events" @"Bob Smith"`,
+			want:  Result{Title: "Plan offsite", Assignee: "Bob Smith", Labels: []string{}, Project: "Team Events"},
+		},
+		{
+			name:  "multiple labels are deduplicated",
+			input: "Refactor #backend #API #api",
+			want:  Result{Title: "Refactor", Labels: []string{"backend", "API"}},
+		},
+		{
+			name:  "sigils inside words are not tokens",
+			input: "Email bob@example.com about C#",
+			want:  Result{Title: "Email bob@example.com about C#", Labels: []string{}},
+		},
+		{
+			name:  "japanese tokens",
+			input: "資料作成 !高 @alice #仕事 +営業",
+			want:  Result{Title: "資料作成", Priority: domain.PriorityHigh, Assignee: "alice", Labels: []string{"仕事"}, Project: "営業"},
+		},
+		{
+			name:  "full-width sigils",
+			input: "資料作成　！低　＃仕事",
+			want:  Result{Title: "資料作成", Priority: domain.PriorityLow, Labels: []string{"仕事"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input, now)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("Parse(%q) =\n  %+v\nwant\n  %+v", tt.input, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDates(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		title   string
+		due     *time.Time
+		hasTime bool
+	}{
+		// English
+		{"today", "Call mom today", "Call mom", at(2024, 5, 15, 0, 0), false},
+		{"tonight", "Pack bags tonight", "Pack bags", at(2024, 5, 15, 0, 0), false},
+		{"tomorrow", "Review PR tomorrow", "Review PR", at(2024, 5, 16, 0, 0), false},
+		{"tmrw", "Review PR tmrw", "Review PR", at(2024, 5, 16, 0, 0), false},
+		{"due tomorrow", "Report due tomorrow", "Report", at(2024, 5, 16, 0, 0), false},
+		{"day after tomorrow", "Dentist day after tomorrow", "Dentist", at(2024, 5, 17, 0, 0), false},
+		{"in days", "Renew passport in 3 days", "Renew passport", at(2024, 5, 18, 0, 0), false},
+		{"in weeks", "Follow up in 2 weeks", "Follow up", at(2024, 5, 29, 0, 0), false},
+		{"in month", "Check budget in 1 month", "Check budget", at(2024, 6, 15, 0, 0), false},
+		{"weekday later this week", "Deploy friday", "Deploy", at(2024, 5, 17, 0, 0), false},
+		{"weekday is today", "Standup wednesday", "Standup", at(2024, 5, 15, 0, 0), false},
+		{"weekday already passed", "Retro monday", "Retro", at(2024, 5, 20, 0, 0), false},
+		{"on abbreviated weekday", "Demo on fri", "Demo", at(2024, 5, 17, 0, 0), false},
+		{"abbreviation without preposition stays", "Sun cream", "Sun cream", nil, false},
+		{"this weekday", "Sync this Tue", "Sync", at(2024, 5, 14, 0, 0), false},
+		{"next weekday", "Sync next friday", "Sync", at(2024, 5, 24, 0, 0), false},
+		{"next week", "Plan sprint next week", "Plan sprint", at(2024, 5, 20, 0, 0), false},
+		{"next month", "Pay rent next month", "Pay rent", at(2024, 6, 1, 0, 0), false},
+		{"iso date", "Release 2024-06-01", "Release", at(2024, 6, 1, 0, 0), false},
+		{"slash date with year", "Release 2024/6/1", "Release", at(2024, 6, 1, 0, 0), false},
+		{"us date with year", "Release 6/1/2025", "Release", at(2025, 6, 1, 0, 0), false},
+		{"month/day", "Release 6/1", "Release", at(2024, 6, 1, 0, 0), false},
+		{"month/day in the past rolls over", "Taxes 3/15", "Taxes", at(2025, 3, 15, 0, 0), false},
+		{"month name", "Conference May 20", "Conference", at(2024, 5, 20, 0, 0), false},
+		{"month name ordinal and year", "Launch Jan 3rd, 2025", "Launch", at(2025, 1, 3, 0, 0), false},
+		{"day month", "Party 1st June", "Party", at(2024, 6, 1, 0, 0), false},
+		{"invalid date stays in title", "Fix 2/30 report", "Fix 2/30 report", nil, false},
+		{"date and time", "Review PR tomorrow 3pm", "Review PR", at(2024, 5, 16, 15, 0), true},
+		{"date and time with minutes", "Call friday at 4:30 pm", "Call", at(2024, 5, 17, 16, 30), true},
+		{"24 hour time", "Sync 2024-06-01 18:45", "Sync", at(2024, 6, 1, 18, 45), true},
+		{"noon", "Lunch tomorrow noon", "Lunch", at(2024, 5, 16, 12, 0), true},
+		{"12am", "Backup tomorrow 12am", "Backup", at(2024, 5, 16, 0, 0), true},
+		{"time only later today", "Call at 3pm", "Call", at(2024, 5, 15, 15, 0), true},
+		{"time only already passed", "Call at 9am", "Call", at(2024, 5, 16, 9, 0), true},
+		{"word containing a date word", "Todays notes", "Todays notes", nil, false},
+		// 日本語
+		{"今日", "今日 買い物", "買い物", at(2024, 5, 15, 0, 0), false},
+		{"本日中", "本日中に提出", "提出", at(2024, 5, 15, 0, 0), false},
+		{"明日", "明日資料作成", "資料作成", at(2024, 5, 16, 0, 0), false},
+		{"明日までに", "資料作成明日までに", "資料作成", at(2024, 5, 16, 0, 0), false},
+		{"あした", "あした病院", "病院", at(2024, 5, 16, 0, 0), false},
+		{"明後日", "明後日に会議", "会議", at(2024, 5, 17, 0, 0), false},
+		{"あさって", "あさって掃除", "掃除", at(2024, 5, 17, 0, 0), false},
+		{"日後", "3日後に返信", "返信", at(2024, 5, 18, 0, 0), false},
+		{"週間後", "2週間後にレビュー", "レビュー", at(2024, 5, 29, 0, 0), false},
+		{"か月後", "1か月後に更新", "更新", at(2024, 6, 15, 0, 0), false},
+		{"ヶ月後", "2ヶ月後 点検", "点検", at(2024, 7, 15, 0, 0), false},
+		{"曜日", "金曜日に提出", "提出", at(2024, 5, 17, 0, 0), false},
+		{"曜", "月曜 定例", "定例", at(2024, 5, 20, 0, 0), false},
+		{"今週", "今週の火曜日 振り返り", "振り返り", at(2024, 5, 14, 0, 0), false},
+		{"来週曜日", "来週金曜までに見積もり", "見積もり", at(2024, 5, 24, 0, 0), false},
+		{"再来週", "再来週水曜 出張", "出張", at(2024, 5, 29, 0, 0), false},
+		{"来週", "来週 計画", "計画", at(2024, 5, 20, 0, 0), false},
+		{"来月", "来月 健康診断", "健康診断", at(2024, 6, 1, 0, 0), false},
+		{"月日", "6月1日 リリース", "リリース", at(2024, 6, 1, 0, 0), false},
+		{"過ぎた月日は来年", "1月10日 更新", "更新", at(2025, 1, 10, 0, 0), false},
+		{"年月日", "2025年4月1日に入社", "入社", at(2025, 4, 1, 0, 0), false},
+		{"全角数字", "６月１日　リリース", "リリース", at(2024, 6, 1, 0, 0), false},
+		{"日付と時刻", "明日15時までに資料作成", "資料作成", at(2024, 5, 16, 15, 0), true},
+		{"日付の時刻", "明日の15時に打ち合わせ", "打ち合わせ", at(2024, 5, 16, 15, 0), true},
+		{"午後", "金曜 午後3時 面談", "面談", at(2024, 5, 17, 15, 0), true},
+		{"午前", "明日午前10時15分 来客", "来客", at(2024, 5, 16, 10, 15), true},
+		{"半", "明日 9時半 集合", "集合", at(2024, 5, 16, 9, 30), true},
+		{"正午", "明日正午にランチ", "ランチ", at(2024, 5, 16, 12, 0), true},
+		{"時刻のみ", "18時 ジム", "ジム", at(2024, 5, 15, 18, 0), true},
+		{"時刻のみ過ぎている", "8時 ジム", "ジム", at(2024, 5, 16, 8, 0), true},
+		{"時間は時刻ではない", "3時間 作業", "3時間 作業", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input, now)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if got.Title != tt.title {
+				t.Errorf("Parse(%q).Title = %q, want %q", tt.input, got.Title, tt.title)
+			}
+			switch {
+			case tt.due == nil && got.Due != nil:
+				t.Errorf("Parse(%q).Due = %v, want nil", tt.input, got.Due)
+			case tt.due != nil && (got.Due == nil || !got.Due.Equal(*tt.due)):
+				t.Errorf("Parse(%q).Due = %v, want %v", tt.input, got.Due, tt.due)
+			}
+			if got.DueHasTime != tt.hasTime {
+				t.Errorf("Parse(%q).DueHasTime = %v, want %v", tt.input, got.DueHasTime, tt.hasTime)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  error
+	}{
+		{"empty", "", ErrEmptyTitle},
+		{"tokens only", "tomorrow !high #backend", ErrEmptyTitle},
+		{"two priorities", "Task !high !low", ErrMultiplePriority},
+		{"two assignees", "Task @alice @bob", ErrMultipleAssignees},
+		{"two projects", "Task +Alpha +Beta", ErrMultipleProjects},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.input, now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Parse(%q) error = %v, want %v", tt.input, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUsesLocation(t *testing.T) {
+	// UTC では 2024-05-15 23:30 でも、JST では 2024-05-16 08:30
+	utcNow := time.Date(2024, 5, 15, 23, 30, 0, 0, time.UTC)
+	got, err := Parse("Review tomorrow", utcNow.In(tokyo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := at(2024, 5, 17, 0, 0); !got.Due.Equal(*want) {
+		t.Errorf("Due = %v, want %v", got.Due, want)
+	}
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"database/sql"
+
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+)
+
+// boardRepoPg は BoardRepository の PostgreSQL 実装
+type boardRepoPg struct{ db *sql.DB }
+
+// NewBoardRepoPg は PostgreSQL 実装（かんばんボード用）を返す
+func NewBoardRepoPg(db *sql.DB) repository.BoardRepository {
+	return &boardRepoPg{db: db}
+}
+
+func (r *boardRepoPg) ListColumns(projectID string) ([]*domain.BoardColumn, error) {
+	rows, err := r.db.Query(`
+        SELECT project_id, status, name, position, wip_limit, wip_policy
+        FROM board_columns
+        WHERE project_id = $1
+        ORDER BY position, status
+    `, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var columns []*domain.BoardColumn
+	for rows.Next() {
+		c := &domain.BoardColumn{}
+		if err := rows.Scan(&c.ProjectID, &c.Status, &c.Name, &c.Position, &c.WIPLimit, &c.WIPPolicy); err != nil {
+			return nil, err
+		}
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+func (r *boardRepoPg) ReplaceColumns(projectID string, columns []*domain.BoardColumn) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM board_columns WHERE project_id = $1`, projectID); err != nil {
+		return err
+	}
+	for _, c := range columns {
+		if _, err := tx.Exec(`
+            INSERT INTO board_columns (project_id, status, name, position, wip_limit, wip_policy)
+            VALUES ($1, $2, $3, $4, $5, $6)
+        `, projectID, c.Status, c.Name, c.Position, c.WIPLimit, c.WIPPolicy); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *boardRepoPg) ColumnTasks(projectID, status string) ([]domain.RankedTask, error) {
+	rows, err := r.db.Query(`
+        SELECT id, rank
+        FROM tasks
+        WHERE project_id = $1 AND status = $2 AND `+activeTaskCond+`
+        ORDER BY rank = '', rank, created_at, id
+    `, projectID, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []domain.RankedTask
+	for rows.Next() {
+		var t domain.RankedTask
+		if err := rows.Scan(&t.ID, &t.Rank); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
@@ -0,0 +1,162 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+
+	"github.com/lib/pq"
+)
+
+// customFieldRepoPg は CustomFieldRepository の PostgreSQL 実装
+type customFieldRepoPg struct{ db *sql.DB }
+
+// NewCustomFieldRepoPg は PostgreSQL 実装（カスタムフィールド用）を返す
+func NewCustomFieldRepoPg(db *sql.DB) repository.CustomFieldRepository {
+	return &customFieldRepoPg{db: db}
+}
+
+const customFieldColumns = `id, project_id, key, name, type, options, created_at, updated_at`
+
+func scanCustomField(row rowScanner) (*domain.CustomField, error) {
+	f := &domain.CustomField{}
+	err := row.Scan(&f.ID, &f.ProjectID, &f.Key, &f.Name, &f.Type, pq.Array(&f.Options), &f.CreatedAt, &f.UpdatedAt)
+	if f.Options == nil {
+		f.Options = []string{}
+	}
+	return f, err
+}
+
+func (r *customFieldRepoPg) Create(f *domain.CustomField) error {
+	query := `
+        INSERT INTO custom_fields (id, project_id, key, name, type, options, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+    `
+	_, err := r.db.Exec(query, f.ID, f.ProjectID, f.Key, f.Name, f.Type, pq.Array(f.Options), f.CreatedAt, f.UpdatedAt)
+	return err
+}
+
+func (r *customFieldRepoPg) FindByID(id string) (*domain.CustomField, error) {
+	f, err := scanCustomField(r.db.QueryRow(`SELECT `+customFieldColumns+` FROM custom_fields WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("custom field not found")
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// Update は名前と選択肢を更新します。選択肢から外れた値は同じトランザクションで取り除きます
+func (r *customFieldRepoPg) Update(f *domain.CustomField) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE custom_fields SET name = $2, options = $3, updated_at = $4 WHERE id = $1`, f.ID, f.Name, pq.Array(f.Options), f.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("custom field not found")
+	}
+
+	options := pq.Array(f.Options)
+	switch f.Type {
+	case domain.CustomFieldSelect:
+		if _, err := tx.Exec(`DELETE FROM task_custom_values WHERE field_id = $1 AND value #>> '{}' <> ALL($2)`, f.ID, options); err != nil {
+			return err
+		}
+	case domain.CustomFieldMultiSelect:
+		query := `
+            UPDATE task_custom_values
+            SET value = (SELECT COALESCE(jsonb_agg(o), '[]'::jsonb) FROM jsonb_array_elements_text(value) o WHERE o = ANY($2))
+            WHERE field_id = $1
+        `
+		if _, err := tx.Exec(query, f.ID, options); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(`DELETE FROM task_custom_values WHERE field_id = $1 AND value = '[]'::jsonb`, f.ID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *customFieldRepoPg) Delete(id string) error {
+	// task_custom_values は ON DELETE CASCADE で削除される
+	result, err := r.db.Exec(`DELETE FROM custom_fields WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("custom field not found")
+	}
+	return nil
+}
+
+func (r *customFieldRepoPg) ListByProject(projectID string) ([]*domain.CustomField, error) {
+	rows, err := r.db.Query(`SELECT `+customFieldColumns+` FROM custom_fields WHERE project_id = $1 ORDER BY created_at, id`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := []*domain.CustomField{}
+	for rows.Next() {
+		f, err := scanCustomField(rows)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, f)
+	}
+	return fields, rows.Err()
+}
+
+func (r *customFieldRepoPg) TaskIDsByField(fieldID string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT task_id FROM task_custom_values WHERE field_id = $1`, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// replaceCustomValues はタスクのカスタムフィールドの値を task.CustomFields に置き換えます
+// 値はタスクのプロジェクトで Key が一致するフィールドに保存し、他のプロジェクトのフィールドの値は削除します
+func replaceCustomValues(ex execer, task *domain.Task) error {
+	if _, err := ex.Exec(`DELETE FROM task_custom_values WHERE task_id = $1`, task.ID); err != nil {
+		return err
+	}
+	for key, value := range task.CustomFields {
+		if value == nil {
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		query := `
+            INSERT INTO task_custom_values (task_id, field_id, value)
+            SELECT $1, f.id, $4 FROM custom_fields f WHERE f.project_id = $2 AND f.key = $3
+        `
+		if _, err := ex.Exec(query, task.ID, task.ProjectID, key, raw); err != nil {
+			return fmt.Errorf("failed to set custom field %s: %w", key, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+
+	"github.com/lib/pq"
+)
+
+// dependencyRepoPg は TaskDependencyRepository の PostgreSQL 実装
+type dependencyRepoPg struct{ db *sql.DB }
+
+// NewTaskDependencyRepoPg は PostgreSQL 実装（タスク依存関係用）を返す
+func NewTaskDependencyRepoPg(db *sql.DB) repository.TaskDependencyRepository {
+	return &dependencyRepoPg{db: db}
+}
+
+func (r *dependencyRepoPg) Create(dep *domain.TaskDependency) error {
+	// 同じ依存関係の再登録は何もしない
+	query := `
+        INSERT INTO task_dependencies (task_id, depends_on_id, created_by, created_at)
+        VALUES ($1, $2, NULLIF($3, ''), $4)
+        ON CONFLICT (task_id, depends_on_id) DO NOTHING
+    `
+	_, err := r.db.Exec(query, dep.TaskID, dep.DependsOnID, dep.CreatedBy, dep.CreatedAt)
+	return err
+}
+
+func (r *dependencyRepoPg) Delete(taskID, dependsOnID string) error {
+	result, err := r.db.Exec(`DELETE FROM task_dependencies WHERE task_id = $1 AND depends_on_id = $2`, taskID, dependsOnID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("dependency not found")
+	}
+	return nil
+}
+
+func (r *dependencyRepoPg) ListByTask(taskID string) ([]*domain.TaskDependency, error) {
+	query := `
+        SELECT task_id, depends_on_id, COALESCE(created_by, ''), created_at
+        FROM task_dependencies
+        WHERE task_id = $1 OR depends_on_id = $1
+        ORDER BY created_at
+    `
+	return r.list(query, taskID)
+}
+
+func (r *dependencyRepoPg) ListByProject(projectID string) ([]*domain.TaskDependency, error) {
+	query := `
+        SELECT d.task_id, d.depends_on_id, COALESCE(d.created_by, ''), d.created_at
+        FROM task_dependencies d
+        WHERE EXISTS (SELECT 1 FROM tasks WHERE tasks.id IN (d.task_id, d.depends_on_id) AND tasks.project_id = $1)
+        ORDER BY d.created_at
+    `
+	return r.list(query, projectID)
+}
+
+func (r *dependencyRepoPg) list(query string, args ...interface{}) ([]*domain.TaskDependency, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deps := []*domain.TaskDependency{}
+	for rows.Next() {
+		dep := &domain.TaskDependency{}
+		if err := rows.Scan(&dep.TaskID, &dep.DependsOnID, &dep.CreatedBy, &dep.CreatedAt); err != nil {
+			return nil, err
+		}
+		deps = append(deps, dep)
+	}
+	return deps, rows.Err()
+}
+
+func (r *dependencyRepoPg) DependsOn(taskID, otherID string) (bool, error) {
+	query := `
+        WITH RECURSIVE upstream(id) AS (
+            SELECT depends_on_id FROM task_dependencies WHERE task_id = $1
+            UNION
+            SELECT d.depends_on_id FROM task_dependencies d JOIN upstream u ON d.task_id = u.id
+        )
+        SELECT EXISTS (SELECT 1 FROM upstream WHERE id = $2)
+    `
+	var exists bool
+	err := r.db.QueryRow(query, taskID, otherID).Scan(&exists)
+	return exists, err
+}
+
+func (r *dependencyRepoPg) OpenBlockers(taskID string) ([]string, error) {
+	query := `
+        SELECT tasks.id
+        FROM task_dependencies d
+        JOIN tasks ON tasks.id = d.depends_on_id
+        WHERE d.task_id = $1 AND tasks.status <> ALL($2) AND ` + activeTaskCond + `
+        ORDER BY tasks.id
+    `
+	rows, err := r.db.Query(query, taskID, pq.Array(closedStatuses))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
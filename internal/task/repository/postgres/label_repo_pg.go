@@ -0,0 +1,186 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+
+	activitydomain "todo-app/internal/activity/domain"
+	activitypostgres "todo-app/internal/activity/repository/postgres"
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+)
+
+// labelRepoPg は LabelRepository の PostgreSQL 実装
+type labelRepoPg struct{ db *sql.DB }
+
+// NewLabelRepoPg は PostgreSQL 実装（ラベル用）を返す
+func NewLabelRepoPg(db *sql.DB) repository.LabelRepository {
+	return &labelRepoPg{db: db}
+}
+
+const labelColumns = `id, project_id, name, color, created_at, updated_at`
+
+func scanLabel(row rowScanner) (*domain.Label, error) {
+	l := &domain.Label{}
+	err := row.Scan(&l.ID, &l.ProjectID, &l.Name, &l.Color, &l.CreatedAt, &l.UpdatedAt)
+	return l, err
+}
+
+func (r *labelRepoPg) Create(l *domain.Label) error {
+	query := `
+        INSERT INTO labels (id, project_id, name, color, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+	_, err := r.db.Exec(query, l.ID, l.ProjectID, l.Name, l.Color, l.CreatedAt, l.UpdatedAt)
+	return err
+}
+
+func (r *labelRepoPg) FindByID(id string) (*domain.Label, error) {
+	return r.find(`SELECT `+labelColumns+` FROM labels WHERE id = $1`, id)
+}
+
+func (r *labelRepoPg) FindByName(projectID, name string) (*domain.Label, error) {
+	return r.find(`SELECT `+labelColumns+` FROM labels WHERE project_id = $1 AND LOWER(name) = LOWER($2)`, projectID, name)
+}
+
+func (r *labelRepoPg) find(query string, args ...interface{}) (*domain.Label, error) {
+	l, err := scanLabel(r.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("label not found")
+		}
+		return nil, err
+	}
+	return l, nil
+}
+
+func (r *labelRepoPg) Update(l *domain.Label) error {
+	result, err := r.db.Exec(`UPDATE labels SET name = $2, color = $3, updated_at = $4 WHERE id = $1`, l.ID, l.Name, l.Color, l.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("label not found")
+	}
+	return nil
+}
+
+func (r *labelRepoPg) Delete(id string) error {
+	// task_labels は ON DELETE CASCADE で削除される
+	result, err := r.db.Exec(`DELETE FROM labels WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("label not found")
+	}
+	return nil
+}
+
+func (r *labelRepoPg) ListByProject(projectID string) ([]*domain.Label, error) {
+	rows, err := r.db.Query(`SELECT `+labelColumns+` FROM labels WHERE project_id = $1 ORDER BY LOWER(name)`, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	labels := []*domain.Label{}
+	for rows.Next() {
+		l, err := scanLabel(rows)
+		if err != nil {
+			return nil, err
+		}
+		labels = append(labels, l)
+	}
+	return labels, rows.Err()
+}
+
+// SetTaskLabels はタスクのラベルを labelIDs に置き換えます
+// ラベルの付け外しはタスクの表現が変わるため、タスクのバージョンも進めます
+func (r *labelRepoPg) SetTaskLabels(taskID string, labelIDs []string, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM task_labels WHERE task_id = $1`, taskID); err != nil {
+		return err
+	}
+	for _, id := range labelIDs {
+		if _, err := tx.Exec(`INSERT INTO task_labels (task_id, label_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, taskID, id); err != nil {
+			return err
+		}
+	}
+	if err := touchTask(tx, taskID, activity); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *labelRepoPg) AddTaskLabel(taskID, labelID string, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`INSERT INTO task_labels (task_id, label_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, taskID, labelID)
+	if err != nil {
+		return err
+	}
+	// 付与済みのラベルの再登録は何もしない
+	if n, _ := result.RowsAffected(); n == 0 {
+		return nil
+	}
+	if err := touchTask(tx, taskID, activity); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *labelRepoPg) RemoveTaskLabel(taskID, labelID string, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM task_labels WHERE task_id = $1 AND label_id = $2`, taskID, labelID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("task label not found")
+	}
+	if err := touchTask(tx, taskID, activity); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// touchTask はタスクの更新日時とバージョンを進め、ラベルの変更履歴を追記します
+func touchTask(tx *sql.Tx, taskID string, activity *activitydomain.Entry) error {
+	if _, err := tx.Exec(`UPDATE tasks SET updated_at = NOW(), version = version + 1 WHERE id = $1`, taskID); err != nil {
+		return err
+	}
+	return activitypostgres.Append(tx, activity)
+}
+
+func (r *labelRepoPg) TaskIDsByLabel(labelID string) ([]string, error) {
+	rows, err := r.db.Query(`SELECT task_id FROM task_labels WHERE label_id = $1`, labelID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+	projectdomain "todo-app/internal/project/domain"
+	"todo-app/internal/task/repository"
+)
+
+// projectSettingsRepoPg は ProjectSettingsRepository の PostgreSQL 実装
+type projectSettingsRepoPg struct{ db *sql.DB }
+
+// NewProjectSettingsRepoPg は PostgreSQL 実装（プロジェクト設定の参照用）を返す
+func NewProjectSettingsRepoPg(db *sql.DB) repository.ProjectSettingsRepository {
+	return &projectSettingsRepoPg{db: db}
+}
+
+func (r *projectSettingsRepoPg) AutoCompleteTasks(projectID string) (bool, error) {
+	var enabled bool
+	err := r.db.QueryRow(`SELECT auto_complete_tasks FROM projects WHERE id = $1`, projectID).Scan(&enabled)
+	if err == sql.ErrNoRows {
+		// プロジェクトに属さないタスクは自動完了しない
+		return false, nil
+	}
+	return enabled, err
+}
+
+func (r *projectSettingsRepoPg) FindProjectIDByName(userID, name string) (string, error) {
+	query := `
+        SELECT p.id FROM projects p
+        WHERE LOWER(p.name) = LOWER($2) AND p.deleted_at IS NULL
+          AND EXISTS (SELECT 1 FROM project_members pm WHERE pm.project_id = p.id AND pm.user_id = $1)
+        ORDER BY p.created_at
+        LIMIT 1
+    `
+	var id string
+	err := r.db.QueryRow(query, userID, name).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("project not found")
+	}
+	return id, err
+}
+
+func (r *projectSettingsRepoPg) CanAccessProject(userID, projectID string) (bool, error) {
+	query := `
+        SELECT EXISTS (
+            SELECT 1 FROM projects p
+            WHERE p.id = $2 AND p.deleted_at IS NULL
+              AND EXISTS (SELECT 1 FROM project_members pm WHERE pm.project_id = p.id AND pm.user_id = $1)
+        )
+    `
+	var ok bool
+	err := r.db.QueryRow(query, userID, projectID).Scan(&ok)
+	return ok, err
+}
+
+func (r *projectSettingsRepoPg) IsArchived(projectID string) (bool, error) {
+	var status string
+	err := r.db.QueryRow(`SELECT status FROM projects WHERE id = $1`, projectID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return status == projectdomain.StatusArchived, err
+}
+
+func (r *projectSettingsRepoPg) SprintProject(sprintID string) (string, string, error) {
+	var projectID, status string
+	err := r.db.QueryRow(`SELECT project_id, status FROM sprints WHERE id = $1`, sprintID).Scan(&projectID, &status)
+	if err == sql.ErrNoRows {
+		return "", "", nil
+	}
+	return projectID, status, err
+}
+
+func (r *projectSettingsRepoPg) MilestoneProject(milestoneID string) (string, error) {
+	var projectID string
+	err := r.db.QueryRow(`SELECT project_id FROM milestones WHERE id = $1`, milestoneID).Scan(&projectID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return projectID, err
+}
+
+func (r *projectSettingsRepoPg) ProjectDates(projectID string) (time.Time, time.Time, error) {
+	var start, end sql.NullTime
+	err := r.db.QueryRow(`SELECT start_date, end_date FROM projects WHERE id = $1`, projectID).Scan(&start, &end)
+	if err == sql.ErrNoRows {
+		return time.Time{}, time.Time{}, nil
+	}
+	return start.Time, end.Time, err
+}
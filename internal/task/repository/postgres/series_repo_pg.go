@@ -0,0 +1,137 @@
+package postgres
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	activitydomain "todo-app/internal/activity/domain"
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+)
+
+// seriesRepoPg は TaskSeriesRepository の PostgreSQL 実装
+type seriesRepoPg struct{ db *sql.DB }
+
+// NewTaskSeriesRepoPg は PostgreSQL 実装（繰り返しタスクの系列用）を返す
+func NewTaskSeriesRepoPg(db *sql.DB) repository.TaskSeriesRepository {
+	return &seriesRepoPg{db: db}
+}
+
+const seriesColumns = `id, rule, start_at, last_due, occurrences, ended, title, COALESCE(description, ''), COALESCE(priority, ''),
+        COALESCE(project_id, ''), COALESCE(assignee_id, ''), COALESCE(created_by, ''), created_at, updated_at`
+
+func scanSeries(row rowScanner) (*domain.TaskSeries, error) {
+	s := &domain.TaskSeries{}
+	err := row.Scan(&s.ID, &s.Rule, &s.Start, &s.LastDue, &s.Occurrences, &s.Ended, &s.Title, &s.Description, &s.Priority,
+		&s.ProjectID, &s.AssigneeID, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
+
+func (r *seriesRepoPg) Create(s *domain.TaskSeries) error {
+	return insertSeries(r.db, s)
+}
+
+// insertSeries は系列を登録します（タスクの変更と同じトランザクションでも使います）
+func insertSeries(ex execer, s *domain.TaskSeries) error {
+	query := `
+        INSERT INTO task_series (id, rule, start_at, last_due, occurrences, ended, title, description, priority, project_id, assignee_id, created_by, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''), NULLIF($12, ''), $13, $14)
+    `
+	_, err := ex.Exec(query, s.ID, s.Rule, s.Start, s.LastDue, s.Occurrences, s.Ended, s.Title, s.Description, s.Priority,
+		s.ProjectID, s.AssigneeID, s.CreatedBy, s.CreatedAt, s.UpdatedAt)
+	return err
+}
+
+func (r *seriesRepoPg) FindByID(id string) (*domain.TaskSeries, error) {
+	s, err := scanSeries(r.db.QueryRow(`SELECT `+seriesColumns+` FROM task_series WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("series not found")
+		}
+		return nil, err
+	}
+	return s, nil
+}
+
+func (r *seriesRepoPg) Update(s *domain.TaskSeries, expectedOccurrences int) error {
+	return updateSeries(r.db, s, expectedOccurrences)
+}
+
+// updateSeries は保存済みの発生回数が expectedOccurrences と一致する場合のみ系列を更新します
+func updateSeries(ex execer, s *domain.TaskSeries, expectedOccurrences int) error {
+	query := `
+        UPDATE task_series
+        SET rule = $2, start_at = $3, last_due = $4, occurrences = $5, ended = $6, title = $7, description = $8, priority = $9,
+            assignee_id = NULLIF($10, ''), updated_at = NOW()
+        WHERE id = $1 AND occurrences = $11
+    `
+	result, err := ex.Exec(query, s.ID, s.Rule, s.Start, s.LastDue, s.Occurrences, s.Ended, s.Title, s.Description, s.Priority,
+		s.AssigneeID, expectedOccurrences)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return apperrors.ErrVersionMismatch
+	}
+	return nil
+}
+
+// Advance は系列を更新し、next を系列の新しい発生として同じトランザクションで登録します
+func (r *seriesRepoPg) Advance(s *domain.TaskSeries, expectedOccurrences int, next *domain.Task, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := updateSeries(tx, s, expectedOccurrences); err != nil {
+		return err
+	}
+	if err := insertTask(tx, next, activity); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *seriesRepoPg) LatestOccurrence(seriesID string) (*domain.Task, error) {
+	query := `
+        SELECT ` + taskColumns + `
+        FROM tasks
+        WHERE series_id = $1 AND ` + activeTaskCond + `
+        ORDER BY occurrence DESC
+        LIMIT 1
+    `
+	task, err := scanTask(r.db.QueryRow(query, seriesID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return task, err
+}
+
+func (r *seriesRepoPg) ListDue(now time.Time) ([]*domain.TaskSeries, error) {
+	// ゴミ箱に移動された系列（有効な発生が1件もない系列）は対象外
+	query := `
+        SELECT ` + seriesColumns + `
+        FROM task_series
+        WHERE NOT ended AND last_due <= $1
+          AND EXISTS (SELECT 1 FROM tasks WHERE tasks.series_id = task_series.id AND ` + activeTaskCond + `)
+        ORDER BY last_due
+    `
+	rows, err := r.db.Query(query, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var series []*domain.TaskSeries
+	for rows.Next() {
+		s, err := scanSeries(rows)
+		if err != nil {
+			return nil, err
+		}
+		series = append(series, s)
+	}
+	return series, rows.Err()
+}
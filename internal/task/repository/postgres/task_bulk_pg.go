@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	activitypostgres "todo-app/internal/activity/repository/postgres"
+	"todo-app/internal/task/repository"
+)
+
+// ApplyChanges は変更ごとにセーブポイントを作り、失敗した変更のみをセーブポイントまで巻き戻します
+// atomic の場合は最初に失敗した変更でトランザクション全体を取り消し、残りの変更は適用しません
+func (r *taskRepoPg) ApplyChanges(ctx context.Context, changes []*repository.TaskChange, atomic bool) ([]error, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	errs := make([]error, len(changes))
+	for i, change := range changes {
+		if _, err := tx.ExecContext(ctx, `SAVEPOINT task_change`); err != nil {
+			return nil, err
+		}
+		if err := r.applyChange(ctx, tx, change); err != nil {
+			errs[i] = err
+			if atomic {
+				return errs, nil
+			}
+			if _, err := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT task_change`); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT task_change`); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	for i, change := range changes {
+		if errs[i] == nil {
+			change.Task.Version++
+		}
+	}
+	return errs, nil
+}
+
+func (r *taskRepoPg) applyChange(ctx context.Context, tx *sql.Tx, change *repository.TaskChange) error {
+	task := change.Task
+	var result sql.Result
+	var err error
+	// 新しい系列はタスクから参照されるため、タスクより先に保存する
+	if s := change.Series; s != nil {
+		if change.SeriesOccurrences == 0 {
+			err = insertSeries(tx, s)
+		} else {
+			err = updateSeries(tx, s, change.SeriesOccurrences)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if change.Delete {
+		result, err = tx.ExecContext(ctx, `
+        UPDATE tasks
+        SET deleted_at = NOW(), version = version + 1
+        WHERE id = $1 AND version = $2 AND deleted_at IS NULL
+    `, task.ID, task.Version)
+	} else {
+		result, err = tx.ExecContext(ctx, `
+        UPDATE tasks
+        SET title = $2, description = $3, project_id = $4, assignee_id = NULLIF($5, ''), due_date = $6, priority = $7, status = $8, updated_at = $9, version = version + 1,
+            series_id = NULLIF($11, ''), occurrence = NULLIF($12, 0), estimate = $13, sprint_id = NULLIF($14, ''), milestone_id = NULLIF($15, ''),
+            start_date = $16, duration_days = $17, rank = $18
+        WHERE id = $1 AND version = $10 AND deleted_at IS NULL
+    `, task.ID, task.Title, task.Description, task.ProjectID, task.AssigneeID, task.DueDate, task.Priority, task.Status, task.UpdatedAt, task.Version, task.SeriesID, task.Occurrence, task.Estimate,
+			task.SprintID, task.MilestoneID, nullTime(task.StartDate), task.DurationDays, task.Rank)
+	}
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return r.missingOrStale(task.ID)
+	}
+	if change.Delete {
+		return activitypostgres.Append(tx, change.Activity)
+	}
+
+	if change.ReplaceLabels {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM task_labels WHERE task_id = $1`, task.ID); err != nil {
+			return err
+		}
+		for _, l := range task.Labels {
+			if _, err := tx.ExecContext(ctx, `INSERT INTO task_labels (task_id, label_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, task.ID, l.ID); err != nil {
+				return fmt.Errorf("failed to attach label %s: %w", l.ID, err)
+			}
+		}
+	}
+	if change.ReplaceAssignees {
+		if err := replaceAssignees(tx, task.ID, task.AssigneeIDs); err != nil {
+			return err
+		}
+	}
+	if change.ReplaceCustomFields {
+		if err := replaceCustomValues(tx, task); err != nil {
+			return err
+		}
+	}
+	if t := change.Transition; t != nil {
+		query := `
+        INSERT INTO task_transitions (id, task_id, from_status, to_status, actor_id, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+		if _, err := tx.ExecContext(ctx, query, t.ID, t.TaskID, t.FromStatus, t.ToStatus, t.ActorID, t.CreatedAt); err != nil {
+			return err
+		}
+	}
+	return activitypostgres.Append(tx, change.Activity)
+}
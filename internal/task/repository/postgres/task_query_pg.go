@@ -0,0 +1,252 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+
+	"github.com/lib/pq"
+)
+
+// taskSortColumns は並び替え列ごとの SQL 式とカーソル値のキャスト先の型
+var taskSortColumns = map[string]struct{ expr, cast string }{
+	"title":       {"title", "text"},
+	"due_date":    {"due_date", "timestamp"},
+	"priority":    {"CASE priority WHEN 'High' THEN 1 WHEN 'Medium' THEN 2 WHEN 'Low' THEN 3 ELSE 4 END", "integer"},
+	"status":      {"status", "text"},
+	"project_id":  {"project_id", "text"},
+	"assignee_id": {"COALESCE(assignee_id, '')", "text"},
+	"created_by":  {"created_by", "text"},
+	"created_at":  {"created_at", "timestamp"},
+	"updated_at":  {"updated_at", "timestamp"},
+}
+
+// customValueExpr はタスクのプロジェクトで key のカスタムフィールドの値（JSONB。値がない場合は null）を返す SQL 式です
+// JSONB の比較で null・文字列・数値・配列の順に並ぶため、値のないタスクは昇順で先頭になります
+func customValueExpr(key string) string {
+	return fmt.Sprintf(`COALESCE((SELECT v.value FROM task_custom_values v JOIN custom_fields f ON f.id = v.field_id
+            WHERE v.task_id = tasks.id AND f.project_id = tasks.project_id AND f.key = %s), 'null'::jsonb)`, pq.QuoteLiteral(key))
+}
+
+// sortColumn は並び替え列の SQL 式とカーソル値のキャスト先の型を返します（不明な列は既定の並び順）
+func sortColumn(sortBy string) (expr, cast string) {
+	if key, ok := repository.CustomFieldSortKey(sortBy); ok {
+		return customValueExpr(key), "jsonb"
+	}
+	col, ok := taskSortColumns[sortBy]
+	if !ok {
+		col = taskSortColumns[repository.DefaultTaskSort]
+	}
+	return col.expr, col.cast
+}
+
+// closedStatuses は未完了の集計・期限切れ判定から除外するステータス
+var closedStatuses = []string{domain.StatusDone, domain.StatusCanceled}
+
+// taskFilter は TaskQuery の絞り込み条件から WHERE 句を組み立てます
+func taskFilter(q repository.TaskQuery, arg func(interface{}) string) string {
+	where := []string{activeTaskCond}
+	if len(q.IDs) > 0 {
+		where = append(where, "id = ANY("+arg(pq.Array(q.IDs))+")")
+	}
+	if len(q.Statuses) > 0 {
+		where = append(where, "status = ANY("+arg(pq.Array(q.Statuses))+")")
+	}
+	if len(q.Priorities) > 0 {
+		where = append(where, "priority = ANY("+arg(pq.Array(q.Priorities))+")")
+	}
+	if q.AssigneeID != "" {
+		where = append(where, "EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = tasks.id AND ta.user_id = "+arg(q.AssigneeID)+")")
+	}
+	if q.ProjectID != "" {
+		where = append(where, "project_id = "+arg(q.ProjectID))
+	}
+	if q.CreatedBy != "" {
+		where = append(where, "created_by = "+arg(q.CreatedBy))
+	}
+	where = appendScheduleFilter(where, "sprint_id", q.SprintID, arg)
+	where = appendScheduleFilter(where, "milestone_id", q.MilestoneID, arg)
+	if q.DueFrom != nil {
+		where = append(where, "due_date >= "+arg(*q.DueFrom))
+	}
+	if q.DueTo != nil {
+		where = append(where, "due_date <= "+arg(*q.DueTo))
+	}
+	if q.Overdue {
+		where = append(where, "due_date < NOW() AND status <> ALL("+arg(pq.Array(closedStatuses))+")")
+	}
+	if len(q.Labels) > 0 {
+		labels := arg(pq.Array(q.Labels))
+		where = append(where, fmt.Sprintf(`EXISTS (SELECT 1 FROM task_labels tl JOIN labels l ON l.id = tl.label_id
+            WHERE tl.task_id = tasks.id AND (l.id = ANY(%[1]s) OR LOWER(l.name) = ANY(SELECT LOWER(x) FROM UNNEST(%[1]s::text[]) x)))`, labels))
+	}
+	keys := make([]string, 0, len(q.CustomFields))
+	for key := range q.CustomFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		values := arg(pq.Array(q.CustomFields[key]))
+		where = append(where, fmt.Sprintf(`EXISTS (SELECT 1 FROM task_custom_values v JOIN custom_fields f ON f.id = v.field_id
+            WHERE v.task_id = tasks.id AND f.project_id = tasks.project_id AND f.key = %[1]s
+              AND (v.value #>> '{}' = ANY(%[2]s) OR (jsonb_typeof(v.value) = 'array' AND v.value ?| %[2]s)))`, arg(key), values))
+	}
+	if q.VisibleTo != "" {
+		u := arg(q.VisibleTo)
+		where = append(where, fmt.Sprintf(`(tasks.created_by = %[1]s OR EXISTS (
+            SELECT 1 FROM task_assignees ta WHERE ta.task_id = tasks.id AND ta.user_id = %[1]s) OR EXISTS (
+            SELECT 1 FROM project_members pm WHERE pm.project_id = tasks.project_id AND pm.user_id = %[1]s))`, u))
+	}
+
+	return "WHERE " + strings.Join(where, " AND ")
+}
+
+// appendScheduleFilter はスプリント・マイルストーンの絞り込み条件を追加します（ScheduleNone は予定先なし）
+func appendScheduleFilter(where []string, column, id string, arg func(interface{}) string) []string {
+	switch id {
+	case "":
+		return where
+	case repository.ScheduleNone:
+		return append(where, column+" IS NULL")
+	default:
+		return append(where, column+" = "+arg(id))
+	}
+}
+
+// List は TaskQuery の条件でタスクを検索し、1ページ分と総件数を返します
+func (r *taskRepoPg) List(ctx context.Context, q repository.TaskQuery) (*repository.TaskPage, error) {
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	filter := taskFilter(q, arg)
+
+	var total int
+	countQuery := "SELECT COUNT(*) FROM tasks " + filter
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, err
+	}
+
+	expr, cast := sortColumn(q.SortBy)
+
+	// 後方向のカーソルでは並び順を反転して取得し、最後に元の順に戻す
+	desc := q.SortDesc
+	if q.Cursor != nil && q.Cursor.Backward {
+		desc = !desc
+	}
+	direction, cmp := "ASC", ">"
+	if desc {
+		direction, cmp = "DESC", "<"
+	}
+
+	if q.Cursor != nil {
+		filter += fmt.Sprintf(" AND (%s, id) %s (%s::%s, %s)", expr, cmp, arg(q.Cursor.Value), cast, arg(q.Cursor.ID))
+	}
+
+	query := fmt.Sprintf(`
+        SELECT %s
+        FROM tasks
+        %s
+        ORDER BY %s %s, id %s
+    `, taskColumns, filter, expr, direction, direction)
+
+	// 続きの有無を判定するため 1 件多く取得する
+	if q.Limit > 0 {
+		query += " LIMIT " + arg(q.Limit+1)
+	}
+	if q.Cursor == nil && q.Offset > 0 {
+		query += " OFFSET " + arg(q.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []*domain.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &repository.TaskPage{Total: total}
+	if q.Limit > 0 && len(tasks) > q.Limit {
+		tasks = tasks[:q.Limit]
+		page.HasMore = true
+	}
+	if q.Cursor != nil && q.Cursor.Backward {
+		for i, j := 0, len(tasks)-1; i < j; i, j = i+1, j-1 {
+			tasks[i], tasks[j] = tasks[j], tasks[i]
+		}
+	}
+	page.Tasks = tasks
+	return page, nil
+}
+
+// Summary は TaskQuery の絞り込み条件に一致するタスクをステータス・優先度・担当者・期限切れごとに集計します
+func (r *taskRepoPg) Summary(ctx context.Context, q repository.TaskQuery) (*repository.TaskSummary, error) {
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	filter := taskFilter(q, arg)
+	closed := arg(pq.Array(closedStatuses))
+	query := fmt.Sprintf(`
+        SELECT status, priority, COALESCE(assignee_id, ''),
+               COALESCE(due_date < NOW() AND status <> ALL(%[1]s), FALSE) AS overdue,
+               status = ANY(%[1]s) AS closed,
+               COUNT(*)
+        FROM tasks
+        %[2]s
+        GROUP BY 1, 2, 3, 4, 5
+    `, closed, filter)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary := &repository.TaskSummary{
+		ByStatus:   map[string]int{},
+		ByPriority: map[string]int{},
+		ByAssignee: map[string]int{},
+	}
+	for rows.Next() {
+		var status, priority, assigneeID string
+		var overdue, closed bool
+		var count int
+		if err := rows.Scan(&status, &priority, &assigneeID, &overdue, &closed, &count); err != nil {
+			return nil, err
+		}
+		summary.Total += count
+		if !closed {
+			summary.Incomplete += count
+		}
+		if overdue {
+			summary.Overdue += count
+		}
+		summary.ByStatus[status] += count
+		summary.ByPriority[priority] += count
+		summary.ByAssignee[assigneeID] += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
@@ -1,31 +1,135 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"time"
+	activitydomain "todo-app/internal/activity/domain"
+	activitypostgres "todo-app/internal/activity/repository/postgres"
+	apperrors "todo-app/internal/common/errors"
 	"todo-app/internal/task/domain"
 	"todo-app/internal/task/repository"
+
+	"github.com/lib/pq"
 )
 
 type taskRepoPg struct{ db *sql.DB }
 
+// activeTaskCond はゴミ箱内のタスクと、ゴミ箱内のプロジェクトに属するタスクを除外する条件
+const activeTaskCond = `tasks.deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM projects p WHERE p.id = tasks.project_id AND p.deleted_at IS NOT NULL)`
+
+// taskColumns はタスク取得時の列。サブタスクの完了数・総数、繰り返しの RRULE、ラベルと作業時間の合計、担当者、予定先、タイムラインの日程、ボードの順位、
+// カスタムフィールドの値（タスクのプロジェクトのフィールドのみ）も合わせて取得する
+const taskColumns = `id, title, description, project_id, COALESCE(assignee_id, ''), due_date, priority, status, created_by, created_at, updated_at, version,
+        (SELECT COUNT(*) FROM subtasks s WHERE s.task_id = tasks.id AND s.is_complete),
+        (SELECT COUNT(*) FROM subtasks s WHERE s.task_id = tasks.id),
+        COALESCE(series_id, ''), COALESCE(occurrence, 0),
+        COALESCE((SELECT ts.rule FROM task_series ts WHERE ts.id = tasks.series_id AND NOT ts.ended), ''),
+        COALESCE((SELECT json_agg(json_build_object('id', l.id, 'name', l.name, 'color', l.color) ORDER BY LOWER(l.name))
+            FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE tl.task_id = tasks.id), '[]'::json),
+        estimate,
+        (SELECT COALESCE(SUM(te.minutes), 0) FROM time_entries te WHERE te.task_id = tasks.id AND te.ended_at IS NOT NULL),
+        ARRAY(SELECT ta.user_id FROM task_assignees ta WHERE ta.task_id = tasks.id ORDER BY ta.position),
+        COALESCE(sprint_id, ''), COALESCE(milestone_id, ''), start_date, duration_days, rank,
+        COALESCE((SELECT jsonb_object_agg(f.key, v.value)
+            FROM task_custom_values v JOIN custom_fields f ON f.id = v.field_id WHERE v.task_id = tasks.id AND f.project_id = tasks.project_id), '{}'::jsonb)`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTask は taskColumns の順に列を読み取ります
+func scanTask(row rowScanner) (*domain.Task, error) {
+	task := &domain.Task{}
+	var labels, customFields []byte
+	var start sql.NullTime
+	err := row.Scan(&task.ID, &task.Title, &task.Description, &task.ProjectID, &task.AssigneeID, &task.DueDate, &task.Priority, &task.Status, &task.CreatedBy, &task.CreatedAt, &task.UpdatedAt, &task.Version,
+		&task.SubtaskProgress.Done, &task.SubtaskProgress.Total, &task.SeriesID, &task.Occurrence, &task.Recurrence, &labels,
+		&task.Estimate, &task.TimeSpent, pq.Array(&task.AssigneeIDs),
+		&task.SprintID, &task.MilestoneID, &start, &task.DurationDays, &task.Rank, &customFields)
+	if err != nil {
+		return task, err
+	}
+	task.StartDate = start.Time
+	if err := json.Unmarshal(customFields, &task.CustomFields); err != nil {
+		return task, err
+	}
+	err = json.Unmarshal(labels, &task.Labels)
+	return task, err
+}
+
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// nullTime はゼロ値の日時を NULL として保存します
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// replaceAssignees はタスクの担当者を ids（先頭が主担当者）に置き換えます
+func replaceAssignees(ex execer, taskID string, ids []string) error {
+	if _, err := ex.Exec(`DELETE FROM task_assignees WHERE task_id = $1`, taskID); err != nil {
+		return err
+	}
+	for i, id := range ids {
+		if _, err := ex.Exec(`INSERT INTO task_assignees (task_id, user_id, position) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`, taskID, id, i); err != nil {
+			return fmt.Errorf("failed to assign user %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
 func NewTaskRepoPg(db *sql.DB) repository.TaskRepository {
 	return &taskRepoPg{db: db}
 }
 
-func (r *taskRepoPg) Create(task *domain.Task) error {
+// Create はタスクを登録します。task.Labels のラベル、task.AssigneeIDs の担当者、task.CustomFields の値、変更履歴も合わせて登録します
+func (r *taskRepoPg) Create(task *domain.Task, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := insertTask(tx, task, activity); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// insertTask は Create の登録を tx で行います（系列の発生の生成でも使います）
+func insertTask(tx *sql.Tx, task *domain.Task, activity *activitydomain.Entry) error {
 	query := `
-        INSERT INTO tasks (id, title, description, project_id, assignee_id, due_date, priority, status, created_by, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+        INSERT INTO tasks (id, title, description, project_id, assignee_id, due_date, priority, status, created_by, created_at, updated_at, version, series_id, occurrence, estimate,
+            sprint_id, milestone_id, start_date, duration_days, rank)
+        VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9, NOW(), NOW(), $10, NULLIF($11, ''), NULLIF($12, 0), $13, NULLIF($14, ''), NULLIF($15, ''), $16, $17, $18)
     `
-	_, err := r.db.Exec(query, task.ID, task.Title, task.Description, task.ProjectID, task.AssigneeID, task.DueDate, task.Priority, task.Status, task.CreatedBy)
-	return err
+	if _, err := tx.Exec(query, task.ID, task.Title, task.Description, task.ProjectID, task.AssigneeID, task.DueDate, task.Priority, task.Status, task.CreatedBy, task.Version, task.SeriesID, task.Occurrence, task.Estimate,
+		task.SprintID, task.MilestoneID, nullTime(task.StartDate), task.DurationDays, task.Rank); err != nil {
+		return err
+	}
+	for _, l := range task.Labels {
+		if _, err := tx.Exec(`INSERT INTO task_labels (task_id, label_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, task.ID, l.ID); err != nil {
+			return err
+		}
+	}
+	if err := replaceAssignees(tx, task.ID, task.AssigneeIDs); err != nil {
+		return err
+	}
+	if err := replaceCustomValues(tx, task); err != nil {
+		return err
+	}
+	return activitypostgres.Append(tx, activity)
 }
 
 func (r *taskRepoPg) GetAll() ([]*domain.Task, error) {
 	query := `
-        SELECT id, title, description, project_id, assignee_id, due_date, priority, status, created_by, created_at, updated_at
+        SELECT ` + taskColumns + `
         FROM tasks
+        WHERE ` + activeTaskCond + `
         ORDER BY created_at DESC
     `
 	rows, err := r.db.Query(query)
@@ -36,8 +140,7 @@ func (r *taskRepoPg) GetAll() ([]*domain.Task, error) {
 
 	var tasks []*domain.Task
 	for rows.Next() {
-		task := &domain.Task{}
-		err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.ProjectID, &task.AssigneeID, &task.DueDate, &task.Priority, &task.Status, &task.CreatedBy, &task.CreatedAt, &task.UpdatedAt)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -48,12 +151,11 @@ func (r *taskRepoPg) GetAll() ([]*domain.Task, error) {
 
 func (r *taskRepoPg) GetByID(id string) (*domain.Task, error) {
 	query := `
-        SELECT id, title, description, project_id, assignee_id, due_date, priority, status, created_by, created_at, updated_at
+        SELECT ` + taskColumns + `
         FROM tasks
-        WHERE id = $1
+        WHERE id = $1 AND ` + activeTaskCond + `
     `
-	task := &domain.Task{}
-	err := r.db.QueryRow(query, id).Scan(&task.ID, &task.Title, &task.Description, &task.ProjectID, &task.AssigneeID, &task.DueDate, &task.Priority, &task.Status, &task.CreatedBy, &task.CreatedAt, &task.UpdatedAt)
+	task, err := scanTask(r.db.QueryRow(query, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, fmt.Errorf("task not found")
@@ -67,14 +169,68 @@ func (r *taskRepoPg) FindByID(id string) (*domain.Task, error) {
 	return r.GetByID(id)
 }
 
-func (r *taskRepoPg) Update(task *domain.Task) error {
-	// TODO: UPDATE
+// Update は task.Version が保存済みのバージョンと一致する場合のみ更新し、バージョンを 1 つ進めます
+// 担当者は task.AssigneeIDs に、カスタムフィールドの値は task.CustomFields に置き換えます
+func (r *taskRepoPg) Update(task *domain.Task, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE tasks
+        SET title = $2, description = $3, project_id = $4, assignee_id = NULLIF($5, ''), due_date = $6, priority = $7, status = $8, updated_at = $9, version = version + 1,
+            series_id = NULLIF($11, ''), occurrence = NULLIF($12, 0), estimate = $13, sprint_id = NULLIF($14, ''), milestone_id = NULLIF($15, ''),
+            start_date = $16, duration_days = $17, rank = $18
+        WHERE id = $1 AND version = $10 AND deleted_at IS NULL
+    `
+	result, err := tx.Exec(query, task.ID, task.Title, task.Description, task.ProjectID, task.AssigneeID, task.DueDate, task.Priority, task.Status, task.UpdatedAt, task.Version, task.SeriesID, task.Occurrence, task.Estimate,
+		task.SprintID, task.MilestoneID, nullTime(task.StartDate), task.DurationDays, task.Rank)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return r.missingOrStale(task.ID)
+	}
+	if err := replaceAssignees(tx, task.ID, task.AssigneeIDs); err != nil {
+		return err
+	}
+	if err := replaceCustomValues(tx, task); err != nil {
+		return err
+	}
+	if err := activitypostgres.Append(tx, activity); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	task.Version++
 	return nil
 }
 
-func (r *taskRepoPg) Delete(id string) error {
-	query := `DELETE FROM tasks WHERE id = $1`
-	result, err := r.db.Exec(query, id)
+// Delete はタスクをゴミ箱に移動（論理削除）します
+// version が 0 の場合はバージョンを問わず、それ以外は一致する場合のみ削除します
+func (r *taskRepoPg) Delete(id string, version int, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE tasks
+        SET deleted_at = NOW(), version = version + 1
+        WHERE id = $1 AND ($2 = 0 OR version = $2) AND deleted_at IS NULL
+    `
+	result, err := tx.Exec(query, id, version)
 	if err != nil {
 		return err
 	}
@@ -85,17 +241,173 @@ func (r *taskRepoPg) Delete(id string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("task not found")
+		return r.missingOrStale(id)
+	}
+	if err := activitypostgres.Append(tx, activity); err != nil {
+		return err
 	}
+	return tx.Commit()
+}
+
+// missingOrStale は更新・削除が 0 件だった理由（存在しない／バージョン不一致）を判定します
+func (r *taskRepoPg) missingOrStale(id string) error {
+	var exists bool
+	if err := r.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)`, id).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return apperrors.ErrVersionMismatch
+	}
+	return fmt.Errorf("task not found")
+}
 
+// Restore はゴミ箱内のタスクを元に戻します
+func (r *taskRepoPg) Restore(id string, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE tasks
+        SET deleted_at = NULL, version = version + 1
+        WHERE id = $1 AND deleted_at IS NOT NULL
+    `
+	result, err := tx.Exec(query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("task not found in trash")
+	}
+	if err := activitypostgres.Append(tx, activity); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListDeleted はゴミ箱内のタスクのうち userID が扱えるものを削除日時の新しい順に返します
+func (r *taskRepoPg) ListDeleted(userID string, roles []string) ([]*domain.Task, error) {
+	query := `
+        SELECT id, title, description, project_id, COALESCE(assignee_id, ''), due_date, priority, status, created_by, created_at, updated_at, version, deleted_at
+        FROM tasks
+        WHERE deleted_at IS NOT NULL
+          AND (EXISTS (SELECT 1 FROM project_members pm WHERE pm.project_id = tasks.project_id AND pm.user_id = $1 AND pm.role = ANY($2))
+            OR (COALESCE(project_id, '') = '' AND (created_by = $1 OR assignee_id = $1 OR EXISTS (
+                SELECT 1 FROM task_assignees ta WHERE ta.task_id = tasks.id AND ta.user_id = $1))))
+        ORDER BY deleted_at DESC
+    `
+	rows, err := r.db.Query(query, userID, pq.Array(roles))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*domain.Task
+	for rows.Next() {
+		task := &domain.Task{}
+		err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.ProjectID, &task.AssigneeID, &task.DueDate, &task.Priority, &task.Status, &task.CreatedBy, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.DeletedAt)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// FindDeleted はゴミ箱内のタスクを返します
+func (r *taskRepoPg) FindDeleted(id string) (*domain.Task, error) {
+	query := `
+        SELECT id, title, description, project_id, COALESCE(assignee_id, ''), due_date, priority, status, created_by, created_at, updated_at, version, deleted_at
+        FROM tasks
+        WHERE id = $1 AND deleted_at IS NOT NULL
+    `
+	task := &domain.Task{}
+	err := r.db.QueryRow(query, id).Scan(&task.ID, &task.Title, &task.Description, &task.ProjectID, &task.AssigneeID, &task.DueDate, &task.Priority, &task.Status, &task.CreatedBy, &task.CreatedAt, &task.UpdatedAt, &task.Version, &task.DeletedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("task not found in trash")
+		}
+		return nil, err
+	}
+	return task, nil
+}
+
+// PurgeDeletedBefore は cutoff より前にゴミ箱に入ったタスク（およびそのようなプロジェクトのタスク）を
+// 物理削除し、削除したタスクの ID を返します
+func (r *taskRepoPg) PurgeDeletedBefore(cutoff time.Time) ([]string, error) {
+	query := `
+        DELETE FROM tasks
+        WHERE deleted_at < $1
+           OR project_id IN (SELECT id FROM projects WHERE deleted_at < $1)
+        RETURNING id
+    `
+	rows, err := r.db.Query(query, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// PurgeDeleted はゴミ箱内のタスクを物理削除します。サブタスク・コメント・添付ファイルなどは CASCADE で削除されます
+func (r *taskRepoPg) PurgeDeleted(id string) error {
+	result, err := r.db.Exec(`DELETE FROM tasks WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("task not found in trash")
+	}
 	return nil
 }
 
+func (r *taskRepoPg) Discard(id string, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// サブタスク・コメント・添付ファイルは ON DELETE CASCADE で削除される
+	result, err := tx.Exec(`DELETE FROM tasks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return apperrors.ErrNotFound
+	}
+	if err := activitypostgres.Append(tx, activity); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 func (r *taskRepoPg) ListByProject(projectID string) ([]*domain.Task, error) {
 	query := `
-        SELECT id, title, description, project_id, assignee_id, due_date, priority, status, created_by, created_at, updated_at
+        SELECT ` + taskColumns + `
         FROM tasks
-        WHERE project_id = $1
+        WHERE project_id = $1 AND ` + activeTaskCond + `
         ORDER BY created_at DESC
     `
 	rows, err := r.db.Query(query, projectID)
@@ -106,8 +418,7 @@ func (r *taskRepoPg) ListByProject(projectID string) ([]*domain.Task, error) {
 
 	var tasks []*domain.Task
 	for rows.Next() {
-		task := &domain.Task{}
-		err := rows.Scan(&task.ID, &task.Title, &task.Description, &task.ProjectID, &task.AssigneeID, &task.DueDate, &task.Priority, &task.Status, &task.CreatedBy, &task.CreatedAt, &task.UpdatedAt)
+		task, err := scanTask(rows)
 		if err != nil {
 			return nil, err
 		}
@@ -126,23 +437,189 @@ func NewSubtaskRepoPg(db *sql.DB) repository.SubtaskRepository {
 // subtaskRepoPg は SubtaskRepository の PostgreSQL 実装
 type subtaskRepoPg struct{ db *sql.DB }
 
-func (r *subtaskRepoPg) Create(subtask *domain.Subtask) error {
-	// TODO: SQL INSERT
-	return nil
+func (r *subtaskRepoPg) Create(subtask *domain.Subtask, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// 新しいサブタスクは末尾に追加する
+	query := `
+        INSERT INTO subtasks (id, title, is_complete, task_id, position, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, (SELECT COALESCE(MAX(position) + 1, 0) FROM subtasks WHERE task_id = $4), NOW(), NOW())
+        RETURNING position
+    `
+	if err := tx.QueryRow(query, subtask.ID, subtask.Title, subtask.IsComplete, subtask.TaskID).Scan(&subtask.Position); err != nil {
+		return err
+	}
+	if err := activitypostgres.Append(tx, activity); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
+
 func (r *subtaskRepoPg) FindByID(id string) (*domain.Subtask, error) {
-	// TODO: SQL SELECT
-	return &domain.Subtask{}, nil
+	query := `
+        SELECT id, title, is_complete, task_id, position, created_at, updated_at
+        FROM subtasks
+        WHERE id = $1
+    `
+	subtask := &domain.Subtask{}
+	err := r.db.QueryRow(query, id).Scan(&subtask.ID, &subtask.Title, &subtask.IsComplete, &subtask.TaskID, &subtask.Position, &subtask.CreatedAt, &subtask.UpdatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("subtask not found")
+		}
+		return nil, err
+	}
+	return subtask, nil
 }
-func (r *subtaskRepoPg) Update(subtask *domain.Subtask) error {
-	// TODO: SQL UPDATE
-	return nil
+
+func (r *subtaskRepoPg) Update(subtask *domain.Subtask, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `
+        UPDATE subtasks
+        SET title = $1, is_complete = $2, updated_at = NOW()
+        WHERE id = $3
+    `
+	result, err := tx.Exec(query, subtask.Title, subtask.IsComplete, subtask.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("subtask not found")
+	}
+	if err := activitypostgres.Append(tx, activity); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
-func (r *subtaskRepoPg) Delete(id string) error {
-	// TODO: SQL DELETE
-	return nil
+
+func (r *subtaskRepoPg) Delete(id string, activity *activitydomain.Entry) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM subtasks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("subtask not found")
+	}
+	if err := activitypostgres.Append(tx, activity); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
+
 func (r *subtaskRepoPg) ListByTask(taskID string) ([]*domain.Subtask, error) {
-	// TODO: SQL SELECT
-	return []*domain.Subtask{}, nil
+	query := `
+        SELECT id, title, is_complete, task_id, position, created_at, updated_at
+        FROM subtasks
+        WHERE task_id = $1
+        ORDER BY position, created_at
+    `
+	rows, err := r.db.Query(query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subtasks := []*domain.Subtask{}
+	for rows.Next() {
+		subtask := &domain.Subtask{}
+		if err := rows.Scan(&subtask.ID, &subtask.Title, &subtask.IsComplete, &subtask.TaskID, &subtask.Position, &subtask.CreatedAt, &subtask.UpdatedAt); err != nil {
+			return nil, err
+		}
+		subtasks = append(subtasks, subtask)
+	}
+	return subtasks, rows.Err()
+}
+
+// Reorder は ids の順にサブタスクの position を 0 から振り直します
+func (r *subtaskRepoPg) Reorder(taskID string, ids []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for i, id := range ids {
+		result, err := tx.Exec(`UPDATE subtasks SET position = $1, updated_at = NOW() WHERE id = $2 AND task_id = $3`, i, id, taskID)
+		if err != nil {
+			return err
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return fmt.Errorf("subtask not found")
+		}
+	}
+	return tx.Commit()
+}
+
+// ReassignOpen は tx の中で、プロジェクト内で fromUserID が担当している未完了（Done / Canceled 以外）のタスクの担当者を
+// 同じ位置の toUserID（空の場合は未割り当て）に置き換え（toUserID がすでに担当者の場合は外すだけ）、変更したタスクの ID を返します
+// 主担当者の更新と合わせてバージョンを 1 つ進め、タスクごとの変更履歴 activity(id) を追記します
+// メンバーを外す処理と同じトランザクションで割り当て直すため、プロジェクトのリポジトリから呼び出します
+func ReassignOpen(ctx context.Context, tx *sql.Tx, projectID, fromUserID, toUserID string, activity func(taskID string) *activitydomain.Entry) ([]string, error) {
+	query := `
+        SELECT id FROM tasks
+        WHERE project_id = $1 AND status NOT IN ($3, $4) AND ` + activeTaskCond + `
+            AND EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = tasks.id AND ta.user_id = $2)
+    `
+	rows, err := tx.QueryContext(ctx, query, projectID, fromUserID, domain.StatusDone, domain.StatusCanceled)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	if toUserID != "" {
+		if _, err := tx.ExecContext(ctx, `
+        UPDATE task_assignees SET user_id = $3
+        WHERE task_id = ANY($1) AND user_id = $2
+            AND NOT EXISTS (SELECT 1 FROM task_assignees o WHERE o.task_id = task_assignees.task_id AND o.user_id = $3)
+    `, pq.Array(ids), fromUserID, toUserID); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_assignees WHERE task_id = ANY($1) AND user_id = $2`, pq.Array(ids), fromUserID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, `
+        UPDATE tasks
+        SET assignee_id = (SELECT ta.user_id FROM task_assignees ta WHERE ta.task_id = tasks.id ORDER BY ta.position LIMIT 1),
+            updated_at = NOW(), version = version + 1
+        WHERE id = ANY($1)
+    `, pq.Array(ids)); err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if err := activitypostgres.Append(tx, activity(id)); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
 }
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+
+	"github.com/lib/pq"
+)
+
+// timeEntryRepoPg は TimeEntryRepository の PostgreSQL 実装
+type timeEntryRepoPg struct{ db *sql.DB }
+
+// NewTimeEntryRepoPg は PostgreSQL 実装（作業時間の記録用）を返す
+func NewTimeEntryRepoPg(db *sql.DB) repository.TimeEntryRepository {
+	return &timeEntryRepoPg{db: db}
+}
+
+const timeEntryColumns = `id, task_id, COALESCE(user_id, ''), started_at, ended_at, minutes, note, source, created_at, updated_at`
+
+func scanTimeEntry(row rowScanner) (*domain.TimeEntry, error) {
+	e := &domain.TimeEntry{}
+	var endedAt sql.NullTime
+	err := row.Scan(&e.ID, &e.TaskID, &e.UserID, &e.StartedAt, &endedAt, &e.Minutes, &e.Note, &e.Source, &e.CreatedAt, &e.UpdatedAt)
+	if endedAt.Valid {
+		e.EndedAt = &endedAt.Time
+	}
+	return e, err
+}
+
+func (r *timeEntryRepoPg) Create(e *domain.TimeEntry) error {
+	query := `
+        INSERT INTO time_entries (id, task_id, user_id, started_at, ended_at, minutes, note, source, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+    `
+	_, err := r.db.Exec(query, e.ID, e.TaskID, e.UserID, e.StartedAt, e.EndedAt, e.Minutes, e.Note, e.Source, e.CreatedAt, e.UpdatedAt)
+	// 計測中のタイマーはユーザーごとに1つ（idx_time_entries_running）
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && e.EndedAt == nil {
+		if running, ferr := r.FindRunning(e.UserID); ferr == nil {
+			return &domain.TimerRunningError{TaskID: running.TaskID, EntryID: running.ID}
+		}
+	}
+	return err
+}
+
+func (r *timeEntryRepoPg) FindByID(id string) (*domain.TimeEntry, error) {
+	return r.find(`SELECT `+timeEntryColumns+` FROM time_entries WHERE id = $1`, id)
+}
+
+func (r *timeEntryRepoPg) FindRunning(userID string) (*domain.TimeEntry, error) {
+	return r.find(`SELECT `+timeEntryColumns+` FROM time_entries WHERE user_id = $1 AND ended_at IS NULL`, userID)
+}
+
+func (r *timeEntryRepoPg) find(query string, args ...interface{}) (*domain.TimeEntry, error) {
+	e, err := scanTimeEntry(r.db.QueryRow(query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("time entry not found")
+		}
+		return nil, err
+	}
+	return e, nil
+}
+
+func (r *timeEntryRepoPg) Update(e *domain.TimeEntry) error {
+	query := `
+        UPDATE time_entries
+        SET started_at = $2, ended_at = $3, minutes = $4, note = $5, updated_at = $6
+        WHERE id = $1
+    `
+	result, err := r.db.Exec(query, e.ID, e.StartedAt, e.EndedAt, e.Minutes, e.Note, e.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("time entry not found")
+	}
+	return nil
+}
+
+func (r *timeEntryRepoPg) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM time_entries WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return fmt.Errorf("time entry not found")
+	}
+	return nil
+}
+
+func (r *timeEntryRepoPg) ListByTask(taskID string) ([]*domain.TimeEntry, error) {
+	rows, err := r.db.Query(`SELECT `+timeEntryColumns+` FROM time_entries WHERE task_id = $1 ORDER BY started_at DESC, id`, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*domain.TimeEntry{}
+	for rows.Next() {
+		e, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// Summary は q.Tasks に一致するタスクの停止済みの記録を、ユーザー・プロジェクト・タスクごとに集計します
+func (r *timeEntryRepoPg) Summary(ctx context.Context, q repository.TimeQuery) (*repository.TimeSummary, error) {
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	// 記録側の条件（tasks と列名が重なるため、タスクの絞り込みはサブクエリで行う）
+	entryCond := []string{"te.ended_at IS NOT NULL"}
+	if q.UserID != "" {
+		entryCond = append(entryCond, "te.user_id = "+arg(q.UserID))
+	}
+	if q.From != nil {
+		entryCond = append(entryCond, "te.started_at >= "+arg(*q.From))
+	}
+	if q.To != nil {
+		entryCond = append(entryCond, "te.started_at < "+arg(*q.To))
+	}
+	taskIDs := "SELECT id FROM tasks " + taskFilter(q.Tasks, arg)
+	// ユーザーで絞り込まない場合は、記録がなくても見積もりのあるタスクを含める
+	includeEstimated := arg(q.UserID == "")
+
+	query := fmt.Sprintf(`
+        SELECT t.id, t.title, COALESCE(t.project_id, ''), t.estimate, COALESCE(te.user_id, ''), COALESCE(SUM(te.minutes), 0)
+        FROM tasks t
+        LEFT JOIN time_entries te ON te.task_id = t.id AND %s
+        WHERE t.id IN (%s)
+        GROUP BY t.id, t.title, t.project_id, t.estimate, te.user_id
+        HAVING COUNT(te.id) > 0 OR (%s::boolean AND t.estimate > 0)
+    `, strings.Join(entryCond, " AND "), taskIDs, includeEstimated)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary := &repository.TimeSummary{
+		ByUser:    map[string]int{},
+		ByProject: map[string]int{},
+		ByTask:    []repository.TaskTime{},
+	}
+	byTask := map[string]int{}
+	for rows.Next() {
+		var t repository.TaskTime
+		var userID string
+		if err := rows.Scan(&t.TaskID, &t.Title, &t.ProjectID, &t.Estimate, &userID, &t.Minutes); err != nil {
+			return nil, err
+		}
+		// 1 行はタスクとユーザーの組。タスクごとにまとめる
+		i, ok := byTask[t.TaskID]
+		if !ok {
+			i = len(summary.ByTask)
+			byTask[t.TaskID] = i
+			summary.ByTask = append(summary.ByTask, repository.TaskTime{TaskID: t.TaskID, Title: t.Title, ProjectID: t.ProjectID, Estimate: t.Estimate})
+			summary.Estimate += t.Estimate
+		}
+		summary.ByTask[i].Minutes += t.Minutes
+		summary.TotalMinutes += t.Minutes
+		summary.ByProject[t.ProjectID] += t.Minutes
+		if userID != "" {
+			summary.ByUser[userID] += t.Minutes
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// 作業時間の多い順（同じ場合はタイトル順）
+	sort.SliceStable(summary.ByTask, func(i, j int) bool {
+		a, b := summary.ByTask[i], summary.ByTask[j]
+		if a.Minutes != b.Minutes {
+			return a.Minutes > b.Minutes
+		}
+		return a.Title < b.Title
+	})
+	return summary, nil
+}
@@ -0,0 +1,48 @@
+package postgres
+
+import (
+	"database/sql"
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+)
+
+// transitionRepoPg は TaskTransitionRepository の PostgreSQL 実装
+type transitionRepoPg struct{ db *sql.DB }
+
+// NewTaskTransitionRepoPg は PostgreSQL 実装（ステータス遷移履歴用）を返す
+func NewTaskTransitionRepoPg(db *sql.DB) repository.TaskTransitionRepository {
+	return &transitionRepoPg{db: db}
+}
+
+func (r *transitionRepoPg) Create(t *domain.TaskTransition) error {
+	query := `
+        INSERT INTO task_transitions (id, task_id, from_status, to_status, actor_id, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+	_, err := r.db.Exec(query, t.ID, t.TaskID, t.FromStatus, t.ToStatus, t.ActorID, t.CreatedAt)
+	return err
+}
+
+func (r *transitionRepoPg) ListByTask(taskID string) ([]*domain.TaskTransition, error) {
+	query := `
+        SELECT id, task_id, from_status, to_status, COALESCE(actor_id, ''), created_at
+        FROM task_transitions
+        WHERE task_id = $1
+        ORDER BY created_at ASC
+    `
+	rows, err := r.db.Query(query, taskID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transitions []*domain.TaskTransition
+	for rows.Next() {
+		t := &domain.TaskTransition{}
+		if err := rows.Scan(&t.ID, &t.TaskID, &t.FromStatus, &t.ToStatus, &t.ActorID, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		transitions = append(transitions, t)
+	}
+	return transitions, nil
+}
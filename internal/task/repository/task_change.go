@@ -0,0 +1,28 @@
+package repository
+
+import (
+    activitydomain "todo-app/internal/activity/domain"
+    "todo-app/internal/task/domain"
+)
+
+// TaskChange は一括操作で1件のタスクに適用する変更です
+type TaskChange struct {
+    // Task は変更後のタスクです。Task.Version は読み込んだ時点のバージョンで、一致する場合のみ適用します
+    Task *domain.Task
+    // Delete が true の場合はタスクをゴミ箱に移動します（Task の他の変更は適用しません）
+    Delete bool
+    // ReplaceLabels が true の場合はタスクのラベルを Task.Labels に置き換えます
+    ReplaceLabels bool
+    // ReplaceAssignees が true の場合はタスクの担当者を Task.AssigneeIDs に置き換えます
+    ReplaceAssignees bool
+    // ReplaceCustomFields が true の場合はタスクのカスタムフィールドの値を Task.CustomFields に置き換えます
+    ReplaceCustomFields bool
+    // Transition はステータスが変わる場合の遷移履歴です
+    Transition *domain.TaskTransition
+    // Series は「以降すべて」の編集でタスクと合わせて保存する系列です（nil の場合は保存しません）
+    // SeriesOccurrences が 0 の場合は新しい系列として作成し、それ以外は保存済みの発生回数が一致する場合のみ更新します
+    Series            *domain.TaskSeries
+    SeriesOccurrences int
+    // Activity は変更と合わせて追記する変更履歴です（nil の場合は記録しません）
+    Activity *activitydomain.Entry
+}
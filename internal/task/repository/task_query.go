@@ -0,0 +1,164 @@
+package repository
+
+import (
+    "encoding/json"
+    "strconv"
+    "strings"
+    "time"
+
+    "todo-app/internal/task/domain"
+)
+
+// TaskQuery はタスク一覧の検索条件・並び順・ページングを表します
+type TaskQuery struct {
+    IDs        []string
+    Statuses   []string
+    Priorities []string
+    // AssigneeID が指定された場合、そのユーザーが担当者（複数担当の場合はいずれか）のタスクに絞り込みます
+    AssigneeID string
+    ProjectID  string
+    CreatedBy  string
+    // SprintID・MilestoneID が指定された場合、そのスプリント・マイルストーンに予定されたタスクに絞り込みます
+    // ScheduleNone を指定した場合は予定先のないタスクに絞り込みます
+    SprintID    string
+    MilestoneID string
+    DueFrom    *time.Time
+    DueTo      *time.Time
+    // Overdue が true の場合、期限切れかつ未完了（Done / Canceled 以外）のタスクに絞り込みます
+    Overdue bool
+    // VisibleTo が指定された場合、そのユーザーが作成・担当しているタスクと、
+    // 作成者またはメンバーとして参加しているプロジェクトのタスクに絞り込みます
+    VisibleTo string
+    // Labels が指定された場合、いずれかのラベル（ID または名前）が付いているタスクに絞り込みます
+    Labels []string
+    // CustomFields はカスタムフィールドのキーごとの絞り込み条件です
+    // タスクのプロジェクトでキーが一致するフィールドの値がいずれかの値と一致する（複数選択の場合は含む）タスクに絞り込みます
+    CustomFields map[string][]string
+
+    // SortBy は TaskSortFields のいずれか、または CustomFieldSortPrefix にキーを付けた列です
+    SortBy   string
+    SortDesc bool
+
+    // Limit は取得件数の上限です。0 の場合は制限しません
+    Limit int
+    // Offset はオフセットページング用です。Cursor が指定されている場合は無視されます
+    Offset int
+    // Cursor はカーソルページング用のキーセット位置です
+    Cursor *TaskCursor
+}
+
+// ScheduleNone は TaskQuery.SprintID・MilestoneID で予定先のないタスクを表します
+const ScheduleNone = "none"
+
+// TaskCursor はキーセットページングの位置（並び替え列の値と ID）を表します
+type TaskCursor struct {
+    Value string
+    ID    string
+    // Backward が true の場合、カーソルより前のページを取得します
+    Backward bool
+}
+
+// TaskPage は一覧の1ページ分の結果です
+type TaskPage struct {
+    Tasks []*domain.Task
+    // Total はページングを適用する前の該当件数です
+    Total int
+    // HasMore はカーソルの進行方向にまだ続きがあるかを表します
+    HasMore bool
+}
+
+// TaskSummary はタスクの件数集計です
+type TaskSummary struct {
+    Total int
+    // Incomplete は Done / Canceled 以外のタスク数です
+    Incomplete int
+    // Overdue は期限切れかつ未完了のタスク数です
+    Overdue    int
+    ByStatus   map[string]int
+    ByPriority map[string]int
+    // ByAssignee のキーは担当者 ID です。未割り当ては空文字列になります
+    ByAssignee map[string]int
+}
+
+// DefaultTaskSort は並び順が指定されなかった場合の並び替え列です
+const DefaultTaskSort = "created_at"
+
+// TaskSortFields は並び替えに使用できる列の一覧です
+var TaskSortFields = []string{
+    "title", "due_date", "priority", "status", "project_id",
+    "assignee_id", "created_by", "created_at", "updated_at",
+}
+
+// CustomFieldSortPrefix はカスタムフィールドで並び替える場合の SortBy の接頭辞です（例: cf.story_points）
+// 絞り込みのクエリパラメータ名にも使います
+const CustomFieldSortPrefix = "cf."
+
+// CustomFieldSortKey は field がカスタムフィールドの並び替え列の場合にそのキーを返します
+func CustomFieldSortKey(field string) (string, bool) {
+    if !strings.HasPrefix(field, CustomFieldSortPrefix) {
+        return "", false
+    }
+    key := strings.TrimPrefix(field, CustomFieldSortPrefix)
+    return key, domain.ValidCustomFieldKey(key)
+}
+
+// IsTaskSortField は field が並び替え可能な列かを返します
+func IsTaskSortField(field string) bool {
+    if _, ok := CustomFieldSortKey(field); ok {
+        return true
+    }
+    for _, f := range TaskSortFields {
+        if f == field {
+            return true
+        }
+    }
+    return false
+}
+
+// cursorTimeFormat はカーソルに埋め込む日時の書式です（DB の TIMESTAMP と同じくタイムゾーンなし）
+const cursorTimeFormat = "2006-01-02T15:04:05.999999"
+
+// TaskSortValue はカーソル生成用に、並び替え列に対応するタスクの値を文字列で返します
+// カスタムフィールドの値は JSON で表します（値がない場合は null）
+func TaskSortValue(task *domain.Task, field string) string {
+    if key, ok := CustomFieldSortKey(field); ok {
+        b, err := json.Marshal(task.CustomFields[key])
+        if err != nil {
+            return "null"
+        }
+        return string(b)
+    }
+    switch field {
+    case "title":
+        return task.Title
+    case "due_date":
+        return task.DueDate.UTC().Format(cursorTimeFormat)
+    case "priority":
+        return strconv.Itoa(PriorityRank(task.Priority))
+    case "status":
+        return task.Status
+    case "project_id":
+        return task.ProjectID
+    case "assignee_id":
+        return task.AssigneeID
+    case "created_by":
+        return task.CreatedBy
+    case "updated_at":
+        return task.UpdatedAt.UTC().Format(cursorTimeFormat)
+    default:
+        return task.CreatedAt.UTC().Format(cursorTimeFormat)
+    }
+}
+
+// PriorityRank は優先度を並び替え用の数値に変換します（High が最も小さい）
+func PriorityRank(priority string) int {
+    switch priority {
+    case domain.PriorityHigh:
+        return 1
+    case domain.PriorityMedium:
+        return 2
+    case domain.PriorityLow:
+        return 3
+    }
+    return 4
+}
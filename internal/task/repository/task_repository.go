@@ -1,21 +1,161 @@
 package repository
 
-import "todo-app/internal/task/domain"
+import (
+    "context"
+    "time"
 
+    activitydomain "todo-app/internal/activity/domain"
+    "todo-app/internal/task/domain"
+)
+
+// 書き込みのメソッドは activity（nil の場合は記録しない）を変更と同じトランザクションで変更履歴に追記します
 type TaskRepository interface {
-    Create(task *domain.Task) error
+    Create(task *domain.Task, activity *activitydomain.Entry) error
     GetAll() ([]*domain.Task, error)
     GetByID(id string) (*domain.Task, error)
     FindByID(id string) (*domain.Task, error)
-    Update(task *domain.Task) error
-    Delete(id string) error
+    Update(task *domain.Task, activity *activitydomain.Entry) error
+    Delete(id string, version int, activity *activitydomain.Entry) error
     ListByProject(projectID string) ([]*domain.Task, error) 
+    List(ctx context.Context, q TaskQuery) (*TaskPage, error)
+    Summary(ctx context.Context, q TaskQuery) (*TaskSummary, error)
+    Restore(id string, activity *activitydomain.Entry) error
+    // ListDeleted はゴミ箱内のタスクのうち、userID がプロジェクトで roles のいずれかのロールを持つもの
+    // （プロジェクトのないタスクは userID が作成・担当しているもの）を返します
+    ListDeleted(userID string, roles []string) ([]*domain.Task, error)
+    // FindDeleted はゴミ箱内のタスクを返します
+    FindDeleted(id string) (*domain.Task, error)
+    PurgeDeletedBefore(cutoff time.Time) ([]string, error)
+    // PurgeDeleted はゴミ箱内のタスクを物理削除します
+    PurgeDeleted(id string) error
+    // Discard は途中まで作成したタスクをゴミ箱を経由せずに物理削除し、activity を変更履歴に追記します
+    // サブタスク・コメント・添付ファイルの参照も合わせて削除されます
+    Discard(id string, activity *activitydomain.Entry) error
+    // ApplyChanges は changes を1つのトランザクションで適用し、変更ごとの結果（成功は nil）を返します
+    // atomic が true の場合は1件でも失敗すればすべてを取り消し、false の場合は失敗した変更のみを取り消します
+    // 適用に成功したタスクの Version は1つ進みます
+    ApplyChanges(ctx context.Context, changes []*TaskChange, atomic bool) ([]error, error)
 }
 
+// 書き込みのメソッドは activity（nil の場合は記録しない）を変更と同じトランザクションで変更履歴に追記します
 type SubtaskRepository interface {
-    Create(subtask *domain.Subtask) error
+    Create(subtask *domain.Subtask, activity *activitydomain.Entry) error
     FindByID(id string) (*domain.Subtask, error)
-    Update(subtask *domain.Subtask) error
-    Delete(id string) error
+    Update(subtask *domain.Subtask, activity *activitydomain.Entry) error
+    Delete(id string, activity *activitydomain.Entry) error
     ListByTask(taskID string) ([]*domain.Subtask, error)
+    Reorder(taskID string, ids []string) error
+}
+
+// ProjectSettingsRepository はタスクの振る舞いに関わるプロジェクト設定を参照します
+type ProjectSettingsRepository interface {
+    // AutoCompleteTasks はサブタスクがすべて完了したときに親タスクを Done にするかを返します
+    AutoCompleteTasks(projectID string) (bool, error)
+    // FindProjectIDByName は userID が作成者またはメンバーであるプロジェクトのうち、
+    // 名前が一致する（大文字・小文字を区別しない）プロジェクトの ID を返します
+    FindProjectIDByName(userID, name string) (string, error)
+    // CanAccessProject は userID がプロジェクトの作成者またはメンバーかを返します（ゴミ箱内のプロジェクトは false）
+    CanAccessProject(userID, projectID string) (bool, error)
+    // IsArchived はプロジェクトがアーカイブ済みかを返します（プロジェクトが存在しない場合は false）
+    IsArchived(projectID string) (bool, error)
+    // SprintProject はスプリントのプロジェクト ID と状態を返します（スプリントが存在しない場合は空文字列）
+    SprintProject(sprintID string) (projectID, status string, err error)
+    // MilestoneProject はマイルストーンのプロジェクト ID を返します（マイルストーンが存在しない場合は空文字列）
+    MilestoneProject(milestoneID string) (string, error)
+    // ProjectDates はプロジェクトの開始日と終了日を返します（設定されていない場合はゼロ値）
+    ProjectDates(projectID string) (start, end time.Time, err error)
+}
+
+// BoardRepository はかんばんボードの列の設定と、列内のタスクの順位を管理します
+type BoardRepository interface {
+    // ListColumns はプロジェクトで設定済みの列を位置の順に返します（設定のないステータスは含みません）
+    ListColumns(projectID string) ([]*domain.BoardColumn, error)
+    // ReplaceColumns はプロジェクトの列の設定を置き換えます
+    ReplaceColumns(projectID string, columns []*domain.BoardColumn) error
+    // ColumnTasks はプロジェクトで status のタスク（ゴミ箱内を除く）を列内の順（順位のないタスクは最後に作成順）に返します
+    ColumnTasks(projectID, status string) ([]domain.RankedTask, error)
+}
+
+// TaskSeriesRepository は繰り返しタスクの系列を管理します
+type TaskSeriesRepository interface {
+    Create(series *domain.TaskSeries) error
+    FindByID(id string) (*domain.TaskSeries, error)
+    // Update は保存済みの発生回数が expectedOccurrences と一致する場合のみ更新します
+    // 一致しない場合（他の処理が先に次の発生を生成した場合）は ErrVersionMismatch を返します
+    Update(series *domain.TaskSeries, expectedOccurrences int) error
+    // Advance は Update と同じ条件で系列を更新し、next を系列の新しい発生として同じトランザクションで登録します
+    // activity（nil の場合は記録しない）も合わせて変更履歴に追記します
+    Advance(series *domain.TaskSeries, expectedOccurrences int, next *domain.Task, activity *activitydomain.Entry) error
+    // LatestOccurrence は系列の発生のうち、ゴミ箱内を除いて最も新しいものを返します（ない場合は nil）
+    LatestOccurrence(seriesID string) (*domain.Task, error)
+    // ListDue は最後の発生の期限が now 以前で、まだ終了していない系列を返します
+    ListDue(now time.Time) ([]*domain.TaskSeries, error)
+}
+
+// TaskDependencyRepository はタスク間の依存関係（blocked by）を管理します
+type TaskDependencyRepository interface {
+    Create(dep *domain.TaskDependency) error
+    Delete(taskID, dependsOnID string) error
+    // ListByTask はタスクが依存している関係と、タスクに依存している関係の両方を返します
+    ListByTask(taskID string) ([]*domain.TaskDependency, error)
+    // ListByProject は少なくとも一方のタスクがプロジェクトに属する関係を返します
+    ListByProject(projectID string) ([]*domain.TaskDependency, error)
+    // DependsOn は taskID が otherID に（推移的に）依存しているかを返します
+    DependsOn(taskID, otherID string) (bool, error)
+    // OpenBlockers は taskID が依存しているタスクのうち未完了のものの ID を返します
+    OpenBlockers(taskID string) ([]string, error)
+}
+
+// LabelRepository はプロジェクトのラベル定義と、タスクへのラベルの付与を管理します
+type LabelRepository interface {
+    Create(label *domain.Label) error
+    FindByID(id string) (*domain.Label, error)
+    Update(label *domain.Label) error
+    // Delete はラベルを削除します。タスクへの付与も合わせて外れます
+    Delete(id string) error
+    ListByProject(projectID string) ([]*domain.Label, error)
+    // FindByName はプロジェクト内で名前が一致する（大文字・小文字を区別しない）ラベルを返します
+    FindByName(projectID, name string) (*domain.Label, error)
+    // SetTaskLabels はタスクのラベルを labelIDs に置き換えます
+    // タスクのラベルを変更するメソッドは activity を同じトランザクションで変更履歴に追記します
+    SetTaskLabels(taskID string, labelIDs []string, activity *activitydomain.Entry) error
+    AddTaskLabel(taskID, labelID string, activity *activitydomain.Entry) error
+    RemoveTaskLabel(taskID, labelID string, activity *activitydomain.Entry) error
+    // TaskIDsByLabel はラベルが付いているタスクの ID を返します
+    TaskIDsByLabel(labelID string) ([]string, error)
+}
+
+// CustomFieldRepository はプロジェクトのカスタムフィールドの定義を管理します
+// タスクの値は TaskRepository がタスクと合わせて保存・取得します
+type CustomFieldRepository interface {
+    Create(field *domain.CustomField) error
+    FindByID(id string) (*domain.CustomField, error)
+    // Update は名前と選択肢を更新し、選択肢から外れた値をタスクから取り除きます
+    Update(field *domain.CustomField) error
+    // Delete はカスタムフィールドを削除します。タスクの値も合わせて削除されます
+    Delete(id string) error
+    // ListByProject はプロジェクトのカスタムフィールドを作成順に返します
+    ListByProject(projectID string) ([]*domain.CustomField, error)
+    // TaskIDsByField はカスタムフィールドに値があるタスクの ID を返します
+    TaskIDsByField(fieldID string) ([]string, error)
+}
+
+// TimeEntryRepository はタスクの作業時間の記録（タイマー・手入力）を管理します
+type TimeEntryRepository interface {
+    // Create は記録を登録します。ユーザーのタイマーが既に計測中の場合は *domain.TimerRunningError を返します
+    Create(entry *domain.TimeEntry) error
+    FindByID(id string) (*domain.TimeEntry, error)
+    Update(entry *domain.TimeEntry) error
+    Delete(id string) error
+    // FindRunning はユーザーの計測中のタイマーを返します
+    FindRunning(userID string) (*domain.TimeEntry, error)
+    // ListByTask はタスクの記録を開始日時の新しい順に返します
+    ListByTask(taskID string) ([]*domain.TimeEntry, error)
+    // Summary は TimeQuery の条件に一致する停止済みの記録を集計します
+    Summary(ctx context.Context, q TimeQuery) (*TimeSummary, error)
+}
+
+type TaskTransitionRepository interface {
+    Create(transition *domain.TaskTransition) error
+    ListByTask(taskID string) ([]*domain.TaskTransition, error)
 }
@@ -0,0 +1,37 @@
+package repository
+
+import (
+    "time"
+)
+
+// TimeQuery は作業時間の集計条件です
+type TimeQuery struct {
+    // Tasks は対象タスクの絞り込み条件です（ページング・並び順は使用しません）
+    Tasks TaskQuery
+    // UserID が指定された場合、そのユーザーの記録に絞り込みます
+    UserID string
+    // From・To は記録の開始日時の範囲です（From 以上 To 未満）
+    From *time.Time
+    To   *time.Time
+}
+
+// TaskTime はタスクごとの見積もりと作業時間（分）です
+type TaskTime struct {
+    TaskID    string
+    Title     string
+    ProjectID string
+    Estimate  int
+    Minutes   int
+}
+
+// TimeSummary は作業時間（分）の集計です
+type TimeSummary struct {
+    TotalMinutes int
+    // Estimate は ByTask のタスクの見積もり時間の合計です
+    Estimate int
+    // ByUser のキーはユーザー ID、ByProject のキーはプロジェクト ID です（プロジェクトなしは空文字列）
+    ByUser    map[string]int
+    ByProject map[string]int
+    // ByTask は記録のあるタスク（ユーザーで絞り込まない場合は見積もりのあるタスクも）を作業時間の多い順に並べたものです
+    ByTask []TaskTime
+}
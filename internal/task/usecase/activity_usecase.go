@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+
+	activitydomain "todo-app/internal/activity/domain"
+	"todo-app/internal/task/domain"
+)
+
+// ActivityRecorder は変更履歴に ID と日時を設定します。更新で変更がなかった場合は nil を返します
+// 変更履歴はリポジトリが変更と同じトランザクションで追記します
+type ActivityRecorder interface {
+	Prepare(entry *activitydomain.Entry) *activitydomain.Entry
+}
+
+// taskActivityFields は変更履歴に記録するタスクのフィールドです（記録する順）
+// カスタムフィールドはこの後に custom_fields.<key> としてキーの順に記録します
+var taskActivityFields = []string{
+	"title", "description", "status", "priority", "assignee_ids", "due_date", "start_date", "duration_days",
+	"estimate", "project_id", "sprint_id", "milestone_id", "label_ids", "recurrence", "rank",
+}
+
+// taskValues は変更履歴に記録するタスクのフィールドの値を返します。未設定の値は nil です
+// タスクを変更する前に呼び出し、変更後の値と比べます
+func taskValues(task *domain.Task) map[string]interface{} {
+	if task == nil {
+		return map[string]interface{}{}
+	}
+	values := map[string]interface{}{
+		"title":         stringValue(task.Title),
+		"description":   stringValue(task.Description),
+		"status":        stringValue(task.Status),
+		"priority":      stringValue(task.Priority),
+		"assignee_ids":  idsValue(task.AssigneeIDs),
+		"due_date":      activitydomain.Time(task.DueDate),
+		"start_date":    activitydomain.Time(task.StartDate),
+		"duration_days": intValue(task.DurationDays),
+		"estimate":      intValue(task.Estimate),
+		"project_id":    stringValue(task.ProjectID),
+		"sprint_id":     stringValue(task.SprintID),
+		"milestone_id":  stringValue(task.MilestoneID),
+		"label_ids":     labelIDsValue(task.Labels),
+		"recurrence":    stringValue(task.Recurrence),
+		"rank":          stringValue(task.Rank),
+	}
+	for key, value := range task.CustomFields {
+		values[customFieldActivityPrefix+key] = value
+	}
+	return values
+}
+
+// customFieldActivityPrefix はカスタムフィールドの変更を記録するフィールド名の接頭辞です（例: custom_fields.story_points）
+const customFieldActivityPrefix = "custom_fields."
+
+// customFieldActivityKeys は before と after に含まれるカスタムフィールドのフィールド名を名前順に返します
+func customFieldActivityKeys(before, after map[string]interface{}) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, values := range []map[string]interface{}{before, after} {
+		for key := range values {
+			if strings.HasPrefix(key, customFieldActivityPrefix) && !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringValue(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func intValue(n int) interface{} {
+	if n == 0 {
+		return nil
+	}
+	return n
+}
+
+func idsValue(ids []string) interface{} {
+	if len(ids) == 0 {
+		return nil
+	}
+	return append([]string(nil), ids...)
+}
+
+func labelIDsValue(labels []domain.TaskLabel) interface{} {
+	ids := make([]string, 0, len(labels))
+	for _, l := range labels {
+		ids = append(ids, l.ID)
+	}
+	return idsValue(ids)
+}
+
+// prepare は変更履歴を記録できる状態にして返します。記録しない場合は nil です
+func (uc *TaskUseCase) prepare(entry *activitydomain.Entry) *activitydomain.Entry {
+	if uc.activity == nil {
+		return nil
+	}
+	return uc.activity.Prepare(entry)
+}
+
+// taskEntry はタスクの変更履歴を返します。before は変更前の taskValues（作成時は nil）です
+// 削除・復元は変更を記録しません
+func (uc *TaskUseCase) taskEntry(before map[string]interface{}, task *domain.Task, action, actorID string) *activitydomain.Entry {
+	entry := activitydomain.NewEntry(activitydomain.EntityTask, task.ID, action, actorID)
+	entry.TaskID, entry.ProjectID = task.ID, task.ProjectID
+	if action == activitydomain.ActionCreated || action == activitydomain.ActionUpdated {
+		after := taskValues(task)
+		for _, field := range taskActivityFields {
+			entry.Add(field, before[field], after[field])
+		}
+		for _, field := range customFieldActivityKeys(before, after) {
+			entry.Add(field, before[field], after[field])
+		}
+	}
+	return uc.prepare(entry)
+}
+
+// subtaskEntry はサブタスクの変更履歴を返します。before は変更前のサブタスク（作成時は nil）です
+func (uc *TaskUseCase) subtaskEntry(before, subtask *domain.Subtask, action, actorID string) *activitydomain.Entry {
+	entry := activitydomain.NewEntry(activitydomain.EntitySubtask, subtask.ID, action, actorID)
+	entry.TaskID = subtask.TaskID
+	switch action {
+	case activitydomain.ActionCreated:
+		entry.Add("title", nil, subtask.Title)
+	case activitydomain.ActionUpdated:
+		entry.Add("title", before.Title, subtask.Title)
+		entry.Add("is_complete", before.IsComplete, subtask.IsComplete)
+	case activitydomain.ActionDeleted:
+		entry.Add("title", subtask.Title, nil)
+	}
+	return uc.prepare(entry)
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+)
+
+// assigneesFor は作成・更新後の担当者を返します
+// assignee_ids が指定（更新時は変更）されていればそれを、そうでなければ assignee_id の 1 人を担当者にします
+func assigneesFor(dto *TaskDTO, current *domain.Task) []string {
+	if current == nil {
+		if len(dto.AssigneeIDs) > 0 {
+			return dto.AssigneeIDs
+		}
+		return []string{dto.AssigneeID}
+	}
+	if dto.AssigneeIDs != nil && !sameIDs(dto.AssigneeIDs, current.AssigneeIDs) {
+		return dto.AssigneeIDs
+	}
+	if dto.AssigneeID != current.AssigneeID {
+		return []string{dto.AssigneeID}
+	}
+	return current.AssigneeIDs
+}
+
+// assigneeField は担当者の検証エラーを返すフィールド名です
+func assigneeField(dto *TaskDTO) string {
+	if len(dto.AssigneeIDs) > 0 {
+		return "assignee_ids"
+	}
+	return "assignee_id"
+}
+
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// validateAssignees は新たに担当者になるユーザーを検証します
+// プロジェクトのタスクはプロジェクトのメンバー、プロジェクトに属さないタスクは存在するユーザーである必要があります
+// existing に含まれる（すでに担当している）ユーザーは検証しません
+func (uc *TaskUseCase) validateAssignees(projectID string, ids, existing []string, field string) error {
+	assigned := map[string]bool{}
+	for _, id := range existing {
+		assigned[id] = true
+	}
+	var added []string
+	for _, id := range ids {
+		if id != "" && !assigned[id] {
+			added = append(added, id)
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	verr := apperrors.NewValidationError()
+	if projectID == "" {
+		for _, id := range added {
+			if _, err := uc.userRepo.FindByID(id); err != nil {
+				verr.Add(field, "user not found: "+id)
+			}
+		}
+	} else {
+		members, err := uc.members.GetMembers(projectID)
+		if err != nil {
+			return err
+		}
+		isMember := map[string]bool{}
+		for _, m := range members {
+			isMember[m.ID] = true
+		}
+		for _, id := range added {
+			if !isMember[id] {
+				verr.Add(field, "user is not a member of the project: "+id)
+			}
+		}
+	}
+	if verr.HasErrors() {
+		return verr
+	}
+	return nil
+}
+
+// MyTasks は userID が担当している未完了のタスクを、期限によって期限切れ・今日・今週・それ以降に区分して返します
+// 区分はユーザーのタイムゾーンの日付で判定し、週は月曜日から始まります。期限のないタスクはそれ以降に含めます
+func (uc *TaskUseCase) MyTasks(ctx context.Context, userID string) (*MyTasksDTO, error) {
+	page, err := uc.taskRepo.List(ctx, repository.TaskQuery{AssigneeID: userID, SortBy: "due_date"})
+	if err != nil {
+		return nil, err
+	}
+
+	loc := uc.userLocation(userID)
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	tomorrow := today.AddDate(0, 0, 1)
+	nextWeek := today.AddDate(0, 0, 7-(int(today.Weekday())+6)%7)
+
+	result := &MyTasksDTO{
+		Timezone: loc.String(),
+		Overdue:  []*TaskDTO{},
+		Today:    []*TaskDTO{},
+		ThisWeek: []*TaskDTO{},
+		Later:    []*TaskDTO{},
+	}
+	for _, task := range page.Tasks {
+		if task.Status == domain.StatusDone || task.Status == domain.StatusCanceled {
+			continue
+		}
+		dto := toTaskDTO(task)
+		switch due := task.DueDate; {
+		case due.IsZero() || !due.Before(nextWeek):
+			result.Later = append(result.Later, dto)
+		case due.Before(today):
+			result.Overdue = append(result.Overdue, dto)
+		case due.Before(tomorrow):
+			result.Today = append(result.Today, dto)
+		default:
+			result.ThisWeek = append(result.ThisWeek, dto)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,301 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+
+	apperrors "todo-app/internal/common/errors"
+	projectdomain "todo-app/internal/project/domain"
+	"todo-app/internal/task/domain"
+)
+
+// boardColumns はプロジェクトのかんばんボードの列を返します
+// 設定された列を先に並べ、設定のないステータスは既定の設定で後ろに並べます
+func (uc *TaskUseCase) boardColumns(projectID string) ([]*domain.BoardColumn, error) {
+	wf := uc.workflowFor(projectID)
+	configured, err := uc.boardRepo.ListColumns(projectID)
+	if err != nil {
+		return nil, err
+	}
+	var columns []*domain.BoardColumn
+	seen := map[string]bool{}
+	for _, c := range configured {
+		if wf.HasState(c.Status) && !seen[c.Status] {
+			seen[c.Status] = true
+			columns = append(columns, c)
+		}
+	}
+	for _, c := range domain.DefaultColumns(projectID, wf) {
+		if !seen[c.Status] {
+			columns = append(columns, c)
+		}
+	}
+	for i, c := range columns {
+		c.Position = i
+	}
+	return columns, nil
+}
+
+// columnFor は status の列を返します
+func columnFor(columns []*domain.BoardColumn, status string) *domain.BoardColumn {
+	for _, c := range columns {
+		if c.Status == status {
+			return c
+		}
+	}
+	return nil
+}
+
+// rankedColumn は列に並ぶタスクを順位の順に返します（excludeID のタスクは除きます）
+// 既存のタスクの順位は scripts/migrate_backfill_task_ranks.sql で補完するため、参照時には保存しません
+// それでも順位のないタスク（作成順で最後に並ぶもの）には、並びを変えないように最後の順位の後ろの順位をこの結果の中だけで付けます
+func (uc *TaskUseCase) rankedColumn(projectID, status, excludeID string) ([]domain.RankedTask, error) {
+	tasks, err := uc.boardRepo.ColumnTasks(projectID, status)
+	if err != nil {
+		return nil, err
+	}
+	ranked := make([]domain.RankedTask, 0, len(tasks))
+	last := ""
+	for _, t := range tasks {
+		if t.Rank == "" {
+			t.Rank = domain.RankBetween(last, "")
+		}
+		last = t.Rank
+		if t.ID != excludeID {
+			ranked = append(ranked, t)
+		}
+	}
+	return ranked, nil
+}
+
+// bottomRank は status の列の最後に並ぶ順位を返します。プロジェクトに属さないタスクは順位を持ちません
+func (uc *TaskUseCase) bottomRank(projectID, status string) (string, error) {
+	if projectID == "" {
+		return "", nil
+	}
+	tasks, err := uc.rankedColumn(projectID, status, "")
+	if err != nil {
+		return "", err
+	}
+	last := ""
+	if len(tasks) > 0 {
+		last = tasks[len(tasks)-1].Rank
+	}
+	return domain.RankBetween(last, ""), nil
+}
+
+// GetBoard はプロジェクトのタスクをステータスごとの列に分け、列の中を順位の順に並べて返します
+func (uc *TaskUseCase) GetBoard(projectID, actorID string) (*BoardDTO, error) {
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
+	columns, err := uc.boardColumns(projectID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := uc.taskRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if (a.Rank == "") != (b.Rank == "") {
+			return b.Rank == ""
+		}
+		if a.Rank != b.Rank {
+			return a.Rank < b.Rank
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+
+	board := &BoardDTO{ProjectID: projectID, Columns: make([]*BoardColumnDTO, 0, len(columns))}
+	byStatus := map[string]*BoardColumnDTO{}
+	for _, c := range columns {
+		dto := &BoardColumnDTO{
+			Status:    c.Status,
+			Name:      c.Name,
+			Position:  c.Position,
+			WIPLimit:  c.WIPLimit,
+			WIPPolicy: c.WIPPolicy,
+			Tasks:     []*TaskDTO{},
+		}
+		board.Columns = append(board.Columns, dto)
+		byStatus[c.Status] = dto
+	}
+	for _, task := range tasks {
+		if column, ok := byStatus[task.Status]; ok {
+			column.Tasks = append(column.Tasks, toTaskDTO(task))
+		}
+	}
+	for _, column := range board.Columns {
+		column.Count = len(column.Tasks)
+		column.OverLimit = column.WIPLimit > 0 && column.Count > column.WIPLimit
+	}
+	return board, nil
+}
+
+// UpdateBoardColumns はかんばんボードの列の名前・並び順・WIP 制限を設定し、更新後のボードを返します
+func (uc *TaskUseCase) UpdateBoardColumns(projectID string, req *BoardColumnsRequest, actorID string) (*BoardDTO, error) {
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermManageProject); err != nil {
+		return nil, err
+	}
+	if err := uc.checkWritable(projectID); err != nil {
+		return nil, err
+	}
+
+	wf := uc.workflowFor(projectID)
+	verr := apperrors.NewValidationError()
+	seen := map[string]bool{}
+	columns := make([]*domain.BoardColumn, 0, len(req.Columns))
+	for i, c := range req.Columns {
+		field := fmt.Sprintf("columns[%d].", i)
+		if c == nil {
+			verr.Add(field+"status", "status is required")
+			continue
+		}
+		switch {
+		case !wf.HasState(c.Status):
+			verr.Add(field+"status", domain.ErrInvalidStatus.Error())
+		case seen[c.Status]:
+			verr.Add(field+"status", fmt.Sprintf("duplicate column %q", c.Status))
+		}
+		seen[c.Status] = true
+
+		name := c.Name
+		if name == "" {
+			name = c.Status
+		}
+		if len([]rune(name)) > domain.MaxColumnNameLength {
+			verr.Add(field+"name", domain.ErrColumnNameLength.Error())
+		}
+		policy := c.WIPPolicy
+		if policy == "" {
+			policy = domain.WIPWarn
+		}
+		switch err := domain.ValidateWIP(c.WIPLimit, policy); err {
+		case nil:
+		case domain.ErrInvalidWIPLimit:
+			verr.Add(field+"wip_limit", err.Error())
+		default:
+			verr.Add(field+"wip_policy", err.Error())
+		}
+		columns = append(columns, &domain.BoardColumn{ProjectID: projectID, Status: c.Status, Name: name, Position: i, WIPLimit: c.WIPLimit, WIPPolicy: policy})
+	}
+	if verr.HasErrors() {
+		return nil, verr
+	}
+
+	if err := uc.boardRepo.ReplaceColumns(projectID, columns); err != nil {
+		return nil, err
+	}
+	return uc.GetBoard(projectID, actorID)
+}
+
+// MoveTask はタスクをかんばんボードの列の after_id と before_id のタスクの間に移します
+// 列が変わる場合はステータスの遷移として扱い、ワークフローとブロッカーを検証します
+// 移動先の列が WIP 制限を超える場合、reject の列では *domain.WIPLimitError を返し、warn の列では警告を付けて移します
+// project_id を指定した場合は別のプロジェクトに移します（moveToProject を参照）
+func (uc *TaskUseCase) MoveTask(taskID string, req *MoveTaskRequest, actorID string, version int) (*MoveResultDTO, error) {
+	task, err := uc.writableTask(taskID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if req.ProjectID != nil {
+		moved, err := uc.moveToProject(task, req, actorID, version)
+		if err != nil {
+			return nil, err
+		}
+		return &MoveResultDTO{Task: moved, Warnings: []string{}}, nil
+	}
+	if task.ProjectID == "" {
+		return nil, fieldError("column", "only tasks in a project can be moved on the board")
+	}
+	if version != 0 && version != task.Version {
+		return nil, apperrors.ErrVersionMismatch
+	}
+
+	status := req.Column
+	if status == "" {
+		status = task.Status
+	}
+	columns, err := uc.boardColumns(task.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	column := columnFor(columns, status)
+	if column == nil {
+		return nil, fieldError("column", domain.ErrInvalidStatus.Error())
+	}
+	if req.AfterID == task.ID {
+		return nil, fieldError("after_id", "task cannot be placed next to itself")
+	}
+	if req.BeforeID == task.ID {
+		return nil, fieldError("before_id", "task cannot be placed next to itself")
+	}
+
+	tasks, err := uc.rankedColumn(task.ProjectID, status, task.ID)
+	if err != nil {
+		return nil, err
+	}
+	indexOf := func(id string) int {
+		for i, t := range tasks {
+			if t.ID == id {
+				return i
+			}
+		}
+		return -1
+	}
+
+	// after_id だけ、before_id だけの場合はもう一方を隣のタスクとする
+	lo, hi := -1, len(tasks)
+	if req.AfterID != "" {
+		if lo = indexOf(req.AfterID); lo < 0 {
+			return nil, fieldError("after_id", "task is not in the column")
+		}
+		if req.BeforeID == "" {
+			hi = lo + 1
+		}
+	}
+	if req.BeforeID != "" {
+		if hi = indexOf(req.BeforeID); hi < 0 {
+			return nil, fieldError("before_id", "task is not in the column")
+		}
+		if req.AfterID == "" {
+			lo = hi - 1
+		}
+	}
+	if req.AfterID != "" && req.BeforeID != "" && hi != lo+1 {
+		return nil, fieldError("before_id", "before_id must directly follow after_id in the column")
+	}
+	if req.AfterID == "" && req.BeforeID == "" {
+		lo, hi = len(tasks)-1, len(tasks)
+	}
+	lower, upper := "", ""
+	if lo >= 0 {
+		lower = tasks[lo].Rank
+	}
+	if hi < len(tasks) {
+		upper = tasks[hi].Rank
+	}
+
+	var warnings []string
+	if status != task.Status && column.WIPLimit > 0 && len(tasks)+1 > column.WIPLimit {
+		wipErr := &domain.WIPLimitError{Column: column.Status, Limit: column.WIPLimit, Count: len(tasks) + 1}
+		if column.WIPPolicy == domain.WIPReject {
+			return nil, wipErr
+		}
+		warnings = append(warnings, wipErr.Error())
+	}
+
+	task.Rank = domain.RankBetween(lower, upper)
+	dto := toTaskDTO(task)
+	dto.Status = status
+	updated, err := uc.applyUpdate(task, dto, actorID, version, ScopeThis)
+	if err != nil {
+		return nil, err
+	}
+	if warnings == nil {
+		warnings = []string{}
+	}
+	return &MoveResultDTO{Task: updated, Warnings: warnings}, nil
+}
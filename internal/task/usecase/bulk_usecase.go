@@ -0,0 +1,387 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	activitydomain "todo-app/internal/activity/domain"
+	apperrors "todo-app/internal/common/errors"
+	projectdomain "todo-app/internal/project/domain"
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+
+	"github.com/google/uuid"
+)
+
+// MaxBulkTasks は一括操作の対象にできるタスクの最大数です
+const MaxBulkTasks = 500
+
+// BulkTasks は複数のタスクに同じ操作（フィールドの更新・プロジェクトの移動・ラベルの付け外し・削除）を適用します
+// 変更は1つのトランザクションで保存し、タスクごとの結果を返します。呼び出し元が閲覧できないタスクは対象外です
+// ステータスの変更はタスクごとにワークフローとブロッカーで検証します
+func (uc *TaskUseCase) BulkTasks(ctx context.Context, req *BulkRequest, actorID string) (*BulkResultDTO, error) {
+	if err := uc.validateBulkRequest(req, actorID); err != nil {
+		return nil, err
+	}
+	ids, tasks, err := uc.bulkTargets(ctx, req, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkResultDTO{Atomic: req.Atomic, Results: make([]*BulkItemResultDTO, len(ids))}
+	var changes []*repository.TaskChange
+	var changed []*BulkItemResultDTO
+	ranks := map[string]string{}
+	created := map[string]bool{}
+	for i, id := range ids {
+		item := &BulkItemResultDTO{ID: id}
+		result.Results[i] = item
+		task := tasks[id]
+		if task == nil {
+			failBulkItem(item, apperrors.ErrNotFound)
+			continue
+		}
+		before := taskValues(task)
+		change, err := uc.bulkChange(task, &req.Operations, ranks, created, actorID)
+		if err != nil {
+			failBulkItem(item, err)
+			continue
+		}
+		action := activitydomain.ActionUpdated
+		if change.Delete {
+			action = activitydomain.ActionDeleted
+		}
+		change.Activity = uc.taskEntry(before, change.Task, action, actorID)
+		changes = append(changes, change)
+		changed = append(changed, item)
+	}
+
+	// atomic で検証に失敗したタスクがある場合は保存しない
+	if req.Atomic && len(changed) < len(ids) {
+		changes = nil
+	}
+	var errs []error
+	if len(changes) > 0 {
+		if errs, err = uc.taskRepo.ApplyChanges(ctx, changes, req.Atomic); err != nil {
+			uc.discardUnusedLabels(created, nil)
+			return nil, err
+		}
+	}
+	for i, item := range changed {
+		if i < len(errs) && errs[i] != nil {
+			failBulkItem(item, errs[i])
+		}
+	}
+
+	aborted := req.Atomic && (len(changed) < len(ids) || hasError(errs))
+	var applied []*repository.TaskChange
+	for i, item := range changed {
+		switch {
+		case item.Status == BulkFailed:
+		case aborted:
+			item.Status = BulkRolledBack
+		default:
+			item.Status = BulkOK
+			item.Version = changes[i].Task.Version
+			applied = append(applied, changes[i])
+		}
+	}
+	for _, item := range result.Results {
+		if item.Status == BulkOK {
+			result.Succeeded++
+		} else {
+			result.Failed++
+		}
+	}
+
+	uc.discardUnusedLabels(created, applied)
+	uc.afterBulk(applied)
+	return result, nil
+}
+
+// discardUnusedLabels は移動で作成したラベルのうち、保存した変更のいずれのタスクにも付いていないものを削除します
+func (uc *TaskUseCase) discardUnusedLabels(created map[string]bool, applied []*repository.TaskChange) {
+	for _, change := range applied {
+		for _, l := range change.Task.Labels {
+			delete(created, l.ID)
+		}
+	}
+	ids := make([]string, 0, len(created))
+	for id := range created {
+		ids = append(ids, id)
+	}
+	uc.discardLabels(ids)
+}
+
+// validateBulkRequest は対象の指定と操作を検証します。タスクごとの検証は bulkChange で行います
+func (uc *TaskUseCase) validateBulkRequest(req *BulkRequest, actorID string) error {
+	verr := apperrors.NewValidationError()
+	switch {
+	case len(req.TaskIDs) > 0 && req.Query != nil, len(req.TaskIDs) == 0 && req.Query == nil:
+		verr.Add("task_ids", "exactly one of task_ids or filter is required")
+	case len(req.TaskIDs) > MaxBulkTasks:
+		verr.Add("task_ids", fmt.Sprintf("at most %d tasks can be changed at once", MaxBulkTasks))
+	}
+
+	ops := req.Operations
+	updates := ops.Status != nil || ops.Priority != nil || ops.AssigneeID != nil || ops.DueDate != nil || ops.Estimate != nil ||
+		ops.ProjectID != nil || ops.SprintID != nil || ops.MilestoneID != nil || len(ops.AddLabels) > 0 || len(ops.RemoveLabels) > 0
+	switch {
+	case ops.Delete && updates:
+		verr.Add("operations.delete", "delete cannot be combined with other operations")
+	case !ops.Delete && !updates:
+		verr.Add("operations", "at least one operation is required")
+	}
+	if ops.Status != nil && !uc.workflowFor("").HasState(*ops.Status) {
+		verr.Add("operations.status", domain.ErrInvalidStatus.Error())
+	}
+	if ops.Priority != nil {
+		if err := domain.ValidatePriority(*ops.Priority); err != nil {
+			verr.Add("operations.priority", err.Error())
+		}
+	}
+	if ops.Estimate != nil {
+		if err := domain.ValidateEstimate(*ops.Estimate); err != nil {
+			verr.Add("operations.estimate", err.Error())
+		}
+	}
+	if ops.ProjectID != nil {
+		if *ops.ProjectID == "" {
+			verr.Add("operations.project_id", "project_id must not be empty")
+		} else if ok, err := uc.settingsRepo.CanAccessProject(actorID, *ops.ProjectID); err != nil {
+			return err
+		} else if !ok {
+			verr.Add("operations.project_id", "project not found")
+		} else if err := uc.policy.Authorize(*ops.ProjectID, actorID, projectdomain.PermEditTasks); err != nil {
+			verr.Add("operations.project_id", "you cannot edit tasks in this project")
+		} else if err := uc.checkWritable(*ops.ProjectID); err != nil {
+			verr.Add("operations.project_id", err.Error())
+		}
+	}
+	if verr.HasErrors() {
+		return verr
+	}
+	return nil
+}
+
+// bulkTargets は対象のタスクの ID（指定順、重複なし）と、そのうち呼び出し元が閲覧できるタスクを返します
+func (uc *TaskUseCase) bulkTargets(ctx context.Context, req *BulkRequest, actorID string) ([]string, map[string]*domain.Task, error) {
+	if req.Query == nil {
+		var ids []string
+		seen := map[string]bool{}
+		for _, id := range req.TaskIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		tasks, err := uc.visibleTasks(ctx, actorID, ids)
+		return ids, tasks, err
+	}
+
+	q := *req.Query
+	q.VisibleTo = actorID
+	q.SortBy, q.SortDesc = repository.DefaultTaskSort, false
+	q.Limit, q.Offset, q.Cursor = MaxBulkTasks+1, 0, nil
+	page, err := uc.taskRepo.List(ctx, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(page.Tasks) > MaxBulkTasks {
+		return nil, nil, fieldError("filter", fmt.Sprintf("filter matches more than %d tasks", MaxBulkTasks))
+	}
+	ids := make([]string, 0, len(page.Tasks))
+	tasks := make(map[string]*domain.Task, len(page.Tasks))
+	for _, task := range page.Tasks {
+		ids = append(ids, task.ID)
+		tasks[task.ID] = task
+	}
+	return ids, tasks, nil
+}
+
+// bulkChange は操作をタスクに適用した変更を返します
+// ranks は別のプロジェクトに移したタスクに割り当てた列ごとの最後の順位です（bulkRank を参照）
+// created には移動で作成したラベルの ID を追加します（保存しなかった場合は discardUnusedLabels で削除します）
+func (uc *TaskUseCase) bulkChange(task *domain.Task, ops *BulkOperations, ranks map[string]string, created map[string]bool, actorID string) (*repository.TaskChange, error) {
+	change := &repository.TaskChange{Task: task}
+	if err := uc.authorizeTask(task, actorID, projectdomain.PermEditTasks); err != nil {
+		return nil, err
+	}
+	if err := uc.checkWritable(task.ProjectID); err != nil {
+		return nil, err
+	}
+	if ops.Delete {
+		change.Delete = true
+		return change, nil
+	}
+
+	// 移動では POST /tasks/{taskID}/move と同じく、プロジェクトごとのデータを移動先に合わせて付け替える（remapTask を参照）
+	existing := task.AssigneeIDs
+	moved := ops.ProjectID != nil && *ops.ProjectID != task.ProjectID
+	if moved {
+		if task.SeriesID != "" {
+			return nil, fieldError("operations.project_id", "recurring tasks cannot be moved to another project")
+		}
+		source := *task
+		task.ProjectID = *ops.ProjectID
+		labels, err := uc.remapTask(task, &source, ops.Include.Labels)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range labels {
+			created[id] = true
+		}
+		change.ReplaceLabels, change.ReplaceCustomFields = true, true
+		task.SprintID, task.MilestoneID = "", ""
+		existing = nil
+	}
+
+	if ops.Status != nil && *ops.Status != task.Status {
+		if err := uc.workflowFor(task.ProjectID).Validate(task.Status, *ops.Status); err != nil {
+			return nil, err
+		}
+		if err := uc.checkBlockers(task.ID, *ops.Status); err != nil {
+			return nil, err
+		}
+		change.Transition = domain.NewTaskTransition(uuid.New().String(), task.ID, task.Status, *ops.Status, actorID)
+		task.Status = *ops.Status
+	}
+	if moved {
+		rank, err := uc.bulkRank(ranks, task.ProjectID, task.Status)
+		if err != nil {
+			return nil, err
+		}
+		task.Rank = rank
+	}
+	if ops.Priority != nil {
+		task.Priority = *ops.Priority
+	}
+	if ops.AssigneeID != nil {
+		task.SetAssignees([]string{*ops.AssigneeID})
+	}
+	if ops.AssigneeID != nil || moved {
+		if err := uc.validateAssignees(task.ProjectID, task.AssigneeIDs, existing, "operations.assignee_id"); err != nil {
+			return nil, err
+		}
+		change.ReplaceAssignees = true
+	}
+	if ops.DueDate != nil {
+		task.DueDate = *ops.DueDate
+	}
+	if ops.SprintID != nil || ops.MilestoneID != nil {
+		current := *task
+		if ops.SprintID != nil {
+			task.SprintID = *ops.SprintID
+		}
+		if ops.MilestoneID != nil {
+			task.MilestoneID = *ops.MilestoneID
+		}
+		if err := uc.validateSchedule(task.ProjectID, task.SprintID, task.MilestoneID, &current, "operations."); err != nil {
+			return nil, err
+		}
+	}
+	if ops.Estimate != nil {
+		task.Estimate = *ops.Estimate
+	}
+
+	if len(ops.RemoveLabels) > 0 {
+		remove := map[string]bool{}
+		for _, id := range ops.RemoveLabels {
+			remove[id] = true
+		}
+		kept := task.Labels[:0]
+		for _, l := range task.Labels {
+			if !remove[l.ID] {
+				kept = append(kept, l)
+			}
+		}
+		change.ReplaceLabels = change.ReplaceLabels || len(kept) < len(task.Labels)
+		task.Labels = kept
+	}
+	for _, id := range ops.AddLabels {
+		if hasLabel(task, id) {
+			continue
+		}
+		label, err := uc.taskLabel(task, id, "operations.add_labels")
+		if err != nil {
+			return nil, err
+		}
+		task.Labels = append(task.Labels, domain.TaskLabel{ID: label.ID, Name: label.Name, Color: label.Color})
+		change.ReplaceLabels = true
+	}
+
+	task.UpdatedAt = time.Now()
+	return change, nil
+}
+
+// bulkRank は別のプロジェクトに移したタスクを列の最後に並べる順位を返します
+// 同じ列に移すタスクは一括操作の対象の順に並ぶよう、割り当てた順位を ranks に記録します
+func (uc *TaskUseCase) bulkRank(ranks map[string]string, projectID, status string) (string, error) {
+	key := projectID + "/" + status
+	last, ok := ranks[key]
+	if !ok {
+		rank, err := uc.bottomRank(projectID, status)
+		if err != nil {
+			return "", err
+		}
+		ranks[key] = rank
+		return rank, nil
+	}
+	rank := domain.RankBetween(last, "")
+	ranks[key] = rank
+	return rank, nil
+}
+
+// afterBulk は保存済みの変更について Solr への一括登録と次の発生の生成を行います
+func (uc *TaskUseCase) afterBulk(applied []*repository.TaskChange) {
+	var updated []*domain.Task
+	for _, change := range applied {
+		task := change.Task
+		if change.Delete {
+			continue
+		}
+		updated = append(updated, task)
+		if change.Transition != nil && task.Status == domain.StatusDone && task.SeriesID != "" {
+			if err := uc.completeOccurrence(task, time.Now()); err != nil {
+				log.Printf("Failed to generate next occurrence of task %s: %v", task.ID, err)
+			}
+		}
+	}
+	indexTasks(updated)
+}
+
+// failBulkItem はエラーを一括操作の結果に設定します
+func failBulkItem(item *BulkItemResultDTO, err error) {
+	item.Status = BulkFailed
+	var verr *apperrors.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		item.Error = apperrors.ErrInvalidInput.Error()
+		item.Fields = verr.Fields
+	case errors.Is(err, apperrors.ErrNotFound):
+		item.Error = "task not found"
+	default:
+		item.Error = err.Error()
+	}
+}
+
+func hasError(errs []error) bool {
+	for _, err := range errs {
+		if err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLabel(task *domain.Task, labelID string) bool {
+	for _, l := range task.Labels {
+		if l.ID == labelID {
+			return true
+		}
+	}
+	return false
+}
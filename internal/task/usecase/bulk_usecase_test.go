@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	apperrors "todo-app/internal/common/errors"
+	projectdomain "todo-app/internal/project/domain"
+	"todo-app/internal/task/domain"
+)
+
+func newProjectTask(id, projectID, createdBy string) *domain.Task {
+	return domain.NewTask(id, "Task "+id, "", projectID, "", time.Time{}, domain.PriorityLow, domain.DefaultWorkflow().Initial, createdBy)
+}
+
+// bulkStatuses はタスクごとの結果の Status を返します
+func bulkStatuses(result *BulkResultDTO) []string {
+	statuses := make([]string, 0, len(result.Results))
+	for _, item := range result.Results {
+		statuses = append(statuses, item.Status)
+	}
+	return statuses
+}
+
+func bulkPriorities(env *testEnv, ids ...string) []string {
+	priorities := make([]string, 0, len(ids))
+	for _, id := range ids {
+		priorities = append(priorities, env.tasks.tasks[id].Priority)
+	}
+	return priorities
+}
+
+func TestBulkTasks(t *testing.T) {
+	high := domain.PriorityHigh
+	members := fakeMembers{"p1": {"alice": projectdomain.RoleMember}, "p2": {"alice": projectdomain.RoleViewer}}
+	tests := []struct {
+		name   string
+		atomic bool
+		// ids は対象のタスクの ID で、missing は存在しないタスク、viewer は alice が閲覧のみのプロジェクトのタスクです
+		ids  []string
+		fail map[string]error
+
+		wantStatuses   []string
+		wantSucceeded  int
+		wantPriorities []string
+	}{
+		{
+			name:           "all succeed",
+			ids:            []string{"t1", "t2", "t3"},
+			wantStatuses:   []string{BulkOK, BulkOK, BulkOK},
+			wantSucceeded:  3,
+			wantPriorities: []string{high, high, high},
+		},
+		{
+			name:           "partial failure keeps the other changes",
+			ids:            []string{"t1", "t2", "t3"},
+			fail:           map[string]error{"t2": apperrors.ErrVersionMismatch},
+			wantStatuses:   []string{BulkOK, BulkFailed, BulkOK},
+			wantSucceeded:  2,
+			wantPriorities: []string{high, domain.PriorityLow, high},
+		},
+		{
+			name:           "atomic rolls back every change when saving one fails",
+			atomic:         true,
+			ids:            []string{"t1", "t2", "t3"},
+			fail:           map[string]error{"t2": apperrors.ErrVersionMismatch},
+			wantStatuses:   []string{BulkRolledBack, BulkFailed, BulkRolledBack},
+			wantPriorities: []string{domain.PriorityLow, domain.PriorityLow, domain.PriorityLow},
+		},
+		{
+			name:           "atomic does not save when a task fails validation",
+			atomic:         true,
+			ids:            []string{"t1", "missing", "t3"},
+			wantStatuses:   []string{BulkRolledBack, BulkFailed, BulkRolledBack},
+			wantPriorities: []string{domain.PriorityLow, domain.PriorityLow},
+		},
+		{
+			name:           "tasks the caller cannot edit fail without affecting the others",
+			ids:            []string{"t1", "viewer"},
+			wantStatuses:   []string{BulkOK, BulkFailed},
+			wantSucceeded:  1,
+			wantPriorities: []string{high, domain.PriorityLow},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(members, nil, nil,
+				newProjectTask("t1", "p1", "bob"), newProjectTask("t2", "p1", "bob"), newProjectTask("t3", "p1", "bob"),
+				newProjectTask("viewer", "p2", "bob"))
+			for id, err := range tt.fail {
+				env.tasks.fail[id] = err
+			}
+
+			req := &BulkRequest{TaskIDs: tt.ids, Operations: BulkOperations{Priority: &high}, Atomic: tt.atomic}
+			result, err := env.uc.BulkTasks(context.Background(), req, "alice")
+			if err != nil {
+				t.Fatalf("BulkTasks() error = %v", err)
+			}
+			if got := bulkStatuses(result); !reflect.DeepEqual(got, tt.wantStatuses) {
+				t.Errorf("statuses = %v, want %v", got, tt.wantStatuses)
+			}
+			if result.Succeeded != tt.wantSucceeded || result.Failed != len(tt.ids)-tt.wantSucceeded {
+				t.Errorf("succeeded, failed = %d, %d, want %d, %d", result.Succeeded, result.Failed, tt.wantSucceeded, len(tt.ids)-tt.wantSucceeded)
+			}
+			var existing []string
+			for _, id := range tt.ids {
+				if _, ok := env.tasks.tasks[id]; ok {
+					existing = append(existing, id)
+				}
+			}
+			if got := bulkPriorities(env, existing...); !reflect.DeepEqual(got, tt.wantPriorities) {
+				t.Errorf("saved priorities = %v, want %v", got, tt.wantPriorities)
+			}
+			for _, item := range result.Results {
+				switch item.Status {
+				case BulkOK:
+					if item.Version != 2 {
+						t.Errorf("%s: version = %d, want 2", item.ID, item.Version)
+					}
+				case BulkFailed:
+					if item.Error == "" {
+						t.Errorf("%s: failed without an error", item.ID)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestBulkTasksReportsForbidden(t *testing.T) {
+	high := domain.PriorityHigh
+	env := newTestEnv(fakeMembers{"p1": {"alice": projectdomain.RoleViewer}}, nil, nil, newProjectTask("t1", "p1", "bob"))
+
+	req := &BulkRequest{TaskIDs: []string{"t1"}, Operations: BulkOperations{Priority: &high}}
+	result, err := env.uc.BulkTasks(context.Background(), req, "alice")
+	if err != nil {
+		t.Fatalf("BulkTasks() error = %v", err)
+	}
+	if item := result.Results[0]; item.Status != BulkFailed || item.Error != apperrors.ErrForbidden.Error() {
+		t.Errorf("result = %+v, want failed with %q", item, apperrors.ErrForbidden.Error())
+	}
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/task/repository"
+)
+
+// cursorPayload は不透明なカーソル文字列の中身です
+// 並び順を含めておき、異なる並び順のリクエストで使い回されないようにします
+type cursorPayload struct {
+	Sort     string `json:"s"`
+	Desc     bool   `json:"d,omitempty"`
+	Value    string `json:"v"`
+	ID       string `json:"id"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+func encodeCursor(p cursorPayload) string {
+	b, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// decodeCursor はカーソル文字列を復元し、現在の並び順と一致するかを検証します
+func decodeCursor(s string, q repository.TaskQuery) (*repository.TaskCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", apperrors.ErrInvalidInput)
+	}
+	var p cursorPayload
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", apperrors.ErrInvalidInput)
+	}
+	if p.Sort != q.SortBy || p.Desc != q.SortDesc {
+		return nil, fmt.Errorf("%w: cursor does not match the requested sort order", apperrors.ErrInvalidInput)
+	}
+	return &repository.TaskCursor{Value: p.Value, ID: p.ID, Backward: p.Backward}, nil
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"sort"
+	"time"
+
+	apperrors "todo-app/internal/common/errors"
+	projectdomain "todo-app/internal/project/domain"
+	"todo-app/internal/task/domain"
+
+	"github.com/google/uuid"
+)
+
+// ListCustomFields はプロジェクトのカスタムフィールドを作成順に返します
+func (uc *TaskUseCase) ListCustomFields(projectID, actorID string) ([]*CustomFieldDTO, error) {
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
+	fields, err := uc.fieldRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*CustomFieldDTO, 0, len(fields))
+	for _, f := range fields {
+		dtos = append(dtos, toCustomFieldDTO(f))
+	}
+	return dtos, nil
+}
+
+// CreateCustomField はプロジェクトにカスタムフィールドを追加します。キーはプロジェクト内で一意です
+// 定義はプロジェクトのすべてのタスクに関わるため、maintainer 以上が追加できます
+func (uc *TaskUseCase) CreateCustomField(projectID string, req *CustomFieldRequest, actorID string) (*CustomFieldDTO, error) {
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermManageProject); err != nil {
+		return nil, err
+	}
+	if err := uc.checkWritable(projectID); err != nil {
+		return nil, err
+	}
+	existing, err := uc.fieldRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	verr := apperrors.NewValidationError()
+	key := stringOr(req.Key, "")
+	if !domain.ValidCustomFieldKey(key) {
+		verr.Add("key", domain.ErrCustomFieldKeyInvalid.Error())
+	}
+	for _, f := range existing {
+		if f.Key == key {
+			verr.Add("key", "a custom field with this key already exists in the project")
+		}
+	}
+	fieldType := stringOr(req.Type, "")
+	if err := domain.ValidateCustomFieldType(fieldType); err != nil {
+		verr.Add("type", err.Error())
+	}
+	name, options := uc.validateCustomField(verr, fieldType, stringOr(req.Name, ""), req.Options)
+	if verr.HasErrors() {
+		return nil, verr
+	}
+
+	field := domain.NewCustomField(uuid.New().String(), projectID, key, name, fieldType, options)
+	if err := uc.fieldRepo.Create(field); err != nil {
+		return nil, err
+	}
+	return toCustomFieldDTO(field), nil
+}
+
+// UpdateCustomField はカスタムフィールドの名前・選択肢を更新します。キーと型は変更できません
+// 選択肢から外れた値はタスクから取り除き、値のあるタスクを Solr に登録し直します。maintainer 以上が変更できます
+func (uc *TaskUseCase) UpdateCustomField(projectID, fieldID string, req *CustomFieldRequest, actorID string) (*CustomFieldDTO, error) {
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermManageProject); err != nil {
+		return nil, err
+	}
+	field, err := uc.findCustomField(projectID, fieldID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkWritable(projectID); err != nil {
+		return nil, err
+	}
+
+	verr := apperrors.NewValidationError()
+	if req.Key != nil && *req.Key != field.Key {
+		verr.Add("key", "key cannot be changed")
+	}
+	if req.Type != nil && *req.Type != field.Type {
+		verr.Add("type", "type cannot be changed")
+	}
+	options := field.Options
+	if req.Options != nil {
+		options = req.Options
+	}
+	name, options := uc.validateCustomField(verr, field.Type, stringOr(req.Name, field.Name), options)
+	if verr.HasErrors() {
+		return nil, verr
+	}
+
+	taskIDs, err := uc.fieldRepo.TaskIDsByField(field.ID)
+	if err != nil {
+		return nil, err
+	}
+	field.Name, field.Options, field.UpdatedAt = name, options, time.Now()
+	if err := uc.fieldRepo.Update(field); err != nil {
+		return nil, err
+	}
+	uc.reindexTasks(taskIDs)
+	return toCustomFieldDTO(field), nil
+}
+
+// DeleteCustomField はカスタムフィールドと、タスクのその値を削除します。maintainer 以上が削除できます
+func (uc *TaskUseCase) DeleteCustomField(projectID, fieldID, actorID string) error {
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermManageProject); err != nil {
+		return err
+	}
+	if _, err := uc.findCustomField(projectID, fieldID); err != nil {
+		return err
+	}
+	if err := uc.checkWritable(projectID); err != nil {
+		return err
+	}
+	taskIDs, err := uc.fieldRepo.TaskIDsByField(fieldID)
+	if err != nil {
+		return err
+	}
+	if err := uc.fieldRepo.Delete(fieldID); err != nil {
+		return apperrors.ErrNotFound
+	}
+	uc.reindexTasks(taskIDs)
+	return nil
+}
+
+// validateCustomField はカスタムフィールドの名前と選択肢を検証・正規化します
+func (uc *TaskUseCase) validateCustomField(verr *apperrors.ValidationError, fieldType, name string, options []string) (string, []string) {
+	name, err := domain.NormalizeCustomFieldName(name)
+	if err != nil {
+		verr.Add("name", err.Error())
+	}
+	if domain.ValidateCustomFieldType(fieldType) != nil {
+		return name, nil
+	}
+	options, err = domain.NormalizeCustomFieldOptions(fieldType, options)
+	if err != nil {
+		verr.Add("options", err.Error())
+	}
+	return name, options
+}
+
+// findCustomField はプロジェクトに属するカスタムフィールドを返します
+func (uc *TaskUseCase) findCustomField(projectID, fieldID string) (*domain.CustomField, error) {
+	field, err := uc.fieldRepo.FindByID(fieldID)
+	if err != nil || field.ProjectID != projectID {
+		return nil, apperrors.ErrNotFound
+	}
+	return field, nil
+}
+
+// customValues はタスクに設定するカスタムフィールドの値を検証し、保存する値（値のない項目を除く）を返します
+// キーはタスクのプロジェクトのフィールドのもの、user 型の値はプロジェクトのメンバーである必要があります
+// current は更新前の値で、変わらない user 型の値（すでに設定されているユーザー）は検証しません
+func (uc *TaskUseCase) customValues(projectID string, values, current map[string]interface{}) (map[string]interface{}, error) {
+	normalized := map[string]interface{}{}
+	if len(values) == 0 {
+		return normalized, nil
+	}
+	if projectID == "" {
+		return nil, fieldError("custom_fields", "custom fields can only be set on project tasks")
+	}
+	fields, err := uc.fieldRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	byKey := map[string]*domain.CustomField{}
+	for _, f := range fields {
+		byKey[f.Key] = f
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	verr := apperrors.NewValidationError()
+	var users []string
+	for _, key := range keys {
+		field, ok := byKey[key]
+		if !ok {
+			verr.Add("custom_fields."+key, "custom field not found in the project")
+			continue
+		}
+		value, err := field.NormalizeValue(values[key])
+		if err != nil {
+			verr.Add("custom_fields."+key, err.Error())
+			continue
+		}
+		if value == nil {
+			continue
+		}
+		if field.Type == domain.CustomFieldUser && value != current[key] {
+			users = append(users, key)
+		}
+		normalized[key] = value
+	}
+	if len(users) > 0 {
+		members, err := uc.members.GetMembers(projectID)
+		if err != nil {
+			return nil, err
+		}
+		isMember := map[string]bool{}
+		for _, m := range members {
+			isMember[m.ID] = true
+		}
+		for _, key := range users {
+			if id := normalized[key].(string); !isMember[id] {
+				verr.Add("custom_fields."+key, "user is not a member of the project: "+id)
+			}
+		}
+	}
+	if verr.HasErrors() {
+		return nil, verr
+	}
+	return normalized, nil
+}
+
+func stringOr(s *string, fallback string) string {
+	if s == nil {
+		return fallback
+	}
+	return *s
+}
+
+func toCustomFieldDTO(field *domain.CustomField) *CustomFieldDTO {
+	return &CustomFieldDTO{
+		ID:        field.ID,
+		ProjectID: field.ProjectID,
+		Key:       field.Key,
+		Name:      field.Name,
+		Type:      field.Type,
+		Options:   field.Options,
+		CreatedAt: field.CreatedAt,
+		UpdatedAt: field.UpdatedAt,
+	}
+}
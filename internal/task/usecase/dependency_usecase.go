@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+
+	apperrors "todo-app/internal/common/errors"
+	projectdomain "todo-app/internal/project/domain"
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+)
+
+// AddDependency は依存関係を追加し、タスクの依存関係の一覧を返します
+// 循環する依存関係と、呼び出し元が閲覧できないプロジェクトのタスクとの依存関係は拒否します
+func (uc *TaskUseCase) AddDependency(ctx context.Context, taskID string, req *DependencyRequest, actorID string) (*DependenciesDTO, error) {
+	var blockedID, blockerID, field string
+	switch {
+	case req.BlockedByID != "" && req.BlocksID == "":
+		blockedID, blockerID, field = taskID, req.BlockedByID, "blocked_by_id"
+	case req.BlocksID != "" && req.BlockedByID == "":
+		blockedID, blockerID, field = req.BlocksID, taskID, "blocks_id"
+	default:
+		return nil, fieldError("blocked_by_id", "exactly one of blocked_by_id or blocks_id is required")
+	}
+	if blockedID == blockerID {
+		return nil, fieldError(field, domain.ErrSelfDependency.Error())
+	}
+
+	for _, id := range []string{blockedID, blockerID} {
+		if _, err := uc.taskRepo.GetByID(id); err != nil {
+			return nil, apperrors.ErrNotFound
+		}
+	}
+	if _, err := uc.writableTask(blockedID, actorID); err != nil {
+		return nil, err
+	}
+	visible, err := uc.visibleTasks(ctx, actorID, []string{blockedID, blockerID})
+	if err != nil {
+		return nil, err
+	}
+	if visible[blockedID] == nil || visible[blockerID] == nil {
+		return nil, apperrors.ErrForbidden
+	}
+
+	// blocker が blocked に（推移的に）依存していれば、追加すると循環する
+	cyclic, err := uc.dependencyRepo.DependsOn(blockerID, blockedID)
+	if err != nil {
+		return nil, err
+	}
+	if cyclic {
+		return nil, domain.ErrDependencyCycle
+	}
+
+	if err := uc.dependencyRepo.Create(domain.NewTaskDependency(blockedID, blockerID, actorID)); err != nil {
+		return nil, err
+	}
+	return uc.GetDependencies(ctx, taskID, actorID)
+}
+
+// RemoveDependency は taskID が dependsOnID に依存している関係を削除します
+func (uc *TaskUseCase) RemoveDependency(taskID, dependsOnID, actorID string) error {
+	if _, err := uc.writableTask(taskID, actorID); err != nil {
+		return err
+	}
+	if err := uc.dependencyRepo.Delete(taskID, dependsOnID); err != nil {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// GetDependencies はタスクが待っているタスク（blocked_by）と、タスクを待っているタスク（blocks）を返します
+// 呼び出し元が閲覧できないタスクは含めません
+func (uc *TaskUseCase) GetDependencies(ctx context.Context, taskID, actorID string) (*DependenciesDTO, error) {
+	if _, err := uc.viewableTask(taskID, actorID); err != nil {
+		return nil, err
+	}
+	deps, err := uc.dependencyRepo.ListByTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		ids = append(ids, dep.TaskID, dep.DependsOnID)
+	}
+	visible, err := uc.visibleTasks(ctx, actorID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DependenciesDTO{BlockedBy: []*TaskRefDTO{}, Blocks: []*TaskRefDTO{}}
+	for _, dep := range deps {
+		if dep.TaskID == taskID {
+			if t := visible[dep.DependsOnID]; t != nil {
+				result.BlockedBy = append(result.BlockedBy, toTaskRefDTO(t))
+			}
+		} else if t := visible[dep.TaskID]; t != nil {
+			result.Blocks = append(result.Blocks, toTaskRefDTO(t))
+		}
+	}
+	return result, nil
+}
+
+// GetDependencyGraph はプロジェクトのタスクと依存関係を DAG として返します
+// 他のプロジェクトのタスクとの依存関係も、呼び出し元が閲覧できる場合はノードとして含めます
+func (uc *TaskUseCase) GetDependencyGraph(ctx context.Context, projectID, actorID string) (*DependencyGraphDTO, error) {
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
+	tasks, err := uc.taskRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := uc.dependencyRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(tasks)+len(deps)*2)
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	for _, dep := range deps {
+		ids = append(ids, dep.TaskID, dep.DependsOnID)
+	}
+	visible, err := uc.visibleTasks(ctx, actorID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &DependencyGraphDTO{Nodes: []*TaskRefDTO{}, Edges: []*DependencyEdgeDTO{}}
+	added := map[string]bool{}
+	addNode := func(id string) {
+		if t := visible[id]; t != nil && !added[id] {
+			added[id] = true
+			graph.Nodes = append(graph.Nodes, toTaskRefDTO(t))
+		}
+	}
+	for _, task := range tasks {
+		addNode(task.ID)
+	}
+	for _, dep := range deps {
+		if visible[dep.TaskID] == nil || visible[dep.DependsOnID] == nil {
+			continue
+		}
+		addNode(dep.DependsOnID)
+		addNode(dep.TaskID)
+		graph.Edges = append(graph.Edges, &DependencyEdgeDTO{From: dep.DependsOnID, To: dep.TaskID})
+	}
+	return graph, nil
+}
+
+// checkBlockers は to への遷移にブロッカーの完了が必要な場合、未完了のブロッカーがあれば *domain.BlockedError を返します
+func (uc *TaskUseCase) checkBlockers(taskID, to string) error {
+	if !domain.RequiresUnblocked(to) {
+		return nil
+	}
+	blockers, err := uc.dependencyRepo.OpenBlockers(taskID)
+	if err != nil {
+		return err
+	}
+	if len(blockers) > 0 {
+		return &domain.BlockedError{To: to, Blockers: blockers}
+	}
+	return nil
+}
+
+// visibleTasks は ids のうち userID が閲覧できるタスクを ID をキーにして返します
+func (uc *TaskUseCase) visibleTasks(ctx context.Context, userID string, ids []string) (map[string]*domain.Task, error) {
+	visible := map[string]*domain.Task{}
+	if len(ids) == 0 {
+		return visible, nil
+	}
+	page, err := uc.taskRepo.List(ctx, repository.TaskQuery{IDs: ids, VisibleTo: userID})
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range page.Tasks {
+		visible[task.ID] = task
+	}
+	return visible, nil
+}
+
+func toTaskRefDTO(task *domain.Task) *TaskRefDTO {
+	return &TaskRefDTO{
+		ID:        task.ID,
+		Title:     task.Title,
+		Status:    task.Status,
+		ProjectID: task.ProjectID,
+	}
+}
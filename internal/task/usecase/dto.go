@@ -3,6 +3,9 @@ package usecase
 import (
     "encoding/json"
     "time"
+
+    "todo-app/internal/task/domain"
+    "todo-app/internal/task/repository"
 )
 
 type TaskDTO struct {
@@ -14,14 +17,46 @@ type TaskDTO struct {
     Status      string    `json:"status"`
     ProjectID   string    `json:"project_id"`
     AssigneeID  string    `json:"assignee_id"`
+    // AssigneeIDs はタスクの担当者です。先頭が主担当者（AssigneeID）になります
+    // 作成・更新時は assignee_ids を指定すると担当者をまとめて置き換え、assignee_id だけを指定すると担当者 1 人にします
+    AssigneeIDs []string  `json:"assignee_ids"`
     CreatedBy   string    `json:"created_by"`
+    CreatedAt   time.Time `json:"created_at"`
+    UpdatedAt   time.Time `json:"updated_at"`
+    Version     int       `json:"version"`
+    // SubtaskProgress はサブタスクの完了数/総数です（読み取り専用）
+    SubtaskProgress domain.SubtaskProgress `json:"subtask_progress"`
+    // Recurrence は繰り返しの RRULE です（例: FREQ=WEEKLY;BYDAY=MO）
+    Recurrence string `json:"recurrence,omitempty"`
+    // SeriesID と Occurrence は繰り返しタスクの系列と何回目の発生かを表します（読み取り専用）
+    SeriesID   string `json:"series_id,omitempty"`
+    Occurrence int    `json:"occurrence,omitempty"`
+    // Labels はタスクに付いているラベルです（読み取り専用。付け外しは /tasks/{taskID}/labels で行います）
+    Labels []domain.TaskLabel `json:"labels"`
+    // LabelIDs は作成時に付けるラベルです（POST /tasks のみ）
+    LabelIDs []string `json:"label_ids,omitempty"`
+    // Estimate は見積もり時間（分）、TimeSpent は記録済みの作業時間（分。読み取り専用）です
+    Estimate  int `json:"estimate"`
+    TimeSpent int `json:"time_spent"`
+    // SprintID と MilestoneID はタスクを予定する同じプロジェクトのスプリント・マイルストーンです（空文字列で外します）
+    SprintID    string `json:"sprint_id"`
+    MilestoneID string `json:"milestone_id"`
+    // StartDate はタイムライン上の開始日、DurationDays は日数です（ゼロ値は未設定）
+    StartDate    time.Time `json:"start_date"`
+    DurationDays int       `json:"duration_days"`
+    // Rank はかんばんボードの列内の並び順です（読み取り専用。POST /tasks/{taskID}/move で変更します）
+    Rank string `json:"rank"`
+    // CustomFields はプロジェクトのカスタムフィールドの値です（キーはフィールドの key）
+    // 作成・更新時は指定すると値をまとめて置き換え（null の項目は削除）、省略すると変更しません
+    CustomFields map[string]interface{} `json:"custom_fields"`
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for TaskDTO
 func (t *TaskDTO) UnmarshalJSON(data []byte) error {
     type Alias TaskDTO
     aux := &struct {
-        DueDate string `json:"due_date"`
+        DueDate   string `json:"due_date"`
+        StartDate string `json:"start_date"`
         *Alias
     }{
         Alias: (*Alias)(t),
@@ -30,6 +65,14 @@ func (t *TaskDTO) UnmarshalJSON(data []byte) error {
     if err := json.Unmarshal(data, &aux); err != nil {
         return err
     }
+
+    if aux.StartDate != "" {
+        start, err := parseStartDate(aux.StartDate)
+        if err != nil {
+            return err
+        }
+        t.StartDate = start
+    }
     
     // Parse due_date from various formats
     if aux.DueDate != "" {
@@ -52,7 +95,8 @@ func (t *TaskDTO) UnmarshalJSON(data []byte) error {
         
         if !parsed {
             return json.Unmarshal(data, &struct {
-                DueDate time.Time `json:"due_date"`
+                DueDate   time.Time `json:"due_date"`
+                StartDate string    `json:"start_date"`
                 *Alias
             }{
                 Alias: (*Alias)(t),
@@ -63,8 +107,414 @@ func (t *TaskDTO) UnmarshalJSON(data []byte) error {
     return nil
 }
 
+// parseStartDate は start_date を YYYY-MM-DD または RFC3339 として読み取ります
+func parseStartDate(s string) (time.Time, error) {
+    if t, err := time.Parse("2006-01-02", s); err == nil {
+        return t, nil
+    }
+    return time.Parse(time.RFC3339, s)
+}
+
 type SubtaskDTO struct {
-    ID     string `json:"id"`
-    Title  string `json:"title"`
-    TaskID string `json:"task_id"`
+    ID         string    `json:"id"`
+    Title      string    `json:"title"`
+    TaskID     string    `json:"task_id"`
+    IsComplete bool      `json:"is_complete"`
+    Position   int       `json:"position"`
+    CreatedAt  time.Time `json:"created_at"`
+    UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SubtaskUpdateRequest は PATCH /tasks/{taskID}/subtasks/{subtaskID} のリクエストボディです
+// 指定されたフィールドのみ更新します
+type SubtaskUpdateRequest struct {
+    Title      *string `json:"title"`
+    IsComplete *bool   `json:"is_complete"`
+}
+
+// SubtaskOrderRequest は PUT /tasks/{taskID}/subtasks/order のリクエストボディです
+type SubtaskOrderRequest struct {
+    IDs []string `json:"ids"`
+}
+
+// TransitionRequest は POST /tasks/{taskID}/transitions のリクエストボディです
+type TransitionRequest struct {
+    To string `json:"to"`
+}
+
+type WorkflowDTO struct {
+    Workflow *domain.Workflow `json:"workflow"`
+    Current  string           `json:"current"`
+    Allowed  []string         `json:"allowed"`
+}
+
+// TaskListRequest は GET /tasks の検索条件とページング指定です
+type TaskListRequest struct {
+    Query    repository.TaskQuery
+    Page     int
+    PageSize int
+    // CursorMode が true の場合はオフセットではなくカーソルでページングします
+    CursorMode bool
+    Cursor     string
+}
+
+// TaskListResult はタスク一覧の1ページ分とページング情報です
+type TaskListResult struct {
+    Tasks      []*TaskDTO
+    Total      int
+    Page       int
+    PageSize   int
+    HasNext    bool
+    HasPrev    bool
+    NextCursor string
+    PrevCursor string
+}
+
+// TaskSummaryDTO は GET /tasks/summary のレスポンスです
+type TaskSummaryDTO struct {
+    Total      int            `json:"total"`
+    Incomplete int            `json:"incomplete"`
+    Overdue    int            `json:"overdue"`
+    ByStatus   map[string]int `json:"by_status"`
+    ByPriority map[string]int `json:"by_priority"`
+    ByAssignee map[string]int `json:"by_assignee"`
+    Unassigned int            `json:"unassigned"`
+}
+
+// MyTasksDTO は GET /users/me/tasks のレスポンスです。呼び出し元が担当している未完了のタスクを期限で区分します
+// 区分は呼び出し元のタイムゾーンで判定し、期限のないタスクは later に含めます
+type MyTasksDTO struct {
+    Timezone string     `json:"timezone"`
+    Overdue  []*TaskDTO `json:"overdue"`
+    Today    []*TaskDTO `json:"today"`
+    ThisWeek []*TaskDTO `json:"this_week"`
+    Later    []*TaskDTO `json:"later"`
+}
+
+// DependencyRequest は POST /tasks/{taskID}/dependencies のリクエストボディです
+// blocked_by_id（このタスクが完了を待つタスク）か blocks_id（このタスクの完了を待つタスク）のどちらか一方を指定します
+type DependencyRequest struct {
+    BlockedByID string `json:"blocked_by_id"`
+    BlocksID    string `json:"blocks_id"`
+}
+
+// TaskRefDTO は依存関係の表示に使うタスクの概要です
+type TaskRefDTO struct {
+    ID        string `json:"id"`
+    Title     string `json:"title"`
+    Status    string `json:"status"`
+    ProjectID string `json:"project_id"`
+}
+
+type DependenciesDTO struct {
+    BlockedBy []*TaskRefDTO `json:"blocked_by"`
+    Blocks    []*TaskRefDTO `json:"blocks"`
+}
+
+// DependencyEdgeDTO は From のタスクが完了するまで To のタスクを開始できないことを表します
+type DependencyEdgeDTO struct {
+    From string `json:"from"`
+    To   string `json:"to"`
+}
+
+// DependencyGraphDTO は GET /projects/{projectID}/dependency-graph のレスポンスです
+type DependencyGraphDTO struct {
+    Nodes []*TaskRefDTO        `json:"nodes"`
+    Edges []*DependencyEdgeDTO `json:"edges"`
+}
+
+// TimelineBarDTO はタイムライン上のタスクの期間です。日付は YYYY-MM-DD で、両端を含みます
+// Start / End は予定の期間、EarliestStart 以降は依存関係を考慮した最早・最遅の日程です
+// ShiftDays は依存先の完了を待つために予定より後ろにずれる日数、Slack は全体の終了日を遅らせずにずらせる日数です
+type TimelineBarDTO struct {
+    TaskID         string `json:"task_id"`
+    Title          string `json:"title"`
+    Status         string `json:"status"`
+    AssigneeID     string `json:"assignee_id"`
+    Start          string `json:"start"`
+    End            string `json:"end"`
+    DurationDays   int    `json:"duration_days"`
+    EarliestStart  string `json:"earliest_start"`
+    EarliestFinish string `json:"earliest_finish"`
+    LatestStart    string `json:"latest_start"`
+    LatestFinish   string `json:"latest_finish"`
+    ShiftDays      int    `json:"shift_days"`
+    Slack          int    `json:"slack"`
+    Critical       bool   `json:"critical"`
+    // PastProjectEnd は最早終了日がプロジェクトの終了日より後になることを表します
+    PastProjectEnd bool `json:"past_project_end"`
+}
+
+// TimelineDTO は GET /projects/{projectID}/timeline のレスポンスです
+// 開始日・期限のどちらもないタスクは Unscheduled に、Canceled のタスクはどちらにも含めません
+type TimelineDTO struct {
+    ProjectID    string               `json:"project_id"`
+    StartDate    string               `json:"start_date"`
+    EndDate      string               `json:"end_date"`
+    // ProjectedEnd は依存関係を考慮したすべてのタスクの終了日、OverrunDays はそれがプロジェクトの終了日を超える日数です
+    ProjectedEnd string               `json:"projected_end"`
+    OverrunDays  int                  `json:"overrun_days"`
+    Bars         []*TimelineBarDTO    `json:"bars"`
+    Dependencies []*DependencyEdgeDTO `json:"dependencies"`
+    // CriticalPath は全体の終了日を決めるタスクの ID を開始の順に並べたものです
+    CriticalPath []string             `json:"critical_path"`
+    Unscheduled  []*TaskRefDTO        `json:"unscheduled"`
+}
+
+// RescheduleRequest は POST /tasks/{taskID}/reschedule のリクエストボディです
+// start_date（YYYY-MM-DD）に開始するようにタスクを移し、期限も同じ日数だけずらします。duration_days を指定すると日数も変更します
+// cascade が true の場合、移した後のタスクの終了より前に始まる依存元のタスク（推移的）を後ろにずらします
+type RescheduleRequest struct {
+    StartDate    string `json:"start_date"`
+    DurationDays *int   `json:"duration_days"`
+    Cascade      bool   `json:"cascade"`
+}
+
+// ShiftedTaskDTO は連動してずらしたタスクです
+type ShiftedTaskDTO struct {
+    TaskID    string `json:"task_id"`
+    Title     string `json:"title"`
+    Start     string `json:"start"`
+    End       string `json:"end"`
+    ShiftDays int    `json:"shift_days"`
+}
+
+// RescheduleResultDTO は POST /tasks/{taskID}/reschedule のレスポンスです
+// Skipped は呼び出し元が編集できないためにずらさなかったタスクの ID です（その先のタスクもずらしません）
+type RescheduleResultDTO struct {
+    Task    *TaskDTO          `json:"task"`
+    Shifted []*ShiftedTaskDTO `json:"shifted"`
+    Skipped []string          `json:"skipped"`
+}
+
+// BoardDTO は GET /projects/{projectID}/board のレスポンスです。列はワークフローのステータスに対応します
+type BoardDTO struct {
+    ProjectID string            `json:"project_id"`
+    Columns   []*BoardColumnDTO `json:"columns"`
+}
+
+// BoardColumnDTO はかんばんボードの列と、列に並ぶタスクです（順位の順）
+// OverLimit は列のタスク数が WIP 制限を超えていることを表します
+type BoardColumnDTO struct {
+    Status    string     `json:"status"`
+    Name      string     `json:"name"`
+    Position  int        `json:"position"`
+    WIPLimit  int        `json:"wip_limit"`
+    WIPPolicy string     `json:"wip_policy"`
+    Count     int        `json:"count"`
+    OverLimit bool       `json:"over_limit"`
+    Tasks     []*TaskDTO `json:"tasks"`
+}
+
+// BoardColumnRequest は列の設定です。name を省略するとステータス名、wip_policy を省略すると warn です
+type BoardColumnRequest struct {
+    Status    string `json:"status"`
+    Name      string `json:"name"`
+    WIPLimit  int    `json:"wip_limit"`
+    WIPPolicy string `json:"wip_policy"`
+}
+
+// BoardColumnsRequest は PUT /projects/{projectID}/board/columns のリクエストボディです
+// 列は指定した順に並びます。指定しなかったステータスの列は既定の設定で後ろに並びます
+type BoardColumnsRequest struct {
+    Columns []*BoardColumnRequest `json:"columns"`
+}
+
+// MoveTaskRequest は POST /tasks/{taskID}/move のリクエストボディです
+// column（ステータス）の列の after_id のタスクの後、before_id のタスクの前に移します
+// column を省略すると同じ列の中で並べ替え、after_id と before_id をどちらも省略すると列の最後に移します
+// project_id を指定すると別のプロジェクトに移します（column・after_id・before_id とは併用できません）
+type MoveTaskRequest struct {
+    Column    string          `json:"column"`
+    AfterID   string          `json:"after_id"`
+    BeforeID  string          `json:"before_id"`
+    ProjectID *string         `json:"project_id"`
+    Include   TransferOptions `json:"include"`
+}
+
+// CloneTaskRequest は POST /tasks/{taskID}/clone のリクエストボディです
+// project_id を省略すると元のタスクと同じプロジェクトに複製します
+type CloneTaskRequest struct {
+    ProjectID *string         `json:"project_id"`
+    Include   TransferOptions `json:"include"`
+}
+
+// TransferOptions はタスクの移動・複製で引き継ぐ内容です
+// 移動ではサブタスク・コメント・添付ファイルは常にタスクとともに移り、labels のみ意味を持ちます
+type TransferOptions struct {
+    Subtasks    bool `json:"subtasks"`
+    Comments    bool `json:"comments"`
+    Attachments bool `json:"attachments"`
+    Labels      bool `json:"labels"`
+}
+
+// MoveResultDTO は POST /tasks/{taskID}/move のレスポンスです
+// Warnings は WIP 制限が warn の列で制限を超えた場合の警告です
+type MoveResultDTO struct {
+    Task     *TaskDTO `json:"task"`
+    Warnings []string `json:"warnings"`
+}
+
+type LabelDTO struct {
+    ID        string    `json:"id"`
+    ProjectID string    `json:"project_id"`
+    Name      string    `json:"name"`
+    Color     string    `json:"color"`
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"`
+}
+
+// LabelRequest は POST /projects/{projectID}/labels と PATCH /projects/{projectID}/labels/{labelID} のリクエストボディです
+// PATCH では指定されたフィールドのみ更新します
+type LabelRequest struct {
+    Name  *string `json:"name"`
+    Color *string `json:"color"`
+}
+
+type CustomFieldDTO struct {
+    ID        string    `json:"id"`
+    ProjectID string    `json:"project_id"`
+    Key       string    `json:"key"`
+    Name      string    `json:"name"`
+    Type      string    `json:"type"`
+    Options   []string  `json:"options"`
+    CreatedAt time.Time `json:"created_at"`
+    UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CustomFieldRequest は POST /projects/{projectID}/custom-fields と PATCH /projects/{projectID}/custom-fields/{fieldID} のリクエストボディです
+// PATCH では指定されたフィールドのみ更新します（key と type は変更できません）
+type CustomFieldRequest struct {
+    Key     *string  `json:"key"`
+    Name    *string  `json:"name"`
+    Type    *string  `json:"type"`
+    Options []string `json:"options"`
+}
+
+// TaskLabelsRequest は PUT /tasks/{taskID}/labels のリクエストボディです
+type TaskLabelsRequest struct {
+    LabelIDs []string `json:"label_ids"`
+}
+
+// TimeEntryRequest は POST /tasks/{taskID}/time-entries と PATCH /tasks/{taskID}/time-entries/{entryID} のリクエストボディです
+// PATCH では指定されたフィールドのみ更新します
+type TimeEntryRequest struct {
+    Minutes *int    `json:"minutes"`
+    Note    *string `json:"note"`
+    // StartedAt は作業の開始日時です。作成時に省略した場合は現在時刻から Minutes 分前とします
+    StartedAt *time.Time `json:"started_at"`
+}
+
+// TimerStopRequest は POST /tasks/{taskID}/timer/stop のリクエストボディです（省略可）
+type TimerStopRequest struct {
+    Note string `json:"note"`
+}
+
+// TimeSummaryDTO は作業時間（分）の集計です
+type TimeSummaryDTO struct {
+    TotalMinutes int `json:"total_minutes"`
+    // Estimate は by_task のタスクの見積もり時間の合計です
+    Estimate  int            `json:"estimate"`
+    ByUser    map[string]int `json:"by_user"`
+    ByProject map[string]int `json:"by_project"`
+    ByTask    []*TaskTimeDTO `json:"by_task"`
+}
+
+// TaskTimeDTO はタスクの見積もりと実績（分）です
+// Remaining は見積もりの残りで、超過した場合は負の値、見積もりがない場合は 0 です
+type TaskTimeDTO struct {
+    TaskID    string `json:"task_id"`
+    Title     string `json:"title"`
+    ProjectID string `json:"project_id"`
+    Estimate  int    `json:"estimate"`
+    Minutes   int    `json:"minutes"`
+    Remaining int    `json:"remaining"`
+}
+
+// QuickAddRequest は POST /tasks/quick のリクエストボディです
+// 例: "Review PR tomorrow 3pm !high @alice #backend +ProjectX"
+type QuickAddRequest struct {
+    Text string `json:"text"`
+}
+
+// QuickAddParsedDTO は一行入力の解釈です
+type QuickAddParsedDTO struct {
+    Title string `json:"title"`
+    // DueDate は呼び出し元のタイムゾーン（Timezone）での期限です
+    DueDate    *time.Time `json:"due_date"`
+    DueHasTime bool       `json:"due_has_time"`
+    Timezone   string     `json:"timezone"`
+    Priority   string     `json:"priority,omitempty"`
+    // Assignee・Project・Labels は入力された名前、AssigneeID・ProjectID・LabelIDs は解決した ID です
+    Assignee   string   `json:"assignee,omitempty"`
+    AssigneeID string   `json:"assignee_id,omitempty"`
+    Project    string   `json:"project,omitempty"`
+    ProjectID  string   `json:"project_id,omitempty"`
+    Labels     []string `json:"labels"`
+    LabelIDs   []string `json:"label_ids"`
+}
+
+// QuickAddResultDTO は POST /tasks/quick のレスポンスです
+type QuickAddResultDTO struct {
+    Task   *TaskDTO           `json:"task"`
+    Parsed *QuickAddParsedDTO `json:"parsed"`
+}
+
+// BulkRequest は POST /tasks/bulk のリクエストボディです
+// 対象は task_ids か filter（GET /tasks と同じクエリパラメータ名と値）のどちらか一方で指定します
+type BulkRequest struct {
+    TaskIDs    []string          `json:"task_ids"`
+    Filter     map[string]string `json:"filter"`
+    Operations BulkOperations    `json:"operations"`
+    // Atomic が true の場合は1件でも失敗すればすべての変更を取り消します
+    Atomic bool `json:"atomic"`
+
+    // Query は Filter を変換した検索条件です（ハンドラーが設定します）
+    Query *repository.TaskQuery `json:"-"`
+}
+
+// BulkOperations は対象のタスクに適用する操作です。指定されたものだけを適用します
+// Delete は他の操作と同時に指定できません
+type BulkOperations struct {
+    Status   *string `json:"status"`
+    Priority *string `json:"priority"`
+    // AssigneeID に空文字列を指定すると担当者を外します
+    AssigneeID *string    `json:"assignee_id"`
+    DueDate    *time.Time `json:"due_date"`
+    Estimate   *int       `json:"estimate"`
+    // ProjectID はタスクの移動先のプロジェクトです。POST /tasks/{taskID}/move と同じく、担当者・カスタムフィールドの値は移動先に合わせて付け替え、
+    // スプリント・マイルストーンは外れます。ラベルは Include.Labels を指定した場合のみ同じ名前のラベルに付け替えます
+    ProjectID    *string         `json:"project_id"`
+    Include      TransferOptions `json:"include"`
+    // SprintID と MilestoneID はタスクを予定するスプリント・マイルストーンです。空文字列を指定すると外します
+    SprintID     *string  `json:"sprint_id"`
+    MilestoneID  *string  `json:"milestone_id"`
+    AddLabels    []string `json:"add_labels"`
+    RemoveLabels []string `json:"remove_labels"`
+    Delete       bool     `json:"delete"`
+}
+
+// 一括操作の各タスクの結果
+const (
+    BulkOK     = "ok"
+    BulkFailed = "failed"
+    // BulkRolledBack は atomic の場合に、他のタスクの失敗によって取り消されたことを表します
+    BulkRolledBack = "rolled_back"
+)
+
+// BulkResultDTO は POST /tasks/bulk のレスポンスです
+type BulkResultDTO struct {
+    Atomic    bool                 `json:"atomic"`
+    Succeeded int                  `json:"succeeded"`
+    Failed    int                  `json:"failed"`
+    Results   []*BulkItemResultDTO `json:"results"`
+}
+
+// BulkItemResultDTO は一括操作の1件の結果です。Version は成功した場合の更新後のバージョンです
+type BulkItemResultDTO struct {
+    ID      string            `json:"id"`
+    Status  string            `json:"status"`
+    Error   string            `json:"error,omitempty"`
+    Fields  map[string]string `json:"fields,omitempty"`
+    Version int               `json:"version,omitempty"`
 }
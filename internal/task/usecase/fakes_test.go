@@ -0,0 +1,250 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	activitydomain "todo-app/internal/activity/domain"
+	apperrors "todo-app/internal/common/errors"
+	projectdomain "todo-app/internal/project/domain"
+	"todo-app/internal/project/policy"
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+)
+
+// テスト用のメモリ上のリポジトリです。インターフェースを埋め込み、テストで使うメソッドだけを実装します
+// （実装していないメソッドを呼ぶと nil のインターフェースの呼び出しで panic します）
+
+// fakeTasks は TaskRepository の実装です
+// ApplyChanges は PostgreSQL の実装と同じく、atomic の場合は最初に失敗した変更ですべてを取り消します
+type fakeTasks struct {
+	repository.TaskRepository
+	tasks map[string]*domain.Task
+	// fail は ApplyChanges で失敗させるタスクの ID とそのエラーです
+	fail      map[string]error
+	discarded []string
+}
+
+func newFakeTasks(tasks ...*domain.Task) *fakeTasks {
+	f := &fakeTasks{tasks: map[string]*domain.Task{}, fail: map[string]error{}}
+	for _, t := range tasks {
+		f.tasks[t.ID] = t
+	}
+	return f
+}
+
+// copyTask は保存済みのタスクと読み込んだタスクが別のものになるようにコピーします
+func copyTask(t *domain.Task) *domain.Task {
+	c := *t
+	c.AssigneeIDs = append([]string(nil), t.AssigneeIDs...)
+	c.Labels = append([]domain.TaskLabel(nil), t.Labels...)
+	c.CustomFields = map[string]interface{}{}
+	for k, v := range t.CustomFields {
+		c.CustomFields[k] = v
+	}
+	return &c
+}
+
+func (f *fakeTasks) GetByID(id string) (*domain.Task, error) {
+	t, ok := f.tasks[id]
+	if !ok {
+		return nil, apperrors.ErrNotFound
+	}
+	return copyTask(t), nil
+}
+
+// List は q.IDs のタスクを返します（閲覧できるかは絞り込みません）
+func (f *fakeTasks) List(ctx context.Context, q repository.TaskQuery) (*repository.TaskPage, error) {
+	page := &repository.TaskPage{}
+	for _, id := range q.IDs {
+		if t, ok := f.tasks[id]; ok {
+			page.Tasks = append(page.Tasks, copyTask(t))
+		}
+	}
+	page.Total = len(page.Tasks)
+	return page, nil
+}
+
+func (f *fakeTasks) Create(task *domain.Task, activity *activitydomain.Entry) error {
+	f.tasks[task.ID] = copyTask(task)
+	return nil
+}
+
+func (f *fakeTasks) Discard(id string, activity *activitydomain.Entry) error {
+	if _, ok := f.tasks[id]; !ok {
+		return apperrors.ErrNotFound
+	}
+	delete(f.tasks, id)
+	f.discarded = append(f.discarded, id)
+	return nil
+}
+
+func (f *fakeTasks) ApplyChanges(ctx context.Context, changes []*repository.TaskChange, atomic bool) ([]error, error) {
+	errs := make([]error, len(changes))
+	staged := map[string]*domain.Task{}
+	for i, change := range changes {
+		id := change.Task.ID
+		stored, ok := f.tasks[id]
+		switch {
+		case !ok:
+			errs[i] = apperrors.ErrNotFound
+		case f.fail[id] != nil:
+			errs[i] = f.fail[id]
+		case stored.Version != change.Task.Version:
+			errs[i] = apperrors.ErrVersionMismatch
+		}
+		if errs[i] != nil {
+			if atomic {
+				return errs, nil
+			}
+			continue
+		}
+		saved := copyTask(change.Task)
+		saved.Version++
+		staged[id] = saved
+	}
+	for id, t := range staged {
+		f.tasks[id] = t
+	}
+	for i, change := range changes {
+		if errs[i] == nil {
+			change.Task.Version++
+		}
+	}
+	return errs, nil
+}
+
+// fakeLabels は LabelRepository の実装です
+type fakeLabels struct {
+	repository.LabelRepository
+	labels  map[string]*domain.Label
+	created []string
+	deleted []string
+}
+
+func newFakeLabels(labels ...*domain.Label) *fakeLabels {
+	f := &fakeLabels{labels: map[string]*domain.Label{}}
+	for _, l := range labels {
+		f.labels[l.ID] = l
+	}
+	return f
+}
+
+func (f *fakeLabels) Create(label *domain.Label) error {
+	f.labels[label.ID] = label
+	f.created = append(f.created, label.ID)
+	return nil
+}
+
+func (f *fakeLabels) FindByID(id string) (*domain.Label, error) {
+	if l, ok := f.labels[id]; ok {
+		return l, nil
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (f *fakeLabels) FindByName(projectID, name string) (*domain.Label, error) {
+	for _, l := range f.labels {
+		if l.ProjectID == projectID && strings.EqualFold(l.Name, name) {
+			return l, nil
+		}
+	}
+	return nil, apperrors.ErrNotFound
+}
+
+func (f *fakeLabels) Delete(id string) error {
+	if _, ok := f.labels[id]; !ok {
+		return apperrors.ErrNotFound
+	}
+	delete(f.labels, id)
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+// fakeFields は CustomFieldRepository の実装です
+type fakeFields struct {
+	repository.CustomFieldRepository
+	fields []*domain.CustomField
+}
+
+func (f *fakeFields) ListByProject(projectID string) ([]*domain.CustomField, error) {
+	var fields []*domain.CustomField
+	for _, field := range f.fields {
+		if field.ProjectID == projectID {
+			fields = append(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// fakeSettings は ProjectSettingsRepository の実装です。アーカイブ済みのプロジェクトはありません
+type fakeSettings struct {
+	repository.ProjectSettingsRepository
+	members fakeMembers
+}
+
+func (f *fakeSettings) IsArchived(projectID string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeSettings) CanAccessProject(userID, projectID string) (bool, error) {
+	role, err := f.members.MemberRole(projectID, userID)
+	return role != "", err
+}
+
+// fakeBoard は BoardRepository の実装です。列は常に空です
+type fakeBoard struct {
+	repository.BoardRepository
+}
+
+func (f *fakeBoard) ColumnTasks(projectID, status string) ([]domain.RankedTask, error) {
+	return nil, nil
+}
+
+// fakeDependencies は TaskDependencyRepository の実装です。ブロッカーはありません
+type fakeDependencies struct {
+	repository.TaskDependencyRepository
+}
+
+func (f *fakeDependencies) OpenBlockers(taskID string) ([]string, error) {
+	return nil, nil
+}
+
+// fakeMembers はプロジェクトごとのメンバーとロールです。MemberLister と policy.RoleSource を実装します
+type fakeMembers map[string]map[string]string
+
+func (f fakeMembers) MemberRole(projectID, userID string) (string, error) {
+	return f[projectID][userID], nil
+}
+
+func (f fakeMembers) GetMembers(projectID string) ([]*projectdomain.Member, error) {
+	var members []*projectdomain.Member
+	for id, role := range f[projectID] {
+		members = append(members, &projectdomain.Member{ID: id, Role: role})
+	}
+	return members, nil
+}
+
+// fakeContent は TaskContentCopier の実装です。err を返します
+type fakeContent struct {
+	err error
+}
+
+func (f *fakeContent) CopyTaskContent(fromTaskID, toTaskID string, comments, attachments bool) error {
+	return f.err
+}
+
+// testEnv はテスト用のリポジトリと、それを使う TaskUseCase です
+type testEnv struct {
+	uc      *TaskUseCase
+	tasks   *fakeTasks
+	labels  *fakeLabels
+	content *fakeContent
+}
+
+func newTestEnv(members fakeMembers, fields []*domain.CustomField, labels []*domain.Label, tasks ...*domain.Task) *testEnv {
+	env := &testEnv{tasks: newFakeTasks(tasks...), labels: newFakeLabels(labels...), content: &fakeContent{}}
+	env.uc = NewTaskUseCase(env.tasks, nil, nil, &fakeSettings{members: members}, &fakeDependencies{}, nil, env.labels, nil,
+		&fakeBoard{}, &fakeFields{fields: fields}, nil, env.content, nil, policy.NewPolicy(members), members)
+	return env
+}
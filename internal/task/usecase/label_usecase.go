@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	activitydomain "todo-app/internal/activity/domain"
+	apperrors "todo-app/internal/common/errors"
+	projectdomain "todo-app/internal/project/domain"
+	"todo-app/internal/task/domain"
+
+	"github.com/google/uuid"
+)
+
+// ListLabels はプロジェクトのラベルを名前順に返します
+func (uc *TaskUseCase) ListLabels(projectID, actorID string) ([]*LabelDTO, error) {
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
+	labels, err := uc.labelRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*LabelDTO, 0, len(labels))
+	for _, l := range labels {
+		dtos = append(dtos, toLabelDTO(l))
+	}
+	return dtos, nil
+}
+
+// CreateLabel はプロジェクトにラベルを追加します。名前はプロジェクト内で一意です
+// タスクを編集できるメンバー（member 以上）が追加できます
+func (uc *TaskUseCase) CreateLabel(projectID string, req *LabelRequest, actorID string) (*LabelDTO, error) {
+	var name, color string
+	if req.Name != nil {
+		name = *req.Name
+	}
+	if req.Color != nil {
+		color = *req.Color
+	}
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermEditTasks); err != nil {
+		return nil, err
+	}
+	if err := uc.checkWritable(projectID); err != nil {
+		return nil, err
+	}
+	name, color, err := uc.validateLabel(projectID, "", name, color)
+	if err != nil {
+		return nil, err
+	}
+
+	label := domain.NewLabel(uuid.New().String(), projectID, name, color)
+	if err := uc.labelRepo.Create(label); err != nil {
+		return nil, err
+	}
+	return toLabelDTO(label), nil
+}
+
+// UpdateLabel はラベルの名前・色を更新します
+// 名前が変わった場合は、ラベルが付いているタスクを Solr に登録し直します。maintainer 以上が変更できます
+func (uc *TaskUseCase) UpdateLabel(projectID, labelID string, req *LabelRequest, actorID string) (*LabelDTO, error) {
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermManageProject); err != nil {
+		return nil, err
+	}
+	label, err := uc.findLabel(projectID, labelID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkWritable(projectID); err != nil {
+		return nil, err
+	}
+	name, color := label.Name, label.Color
+	if req.Name != nil {
+		name = *req.Name
+	}
+	if req.Color != nil {
+		color = *req.Color
+	}
+	name, color, err = uc.validateLabel(projectID, label.ID, name, color)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := name != label.Name
+	label.Name, label.Color, label.UpdatedAt = name, color, time.Now()
+	if err := uc.labelRepo.Update(label); err != nil {
+		return nil, err
+	}
+	if renamed {
+		uc.reindexLabelTasks(label.ID)
+	}
+	return toLabelDTO(label), nil
+}
+
+// DeleteLabel はラベルを削除し、タスクからも外します。maintainer 以上が削除できます
+func (uc *TaskUseCase) DeleteLabel(projectID, labelID, actorID string) error {
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermManageProject); err != nil {
+		return err
+	}
+	if _, err := uc.findLabel(projectID, labelID); err != nil {
+		return err
+	}
+	if err := uc.checkWritable(projectID); err != nil {
+		return err
+	}
+	taskIDs, err := uc.labelRepo.TaskIDsByLabel(labelID)
+	if err != nil {
+		return err
+	}
+	if err := uc.labelRepo.Delete(labelID); err != nil {
+		return apperrors.ErrNotFound
+	}
+	uc.reindexTasks(taskIDs)
+	return nil
+}
+
+// SetTaskLabels はタスクのラベルを labelIDs に置き換えます
+// ラベルはタスクと同じプロジェクトのものに限ります
+func (uc *TaskUseCase) SetTaskLabels(taskID string, labelIDs []string, actorID string) (*TaskDTO, error) {
+	task, err := uc.writableTask(taskID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	labeled := *task
+	labeled.Labels = nil
+	if err := uc.attachLabels(&labeled, labelIDs); err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(labeled.Labels))
+	for _, l := range labeled.Labels {
+		ids = append(ids, l.ID)
+	}
+	if err := uc.labelRepo.SetTaskLabels(taskID, ids, uc.labelsEntry(task, labeled.Labels, actorID)); err != nil {
+		return nil, err
+	}
+	return uc.reloadLabeledTask(taskID)
+}
+
+// AddTaskLabel はタスクにラベルを付けます（付与済みの場合は何もしません）
+func (uc *TaskUseCase) AddTaskLabel(taskID, labelID, actorID string) (*TaskDTO, error) {
+	task, err := uc.writableTask(taskID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	label, err := uc.taskLabel(task, labelID, "label_id")
+	if err != nil {
+		return nil, err
+	}
+	labels := []domain.TaskLabel{}
+	for _, l := range task.Labels {
+		if l.ID != labelID {
+			labels = append(labels, l)
+		}
+	}
+	labels = append(labels, domain.TaskLabel{ID: label.ID, Name: label.Name, Color: label.Color})
+	if err := uc.labelRepo.AddTaskLabel(taskID, labelID, uc.labelsEntry(task, labels, actorID)); err != nil {
+		return nil, err
+	}
+	return uc.reloadLabeledTask(taskID)
+}
+
+// RemoveTaskLabel はタスクからラベルを外します
+func (uc *TaskUseCase) RemoveTaskLabel(taskID, labelID, actorID string) (*TaskDTO, error) {
+	task, err := uc.writableTask(taskID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	labels := []domain.TaskLabel{}
+	for _, l := range task.Labels {
+		if l.ID != labelID {
+			labels = append(labels, l)
+		}
+	}
+	if err := uc.labelRepo.RemoveTaskLabel(taskID, labelID, uc.labelsEntry(task, labels, actorID)); err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	return uc.reloadLabeledTask(taskID)
+}
+
+// attachLabels は作成前のタスクに labelIDs のラベルを設定します
+func (uc *TaskUseCase) attachLabels(task *domain.Task, labelIDs []string) error {
+	seen := map[string]bool{}
+	for _, id := range labelIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		label, err := uc.taskLabel(task, id, "label_ids")
+		if err != nil {
+			return err
+		}
+		task.Labels = append(task.Labels, domain.TaskLabel{ID: label.ID, Name: label.Name, Color: label.Color})
+	}
+	return nil
+}
+
+// validateLabel はラベルの名前と色を検証・正規化します。exceptID は重複チェックから除外するラベルです
+func (uc *TaskUseCase) validateLabel(projectID, exceptID, name, color string) (string, string, error) {
+	verr := apperrors.NewValidationError()
+	name, err := domain.NormalizeLabelName(name)
+	if err != nil {
+		verr.Add("name", err.Error())
+	} else if existing, err := uc.labelRepo.FindByName(projectID, name); err == nil && existing.ID != exceptID {
+		verr.Add("name", "a label with this name already exists in the project")
+	}
+	color, err = domain.NormalizeLabelColor(color)
+	if err != nil {
+		verr.Add("color", err.Error())
+	}
+	if verr.HasErrors() {
+		return "", "", verr
+	}
+	return name, color, nil
+}
+
+// findLabel はプロジェクトに属するラベルを返します
+func (uc *TaskUseCase) findLabel(projectID, labelID string) (*domain.Label, error) {
+	label, err := uc.labelRepo.FindByID(labelID)
+	if err != nil || label.ProjectID != projectID {
+		return nil, apperrors.ErrNotFound
+	}
+	return label, nil
+}
+
+// taskLabel はタスクに付けられるラベル（タスクと同じプロジェクトのラベル）を返します
+func (uc *TaskUseCase) taskLabel(task *domain.Task, labelID, field string) (*domain.Label, error) {
+	label, err := uc.labelRepo.FindByID(labelID)
+	if err != nil {
+		return nil, fieldError(field, "label not found: "+labelID)
+	}
+	if label.ProjectID != task.ProjectID {
+		return nil, fieldError(field, "label belongs to another project: "+labelID)
+	}
+	return label, nil
+}
+
+// labelsEntry はタスクのラベルを labels に置き換える変更履歴を返します
+// ラベルは読み込み時と同じく名前の順（大文字・小文字を区別しない）に並べて記録します
+func (uc *TaskUseCase) labelsEntry(task *domain.Task, labels []domain.TaskLabel, actorID string) *activitydomain.Entry {
+	labeled := *task
+	labeled.Labels = append([]domain.TaskLabel(nil), labels...)
+	sort.SliceStable(labeled.Labels, func(i, j int) bool {
+		return strings.ToLower(labeled.Labels[i].Name) < strings.ToLower(labeled.Labels[j].Name)
+	})
+	return uc.taskEntry(taskValues(task), &labeled, activitydomain.ActionUpdated, actorID)
+}
+
+// reloadLabeledTask はラベルの付け外し後のタスクを取得し、Solr に登録し直します
+func (uc *TaskUseCase) reloadLabeledTask(taskID string) (*TaskDTO, error) {
+	task, err := uc.taskRepo.GetByID(taskID)
+	if err != nil {
+		return nil, err
+	}
+	indexTask(task)
+	return toTaskDTO(task), nil
+}
+
+// reindexLabelTasks はラベルが付いているタスクを Solr に登録し直します
+func (uc *TaskUseCase) reindexLabelTasks(labelID string) {
+	taskIDs, err := uc.labelRepo.TaskIDsByLabel(labelID)
+	if err != nil {
+		log.Printf("Failed to list tasks for label %s: %v", labelID, err)
+		return
+	}
+	uc.reindexTasks(taskIDs)
+}
+
+func (uc *TaskUseCase) reindexTasks(taskIDs []string) {
+	for _, id := range taskIDs {
+		task, err := uc.taskRepo.GetByID(id)
+		if err != nil {
+			// ゴミ箱内のタスクは対象外
+			continue
+		}
+		indexTask(task)
+	}
+}
+
+func toLabelDTO(label *domain.Label) *LabelDTO {
+	return &LabelDTO{
+		ID:        label.ID,
+		ProjectID: label.ProjectID,
+		Name:      label.Name,
+		Color:     label.Color,
+		CreatedAt: label.CreatedAt,
+		UpdatedAt: label.UpdatedAt,
+	}
+}
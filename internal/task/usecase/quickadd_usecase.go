@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"strings"
+	"time"
+
+	projectdomain "todo-app/internal/project/domain"
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/quickadd"
+)
+
+// QuickAddTask は一行の入力（F-1 のクイック追加）を解析してタスクを作成し、作成したタスクと解釈を返します
+// 日付は呼び出し元の Timezone で解釈し、+プロジェクトは呼び出し元が参加しているプロジェクトから名前で探します
+// #ラベルがプロジェクトに存在しない場合は作成します
+func (uc *TaskUseCase) QuickAddTask(text, userID string) (*QuickAddResultDTO, error) {
+	loc := uc.userLocation(userID)
+	parsed, err := quickadd.Parse(text, time.Now().In(loc))
+	if err != nil {
+		return nil, fieldError("text", err.Error())
+	}
+
+	out := &QuickAddParsedDTO{
+		Title:      parsed.Title,
+		DueDate:    parsed.Due,
+		DueHasTime: parsed.DueHasTime,
+		Timezone:   loc.String(),
+		Priority:   parsed.Priority,
+		Assignee:   parsed.Assignee,
+		Project:    parsed.Project,
+		Labels:     parsed.Labels,
+		LabelIDs:   []string{},
+	}
+	dto := &TaskDTO{Title: parsed.Title, Priority: parsed.Priority, CreatedBy: userID}
+	if parsed.Due != nil {
+		dto.DueDate = parsed.Due.UTC()
+	}
+
+	if parsed.Project != "" {
+		projectID, err := uc.settingsRepo.FindProjectIDByName(userID, parsed.Project)
+		if err != nil {
+			return nil, fieldError("project", "project not found: "+parsed.Project)
+		}
+		// ラベルを作成する前に、プロジェクトにタスクを追加できるかを確認する
+		if err := uc.policy.Authorize(projectID, userID, projectdomain.PermEditTasks); err != nil {
+			return nil, err
+		}
+		dto.ProjectID, out.ProjectID = projectID, projectID
+	}
+	if parsed.Assignee != "" {
+		assigneeID, err := uc.findAssignee(parsed.Assignee)
+		if err != nil {
+			return nil, err
+		}
+		dto.AssigneeID, out.AssigneeID = assigneeID, assigneeID
+	}
+	if len(parsed.Labels) > 0 && dto.ProjectID == "" {
+		return nil, fieldError("labels", "labels require a +project")
+	}
+
+	// ラベルはタスクの検証が済んでから作成し、タスクを保存できなかった場合は作成したラベルを削除する
+	task, rrule, err := uc.newTask(dto)
+	if err != nil {
+		return nil, err
+	}
+	created, err := uc.quickAddLabels(task, parsed.Labels)
+	if err != nil {
+		uc.discardLabels(created)
+		return nil, err
+	}
+	for _, l := range task.Labels {
+		out.LabelIDs = append(out.LabelIDs, l.ID)
+	}
+	id, err := uc.saveNewTask(task, rrule)
+	if err != nil {
+		uc.discardLabels(created)
+		return nil, err
+	}
+	saved, err := uc.GetTaskByID(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &QuickAddResultDTO{Task: saved, Parsed: out}, nil
+}
+
+// findAssignee は @ で指定された名前・メールアドレス（@ より前の部分でも可）に一致するユーザーの ID を返します
+func (uc *TaskUseCase) findAssignee(mention string) (string, error) {
+	users, err := uc.userRepo.FindAll()
+	if err != nil {
+		return "", err
+	}
+	var matched []string
+	for _, u := range users {
+		local, _, _ := strings.Cut(u.Email, "@")
+		if strings.EqualFold(u.Name, mention) || strings.EqualFold(u.Email, mention) || strings.EqualFold(local, mention) ||
+			strings.EqualFold(strings.ReplaceAll(u.Name, " ", ""), mention) {
+			matched = append(matched, u.ID)
+		}
+	}
+	switch len(matched) {
+	case 0:
+		return "", fieldError("assignee", "user not found: "+mention)
+	case 1:
+		return matched[0], nil
+	}
+	return "", fieldError("assignee", "multiple users match: "+mention)
+}
+
+// quickAddLabels はプロジェクトのラベルを名前で探し（なければ既定の色で作成し）、task に付けます
+// 作成したラベルの ID を返します（エラーの場合もそれまでに作成したものを返します）
+func (uc *TaskUseCase) quickAddLabels(task *domain.Task, names []string) ([]string, error) {
+	var created []string
+	seen := map[string]bool{}
+	for _, name := range names {
+		label, isNew, err := uc.labelNamed(task.ProjectID, name, "")
+		if err != nil {
+			return created, err
+		}
+		if isNew {
+			created = append(created, label.ID)
+		}
+		if seen[label.ID] {
+			continue
+		}
+		seen[label.ID] = true
+		task.Labels = append(task.Labels, domain.TaskLabel{ID: label.ID, Name: label.Name, Color: label.Color})
+	}
+	return created, nil
+}
@@ -0,0 +1,278 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	activitydomain "todo-app/internal/activity/domain"
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/common/utils"
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+
+	"github.com/google/uuid"
+)
+
+// DefaultRecurrenceInterval は繰り返しタスクのスケジューラが実行される既定の間隔です
+const DefaultRecurrenceInterval = time.Minute
+
+// RecurrenceIntervalFromEnv は RECURRENCE_INTERVAL（例: 1m）からスケジューラの実行間隔を読み込みます
+func RecurrenceIntervalFromEnv() time.Duration {
+	return utils.DurationFromEnv("RECURRENCE_INTERVAL", DefaultRecurrenceInterval)
+}
+
+// EditScope は繰り返しタスクの編集範囲です
+type EditScope string
+
+const (
+	// ScopeThis はこの発生のみを編集します
+	ScopeThis EditScope = "this"
+	// ScopeFuture はこの発生と、以降に生成されるすべての発生（系列）を編集します
+	ScopeFuture EditScope = "future"
+)
+
+// ParseEditScope は ?scope= の値を EditScope に変換します（未指定は ScopeThis）
+func ParseEditScope(v string) (EditScope, error) {
+	switch EditScope(v) {
+	case "", ScopeThis:
+		return ScopeThis, nil
+	case ScopeFuture:
+		return ScopeFuture, nil
+	}
+	return "", fieldError("scope", "scope must be this or future")
+}
+
+// normalizeRecurrence は RRULE を検証して正規化します。空文字列はそのまま返します
+func normalizeRecurrence(rrule string) (string, error) {
+	if rrule == "" {
+		return "", nil
+	}
+	rule, err := domain.ParseRecurrenceRule(rrule)
+	if err != nil {
+		return "", fieldError("recurrence", err.Error())
+	}
+	return rule.String(), nil
+}
+
+// startSeries は task を最初の発生とする系列を作成し、task を系列に関連付けます
+func (uc *TaskUseCase) startSeries(task *domain.Task, rrule string) error {
+	series, err := newSeries(task, rrule)
+	if err != nil {
+		return err
+	}
+	return uc.seriesRepo.Create(series)
+}
+
+// newSeries は task を最初の発生とする系列を返し、task を系列に関連付けます（系列は保存しません）
+func newSeries(task *domain.Task, rrule string) (*domain.TaskSeries, error) {
+	if task.DueDate.IsZero() {
+		return nil, fieldError("due_date", "due_date is required for recurring tasks")
+	}
+	series := domain.NewTaskSeries(uuid.New().String(), rrule, task)
+	task.SeriesID, task.Occurrence, task.Recurrence = series.ID, series.Occurrences, series.Rule
+	return series, nil
+}
+
+// applyToSeries は「以降すべて」の編集を系列に反映し、change.Series に設定します（change.Task には編集内容が反映済み）
+// 系列はタスクと同じトランザクションで保存するため、ここでは保存しません
+// RRULE や期限が変わった場合は、この発生を起点に繰り返しを組み直します
+func (uc *TaskUseCase) applyToSeries(change *repository.TaskChange, rrule string, previousDue time.Time) error {
+	task := change.Task
+	if task.SeriesID == "" {
+		if rrule == "" {
+			return nil
+		}
+		series, err := newSeries(task, rrule)
+		if err != nil {
+			return err
+		}
+		change.Series = series
+		return nil
+	}
+
+	series, err := uc.seriesRepo.FindByID(task.SeriesID)
+	if err != nil {
+		return err
+	}
+	change.Series, change.SeriesOccurrences = series, series.Occurrences
+
+	series.Title = task.Title
+	series.Description = task.Description
+	series.Priority = task.Priority
+	series.AssigneeID = task.AssigneeID
+	if rrule == "" {
+		// 繰り返しの解除
+		series.Ended = true
+	} else if rrule != series.Rule || series.Ended || !task.DueDate.Equal(previousDue) {
+		if task.Occurrence != series.Occurrences {
+			return fieldError("scope", "only the latest occurrence can change the schedule of future occurrences")
+		}
+		if task.DueDate.IsZero() {
+			return fieldError("due_date", "due_date is required for recurring tasks")
+		}
+		series.Rule = rrule
+		series.Start = task.DueDate
+		series.LastDue = task.DueDate
+		series.Occurrences = 1
+		series.Ended = false
+		task.Occurrence = 1
+	}
+
+	task.Recurrence = ""
+	if !series.Ended {
+		task.Recurrence = series.Rule
+	}
+	return nil
+}
+
+// completeOccurrence は系列の最新の発生が完了したときに次の発生を生成します
+func (uc *TaskUseCase) completeOccurrence(task *domain.Task, now time.Time) error {
+	series, err := uc.seriesRepo.FindByID(task.SeriesID)
+	if err != nil {
+		return err
+	}
+	if series.Ended || task.Occurrence != series.Occurrences {
+		return nil
+	}
+	// 期限前に完了した場合は次の周期、期限後の場合は現在より後の最初の周期を生成する
+	after := now.UTC()
+	if series.LastDue.After(after) {
+		after = series.LastDue
+	}
+	_, err = uc.generateNext(series, after)
+	if errors.Is(err, apperrors.ErrVersionMismatch) {
+		// スケジューラなどが先に生成済み
+		return nil
+	}
+	return err
+}
+
+// GenerateDueOccurrences は期限を過ぎた（周期が切り替わった）系列の次の発生を生成し、生成した件数を返します
+func (uc *TaskUseCase) GenerateDueOccurrences(now time.Time) (int, error) {
+	series, err := uc.seriesRepo.ListDue(now.UTC())
+	if err != nil {
+		return 0, err
+	}
+	generated := 0
+	for _, s := range series {
+		// アーカイブ済みのプロジェクトでは新しい発生を生成しない
+		if archived, err := uc.settingsRepo.IsArchived(s.ProjectID); err != nil || archived {
+			continue
+		}
+		task, err := uc.generateNext(s, now.UTC())
+		if err != nil {
+			if !errors.Is(err, apperrors.ErrVersionMismatch) {
+				log.Printf("Failed to generate next occurrence for series %s: %v", s.ID, err)
+			}
+			continue
+		}
+		if task != nil {
+			generated++
+		}
+	}
+	return generated, nil
+}
+
+// RunRecurrenceScheduler は ctx がキャンセルされるまで interval ごとに GenerateDueOccurrences を実行します
+func (uc *TaskUseCase) RunRecurrenceScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			n, err := uc.GenerateDueOccurrences(now)
+			if err != nil {
+				log.Printf("Failed to generate recurring tasks: %v", err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("Generated %d recurring tasks", n)
+			}
+		}
+	}
+}
+
+// generateNext は after より後の最初の発生をタスクとして生成します
+// 日時は担当者（未割り当ての場合は作成者）のタイムゾーンで計算します
+// COUNT・UNTIL に達した場合は系列を終了し、nil を返します
+func (uc *TaskUseCase) generateNext(series *domain.TaskSeries, after time.Time) (*domain.Task, error) {
+	rule, err := domain.ParseRecurrenceRule(series.Rule)
+	if err != nil {
+		return nil, err
+	}
+	expected := series.Occurrences
+	loc := uc.locationFor(series)
+
+	next, index, ok := rule.NextAfter(series.Start.In(loc), series.LastDue, series.Occurrences, after)
+	if !ok {
+		series.Ended = true
+		return nil, uc.seriesRepo.Update(series, expected)
+	}
+
+	task := domain.NewTask(uuid.New().String(), series.Title, series.Description, series.ProjectID, series.AssigneeID, next.UTC(), series.Priority, uc.workflowFor(series.ProjectID).Initial, series.CreatedBy)
+	task.SeriesID, task.Occurrence, task.Recurrence = series.ID, index, series.Rule
+	if err := uc.inheritOccurrence(task, series); err != nil {
+		return nil, err
+	}
+	rank, err := uc.bottomRank(task.ProjectID, task.Status)
+	if err != nil {
+		return nil, err
+	}
+	task.Rank = rank
+
+	// 系列を進めるのと発生の登録は同じトランザクションで行い、同じ発生が重複して生成されないようにする
+	series.LastDue = next.UTC()
+	series.Occurrences = index
+	// 系列から自動で生成したタスクは操作者なしで記録する
+	if err := uc.seriesRepo.Advance(series, expected, task, uc.taskEntry(nil, task, activitydomain.ActionCreated, "")); err != nil {
+		return nil, err
+	}
+	indexTask(task)
+	return task, nil
+}
+
+// inheritOccurrence は系列の最新の発生から、系列に保存していない見積もり・ラベル・担当者・カスタムフィールドの値を task に引き継ぎます
+// 「以降すべて」の編集で主担当者が変わった場合は、最新の発生の担当者は引き継がずに系列の担当者のみとします
+func (uc *TaskUseCase) inheritOccurrence(task *domain.Task, series *domain.TaskSeries) error {
+	latest, err := uc.seriesRepo.LatestOccurrence(series.ID)
+	if err != nil || latest == nil {
+		return err
+	}
+	task.Estimate = latest.Estimate
+	task.Labels = latest.Labels
+	task.CustomFields = latest.CustomFields
+	if latest.AssigneeID == series.AssigneeID {
+		task.SetAssignees(latest.AssigneeIDs)
+	}
+	return nil
+}
+
+// locationFor は系列の日時計算に使うタイムゾーンを返します
+func (uc *TaskUseCase) locationFor(series *domain.TaskSeries) *time.Location {
+	userID := series.AssigneeID
+	if userID == "" {
+		userID = series.CreatedBy
+	}
+	return uc.userLocation(userID)
+}
+
+// userLocation はユーザーの Timezone を返します。未設定・不正な場合は UTC です
+func (uc *TaskUseCase) userLocation(userID string) *time.Location {
+	if userID == "" {
+		return time.UTC
+	}
+	user, err := uc.userRepo.FindByID(userID)
+	if err != nil || user.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		log.Printf("Invalid timezone %q for user %s, using UTC", user.Timezone, userID)
+		return time.UTC
+	}
+	return loc
+}
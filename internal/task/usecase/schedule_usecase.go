@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	apperrors "todo-app/internal/common/errors"
+	planningdomain "todo-app/internal/planning/domain"
+	"todo-app/internal/task/domain"
+)
+
+// validateSchedule はタスクを予定するスプリント・マイルストーンを検証します
+// どちらもタスクと同じプロジェクトのものである必要があり、終了したスプリントには新たに予定できません
+// current は変更前のタスクで、変更していない予定先は検証しません（作成時は nil）
+func (uc *TaskUseCase) validateSchedule(projectID, sprintID, milestoneID string, current *domain.Task, prefix string) error {
+	verr := apperrors.NewValidationError()
+	if sprintID != "" && (current == nil || sprintID != current.SprintID) {
+		sprintProject, status, err := uc.settingsRepo.SprintProject(sprintID)
+		if err != nil {
+			return err
+		}
+		switch {
+		case sprintProject == "" || sprintProject != projectID:
+			verr.Add(prefix+"sprint_id", "sprint not found in this project")
+		case status == planningdomain.SprintClosed:
+			verr.Add(prefix+"sprint_id", planningdomain.ErrSprintClosed.Error())
+		}
+	}
+	if milestoneID != "" && (current == nil || milestoneID != current.MilestoneID) {
+		milestoneProject, err := uc.settingsRepo.MilestoneProject(milestoneID)
+		if err != nil {
+			return err
+		}
+		if milestoneProject == "" || milestoneProject != projectID {
+			verr.Add(prefix+"milestone_id", "milestone not found in this project")
+		}
+	}
+	if verr.HasErrors() {
+		return verr
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"errors"
+
+	activitydomain "todo-app/internal/activity/domain"
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/task/domain"
+
+	"github.com/google/uuid"
+)
+
+func (uc *TaskUseCase) CreateSubtask(dto *SubtaskDTO, actorID string) (string, error) {
+	if dto.Title == "" {
+		return "", fieldError("title", "title is required")
+	}
+	if _, err := uc.writableTask(dto.TaskID, actorID); err != nil {
+		return "", err
+	}
+	// Always generate a new UUID for the subtask
+	dto.ID = uuid.New().String()
+	subtask := domain.NewSubtask(dto.ID, dto.Title, dto.TaskID)
+	if err := uc.subtaskRepo.Create(subtask, uc.subtaskEntry(nil, subtask, activitydomain.ActionCreated, actorID)); err != nil {
+		return "", err
+	}
+	return subtask.ID, nil
+}
+
+// ListSubtasks はタスクのサブタスクを表示順に返します
+func (uc *TaskUseCase) ListSubtasks(taskID, actorID string) ([]*SubtaskDTO, error) {
+	if _, err := uc.viewableTask(taskID, actorID); err != nil {
+		return nil, err
+	}
+	return uc.listSubtasks(taskID)
+}
+
+func (uc *TaskUseCase) listSubtasks(taskID string) ([]*SubtaskDTO, error) {
+	subtasks, err := uc.subtaskRepo.ListByTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+	dtos := make([]*SubtaskDTO, len(subtasks))
+	for i, subtask := range subtasks {
+		dtos[i] = toSubtaskDTO(subtask)
+	}
+	return dtos, nil
+}
+
+// UpdateSubtask はサブタスクのタイトルと完了状態を更新します
+func (uc *TaskUseCase) UpdateSubtask(taskID, subtaskID string, req *SubtaskUpdateRequest, actorID string) (*SubtaskDTO, error) {
+	subtask, err := uc.findSubtask(taskID, subtaskID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	before := *subtask
+	if req.Title != nil {
+		if *req.Title == "" {
+			return nil, fieldError("title", "title is required")
+		}
+		subtask.Title = *req.Title
+	}
+	if req.IsComplete != nil {
+		subtask.IsComplete = *req.IsComplete
+	}
+	return uc.saveSubtask(&before, subtask, actorID)
+}
+
+// ToggleSubtask はサブタスクの完了状態を反転します
+func (uc *TaskUseCase) ToggleSubtask(taskID, subtaskID, actorID string) (*SubtaskDTO, error) {
+	subtask, err := uc.findSubtask(taskID, subtaskID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	before := *subtask
+	subtask.IsComplete = !subtask.IsComplete
+	return uc.saveSubtask(&before, subtask, actorID)
+}
+
+// DeleteSubtask はサブタスクを削除します
+func (uc *TaskUseCase) DeleteSubtask(taskID, subtaskID, actorID string) error {
+	subtask, err := uc.findSubtask(taskID, subtaskID, actorID)
+	if err != nil {
+		return err
+	}
+	if err := uc.subtaskRepo.Delete(subtaskID, uc.subtaskEntry(nil, subtask, activitydomain.ActionDeleted, actorID)); err != nil {
+		return err
+	}
+	// 未完了のサブタスクを削除した結果、残りがすべて完了になる場合がある
+	return uc.autoCompleteParent(taskID, actorID)
+}
+
+// ReorderSubtasks はサブタスクを ids の順に並べ替えます
+// ids にはタスクのすべてのサブタスクを重複なく含める必要があります
+func (uc *TaskUseCase) ReorderSubtasks(taskID string, ids []string, actorID string) ([]*SubtaskDTO, error) {
+	if _, err := uc.writableTask(taskID, actorID); err != nil {
+		return nil, err
+	}
+	current, err := uc.listSubtasks(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := make(map[string]bool, len(current))
+	for _, subtask := range current {
+		remaining[subtask.ID] = true
+	}
+	for _, id := range ids {
+		if !remaining[id] {
+			return nil, fieldError("ids", "ids must list each subtask of the task exactly once")
+		}
+		delete(remaining, id)
+	}
+	if len(remaining) > 0 {
+		return nil, fieldError("ids", "ids must list each subtask of the task exactly once")
+	}
+
+	if err := uc.subtaskRepo.Reorder(taskID, ids); err != nil {
+		return nil, err
+	}
+	return uc.listSubtasks(taskID)
+}
+
+// findSubtask は taskID のタスクに属するサブタスクを返します
+func (uc *TaskUseCase) findSubtask(taskID, subtaskID, actorID string) (*domain.Subtask, error) {
+	if _, err := uc.writableTask(taskID, actorID); err != nil {
+		return nil, err
+	}
+	subtask, err := uc.subtaskRepo.FindByID(subtaskID)
+	if err != nil || subtask.TaskID != taskID {
+		return nil, apperrors.ErrNotFound
+	}
+	return subtask, nil
+}
+
+// saveSubtask はサブタスクを保存し、before からの変更を履歴に記録します
+func (uc *TaskUseCase) saveSubtask(before, subtask *domain.Subtask, actorID string) (*SubtaskDTO, error) {
+	if err := uc.subtaskRepo.Update(subtask, uc.subtaskEntry(before, subtask, activitydomain.ActionUpdated, actorID)); err != nil {
+		return nil, err
+	}
+	if subtask.IsComplete {
+		if err := uc.autoCompleteParent(subtask.TaskID, actorID); err != nil {
+			return nil, err
+		}
+	}
+	updated, err := uc.subtaskRepo.FindByID(subtask.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toSubtaskDTO(updated), nil
+}
+
+// autoCompleteParent はプロジェクトで自動完了が有効な場合、
+// サブタスクがすべて完了したタスクをワークフローに従って Done に遷移させます
+func (uc *TaskUseCase) autoCompleteParent(taskID, actorID string) error {
+	task, err := uc.taskRepo.GetByID(taskID)
+	if err != nil {
+		return err
+	}
+	if !task.SubtaskProgress.Complete() || task.Status == domain.StatusDone {
+		return nil
+	}
+	if !uc.workflowFor(task.ProjectID).CanTransition(task.Status, domain.StatusDone) {
+		return nil
+	}
+	enabled, err := uc.settingsRepo.AutoCompleteTasks(task.ProjectID)
+	if err != nil || !enabled {
+		return err
+	}
+
+	dto := toTaskDTO(task)
+	dto.Status = domain.StatusDone
+	_, err = uc.applyUpdate(task, dto, actorID, 0, ScopeThis)
+	// 未完了のブロッカーがある場合は自動完了しない
+	var berr *domain.BlockedError
+	if errors.As(err, &berr) {
+		return nil
+	}
+	return err
+}
+
+// fieldError は1フィールドのみの ValidationError を返します
+func fieldError(field, message string) error {
+	verr := apperrors.NewValidationError()
+	verr.Add(field, message)
+	return verr
+}
+
+func toSubtaskDTO(subtask *domain.Subtask) *SubtaskDTO {
+	return &SubtaskDTO{
+		ID:         subtask.ID,
+		Title:      subtask.Title,
+		TaskID:     subtask.TaskID,
+		IsComplete: subtask.IsComplete,
+		Position:   subtask.Position,
+		CreatedAt:  subtask.CreatedAt,
+		UpdatedAt:  subtask.UpdatedAt,
+	}
+}
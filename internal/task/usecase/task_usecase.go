@@ -1,10 +1,21 @@
 package usecase
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"log"
+	"time"
+
+	activitydomain "todo-app/internal/activity/domain"
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/common/utils"
+	"todo-app/internal/infrastructure"
+	projectdomain "todo-app/internal/project/domain"
 	"todo-app/internal/task/domain"
 	"todo-app/internal/task/repository"
-	"todo-app/internal/infrastructure"
+	userrepo "todo-app/internal/user/repository"
+	"todo-app/pkg/paginator"
 
 	"github.com/google/uuid"
 )
@@ -12,62 +23,321 @@ import (
 var solrClient, _ = infrastructure.NewSolrClient("todoapp")
 
 type TaskUseCase struct {
-	taskRepo    repository.TaskRepository
-	subtaskRepo repository.SubtaskRepository
+	taskRepo       repository.TaskRepository
+	subtaskRepo    repository.SubtaskRepository
+	transitionRepo repository.TaskTransitionRepository
+	settingsRepo   repository.ProjectSettingsRepository
+	dependencyRepo repository.TaskDependencyRepository
+	seriesRepo     repository.TaskSeriesRepository
+	labelRepo      repository.LabelRepository
+	timeRepo       repository.TimeEntryRepository
+	boardRepo      repository.BoardRepository
+	fieldRepo      repository.CustomFieldRepository
+	userRepo       userrepo.UserRepository
+	content        TaskContentCopier
+	activity       ActivityRecorder
+	policy         ProjectPolicy
+	members        MemberLister
+}
+
+// ProjectPolicy はプロジェクトのロールに基づいて操作を許可するかを判定します
+type ProjectPolicy interface {
+	Authorize(projectID, userID string, perm projectdomain.Permission) error
+}
+
+// MemberLister はプロジェクトのメンバーを返します。担当者の検証に使います
+type MemberLister interface {
+	GetMembers(projectID string) ([]*projectdomain.Member, error)
+}
+
+// TaskContentCopier はタスクの複製時にコメントと添付ファイルを複製します
+type TaskContentCopier interface {
+	CopyTaskContent(fromTaskID, toTaskID string, comments, attachments bool) error
+}
+
+func NewTaskUseCase(tr repository.TaskRepository, sr repository.SubtaskRepository, trr repository.TaskTransitionRepository, psr repository.ProjectSettingsRepository, dr repository.TaskDependencyRepository, ser repository.TaskSeriesRepository, lr repository.LabelRepository, ter repository.TimeEntryRepository, br repository.BoardRepository, cfr repository.CustomFieldRepository, ur userrepo.UserRepository, cc TaskContentCopier, ar ActivityRecorder, pp ProjectPolicy, ml MemberLister) *TaskUseCase {
+	return &TaskUseCase{taskRepo: tr, subtaskRepo: sr, transitionRepo: trr, settingsRepo: psr, dependencyRepo: dr, seriesRepo: ser, labelRepo: lr, timeRepo: ter, boardRepo: br, fieldRepo: cfr, userRepo: ur, content: cc, activity: ar, policy: pp, members: ml}
+}
+
+// workflowFor はプロジェクトに適用するワークフローを返します
+// 現状はすべてのプロジェクトで標準ワークフローを使用します
+func (uc *TaskUseCase) workflowFor(projectID string) *domain.Workflow {
+	return domain.DefaultWorkflow()
+}
+
+// checkWritable はアーカイブ済み（読み取り専用）のプロジェクトの場合に ErrProjectArchived を返します
+func (uc *TaskUseCase) checkWritable(projectID string) error {
+	if projectID == "" {
+		return nil
+	}
+	archived, err := uc.settingsRepo.IsArchived(projectID)
+	if err != nil {
+		return err
+	}
+	if archived {
+		return domain.ErrProjectArchived
+	}
+	return nil
+}
+
+// authorizeTask は actorID がタスクに対して perm を行えない場合に ErrForbidden を返します
+// プロジェクトに属さないタスクは作成者と担当者のみ扱えます。作成者と担当者はロールに関係なくタスクを閲覧できます
+func (uc *TaskUseCase) authorizeTask(task *domain.Task, actorID string, perm projectdomain.Permission) error {
+	own := actorID != "" && (task.CreatedBy == actorID || task.HasAssignee(actorID))
+	if task.ProjectID == "" {
+		if own {
+			return nil
+		}
+		return apperrors.ErrForbidden
+	}
+	if own && perm == projectdomain.PermView {
+		return nil
+	}
+	return uc.policy.Authorize(task.ProjectID, actorID, perm)
+}
+
+// AuthorizeTask は actorID がタスクに対して perm を行えるかを判定します
+// タスクが存在しない場合は ErrNotFound、行えない場合は ErrForbidden、閲覧以外でプロジェクトがアーカイブ済みの場合は ErrProjectArchived です
+// コメント・添付ファイルのユースケースから使われます
+func (uc *TaskUseCase) AuthorizeTask(taskID, actorID string, perm projectdomain.Permission) error {
+	task, err := uc.taskRepo.GetByID(taskID)
+	if err != nil {
+		return apperrors.ErrNotFound
+	}
+	if err := uc.authorizeTask(task, actorID, perm); err != nil {
+		return err
+	}
+	if perm == projectdomain.PermView {
+		return nil
+	}
+	return uc.checkWritable(task.ProjectID)
 }
 
-func NewTaskUseCase(tr repository.TaskRepository, sr repository.SubtaskRepository) *TaskUseCase {
-	return &TaskUseCase{taskRepo: tr, subtaskRepo: sr}
+// viewableTask は actorID が閲覧できるタスクを返します。存在しない場合は ErrNotFound、閲覧できない場合は ErrForbidden です
+func (uc *TaskUseCase) viewableTask(taskID, actorID string) (*domain.Task, error) {
+	task, err := uc.taskRepo.GetByID(taskID)
+	if err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	if err := uc.authorizeTask(task, actorID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
+	return task, nil
 }
 
+// writableTask は actorID が編集できるタスクを返します
+// 存在しない場合は ErrNotFound、権限がない場合は ErrForbidden、プロジェクトがアーカイブ済みの場合は ErrProjectArchived です
+func (uc *TaskUseCase) writableTask(taskID, actorID string) (*domain.Task, error) {
+	task, err := uc.taskRepo.GetByID(taskID)
+	if err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	if err := uc.authorizeTask(task, actorID, projectdomain.PermEditTasks); err != nil {
+		return nil, err
+	}
+	if err := uc.checkWritable(task.ProjectID); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// ReindexTasks はタスクを Solr に登録し直します
+// プロジェクトからメンバーを外して担当者を割り当て直したときに呼ばれます
+func (uc *TaskUseCase) ReindexTasks(taskIDs []string) {
+	uc.reindexTasks(taskIDs)
+}
+
+// CreateTask はタスクを作成します。プロジェクトのタスクを作成するには dto.CreatedBy が member 以上である必要があります
 func (uc *TaskUseCase) CreateTask(dto *TaskDTO) (string, error) {
+	task, rrule, err := uc.newTask(dto)
+	if err != nil {
+		return "", err
+	}
+	return uc.saveNewTask(task, rrule)
+}
+
+// newTask は dto を検証して作成するタスクを返します（保存はしません）。rrule は正規化済みの繰り返しの RRULE です
+func (uc *TaskUseCase) newTask(dto *TaskDTO) (*domain.Task, string, error) {
 	// Use provided ID if it exists, otherwise generate a new UUID
 	if dto.ID == "" {
 		dto.ID = uuid.New().String()
-		fmt.Printf("Generated new UUID: %s\n", dto.ID)
-	} else {
-		fmt.Printf("Using provided ID: %s\n", dto.ID)
+	}
+	wf := uc.workflowFor(dto.ProjectID)
+	if dto.Status == "" {
+		dto.Status = wf.Initial
+	}
+	if dto.Priority == "" {
+		dto.Priority = domain.PriorityMedium
+	}
+	if err := validateTaskDTO(dto, wf); err != nil {
+		return nil, "", err
+	}
+	if dto.ProjectID != "" {
+		if err := uc.policy.Authorize(dto.ProjectID, dto.CreatedBy, projectdomain.PermEditTasks); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := uc.checkWritable(dto.ProjectID); err != nil {
+		return nil, "", err
+	}
+	rrule, err := normalizeRecurrence(dto.Recurrence)
+	if err != nil {
+		return nil, "", err
 	}
 	task := domain.NewTask(dto.ID, dto.Title, dto.Description, dto.ProjectID, dto.AssigneeID, dto.DueDate, dto.Priority, dto.Status, dto.CreatedBy)
-	fmt.Printf("Created task with ID: %s\n", task.ID)
-	if err := uc.taskRepo.Create(task); err != nil {
-		fmt.Printf("Error creating task: %v\n", err)
+	task.SetAssignees(assigneesFor(dto, nil))
+	if err := uc.validateAssignees(task.ProjectID, task.AssigneeIDs, nil, assigneeField(dto)); err != nil {
+		return nil, "", err
+	}
+	if err := uc.validateSchedule(task.ProjectID, dto.SprintID, dto.MilestoneID, nil, ""); err != nil {
+		return nil, "", err
+	}
+	task.SprintID, task.MilestoneID = dto.SprintID, dto.MilestoneID
+	task.Estimate = dto.Estimate
+	task.StartDate, task.DurationDays = dto.StartDate, dto.DurationDays
+	if err := uc.attachLabels(task, dto.LabelIDs); err != nil {
+		return nil, "", err
+	}
+	if task.CustomFields, err = uc.customValues(task.ProjectID, dto.CustomFields, nil); err != nil {
+		return nil, "", err
+	}
+	if task.Rank, err = uc.bottomRank(task.ProjectID, task.Status); err != nil {
+		return nil, "", err
+	}
+	return task, rrule, nil
+}
+
+// saveNewTask は newTask で作成したタスクを保存し、Solr に登録します
+func (uc *TaskUseCase) saveNewTask(task *domain.Task, rrule string) (string, error) {
+	if rrule != "" {
+		if err := uc.startSeries(task, rrule); err != nil {
+			return "", err
+		}
+	}
+	if err := uc.taskRepo.Create(task, uc.taskEntry(nil, task, activitydomain.ActionCreated, task.CreatedBy)); err != nil {
 		return "", err
 	}
 	// Solrにも投入
-	solrClient.Add(map[string]interface{}{
-		"id":        task.ID,
-		"type":      "task",
-		"title":     task.Title,
-		"description": task.Description,
-	})
+	indexTask(task)
 	return task.ID, nil
 }
 
-func (uc *TaskUseCase) GetAllTasks() ([]*TaskDTO, error) {
-	tasks, err := uc.taskRepo.GetAll()
+const (
+	// DefaultTaskPageSize は page_size 未指定時の1ページあたりの件数です
+	DefaultTaskPageSize = 50
+	// MaxTaskPageSize は1ページあたりの最大件数です
+	MaxTaskPageSize = 200
+)
+
+// ListTasks は条件に一致するタスクをオフセットまたはカーソルでページングして返します
+func (uc *TaskUseCase) ListTasks(ctx context.Context, req *TaskListRequest) (*TaskListResult, error) {
+	q := req.Query
+	if !repository.IsTaskSortField(q.SortBy) {
+		q.SortBy = repository.DefaultTaskSort
+	}
+
+	pageSize := req.PageSize
+	if pageSize < 1 {
+		pageSize = DefaultTaskPageSize
+	}
+	if pageSize > MaxTaskPageSize {
+		pageSize = MaxTaskPageSize
+	}
+
+	result := &TaskListResult{PageSize: pageSize}
+	if req.CursorMode {
+		q.Limit = pageSize
+		if req.Cursor != "" {
+			cursor, err := decodeCursor(req.Cursor, q)
+			if err != nil {
+				return nil, err
+			}
+			q.Cursor = cursor
+		}
+	} else {
+		limit, offset := paginator.Paginate(req.Page, pageSize)
+		q.Limit, q.Offset = limit, offset
+		result.Page = offset/limit + 1
+	}
+
+	page, err := uc.taskRepo.List(ctx, q)
 	if err != nil {
 		return nil, err
 	}
 
-	dtos := make([]*TaskDTO, len(tasks))
-	for i, task := range tasks {
-		dtos[i] = &TaskDTO{
-			ID:          task.ID,
-			Title:       task.Title,
-			Description: task.Description,
-			ProjectID:   task.ProjectID,
-			AssigneeID:  task.AssigneeID,
-			DueDate:     task.DueDate,
-			Priority:    task.Priority,
-			Status:      task.Status,
-			CreatedBy:   task.CreatedBy,
+	result.Total = page.Total
+	result.Tasks = make([]*TaskDTO, len(page.Tasks))
+	for i, task := range page.Tasks {
+		result.Tasks[i] = toTaskDTO(task)
+	}
+
+	if !req.CursorMode {
+		result.HasNext = q.Offset+len(page.Tasks) < page.Total
+		result.HasPrev = result.Page > 1
+		return result, nil
+	}
+
+	backward := q.Cursor != nil && q.Cursor.Backward
+	if backward {
+		result.HasPrev, result.HasNext = page.HasMore, true
+	} else {
+		result.HasNext, result.HasPrev = page.HasMore, q.Cursor != nil
+	}
+	if len(page.Tasks) > 0 {
+		first, last := page.Tasks[0], page.Tasks[len(page.Tasks)-1]
+		if result.HasNext {
+			result.NextCursor = encodeCursor(cursorPayload{Sort: q.SortBy, Desc: q.SortDesc, Value: repository.TaskSortValue(last, q.SortBy), ID: last.ID})
+		}
+		if result.HasPrev {
+			result.PrevCursor = encodeCursor(cursorPayload{Sort: q.SortBy, Desc: q.SortDesc, Value: repository.TaskSortValue(first, q.SortBy), ID: first.ID, Backward: true})
 		}
 	}
-	return dtos, nil
+	return result, nil
+}
+
+// SummarizeTasks は条件に一致するタスクの件数を集計して返します
+// ステータスと優先度は該当が 0 件でもキーを含めます
+func (uc *TaskUseCase) SummarizeTasks(ctx context.Context, q repository.TaskQuery) (*TaskSummaryDTO, error) {
+	summary, err := uc.taskRepo.Summary(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := &TaskSummaryDTO{
+		Total:      summary.Total,
+		Incomplete: summary.Incomplete,
+		Overdue:    summary.Overdue,
+		ByStatus:   map[string]int{},
+		ByPriority: map[string]int{},
+		ByAssignee: map[string]int{},
+	}
+	for _, status := range uc.workflowFor(q.ProjectID).States {
+		dto.ByStatus[status] = 0
+	}
+	for _, priority := range []string{domain.PriorityHigh, domain.PriorityMedium, domain.PriorityLow} {
+		dto.ByPriority[priority] = 0
+	}
+	for status, n := range summary.ByStatus {
+		dto.ByStatus[status] += n
+	}
+	for priority, n := range summary.ByPriority {
+		dto.ByPriority[priority] += n
+	}
+	for assigneeID, n := range summary.ByAssignee {
+		if assigneeID == "" {
+			dto.Unassigned += n
+			continue
+		}
+		dto.ByAssignee[assigneeID] = n
+	}
+	return dto, nil
 }
 
-func (uc *TaskUseCase) GetTasksByProject(projectID string) ([]*TaskDTO, error) {
+// GetTasksByProject はプロジェクトのタスクを返します。actorID はプロジェクトの閲覧権限が必要です
+func (uc *TaskUseCase) GetTasksByProject(projectID, actorID string) ([]*TaskDTO, error) {
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
 	tasks, err := uc.taskRepo.ListByProject(projectID)
 	if err != nil {
 		return nil, err
@@ -75,57 +345,315 @@ func (uc *TaskUseCase) GetTasksByProject(projectID string) ([]*TaskDTO, error) {
 
 	dtos := make([]*TaskDTO, len(tasks))
 	for i, task := range tasks {
-		dtos[i] = &TaskDTO{
-			ID:          task.ID,
-			Title:       task.Title,
-			Description: task.Description,
-			ProjectID:   task.ProjectID,
-			AssigneeID:  task.AssigneeID,
-			DueDate:     task.DueDate,
-			Priority:    task.Priority,
-			Status:      task.Status,
-			CreatedBy:   task.CreatedBy,
-		}
+		dtos[i] = toTaskDTO(task)
 	}
 	return dtos, nil
 }
 
-func (uc *TaskUseCase) GetTaskByID(id string) (*TaskDTO, error) {
-	task, err := uc.taskRepo.GetByID(id)
+// GetTaskByID はタスクを返します。actorID が閲覧できない場合は ErrForbidden です
+func (uc *TaskUseCase) GetTaskByID(id, actorID string) (*TaskDTO, error) {
+	task, err := uc.viewableTask(id, actorID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &TaskDTO{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		ProjectID:   task.ProjectID,
-		AssigneeID:  task.AssigneeID,
-		DueDate:     task.DueDate,
-		Priority:    task.Priority,
-		Status:      task.Status,
-		CreatedBy:   task.CreatedBy,
-	}, nil
+	return toTaskDTO(task), nil
 }
 
-func (uc *TaskUseCase) CreateSubtask(dto *SubtaskDTO) (string, error) {
-	// Always generate a new UUID for the subtask
-	dto.ID = uuid.New().String()
-	subtask := domain.NewSubtask(dto.ID, dto.Title, dto.TaskID)
-	if err := uc.subtaskRepo.Create(subtask); err != nil {
-		return "", err
+// RestoreTask はゴミ箱内のタスクを元に戻します（削除の取り消し）
+func (uc *TaskUseCase) RestoreTask(id, actorID string) (*TaskDTO, error) {
+	deleted, err := uc.taskRepo.FindDeleted(id)
+	if err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	if err := uc.authorizeTask(deleted, actorID, projectdomain.PermEditTasks); err != nil {
+		return nil, err
 	}
-	return subtask.ID, nil
+	if err := uc.taskRepo.Restore(id, uc.taskEntry(nil, deleted, activitydomain.ActionRestored, actorID)); err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	return uc.GetTaskByID(id, actorID)
 }
 
-func (uc *TaskUseCase) DeleteTask(id string) error {
+// DeleteTask はタスクをゴミ箱に移動します。version が 0 以外の場合は一致する場合のみ削除します
+// ゴミ箱内のタスクは保持期間の経過後に TrashUseCase の purger によって物理削除されます
+func (uc *TaskUseCase) DeleteTask(id string, version int, actorID string) error {
 	// First check if task exists
-	_, err := uc.taskRepo.GetByID(id)
+	task, err := uc.writableTask(id, actorID)
 	if err != nil {
 		return err
 	}
 
 	// Delete the task
-	return uc.taskRepo.Delete(id)
+	// 添付ファイルはゴミ箱から復元できるように残し、物理削除のときに本体を削除する（TrashUseCase.Purge）
+	return uc.taskRepo.Delete(id, version, uc.taskEntry(nil, task, activitydomain.ActionDeleted, actorID))
+}
+
+// UpdateTask はタスクの編集可能なフィールドを dto の内容で置き換えます (PUT)
+// version は If-Match で指定されたバージョンで、0 の場合は検証しません
+// 繰り返しタスクで scope が ScopeFuture の場合は、以降の発生にも反映します
+func (uc *TaskUseCase) UpdateTask(id string, dto *TaskDTO, actorID string, version int, scope EditScope) (*TaskDTO, error) {
+	task, err := uc.taskRepo.GetByID(id)
+	if err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	return uc.applyUpdate(task, dto, actorID, version, scope)
+}
+
+// PatchTask は JSON Merge Patch (RFC 7386) をタスクに適用します (PATCH)
+func (uc *TaskUseCase) PatchTask(id string, patch []byte, actorID string, version int, scope EditScope) (*TaskDTO, error) {
+	task, err := uc.taskRepo.GetByID(id)
+	if err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+
+	current, err := json.Marshal(toTaskDTO(task))
+	if err != nil {
+		return nil, err
+	}
+	merged, err := utils.MergePatch(current, patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrInvalidInput, err)
+	}
+	var dto TaskDTO
+	if err := json.Unmarshal(merged, &dto); err != nil {
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrInvalidInput, err)
+	}
+	return uc.applyUpdate(task, &dto, actorID, version, scope)
+}
+
+// TransitionTask はワークフローに従ってタスクのステータスを to に遷移させます
+func (uc *TaskUseCase) TransitionTask(id, to, actorID string, version int) (*TaskDTO, error) {
+	task, err := uc.taskRepo.GetByID(id)
+	if err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	dto := toTaskDTO(task)
+	dto.Status = to
+	return uc.applyUpdate(task, dto, actorID, version, ScopeThis)
+}
+
+// GetTransitions はタスクのステータス遷移履歴を古い順に返します
+func (uc *TaskUseCase) GetTransitions(id, actorID string) ([]*domain.TaskTransition, error) {
+	if _, err := uc.viewableTask(id, actorID); err != nil {
+		return nil, err
+	}
+	return uc.transitionRepo.ListByTask(id)
+}
+
+// GetWorkflow はタスクに適用されるワークフローと現在のステータスから遷移可能な先を返します
+func (uc *TaskUseCase) GetWorkflow(id, actorID string) (*WorkflowDTO, error) {
+	task, err := uc.viewableTask(id, actorID)
+	if err != nil {
+		return nil, err
+	}
+	wf := uc.workflowFor(task.ProjectID)
+	return &WorkflowDTO{
+		Workflow: wf,
+		Current:  task.Status,
+		Allowed:  wf.AllowedTransitions(task.Status),
+	}, nil
+}
+
+// applyUpdate は検証済みの dto をタスクに反映し、保存と Solr の再インデックスを行います
+// ステータスが変わる場合はワークフローと未完了のブロッカーで遷移を検証し、履歴を記録します
+// 繰り返しタスクが Done になった場合は次の発生を生成します
+// ID・作成者・プロジェクトは変更しません
+func (uc *TaskUseCase) applyUpdate(task *domain.Task, dto *TaskDTO, actorID string, version int, scope EditScope) (*TaskDTO, error) {
+	if err := uc.authorizeTask(task, actorID, projectdomain.PermEditTasks); err != nil {
+		return nil, err
+	}
+	if version != 0 && version != task.Version {
+		return nil, apperrors.ErrVersionMismatch
+	}
+	if err := uc.checkWritable(task.ProjectID); err != nil {
+		return nil, err
+	}
+
+	wf := uc.workflowFor(task.ProjectID)
+	if err := validateTaskDTO(dto, wf); err != nil {
+		return nil, err
+	}
+
+	rrule, err := normalizeRecurrence(dto.Recurrence)
+	if err != nil {
+		return nil, err
+	}
+	if scope != ScopeFuture && rrule != "" && rrule != task.Recurrence {
+		return nil, fieldError("recurrence", "recurrence can only be changed with scope=future")
+	}
+
+	before := taskValues(task)
+	fromStatus := task.Status
+	if dto.Status != fromStatus {
+		if err := wf.Validate(fromStatus, dto.Status); err != nil {
+			return nil, err
+		}
+		if err := uc.checkBlockers(task.ID, dto.Status); err != nil {
+			return nil, err
+		}
+	}
+
+	// 系列への反映では変更前の期日と比べる
+	previousDue := task.DueDate
+	task.Title = dto.Title
+	task.Description = dto.Description
+	task.DueDate = dto.DueDate
+	task.Priority = dto.Priority
+	task.Status = dto.Status
+	assignees := assigneesFor(dto, task)
+	if err := uc.validateAssignees(task.ProjectID, assignees, task.AssigneeIDs, assigneeField(dto)); err != nil {
+		return nil, err
+	}
+	if err := uc.validateSchedule(task.ProjectID, dto.SprintID, dto.MilestoneID, task, ""); err != nil {
+		return nil, err
+	}
+	if dto.CustomFields != nil {
+		values, err := uc.customValues(task.ProjectID, dto.CustomFields, task.CustomFields)
+		if err != nil {
+			return nil, err
+		}
+		task.CustomFields = values
+	}
+	task.SetAssignees(assignees)
+	task.SprintID, task.MilestoneID = dto.SprintID, dto.MilestoneID
+	task.Estimate = dto.Estimate
+	task.StartDate, task.DurationDays = dto.StartDate, dto.DurationDays
+	task.UpdatedAt = time.Now()
+
+	// 系列とステータスの遷移履歴はタスクの更新と同じトランザクションで保存する
+	change := &repository.TaskChange{Task: task, ReplaceAssignees: true, ReplaceCustomFields: true}
+	if scope == ScopeFuture {
+		if err := uc.applyToSeries(change, rrule, previousDue); err != nil {
+			return nil, err
+		}
+	}
+	change.Activity = uc.taskEntry(before, task, activitydomain.ActionUpdated, actorID)
+	if task.Status != fromStatus {
+		change.Transition = domain.NewTaskTransition(uuid.New().String(), task.ID, fromStatus, task.Status, actorID)
+	}
+	if err := uc.applyChange(change); err != nil {
+		return nil, err
+	}
+	if task.Status != fromStatus {
+		if task.Status == domain.StatusDone && task.SeriesID != "" {
+			// タスクの更新は完了しているため、次の発生の生成に失敗してもエラーにしない
+			if err := uc.completeOccurrence(task, time.Now()); err != nil {
+				log.Printf("Failed to generate next occurrence of task %s: %v", task.ID, err)
+			}
+		}
+	}
+	indexTask(task)
+	return toTaskDTO(task), nil
+}
+
+// applyChange は1件のタスクの変更を1つのトランザクションで適用します
+func (uc *TaskUseCase) applyChange(change *repository.TaskChange) error {
+	errs, err := uc.taskRepo.ApplyChanges(context.Background(), []*repository.TaskChange{change}, true)
+	if err != nil {
+		return err
+	}
+	return errs[0]
+}
+
+// validateTaskDTO は作成・更新内容をフィールド単位で検証します
+func validateTaskDTO(dto *TaskDTO, wf *domain.Workflow) error {
+	verr := apperrors.NewValidationError()
+	if dto.Title == "" {
+		verr.Add("title", "title is required")
+	}
+	if err := domain.ValidatePriority(dto.Priority); err != nil {
+		verr.Add("priority", err.Error())
+	}
+	if !wf.HasState(dto.Status) {
+		verr.Add("status", domain.ErrInvalidStatus.Error())
+	}
+	if err := domain.ValidateEstimate(dto.Estimate); err != nil {
+		verr.Add("estimate", err.Error())
+	}
+	switch err := domain.ValidateSchedule(dto.StartDate, dto.DueDate, dto.DurationDays); err {
+	case domain.ErrInvalidDuration:
+		verr.Add("duration_days", err.Error())
+	case domain.ErrStartAfterDue:
+		verr.Add("start_date", err.Error())
+	}
+	if verr.HasErrors() {
+		return verr
+	}
+	return nil
+}
+
+// indexTask はタスクを Solr に登録します（同じ ID なら上書き）
+func indexTask(task *domain.Task) {
+	solrClient.Add(solrTaskDoc(task))
+}
+
+// indexTasks は複数のタスクを1回のリクエストで Solr に登録します
+func indexTasks(tasks []*domain.Task) {
+	docs := make([]map[string]interface{}, 0, len(tasks))
+	for _, task := range tasks {
+		docs = append(docs, solrTaskDoc(task))
+	}
+	if err := solrClient.AddAll(docs); err != nil {
+		log.Printf("Failed to index %d tasks: %v", len(docs), err)
+	}
+}
+
+func solrTaskDoc(task *domain.Task) map[string]interface{} {
+	labels := make([]string, 0, len(task.Labels))
+	for _, l := range task.Labels {
+		labels = append(labels, l.Name)
+	}
+	doc := map[string]interface{}{
+		"id":          task.ID,
+		"type":        "task",
+		"title":       task.Title,
+		"description": task.Description,
+		"labels":      labels,
+	}
+	// カスタムフィールドは型に応じた動的フィールド（cf_<key>_s / _d / _ss）に登録する
+	for key, value := range task.CustomFields {
+		doc[domain.CustomFieldSolrName(key, value)] = value
+	}
+	return doc
+}
+
+func toTaskDTO(task *domain.Task) *TaskDTO {
+	return &TaskDTO{
+		ID:              task.ID,
+		Title:           task.Title,
+		Description:     task.Description,
+		ProjectID:       task.ProjectID,
+		AssigneeID:      task.AssigneeID,
+		AssigneeIDs:     task.AssigneeIDs,
+		DueDate:         task.DueDate,
+		Priority:        task.Priority,
+		Status:          task.Status,
+		CreatedBy:       task.CreatedBy,
+		CreatedAt:       task.CreatedAt,
+		UpdatedAt:       task.UpdatedAt,
+		Version:         task.Version,
+		SubtaskProgress: task.SubtaskProgress,
+		SeriesID:        task.SeriesID,
+		Occurrence:      task.Occurrence,
+		Recurrence:      task.Recurrence,
+		Labels:          task.Labels,
+		Estimate:        task.Estimate,
+		TimeSpent:       task.TimeSpent,
+		SprintID:        task.SprintID,
+		MilestoneID:     task.MilestoneID,
+		StartDate:       task.StartDate,
+		DurationDays:    task.DurationDays,
+		Rank:            task.Rank,
+		CustomFields:    customFieldsOf(task),
+	}
+}
+
+// customFieldsOf はタスクのカスタムフィールドの値を返します（値がない場合は空のマップ）
+func customFieldsOf(task *domain.Task) map[string]interface{} {
+	values := make(map[string]interface{}, len(task.CustomFields))
+	for key, value := range task.CustomFields {
+		values[key] = value
+	}
+	return values
 }
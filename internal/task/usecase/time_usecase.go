@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+
+	"github.com/google/uuid"
+)
+
+// StartTimer はタスクの作業時間の計測を始めます
+// タイマーはユーザーごとに1つで、計測中のタイマーがある場合は *domain.TimerRunningError を返します
+func (uc *TaskUseCase) StartTimer(taskID, userID string) (*domain.TimeEntry, error) {
+	if _, err := uc.writableTask(taskID, userID); err != nil {
+		return nil, err
+	}
+	if running, err := uc.timeRepo.FindRunning(userID); err == nil {
+		return nil, &domain.TimerRunningError{TaskID: running.TaskID, EntryID: running.ID}
+	}
+	entry := domain.StartTimer(uuid.New().String(), taskID, userID, time.Now())
+	if err := uc.timeRepo.Create(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// StopTimer はタスクで計測中のユーザーのタイマーを止め、作業時間を確定します
+func (uc *TaskUseCase) StopTimer(taskID, userID string, req *TimerStopRequest) (*domain.TimeEntry, error) {
+	if _, err := uc.taskRepo.GetByID(taskID); err != nil {
+		return nil, apperrors.ErrNotFound
+	}
+	entry, err := uc.timeRepo.FindRunning(userID)
+	if err != nil || entry.TaskID != taskID {
+		return nil, domain.ErrTimerNotRunning
+	}
+	if req.Note != "" {
+		if err := validateNote(req.Note); err != nil {
+			return nil, fieldError("note", err.Error())
+		}
+		entry.Note = req.Note
+	}
+	entry.Stop(time.Now())
+	if err := uc.timeRepo.Update(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// CurrentTimer はユーザーの計測中のタイマーを返します。ない場合は domain.ErrTimerNotRunning です
+func (uc *TaskUseCase) CurrentTimer(userID string) (*domain.TimeEntry, error) {
+	entry, err := uc.timeRepo.FindRunning(userID)
+	if err != nil {
+		return nil, domain.ErrTimerNotRunning
+	}
+	return entry, nil
+}
+
+// ListTimeEntries はタスクの作業時間の記録を新しい順に返します（計測中のタイマーを含む）
+func (uc *TaskUseCase) ListTimeEntries(taskID, actorID string) ([]*domain.TimeEntry, error) {
+	if _, err := uc.viewableTask(taskID, actorID); err != nil {
+		return nil, err
+	}
+	return uc.timeRepo.ListByTask(taskID)
+}
+
+// LogTime は作業時間を手入力で記録します
+func (uc *TaskUseCase) LogTime(taskID, userID string, req *TimeEntryRequest) (*domain.TimeEntry, error) {
+	if _, err := uc.writableTask(taskID, userID); err != nil {
+		return nil, err
+	}
+	verr := apperrors.NewValidationError()
+	minutes, note := 0, ""
+	if req.Minutes == nil {
+		verr.Add("minutes", "minutes is required")
+	} else if err := domain.ValidateMinutes(*req.Minutes); err != nil {
+		verr.Add("minutes", err.Error())
+	} else {
+		minutes = *req.Minutes
+	}
+	if req.Note != nil {
+		if err := validateNote(*req.Note); err != nil {
+			verr.Add("note", err.Error())
+		}
+		note = *req.Note
+	}
+	if verr.HasErrors() {
+		return nil, verr
+	}
+
+	startedAt := time.Now().Add(-time.Duration(minutes) * time.Minute)
+	if req.StartedAt != nil {
+		startedAt = *req.StartedAt
+	}
+	entry := domain.NewManualTimeEntry(uuid.New().String(), taskID, userID, startedAt, minutes, note)
+	if err := uc.timeRepo.Create(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// UpdateTimeEntry は停止済みの記録の作業時間・メモ・開始日時を更新します。記録したユーザーのみ更新できます
+func (uc *TaskUseCase) UpdateTimeEntry(taskID, entryID, userID string, req *TimeEntryRequest) (*domain.TimeEntry, error) {
+	entry, err := uc.ownTimeEntry(taskID, entryID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if entry.Running() {
+		return nil, domain.ErrTimeEntryRunning
+	}
+
+	verr := apperrors.NewValidationError()
+	if req.Minutes != nil {
+		if err := domain.ValidateMinutes(*req.Minutes); err != nil {
+			verr.Add("minutes", err.Error())
+		}
+		entry.Minutes = *req.Minutes
+	}
+	if req.Note != nil {
+		if err := validateNote(*req.Note); err != nil {
+			verr.Add("note", err.Error())
+		}
+		entry.Note = *req.Note
+	}
+	if verr.HasErrors() {
+		return nil, verr
+	}
+	if req.StartedAt != nil {
+		entry.StartedAt = *req.StartedAt
+	}
+	endedAt := entry.StartedAt.Add(time.Duration(entry.Minutes) * time.Minute)
+	entry.EndedAt = &endedAt
+	entry.UpdatedAt = time.Now()
+	if err := uc.timeRepo.Update(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// DeleteTimeEntry は記録を削除します（計測中のタイマーの破棄にも使います）。記録したユーザーのみ削除できます
+func (uc *TaskUseCase) DeleteTimeEntry(taskID, entryID, userID string) error {
+	if _, err := uc.ownTimeEntry(taskID, entryID, userID); err != nil {
+		return err
+	}
+	if err := uc.timeRepo.Delete(entryID); err != nil {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// SummarizeTime は停止済みの記録をユーザー・プロジェクト・タスクごとに集計し、タスクの見積もりと並べて返します
+// q.Tasks.VisibleTo を指定すると、呼び出し元が閲覧できるタスクの記録のみが対象になります
+func (uc *TaskUseCase) SummarizeTime(ctx context.Context, q repository.TimeQuery) (*TimeSummaryDTO, error) {
+	summary, err := uc.timeRepo.Summary(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	dto := &TimeSummaryDTO{
+		TotalMinutes: summary.TotalMinutes,
+		Estimate:     summary.Estimate,
+		ByUser:       summary.ByUser,
+		ByProject:    summary.ByProject,
+		ByTask:       make([]*TaskTimeDTO, 0, len(summary.ByTask)),
+	}
+	for _, t := range summary.ByTask {
+		tt := &TaskTimeDTO{TaskID: t.TaskID, Title: t.Title, ProjectID: t.ProjectID, Estimate: t.Estimate, Minutes: t.Minutes}
+		if t.Estimate > 0 {
+			tt.Remaining = t.Estimate - t.Minutes
+		}
+		dto.ByTask = append(dto.ByTask, tt)
+	}
+	return dto, nil
+}
+
+// ownTimeEntry はタスクの記録のうち、userID が記録したものを返します
+func (uc *TaskUseCase) ownTimeEntry(taskID, entryID, userID string) (*domain.TimeEntry, error) {
+	if _, err := uc.writableTask(taskID, userID); err != nil {
+		return nil, err
+	}
+	entry, err := uc.timeRepo.FindByID(entryID)
+	if err != nil || entry.TaskID != taskID {
+		return nil, apperrors.ErrNotFound
+	}
+	if entry.UserID != userID {
+		return nil, apperrors.ErrForbidden
+	}
+	return entry, nil
+}
+
+func validateNote(note string) error {
+	if utf8.RuneCountInString(note) > domain.MaxTimeEntryNoteLength {
+		return domain.ErrNoteTooLong
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package usecase
+
+import (
+	"sort"
+	"time"
+
+	activitydomain "todo-app/internal/activity/domain"
+	apperrors "todo-app/internal/common/errors"
+	projectdomain "todo-app/internal/project/domain"
+	"todo-app/internal/task/domain"
+)
+
+const dateLayout = "2006-01-02"
+
+// formatDay は日付を YYYY-MM-DD で返します。ゼロ値は空文字列です
+func formatDay(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(dateLayout)
+}
+
+// Timeline はプロジェクトのタスクの期間と、依存関係から計算したクリティカルパスを返します
+// 期間は開始日・日数・期限から決めます（domain.Task.Span）。プロジェクトの終了日を超えるタスクには past_project_end を付けます
+func (uc *TaskUseCase) Timeline(projectID, actorID string) (*TimelineDTO, error) {
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermView); err != nil {
+		return nil, err
+	}
+	tasks, err := uc.taskRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := uc.dependencyRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	projectStart, projectEnd, err := uc.settingsRepo.ProjectDates(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	timeline := &TimelineDTO{
+		ProjectID:    projectID,
+		StartDate:    formatDay(projectStart),
+		EndDate:      formatDay(projectEnd),
+		Bars:         []*TimelineBarDTO{},
+		Dependencies: []*DependencyEdgeDTO{},
+		CriticalPath: []string{},
+		Unscheduled:  []*TaskRefDTO{},
+	}
+	byID := map[string]*domain.Task{}
+	var items []domain.ScheduleItem
+	for _, task := range tasks {
+		if task.Status == domain.StatusCanceled {
+			continue
+		}
+		start, end, ok := task.Span()
+		if !ok {
+			timeline.Unscheduled = append(timeline.Unscheduled, toTaskRefDTO(task))
+			continue
+		}
+		byID[task.ID] = task
+		items = append(items, domain.ScheduleItem{ID: task.ID, Start: start, End: end})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].Start.Equal(items[j].Start) {
+			return items[i].Start.Before(items[j].Start)
+		}
+		return byID[items[i].ID].Title < byID[items[j].ID].Title
+	})
+
+	results, path, finish := domain.Schedule(items, deps)
+	if path != nil {
+		timeline.CriticalPath = path
+	}
+	timeline.ProjectedEnd = formatDay(finish)
+	deadline := domain.Day(projectEnd)
+	if !projectEnd.IsZero() && finish.After(deadline) {
+		timeline.OverrunDays = domain.DaysBetween(deadline, finish)
+	}
+
+	for _, item := range items {
+		task, r := byID[item.ID], results[item.ID]
+		timeline.Bars = append(timeline.Bars, &TimelineBarDTO{
+			TaskID:         task.ID,
+			Title:          task.Title,
+			Status:         task.Status,
+			AssigneeID:     task.AssigneeID,
+			Start:          formatDay(item.Start),
+			End:            formatDay(item.End),
+			DurationDays:   domain.DaysBetween(item.Start, item.End) + 1,
+			EarliestStart:  formatDay(r.EarliestStart),
+			EarliestFinish: formatDay(r.EarliestFinish),
+			LatestStart:    formatDay(r.LatestStart),
+			LatestFinish:   formatDay(r.LatestFinish),
+			ShiftDays:      domain.DaysBetween(item.Start, r.EarliestStart),
+			Slack:          r.Slack,
+			Critical:       r.Critical,
+			PastProjectEnd: !projectEnd.IsZero() && r.EarliestFinish.After(deadline),
+		})
+	}
+	for _, dep := range deps {
+		if byID[dep.TaskID] != nil && byID[dep.DependsOnID] != nil {
+			timeline.Dependencies = append(timeline.Dependencies, &DependencyEdgeDTO{From: dep.DependsOnID, To: dep.TaskID})
+		}
+	}
+	return timeline, nil
+}
+
+// Reschedule はタスクを req.StartDate に開始するように移します。期限が設定されていれば同じ日数だけずらします
+// req.Cascade が true の場合は、依存元のタスクのうち依存先の終了日以前に始まるものを翌日に始まるように後ろにずらし、
+// ずらしたタスクの依存元も同様に（推移的に）ずらします。Done / Canceled のタスクはずらしません
+// version が 0 以外の場合は、タスクのバージョンと一致しなければ ErrVersionMismatch を返します
+func (uc *TaskUseCase) Reschedule(taskID string, req *RescheduleRequest, actorID string, version int) (*RescheduleResultDTO, error) {
+	task, err := uc.writableTask(taskID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if version != 0 && version != task.Version {
+		return nil, apperrors.ErrVersionMismatch
+	}
+	start, err := time.Parse(dateLayout, req.StartDate)
+	if err != nil {
+		return nil, fieldError("start_date", "start_date must be YYYY-MM-DD")
+	}
+	before := taskValues(task)
+
+	if oldStart, _, ok := task.Span(); ok {
+		task.Shift(domain.DaysBetween(oldStart, start))
+	}
+	task.StartDate = start
+	if req.DurationDays != nil {
+		task.DurationDays = *req.DurationDays
+	}
+	switch err := domain.ValidateSchedule(task.StartDate, task.DueDate, task.DurationDays); err {
+	case domain.ErrInvalidDuration:
+		return nil, fieldError("duration_days", err.Error())
+	case domain.ErrStartAfterDue:
+		return nil, fieldError("start_date", err.Error())
+	}
+	task.UpdatedAt = time.Now()
+	if err := uc.taskRepo.Update(task, uc.taskEntry(before, task, activitydomain.ActionUpdated, actorID)); err != nil {
+		return nil, err
+	}
+
+	result := &RescheduleResultDTO{Task: toTaskDTO(task), Shifted: []*ShiftedTaskDTO{}, Skipped: []string{}}
+	if req.Cascade {
+		if err := uc.cascadeShift(task, actorID, result); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// cascadeShift は root の依存元を、依存先の終了日の翌日以降に始まるように後ろにずらします
+// 同じタスクが複数の依存先からずらされる場合は、最も遅い依存先に合わせます
+func (uc *TaskUseCase) cascadeShift(root *domain.Task, actorID string, result *RescheduleResultDTO) error {
+	shifted := map[string]*ShiftedTaskDTO{}
+	skipped := map[string]bool{}
+	queue := []*domain.Task{root}
+	for len(queue) > 0 {
+		blocker := queue[0]
+		queue = queue[1:]
+		_, blockerEnd, ok := blocker.Span()
+		if !ok {
+			continue
+		}
+		deps, err := uc.dependencyRepo.ListByTask(blocker.ID)
+		if err != nil {
+			return err
+		}
+		for _, dep := range deps {
+			if dep.DependsOnID != blocker.ID || skipped[dep.TaskID] {
+				continue
+			}
+			dependent, err := uc.taskRepo.GetByID(dep.TaskID)
+			if err != nil || dependent.Status == domain.StatusDone || dependent.Status == domain.StatusCanceled {
+				continue
+			}
+			start, _, ok := dependent.Span()
+			if !ok {
+				continue
+			}
+			days := domain.DaysBetween(start, blockerEnd.AddDate(0, 0, 1))
+			if days <= 0 {
+				continue
+			}
+			if uc.authorizeTask(dependent, actorID, projectdomain.PermEditTasks) != nil || uc.checkWritable(dependent.ProjectID) != nil {
+				skipped[dependent.ID] = true
+				result.Skipped = append(result.Skipped, dependent.ID)
+				continue
+			}
+
+			before := taskValues(dependent)
+			dependent.Shift(days)
+			dependent.UpdatedAt = time.Now()
+			if err := uc.taskRepo.Update(dependent, uc.taskEntry(before, dependent, activitydomain.ActionUpdated, actorID)); err != nil {
+				return err
+			}
+			newStart, newEnd, _ := dependent.Span()
+			if s := shifted[dependent.ID]; s != nil {
+				s.Start, s.End, s.ShiftDays = formatDay(newStart), formatDay(newEnd), s.ShiftDays+days
+			} else {
+				s = &ShiftedTaskDTO{TaskID: dependent.ID, Title: dependent.Title, Start: formatDay(newStart), End: formatDay(newEnd), ShiftDays: days}
+				shifted[dependent.ID] = s
+				result.Shifted = append(result.Shifted, s)
+			}
+			queue = append(queue, dependent)
+		}
+	}
+	return nil
+}
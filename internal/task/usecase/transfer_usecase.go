@@ -0,0 +1,296 @@
+package usecase
+
+import (
+	"log"
+	"time"
+
+	activitydomain "todo-app/internal/activity/domain"
+	apperrors "todo-app/internal/common/errors"
+	projectdomain "todo-app/internal/project/domain"
+	"todo-app/internal/task/domain"
+	"todo-app/internal/task/repository"
+
+	"github.com/google/uuid"
+)
+
+// moveToProject はタスクを別のプロジェクトに移します。POST /tasks/{taskID}/move で project_id を指定した場合に呼ばれます
+// サブタスク・コメント・添付ファイル・作業時間・依存関係はタスクとともに移ります
+// プロジェクトごとのデータは移動先に合わせて付け替え、付け替えられないものは外します（remapTask を参照）
+func (uc *TaskUseCase) moveToProject(task *domain.Task, req *MoveTaskRequest, actorID string, version int) (*TaskDTO, error) {
+	if req.Column != "" || req.AfterID != "" || req.BeforeID != "" {
+		return nil, fieldError("project_id", "project_id cannot be combined with column, after_id or before_id")
+	}
+	if version != 0 && version != task.Version {
+		return nil, apperrors.ErrVersionMismatch
+	}
+	projectID := *req.ProjectID
+	if projectID == task.ProjectID {
+		return nil, fieldError("project_id", "task is already in the project")
+	}
+	if task.SeriesID != "" {
+		return nil, fieldError("project_id", "recurring tasks cannot be moved to another project")
+	}
+	if err := uc.authorizeTarget(projectID, actorID); err != nil {
+		return nil, err
+	}
+
+	before := taskValues(task)
+	source := *task
+	task.ProjectID = projectID
+	created, err := uc.remapTask(task, &source, req.Include.Labels)
+	if err != nil {
+		return nil, err
+	}
+	task.SprintID, task.MilestoneID = "", ""
+	rank, err := uc.bottomRank(projectID, task.Status)
+	if err != nil {
+		uc.discardLabels(created)
+		return nil, err
+	}
+	task.Rank, task.UpdatedAt = rank, time.Now()
+	change := &repository.TaskChange{Task: task, ReplaceLabels: true, ReplaceAssignees: true, ReplaceCustomFields: true,
+		Activity: uc.taskEntry(before, task, activitydomain.ActionUpdated, actorID)}
+	if err := uc.applyChange(change); err != nil {
+		uc.discardLabels(created)
+		return nil, err
+	}
+	indexTask(task)
+	return toTaskDTO(task), nil
+}
+
+// CloneTask はタスクを複製し、複製したタスクを返します。project_id を指定すると別のプロジェクトに複製します
+// 複製は移動先のワークフローの初期ステータスで作成し、スプリント・マイルストーン・繰り返し・依存関係・作業時間は引き継ぎません
+// サブタスク・コメント・添付ファイル・ラベルは include で指定したものだけ複製します
+func (uc *TaskUseCase) CloneTask(taskID string, req *CloneTaskRequest, actorID string) (*TaskDTO, error) {
+	source, err := uc.viewableTask(taskID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	projectID := source.ProjectID
+	if req.ProjectID != nil {
+		projectID = *req.ProjectID
+	}
+	if projectID != "" {
+		if err := uc.authorizeTarget(projectID, actorID); err != nil {
+			return nil, err
+		}
+	}
+
+	clone := domain.NewTask(uuid.New().String(), source.Title, source.Description, projectID, "", source.DueDate, source.Priority, uc.workflowFor(projectID).Initial, actorID)
+	clone.Estimate = source.Estimate
+	clone.StartDate, clone.DurationDays = source.StartDate, source.DurationDays
+	created, err := uc.remapTask(clone, source, req.Include.Labels)
+	if err != nil {
+		return nil, err
+	}
+	if clone.Rank, err = uc.bottomRank(clone.ProjectID, clone.Status); err != nil {
+		uc.discardLabels(created)
+		return nil, err
+	}
+	if err := uc.taskRepo.Create(clone, uc.taskEntry(nil, clone, activitydomain.ActionCreated, actorID)); err != nil {
+		uc.discardLabels(created)
+		return nil, err
+	}
+
+	if err := uc.copyTaskContent(source, clone, req.Include, actorID); err != nil {
+		// 途中まで複製したタスクはゴミ箱に残さずにサブタスク・コメント・添付ファイルごと物理削除し、作成したラベルも削除する
+		if derr := uc.taskRepo.Discard(clone.ID, uc.taskEntry(nil, clone, activitydomain.ActionDeleted, actorID)); derr != nil {
+			log.Printf("Failed to discard task %s cloned from %s: %v", clone.ID, source.ID, derr)
+		}
+		uc.discardLabels(created)
+		return nil, err
+	}
+	if clone, err = uc.taskRepo.GetByID(clone.ID); err != nil {
+		return nil, err
+	}
+	indexTask(clone)
+	return toTaskDTO(clone), nil
+}
+
+// authorizeTarget は actorID が移動・複製先のプロジェクトのタスクを編集できない場合に ErrForbidden を返します
+// メンバーでないプロジェクト・存在しないプロジェクトも ErrForbidden です
+func (uc *TaskUseCase) authorizeTarget(projectID, actorID string) error {
+	if projectID == "" {
+		return fieldError("project_id", "project_id must not be empty")
+	}
+	if err := uc.policy.Authorize(projectID, actorID, projectdomain.PermEditTasks); err != nil {
+		return err
+	}
+	return uc.checkWritable(projectID)
+}
+
+// remapTask は source の担当者・ラベル・カスタムフィールドを task のプロジェクトに合わせて task に設定します
+// 担当者はプロジェクトのメンバーのみ残し、ラベルは labels を指定した場合に同じ名前のラベル（なければ作成）に付け替えます
+// カスタムフィールドの値は同じキー・型のフィールドがあり、その選択肢やメンバーとして有効な場合のみ残します
+// 作成したラベルの ID を返します。呼び出し側は task の保存に失敗した場合に discardLabels で削除します
+func (uc *TaskUseCase) remapTask(task, source *domain.Task, labels bool) (created []string, err error) {
+	// 途中で失敗した場合は作成したラベルを残さない
+	defer func() {
+		if err != nil {
+			uc.discardLabels(created)
+			created = nil
+		}
+	}()
+
+	projectID := task.ProjectID
+	sameProject := projectID == source.ProjectID
+
+	assignees := source.AssigneeIDs
+	if !sameProject {
+		if assignees, err = uc.memberIDs(projectID, source.AssigneeIDs); err != nil {
+			return nil, err
+		}
+	}
+	task.SetAssignees(assignees)
+
+	var remapped []domain.TaskLabel
+	if labels {
+		for _, l := range source.Labels {
+			if sameProject {
+				remapped = append(remapped, l)
+				continue
+			}
+			label, isNew, err := uc.labelNamed(projectID, l.Name, l.Color)
+			if err != nil {
+				return created, err
+			}
+			if isNew {
+				created = append(created, label.ID)
+			}
+			remapped = append(remapped, domain.TaskLabel{ID: label.ID, Name: label.Name, Color: label.Color})
+		}
+	}
+	task.Labels = remapped
+
+	values := source.CustomFields
+	if !sameProject {
+		if values, err = uc.remapCustomValues(source.ProjectID, projectID, source.CustomFields); err != nil {
+			return created, err
+		}
+	}
+	task.CustomFields = map[string]interface{}{}
+	for key, value := range values {
+		task.CustomFields[key] = value
+	}
+	return created, nil
+}
+
+// discardLabels は remapTask が作成したラベルを削除します。削除に失敗してもエラーにしません
+func (uc *TaskUseCase) discardLabels(ids []string) {
+	for _, id := range ids {
+		if err := uc.labelRepo.Delete(id); err != nil {
+			log.Printf("Failed to delete label %s: %v", id, err)
+		}
+	}
+}
+
+// memberIDs は ids のうちプロジェクトのメンバーのものを順序を保って返します
+func (uc *TaskUseCase) memberIDs(projectID string, ids []string) ([]string, error) {
+	if len(ids) == 0 || projectID == "" {
+		return nil, nil
+	}
+	members, err := uc.members.GetMembers(projectID)
+	if err != nil {
+		return nil, err
+	}
+	isMember := map[string]bool{}
+	for _, m := range members {
+		isMember[m.ID] = true
+	}
+	var kept []string
+	for _, id := range ids {
+		if isMember[id] {
+			kept = append(kept, id)
+		}
+	}
+	return kept, nil
+}
+
+// labelNamed はプロジェクトの name という名前のラベルを返し、なければ color で作成します（作成した場合は created が true）
+func (uc *TaskUseCase) labelNamed(projectID, name, color string) (label *domain.Label, created bool, err error) {
+	if label, err := uc.labelRepo.FindByName(projectID, name); err == nil {
+		return label, false, nil
+	}
+	name, color, err = uc.validateLabel(projectID, "", name, color)
+	if err != nil {
+		return nil, false, err
+	}
+	label = domain.NewLabel(uuid.New().String(), projectID, name, color)
+	if err := uc.labelRepo.Create(label); err != nil {
+		return nil, false, err
+	}
+	return label, true, nil
+}
+
+// remapCustomValues は fromProjectID のカスタムフィールドの値のうち、projectID に同じキー・型のフィールドがあり有効なものを返します
+func (uc *TaskUseCase) remapCustomValues(fromProjectID, projectID string, values map[string]interface{}) (map[string]interface{}, error) {
+	remapped := map[string]interface{}{}
+	if len(values) == 0 || projectID == "" {
+		return remapped, nil
+	}
+	sourceFields, err := uc.fieldRepo.ListByProject(fromProjectID)
+	if err != nil {
+		return nil, err
+	}
+	types := map[string]string{}
+	for _, f := range sourceFields {
+		types[f.Key] = f.Type
+	}
+	fields, err := uc.fieldRepo.ListByProject(projectID)
+	if err != nil {
+		return nil, err
+	}
+	var users []string
+	for _, f := range fields {
+		value, ok := values[f.Key]
+		if !ok || types[f.Key] != f.Type {
+			continue
+		}
+		normalized, err := f.NormalizeValue(value)
+		if err != nil || normalized == nil {
+			continue
+		}
+		if f.Type == domain.CustomFieldUser {
+			users = append(users, normalized.(string))
+		}
+		remapped[f.Key] = normalized
+	}
+	if len(users) == 0 {
+		return remapped, nil
+	}
+	members, err := uc.memberIDs(projectID, users)
+	if err != nil {
+		return nil, err
+	}
+	isMember := map[string]bool{}
+	for _, id := range members {
+		isMember[id] = true
+	}
+	for _, f := range fields {
+		if id, ok := remapped[f.Key].(string); ok && f.Type == domain.CustomFieldUser && !isMember[id] {
+			delete(remapped, f.Key)
+		}
+	}
+	return remapped, nil
+}
+
+// copyTaskContent は include で指定したサブタスク・コメント・添付ファイルを source から clone に複製します
+func (uc *TaskUseCase) copyTaskContent(source, clone *domain.Task, include TransferOptions, actorID string) error {
+	if include.Subtasks {
+		subtasks, err := uc.subtaskRepo.ListByTask(source.ID)
+		if err != nil {
+			return err
+		}
+		for _, s := range subtasks {
+			copied := domain.NewSubtask(uuid.New().String(), s.Title, clone.ID)
+			copied.IsComplete = s.IsComplete
+			if err := uc.subtaskRepo.Create(copied, uc.subtaskEntry(nil, copied, activitydomain.ActionCreated, actorID)); err != nil {
+				return err
+			}
+		}
+	}
+	if (include.Comments || include.Attachments) && uc.content != nil {
+		return uc.content.CopyTaskContent(source.ID, clone.ID, include.Comments, include.Attachments)
+	}
+	return nil
+}
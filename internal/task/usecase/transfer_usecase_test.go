@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	apperrors "todo-app/internal/common/errors"
+	projectdomain "todo-app/internal/project/domain"
+	"todo-app/internal/task/domain"
+)
+
+// newTransferEnv は p1 のタスク t1 を p2 に移動・複製するためのデータを用意します
+// alice は両方のプロジェクトのメンバー、bob は p1 のみ、carol は p2 のみのメンバーで、dave は p2 では viewer です
+func newTransferEnv() *testEnv {
+	members := fakeMembers{
+		"p1": {"alice": projectdomain.RoleMember, "bob": projectdomain.RoleMember, "dave": projectdomain.RoleMember},
+		"p2": {"alice": projectdomain.RoleMember, "carol": projectdomain.RoleMember, "dave": projectdomain.RoleViewer},
+	}
+	fields := []*domain.CustomField{
+		domain.NewCustomField("f1", "p1", "severity", "Severity", domain.CustomFieldSelect, []string{"low", "high"}),
+		domain.NewCustomField("f2", "p1", "reviewer", "Reviewer", domain.CustomFieldUser, nil),
+		domain.NewCustomField("f3", "p1", "notes", "Notes", domain.CustomFieldText, nil),
+		domain.NewCustomField("f4", "p1", "stage", "Stage", domain.CustomFieldSelect, []string{"draft", "final"}),
+		domain.NewCustomField("g1", "p2", "severity", "Severity", domain.CustomFieldSelect, []string{"high", "urgent"}),
+		domain.NewCustomField("g2", "p2", "reviewer", "Reviewer", domain.CustomFieldUser, nil),
+		domain.NewCustomField("g3", "p2", "notes", "Notes", domain.CustomFieldNumber, nil),
+		domain.NewCustomField("g4", "p2", "stage", "Stage", domain.CustomFieldSelect, []string{"review"}),
+	}
+	labels := []*domain.Label{
+		domain.NewLabel("l1", "p1", "Bug", "#ff0000"),
+		domain.NewLabel("l2", "p1", "UI", "#00ff00"),
+		domain.NewLabel("m1", "p2", "bug", "#0000ff"),
+	}
+	task := newProjectTask("t1", "p1", "alice")
+	task.SetAssignees([]string{"bob", "alice"})
+	task.Labels = []domain.TaskLabel{{ID: "l1", Name: "Bug", Color: "#ff0000"}, {ID: "l2", Name: "UI", Color: "#00ff00"}}
+	task.CustomFields = map[string]interface{}{"severity": "high", "reviewer": "bob", "notes": "see spec", "stage": "draft"}
+	task.SprintID = "s1"
+	return newTestEnv(members, fields, labels, task)
+}
+
+// assertRemapped は p1 の t1 を p2 に合わせて付け替えた結果を確認します
+// 担当者は p2 のメンバーのみ、ラベルは同じ名前の p2 のラベル（なければ作成）、カスタムフィールドは同じキー・型で有効な値のみ残ります
+func assertRemapped(t *testing.T, env *testEnv, task *domain.Task) {
+	t.Helper()
+	if task.ProjectID != "p2" {
+		t.Errorf("project = %q, want p2", task.ProjectID)
+	}
+	if want := []string{"alice"}; !reflect.DeepEqual(task.AssigneeIDs, want) {
+		t.Errorf("assignees = %v, want %v", task.AssigneeIDs, want)
+	}
+	if len(env.labels.created) != 1 {
+		t.Fatalf("created labels = %v, want one label for UI", env.labels.created)
+	}
+	created := env.labels.labels[env.labels.created[0]]
+	if created.ProjectID != "p2" || created.Name != "UI" || created.Color != "#00ff00" {
+		t.Errorf("created label = %+v, want UI #00ff00 in p2", created)
+	}
+	var labelIDs []string
+	for _, l := range task.Labels {
+		labelIDs = append(labelIDs, l.ID)
+	}
+	sort.Strings(labelIDs)
+	want := []string{created.ID, "m1"}
+	sort.Strings(want)
+	if !reflect.DeepEqual(labelIDs, want) {
+		t.Errorf("labels = %v, want %v", labelIDs, want)
+	}
+	if want := map[string]interface{}{"severity": "high"}; !reflect.DeepEqual(task.CustomFields, want) {
+		t.Errorf("custom fields = %v, want %v", task.CustomFields, want)
+	}
+	if task.SprintID != "" {
+		t.Errorf("sprint = %q, want none", task.SprintID)
+	}
+}
+
+func TestMoveTaskRemapsToTargetProject(t *testing.T) {
+	env := newTransferEnv()
+	target := "p2"
+	if _, err := env.uc.MoveTask("t1", &MoveTaskRequest{ProjectID: &target, Include: TransferOptions{Labels: true}}, "alice", 1); err != nil {
+		t.Fatalf("MoveTask() error = %v", err)
+	}
+	moved := env.tasks.tasks["t1"]
+	assertRemapped(t, env, moved)
+	if moved.Version != 2 {
+		t.Errorf("version = %d, want 2", moved.Version)
+	}
+}
+
+func TestMoveTaskRemovesCreatedLabelsOnFailure(t *testing.T) {
+	env := newTransferEnv()
+	env.tasks.fail["t1"] = apperrors.ErrVersionMismatch
+	target := "p2"
+	_, err := env.uc.MoveTask("t1", &MoveTaskRequest{ProjectID: &target, Include: TransferOptions{Labels: true}}, "alice", 1)
+	if !errors.Is(err, apperrors.ErrVersionMismatch) {
+		t.Fatalf("MoveTask() error = %v, want ErrVersionMismatch", err)
+	}
+	if !reflect.DeepEqual(env.labels.deleted, env.labels.created) || len(env.labels.created) != 1 {
+		t.Errorf("deleted labels = %v, want the created labels %v", env.labels.deleted, env.labels.created)
+	}
+	if task := env.tasks.tasks["t1"]; task.ProjectID != "p1" {
+		t.Errorf("project = %q, want p1", task.ProjectID)
+	}
+}
+
+func TestCloneTaskRemapsToTargetProject(t *testing.T) {
+	env := newTransferEnv()
+	target := "p2"
+	dto, err := env.uc.CloneTask("t1", &CloneTaskRequest{ProjectID: &target, Include: TransferOptions{Labels: true}}, "alice")
+	if err != nil {
+		t.Fatalf("CloneTask() error = %v", err)
+	}
+	if dto.ID == "t1" {
+		t.Fatalf("clone has the source ID")
+	}
+	clone := env.tasks.tasks[dto.ID]
+	assertRemapped(t, env, clone)
+	if clone.Status != domain.DefaultWorkflow().Initial || clone.CreatedBy != "alice" {
+		t.Errorf("status, created_by = %q, %q, want %q, alice", clone.Status, clone.CreatedBy, domain.DefaultWorkflow().Initial)
+	}
+	if source := env.tasks.tasks["t1"]; source.ProjectID != "p1" || len(source.Labels) != 2 || len(source.CustomFields) != 4 {
+		t.Errorf("source changed: %+v", source)
+	}
+}
+
+func TestCloneTaskDiscardsPartialCloneOnFailure(t *testing.T) {
+	env := newTransferEnv()
+	env.content.err = errors.New("copy failed")
+	target := "p2"
+	include := TransferOptions{Labels: true, Comments: true}
+	if _, err := env.uc.CloneTask("t1", &CloneTaskRequest{ProjectID: &target, Include: include}, "alice"); err != env.content.err {
+		t.Fatalf("CloneTask() error = %v, want %v", err, env.content.err)
+	}
+	if len(env.tasks.discarded) != 1 || len(env.tasks.tasks) != 1 {
+		t.Errorf("discarded = %v, tasks left = %d, want the clone discarded and only the source left", env.tasks.discarded, len(env.tasks.tasks))
+	}
+	if !reflect.DeepEqual(env.labels.deleted, env.labels.created) || len(env.labels.created) != 1 {
+		t.Errorf("deleted labels = %v, want the created labels %v", env.labels.deleted, env.labels.created)
+	}
+}
+
+func TestTransferRequiresEditPermission(t *testing.T) {
+	p2, p3 := "p2", "p3"
+	tests := []struct {
+		name string
+		run  func(uc *TaskUseCase) error
+	}{
+		{"move as a viewer of the target", func(uc *TaskUseCase) error {
+			_, err := uc.MoveTask("t1", &MoveTaskRequest{ProjectID: &p2}, "dave", 0)
+			return err
+		}},
+		{"move as a member of the target only", func(uc *TaskUseCase) error {
+			_, err := uc.MoveTask("t1", &MoveTaskRequest{ProjectID: &p2}, "carol", 0)
+			return err
+		}},
+		{"move to a project the caller is not a member of", func(uc *TaskUseCase) error {
+			_, err := uc.MoveTask("t1", &MoveTaskRequest{ProjectID: &p3}, "alice", 0)
+			return err
+		}},
+		{"clone as a viewer of the target", func(uc *TaskUseCase) error {
+			_, err := uc.CloneTask("t1", &CloneTaskRequest{ProjectID: &p2}, "dave")
+			return err
+		}},
+		{"clone to a project the caller is not a member of", func(uc *TaskUseCase) error {
+			_, err := uc.CloneTask("t1", &CloneTaskRequest{ProjectID: &p2}, "bob")
+			return err
+		}},
+		{"clone from a project the caller cannot view", func(uc *TaskUseCase) error {
+			_, err := uc.CloneTask("t1", &CloneTaskRequest{ProjectID: &p2}, "carol")
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTransferEnv()
+			if err := tt.run(env.uc); err != apperrors.ErrForbidden {
+				t.Errorf("error = %v, want ErrForbidden", err)
+			}
+			if len(env.tasks.tasks) != 1 || env.tasks.tasks["t1"].ProjectID != "p1" || len(env.labels.created) != 0 {
+				t.Errorf("tasks or labels changed after a denied request")
+			}
+		})
+	}
+}
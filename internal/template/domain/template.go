@@ -0,0 +1,203 @@
+package domain
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+)
+
+// テンプレートの種類
+const (
+    // KindProject はプロジェクトとそのタスク・サブタスク・ラベルのテンプレートです
+    KindProject = "project"
+    // KindTask は1件のタスクとそのサブタスク・ラベルのテンプレートです
+    KindTask = "task"
+)
+
+const (
+    MaxNameLength = 255
+    // MaxTasks はテンプレートに含められるタスクの最大数です
+    MaxTasks = 1000
+    // MaxSubtasks はタスクごとのサブタスクの最大数です
+    MaxSubtasks = 200
+    // MaxOffsetDays は期限の相対日数の絶対値の上限です（約10年）
+    MaxOffsetDays = 3650
+    // dueTimeFormat は期限の時刻（UTC）の書式です
+    dueTimeFormat = "15:04"
+)
+
+var (
+    ErrNameRequired = errors.New("name is required")
+    ErrNameTooLong  = errors.New("name must be at most 255 characters")
+    ErrInvalidKind  = errors.New("kind must be project or task")
+)
+
+// Template はプロジェクトまたはタスクのテンプレートです
+// 期限は開始日からの相対日数で保持し、作成時に指定した開始日に合わせてずらします
+type Template struct {
+    ID          string    `json:"id"`
+    Name        string    `json:"name"`
+    Description string    `json:"description"`
+    Kind        string    `json:"kind"`
+    Content     Content   `json:"content"`
+    CreatedBy   string    `json:"created_by"`
+    CreatedAt   time.Time `json:"created_at"`
+    UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func NewTemplate(id, name, description, kind string, content Content, createdBy string) *Template {
+    return &Template{
+        ID:          id,
+        Name:        name,
+        Description: description,
+        Kind:        kind,
+        Content:     content,
+        CreatedBy:   createdBy,
+        CreatedAt:   time.Now(),
+        UpdatedAt:   time.Now(),
+    }
+}
+
+// Content はテンプレートの内容です。Project は KindProject の場合のみ設定します
+type Content struct {
+    Project *ProjectSpec `json:"project,omitempty"`
+    Labels  []LabelSpec  `json:"labels"`
+    Tasks   []TaskSpec   `json:"tasks"`
+}
+
+// ProjectSpec はテンプレートから作成するプロジェクトの設定です
+// DurationDays は開始日から終了日までの日数です（0 の場合は終了日を設定しません）
+type ProjectSpec struct {
+    Name              string `json:"name"`
+    Description       string `json:"description"`
+    DurationDays      int    `json:"duration_days"`
+    AutoCompleteTasks bool   `json:"auto_complete_tasks"`
+}
+
+type LabelSpec struct {
+    Name  string `json:"name"`
+    Color string `json:"color"`
+}
+
+// TaskSpec はテンプレートから作成するタスクです
+// DueOffsetDays は開始日から期限までの日数（期限なしの場合は nil）、DueTime は期限の時刻（UTC, HH:MM）です
+// Labels はラベル名で、Content.Labels に含まれている必要があります
+type TaskSpec struct {
+    Title         string   `json:"title"`
+    Description   string   `json:"description"`
+    Priority      string   `json:"priority"`
+    Estimate      int      `json:"estimate"`
+    DueOffsetDays *int     `json:"due_offset_days,omitempty"`
+    DueTime       string   `json:"due_time,omitempty"`
+    Labels        []string `json:"labels"`
+    Subtasks      []string `json:"subtasks"`
+}
+
+// NewTaskSpecDue は base の日付から due までの相対日数と時刻を返します。due が未設定（ゼロ値）の場合は nil です
+func NewTaskSpecDue(base, due time.Time) (*int, string) {
+    if due.IsZero() {
+        return nil, ""
+    }
+    due = due.UTC()
+    days := int(truncateDay(due).Sub(truncateDay(base.UTC())).Hours() / 24)
+    if due.Equal(truncateDay(due)) {
+        return &days, ""
+    }
+    return &days, due.Format(dueTimeFormat)
+}
+
+// DueDate は開始日 start に相対日数と時刻を適用した期限を返します。期限なしの場合はゼロ値です
+func (t TaskSpec) DueDate(start time.Time) time.Time {
+    if t.DueOffsetDays == nil {
+        return time.Time{}
+    }
+    due := truncateDay(start.UTC()).AddDate(0, 0, *t.DueOffsetDays)
+    if clock, err := time.Parse(dueTimeFormat, t.DueTime); err == nil {
+        due = due.Add(time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute)
+    }
+    return due
+}
+
+// ValidateName はテンプレート・プロジェクト・ラベル等の名前を検証します
+func ValidateName(name string) error {
+    if strings.TrimSpace(name) == "" {
+        return ErrNameRequired
+    }
+    if len([]rune(name)) > MaxNameLength {
+        return ErrNameTooLong
+    }
+    return nil
+}
+
+// Validate は種類に応じて内容の構造を検証します（優先度やラベルの色は作成時に検証します）
+func (c *Content) Validate(kind string) error {
+    switch kind {
+    case KindProject:
+        if c.Project == nil {
+            return errors.New("project is required for project templates")
+        }
+        if err := ValidateName(c.Project.Name); err != nil {
+            return fmt.Errorf("project.name: %w", err)
+        }
+        if c.Project.DurationDays < 0 || c.Project.DurationDays > MaxOffsetDays {
+            return fmt.Errorf("project.duration_days must be between 0 and %d", MaxOffsetDays)
+        }
+    case KindTask:
+        if c.Project != nil {
+            return errors.New("project must not be set for task templates")
+        }
+        if len(c.Tasks) != 1 {
+            return errors.New("task templates must have exactly one task")
+        }
+    default:
+        return ErrInvalidKind
+    }
+
+    labels := map[string]bool{}
+    for i, l := range c.Labels {
+        if err := ValidateName(l.Name); err != nil {
+            return fmt.Errorf("labels[%d].name: %w", i, err)
+        }
+        key := strings.ToLower(l.Name)
+        if labels[key] {
+            return fmt.Errorf("labels[%d].name: duplicate label %q", i, l.Name)
+        }
+        labels[key] = true
+    }
+
+    if len(c.Tasks) > MaxTasks {
+        return fmt.Errorf("tasks: at most %d tasks are allowed", MaxTasks)
+    }
+    for i, t := range c.Tasks {
+        if err := ValidateName(t.Title); err != nil {
+            return fmt.Errorf("tasks[%d].title: %w", i, err)
+        }
+        if t.DueOffsetDays != nil && (*t.DueOffsetDays < -MaxOffsetDays || *t.DueOffsetDays > MaxOffsetDays) {
+            return fmt.Errorf("tasks[%d].due_offset_days must be between %d and %d", i, -MaxOffsetDays, MaxOffsetDays)
+        }
+        if t.DueTime != "" {
+            if _, err := time.Parse(dueTimeFormat, t.DueTime); err != nil {
+                return fmt.Errorf("tasks[%d].due_time must be HH:MM", i)
+            }
+        }
+        for _, name := range t.Labels {
+            if !labels[strings.ToLower(name)] {
+                return fmt.Errorf("tasks[%d].labels: unknown label %q", i, name)
+            }
+        }
+        if len(t.Subtasks) > MaxSubtasks {
+            return fmt.Errorf("tasks[%d].subtasks: at most %d subtasks are allowed", i, MaxSubtasks)
+        }
+        for j, title := range t.Subtasks {
+            if strings.TrimSpace(title) == "" {
+                return fmt.Errorf("tasks[%d].subtasks[%d]: title is required", i, j)
+            }
+        }
+    }
+    return nil
+}
+
+func truncateDay(t time.Time) time.Time {
+    return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	activityhandler "todo-app/internal/activity/handler"
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/common/utils"
+	projectpostgres "todo-app/internal/project/repository/postgres"
+	projectusecase "todo-app/internal/project/usecase"
+	taskhandler "todo-app/internal/task/handler"
+	taskdomain "todo-app/internal/task/domain"
+	taskpostgres "todo-app/internal/task/repository/postgres"
+	"todo-app/internal/template/repository/postgres"
+	"todo-app/internal/template/usecase"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxImportSize はインポートする JSON の最大サイズです
+const maxImportSize = 5 << 20
+
+// NewTemplateUseCase は PostgreSQL のリポジトリを使う TemplateUseCase を返します
+func NewTemplateUseCase(db *sql.DB) *usecase.TemplateUseCase {
+	taskUC := taskhandler.NewTaskUseCase(db)
+	return usecase.NewTemplateUseCase(postgres.NewTemplateRepoPg(db), projectusecase.NewProjectUseCase(projectpostgres.NewProjectRepoPg(db), taskUC, activityhandler.NewActivityUseCase(db)),
+		taskUC, taskpostgres.NewProjectSettingsRepoPg(db))
+}
+
+// RegisterTemplateRoutes はテンプレートのエンドポイントを登録します
+// プロジェクトのテンプレートからの作成（POST /projects/from-template）はプロジェクトのハンドラーで登録します
+func RegisterTemplateRoutes(r chi.Router, db *sql.DB) {
+	uc := NewTemplateUseCase(db)
+
+	r.Route("/templates", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			templates, err := uc.List(userID, r.URL.Query().Get("kind"))
+			if err != nil {
+				log.Printf("Failed to get templates: %v", err)
+				WriteError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusOK, templates)
+		})
+
+		r.Post("/", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			var req usecase.SaveTemplateRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			template, err := uc.Save(&req, userID)
+			if err != nil {
+				log.Printf("Failed to save template from %s %s: %v", req.Source, req.SourceID, err)
+				WriteError(w, err)
+				return
+			}
+			log.Printf("Template saved successfully with ID: %s", template.ID)
+			utils.JSONResponse(w, http.StatusCreated, template)
+		})
+
+		r.Post("/import", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+			var export usecase.TemplateExport
+			if err := utils.DecodeJSON(r, &export); err != nil {
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			template, err := uc.Import(&export, userID)
+			if err != nil {
+				log.Printf("Failed to import template: %v", err)
+				WriteError(w, err)
+				return
+			}
+			log.Printf("Template imported successfully with ID: %s", template.ID)
+			utils.JSONResponse(w, http.StatusCreated, template)
+		})
+
+		r.Get("/{templateID}", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			template, err := uc.Get(chi.URLParam(r, "templateID"), userID)
+			if err != nil {
+				WriteError(w, err)
+				return
+			}
+			utils.JSONResponse(w, http.StatusOK, template)
+		})
+
+		r.Get("/{templateID}/export", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			export, err := uc.Export(chi.URLParam(r, "templateID"), userID)
+			if err != nil {
+				WriteError(w, err)
+				return
+			}
+			w.Header().Set("Content-Disposition", `attachment; filename="template.json"`)
+			utils.JSONResponse(w, http.StatusOK, export)
+		})
+
+		r.Post("/{templateID}/instantiate", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			var req usecase.InstantiateTaskRequest
+			if err := utils.DecodeJSON(r, &req); err != nil {
+				utils.JSONResponse(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			templateID := chi.URLParam(r, "templateID")
+			result, err := uc.InstantiateTask(templateID, &req, userID)
+			if err != nil {
+				log.Printf("Failed to instantiate template %s: %v", templateID, err)
+				WriteError(w, err)
+				return
+			}
+			log.Printf("Template %s instantiated in project %s: %d tasks", templateID, result.ProjectID, len(result.TaskIDs))
+			utils.JSONResponse(w, http.StatusCreated, result)
+		})
+
+		r.Delete("/{templateID}", func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			templateID := chi.URLParam(r, "templateID")
+			if err := uc.Delete(templateID, userID); err != nil {
+				log.Printf("Failed to delete template %s: %v", templateID, err)
+				WriteError(w, err)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	})
+}
+
+// WriteError はテンプレートのエラーをステータスコードに変換して返します
+func WriteError(w http.ResponseWriter, err error) {
+	var verr *apperrors.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		utils.JSONResponse(w, http.StatusBadRequest, map[string]interface{}{"error": apperrors.ErrInvalidInput.Error(), "fields": verr.Fields})
+	case errors.Is(err, taskdomain.ErrProjectArchived):
+		utils.JSONResponse(w, http.StatusConflict, map[string]string{"error": err.Error()})
+	case errors.Is(err, apperrors.ErrForbidden):
+		utils.JSONResponse(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, apperrors.ErrNotFound):
+		utils.JSONResponse(w, http.StatusNotFound, map[string]string{"error": "not found"})
+	default:
+		utils.JSONResponse(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
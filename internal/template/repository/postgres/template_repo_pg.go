@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"todo-app/internal/template/domain"
+	"todo-app/internal/template/repository"
+)
+
+// templateRepoPg は TemplateRepository の PostgreSQL 実装
+type templateRepoPg struct{ db *sql.DB }
+
+// NewTemplateRepoPg は PostgreSQL 実装（テンプレート用）を返す
+func NewTemplateRepoPg(db *sql.DB) repository.TemplateRepository {
+	return &templateRepoPg{db: db}
+}
+
+const templateColumns = `id, name, description, kind, content, COALESCE(created_by, ''), created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanTemplate(row rowScanner) (*domain.Template, error) {
+	t := &domain.Template{}
+	var content []byte
+	if err := row.Scan(&t.ID, &t.Name, &t.Description, &t.Kind, &content, &t.CreatedBy, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(content, &t.Content); err != nil {
+		return nil, fmt.Errorf("invalid content of template %s: %w", t.ID, err)
+	}
+	return t, nil
+}
+
+func (r *templateRepoPg) Create(t *domain.Template) error {
+	content, err := json.Marshal(t.Content)
+	if err != nil {
+		return err
+	}
+	query := `
+        INSERT INTO templates (id, name, description, kind, content, created_by, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8)
+    `
+	_, err = r.db.Exec(query, t.ID, t.Name, t.Description, t.Kind, content, t.CreatedBy, t.CreatedAt, t.UpdatedAt)
+	return err
+}
+
+func (r *templateRepoPg) FindByID(id string) (*domain.Template, error) {
+	t, err := scanTemplate(r.db.QueryRow(`SELECT `+templateColumns+` FROM templates WHERE id = $1`, id))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("template not found")
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *templateRepoPg) ListByUser(userID, kind string) ([]*domain.Template, error) {
+	query := `
+        SELECT ` + templateColumns + `
+        FROM templates
+        WHERE created_by = $1 AND ($2 = '' OR kind = $2)
+        ORDER BY name, created_at
+    `
+	rows, err := r.db.Query(query, userID, kind)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []*domain.Template{}
+	for rows.Next() {
+		t, err := scanTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, rows.Err()
+}
+
+func (r *templateRepoPg) Delete(id string) error {
+	result, err := r.db.Exec(`DELETE FROM templates WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("template not found")
+	}
+	return nil
+}
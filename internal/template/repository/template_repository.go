@@ -0,0 +1,12 @@
+package repository
+
+import "todo-app/internal/template/domain"
+
+// TemplateRepository はプロジェクト・タスクのテンプレートを管理します
+type TemplateRepository interface {
+    Create(template *domain.Template) error
+    FindByID(id string) (*domain.Template, error)
+    // ListByUser は userID が作成したテンプレートを名前順に返します。kind が空でない場合はその種類のみ返します
+    ListByUser(userID, kind string) ([]*domain.Template, error)
+    Delete(id string) error
+}
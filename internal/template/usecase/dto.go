@@ -0,0 +1,59 @@
+package usecase
+
+import "todo-app/internal/template/domain"
+
+// テンプレートの保存元
+const (
+    SourceProject = "project"
+    SourceTask    = "task"
+)
+
+// ExportFormat と ExportVersion はエクスポートした JSON の形式を表します
+const (
+    ExportFormat  = "todo-app/template"
+    ExportVersion = 1
+)
+
+// SaveTemplateRequest は POST /templates のリクエストボディです
+// 名前を省略した場合は保存元のプロジェクト名・タスク名を使います
+type SaveTemplateRequest struct {
+    Source      string `json:"source"`
+    SourceID    string `json:"source_id"`
+    Name        string `json:"name"`
+    Description string `json:"description"`
+}
+
+// InstantiateProjectRequest は POST /projects/from-template のリクエストボディです
+// StartDate は YYYY-MM-DD または RFC3339 で、タスクの期限はこの日付を基準にずらします
+type InstantiateProjectRequest struct {
+    TemplateID string `json:"template_id"`
+    Name       string `json:"name"`
+    StartDate  string `json:"start_date"`
+}
+
+// InstantiateTaskRequest は POST /templates/{templateID}/instantiate のリクエストボディです（タスクのテンプレートのみ）
+type InstantiateTaskRequest struct {
+    ProjectID string `json:"project_id"`
+    StartDate string `json:"start_date"`
+}
+
+// InstantiateResultDTO はテンプレートから作成したプロジェクトとタスクの ID です
+type InstantiateResultDTO struct {
+    ProjectID string   `json:"project_id"`
+    TaskIDs   []string `json:"task_ids"`
+}
+
+// TemplateExport はエクスポート・インポートする JSON です
+type TemplateExport struct {
+    Format   string           `json:"format"`
+    Version  int              `json:"version"`
+    Template ExportedTemplate `json:"template"`
+}
+
+// ExportedTemplate は ID と作成者を除いたテンプレートです
+type ExportedTemplate struct {
+    Name        string         `json:"name"`
+    Description string         `json:"description"`
+    Kind        string         `json:"kind"`
+    Content     domain.Content `json:"content"`
+}
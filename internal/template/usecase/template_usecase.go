@@ -0,0 +1,427 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	apperrors "todo-app/internal/common/errors"
+	projectusecase "todo-app/internal/project/usecase"
+	taskusecase "todo-app/internal/task/usecase"
+	"todo-app/internal/template/domain"
+	"todo-app/internal/template/repository"
+
+	"github.com/google/uuid"
+)
+
+// ProjectAccess は利用者がプロジェクトを閲覧できるか（作成者またはメンバー）を判定します
+type ProjectAccess interface {
+	CanAccessProject(userID, projectID string) (bool, error)
+}
+
+type TemplateUseCase struct {
+	repo     repository.TemplateRepository
+	projects *projectusecase.ProjectUseCase
+	tasks    *taskusecase.TaskUseCase
+	access   ProjectAccess
+}
+
+func NewTemplateUseCase(r repository.TemplateRepository, pu *projectusecase.ProjectUseCase, tu *taskusecase.TaskUseCase, pa ProjectAccess) *TemplateUseCase {
+	return &TemplateUseCase{repo: r, projects: pu, tasks: tu, access: pa}
+}
+
+// Save は既存のプロジェクトまたはタスクをテンプレートとして保存します
+func (uc *TemplateUseCase) Save(req *SaveTemplateRequest, userID string) (*domain.Template, error) {
+	switch req.Source {
+	case SourceProject:
+		return uc.SaveFromProject(req.SourceID, req.Name, req.Description, userID)
+	case SourceTask:
+		return uc.SaveFromTask(req.SourceID, req.Name, req.Description, userID)
+	default:
+		return nil, fieldError("source", "source must be project or task")
+	}
+}
+
+// SaveFromProject はプロジェクトとそのタスク・サブタスク・ラベルをテンプレートとして保存します
+// 期限はプロジェクトの開始日（未設定の場合は最も早い期限）からの相対日数で保持します
+func (uc *TemplateUseCase) SaveFromProject(projectID, name, description, userID string) (*domain.Template, error) {
+	if err := uc.checkAccess(projectID, userID); err != nil {
+		return nil, err
+	}
+	project, err := uc.projects.GetByID(projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	labels, err := uc.tasks.ListLabels(projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := uc.tasks.GetTasksByProject(projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	// 作成された順に並べ、作成時も同じ順に作成する
+	sort.SliceStable(tasks, func(i, j int) bool { return tasks[i].CreatedAt.Before(tasks[j].CreatedAt) })
+
+	base := project.StartDate
+	if base.IsZero() {
+		for _, task := range tasks {
+			if !task.DueDate.IsZero() && (base.IsZero() || task.DueDate.Before(base)) {
+				base = task.DueDate
+			}
+		}
+	}
+	spec := &domain.ProjectSpec{
+		Name:              project.Name,
+		Description:       project.Description,
+		AutoCompleteTasks: project.AutoCompleteTasks,
+	}
+	if !project.EndDate.IsZero() && !base.IsZero() {
+		if days, _ := domain.NewTaskSpecDue(base, project.EndDate); days != nil && *days > 0 {
+			spec.DurationDays = *days
+		}
+	}
+
+	content := domain.Content{Project: spec, Labels: make([]domain.LabelSpec, 0, len(labels)), Tasks: make([]domain.TaskSpec, 0, len(tasks))}
+	for _, l := range labels {
+		content.Labels = append(content.Labels, domain.LabelSpec{Name: l.Name, Color: l.Color})
+	}
+	for _, task := range tasks {
+		ts, err := uc.taskSpec(task, base, userID)
+		if err != nil {
+			return nil, err
+		}
+		content.Tasks = append(content.Tasks, ts)
+	}
+
+	if name == "" {
+		name = project.Name
+	}
+	return uc.create(name, description, domain.KindProject, content, userID)
+}
+
+// SaveFromTask はタスクとそのサブタスク・ラベルをテンプレートとして保存します
+// 期限はタスクの作成日からの相対日数で保持します
+func (uc *TemplateUseCase) SaveFromTask(taskID, name, description, userID string) (*domain.Template, error) {
+	// GetTaskByID が閲覧権限を確認する（プロジェクトのない個人のタスクも作成者・担当者なら保存できる）
+	task, err := uc.tasks.GetTaskByID(taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+	ts, err := uc.taskSpec(task, task.CreatedAt, userID)
+	if err != nil {
+		return nil, err
+	}
+	content := domain.Content{Labels: make([]domain.LabelSpec, 0, len(task.Labels)), Tasks: []domain.TaskSpec{ts}}
+	for _, l := range task.Labels {
+		content.Labels = append(content.Labels, domain.LabelSpec{Name: l.Name, Color: l.Color})
+	}
+
+	if name == "" {
+		name = task.Title
+	}
+	return uc.create(name, description, domain.KindTask, content, userID)
+}
+
+// taskSpec はタスクをテンプレートのタスクに変換します。担当者・ステータス・繰り返しは含めません
+func (uc *TemplateUseCase) taskSpec(task *taskusecase.TaskDTO, base time.Time, userID string) (domain.TaskSpec, error) {
+	subtasks, err := uc.tasks.ListSubtasks(task.ID, userID)
+	if err != nil {
+		return domain.TaskSpec{}, err
+	}
+	ts := domain.TaskSpec{
+		Title:       task.Title,
+		Description: task.Description,
+		Priority:    task.Priority,
+		Estimate:    task.Estimate,
+		Labels:      make([]string, 0, len(task.Labels)),
+		Subtasks:    make([]string, 0, len(subtasks)),
+	}
+	ts.DueOffsetDays, ts.DueTime = domain.NewTaskSpecDue(base, task.DueDate)
+	for _, l := range task.Labels {
+		ts.Labels = append(ts.Labels, l.Name)
+	}
+	for _, s := range subtasks {
+		ts.Subtasks = append(ts.Subtasks, s.Title)
+	}
+	return ts, nil
+}
+
+func (uc *TemplateUseCase) create(name, description, kind string, content domain.Content, userID string) (*domain.Template, error) {
+	verr := apperrors.NewValidationError()
+	if err := domain.ValidateName(name); err != nil {
+		verr.Add("name", err.Error())
+	}
+	if err := content.Validate(kind); err != nil {
+		verr.Add("content", err.Error())
+	}
+	if verr.HasErrors() {
+		return nil, verr
+	}
+	t := domain.NewTemplate(uuid.New().String(), strings.TrimSpace(name), description, kind, content, userID)
+	if err := uc.repo.Create(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// List は利用者が作成したテンプレートを返します。kind を指定した場合はその種類のみ返します
+func (uc *TemplateUseCase) List(userID, kind string) ([]*domain.Template, error) {
+	if kind != "" && kind != domain.KindProject && kind != domain.KindTask {
+		return nil, fieldError("kind", domain.ErrInvalidKind.Error())
+	}
+	return uc.repo.ListByUser(userID, kind)
+}
+
+// Get はテンプレートを返します。作成者以外には存在しないものとして扱います
+func (uc *TemplateUseCase) Get(id, userID string) (*domain.Template, error) {
+	t, err := uc.repo.FindByID(id)
+	if err != nil || t.CreatedBy != userID {
+		return nil, apperrors.ErrNotFound
+	}
+	return t, nil
+}
+
+// Delete はテンプレートを削除します。作成済みのプロジェクト・タスクには影響しません
+func (uc *TemplateUseCase) Delete(id, userID string) error {
+	if _, err := uc.Get(id, userID); err != nil {
+		return err
+	}
+	if err := uc.repo.Delete(id); err != nil {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// Export はテンプレートを他の環境でインポートできる JSON の形式で返します
+func (uc *TemplateUseCase) Export(id, userID string) (*TemplateExport, error) {
+	t, err := uc.Get(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateExport{
+		Format:  ExportFormat,
+		Version: ExportVersion,
+		Template: ExportedTemplate{
+			Name:        t.Name,
+			Description: t.Description,
+			Kind:        t.Kind,
+			Content:     t.Content,
+		},
+	}, nil
+}
+
+// Import はエクスポートした JSON から利用者のテンプレートを作成します
+func (uc *TemplateUseCase) Import(export *TemplateExport, userID string) (*domain.Template, error) {
+	if export.Format != ExportFormat {
+		return nil, fieldError("format", fmt.Sprintf("format must be %s", ExportFormat))
+	}
+	if export.Version != ExportVersion {
+		return nil, fieldError("version", fmt.Sprintf("unsupported version %d", export.Version))
+	}
+	t := export.Template
+	return uc.create(t.Name, t.Description, t.Kind, t.Content, userID)
+}
+
+// InstantiateProject はプロジェクトのテンプレートから新しいプロジェクトを作成します
+// タスクの期限は開始日に合わせてずらし、途中で失敗した場合は作成したプロジェクトをゴミ箱に移動します
+func (uc *TemplateUseCase) InstantiateProject(req *InstantiateProjectRequest, userID string) (*InstantiateResultDTO, error) {
+	start, err := parseStartDate(req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	t, err := uc.Get(req.TemplateID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Kind != domain.KindProject {
+		return nil, fieldError("template_id", "template is not a project template")
+	}
+	spec := t.Content.Project
+	name := spec.Name
+	if req.Name != "" {
+		name = req.Name
+	}
+	if err := domain.ValidateName(name); err != nil {
+		return nil, fieldError("name", err.Error())
+	}
+
+	dto := &projectusecase.ProjectDTO{
+		Name:              strings.TrimSpace(name),
+		Description:       spec.Description,
+		StartDate:         start,
+		CreatedBy:         userID,
+		AutoCompleteTasks: spec.AutoCompleteTasks,
+	}
+	if spec.DurationDays > 0 {
+		dto.EndDate = start.AddDate(0, 0, spec.DurationDays)
+	}
+	projectID, err := uc.projects.Create(dto)
+	if err != nil {
+		return nil, err
+	}
+
+	labelIDs, err := uc.ensureLabels(projectID, t.Content.Labels, userID)
+	if err == nil {
+		var taskIDs []string
+		if taskIDs, err = uc.createTasks(projectID, start, t.Content.Tasks, labelIDs, userID); err == nil {
+			return &InstantiateResultDTO{ProjectID: projectID, TaskIDs: taskIDs}, nil
+		}
+	}
+	if derr := uc.projects.Delete(projectID, 0, userID); derr != nil {
+		log.Printf("Failed to delete project %s created from template %s: %v", projectID, t.ID, derr)
+	}
+	return nil, err
+}
+
+// InstantiateTask はタスクのテンプレートから既存のプロジェクトにタスクを作成します
+// プロジェクトに同じ名前のラベルがない場合は作成します
+func (uc *TemplateUseCase) InstantiateTask(templateID string, req *InstantiateTaskRequest, userID string) (*InstantiateResultDTO, error) {
+	start, err := parseStartDate(req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	t, err := uc.Get(templateID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t.Kind != domain.KindTask {
+		return nil, fieldError("template_id", "template is not a task template; use POST /projects/from-template")
+	}
+	if req.ProjectID == "" {
+		return nil, fieldError("project_id", "project_id is required")
+	}
+	if err := uc.checkAccess(req.ProjectID, userID); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, fieldError("project_id", "project not found")
+		}
+		return nil, err
+	}
+
+	labelIDs, err := uc.ensureLabels(req.ProjectID, t.Content.Labels, userID)
+	if err != nil {
+		return nil, err
+	}
+	taskIDs, err := uc.createTasks(req.ProjectID, start, t.Content.Tasks, labelIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &InstantiateResultDTO{ProjectID: req.ProjectID, TaskIDs: taskIDs}, nil
+}
+
+// ensureLabels はラベル名（小文字）からラベル ID への対応を返します。プロジェクトにないラベルは作成します
+func (uc *TemplateUseCase) ensureLabels(projectID string, specs []domain.LabelSpec, userID string) (map[string]string, error) {
+	existing, err := uc.tasks.ListLabels(projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[string]string, len(existing)+len(specs))
+	for _, l := range existing {
+		ids[strings.ToLower(l.Name)] = l.ID
+	}
+	for i, spec := range specs {
+		if _, ok := ids[strings.ToLower(spec.Name)]; ok {
+			continue
+		}
+		name, color := spec.Name, spec.Color
+		req := &taskusecase.LabelRequest{Name: &name}
+		if color != "" {
+			req.Color = &color
+		}
+		label, err := uc.tasks.CreateLabel(projectID, req, userID)
+		if err != nil {
+			return nil, prefixFields(err, fmt.Sprintf("content.labels[%d].", i))
+		}
+		ids[strings.ToLower(label.Name)] = label.ID
+	}
+	return ids, nil
+}
+
+// createTasks はテンプレートのタスクとサブタスクを作成します。期限は start を基準にずらします
+// 途中で失敗した場合は作成済みのタスクをゴミ箱に移動します
+func (uc *TemplateUseCase) createTasks(projectID string, start time.Time, specs []domain.TaskSpec, labelIDs map[string]string, userID string) ([]string, error) {
+	taskIDs := make([]string, 0, len(specs))
+	err := func() error {
+		for i, spec := range specs {
+			dto := &taskusecase.TaskDTO{
+				Title:       spec.Title,
+				Description: spec.Description,
+				Priority:    spec.Priority,
+				Estimate:    spec.Estimate,
+				DueDate:     spec.DueDate(start),
+				ProjectID:   projectID,
+				CreatedBy:   userID,
+			}
+			for _, name := range spec.Labels {
+				dto.LabelIDs = append(dto.LabelIDs, labelIDs[strings.ToLower(name)])
+			}
+			taskID, err := uc.tasks.CreateTask(dto)
+			if err != nil {
+				return prefixFields(err, fmt.Sprintf("content.tasks[%d].", i))
+			}
+			taskIDs = append(taskIDs, taskID)
+			for j, title := range spec.Subtasks {
+				if _, err := uc.tasks.CreateSubtask(&taskusecase.SubtaskDTO{Title: title, TaskID: taskID}, userID); err != nil {
+					return prefixFields(err, fmt.Sprintf("content.tasks[%d].subtasks[%d].", i, j))
+				}
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		for _, id := range taskIDs {
+			if derr := uc.tasks.DeleteTask(id, 0, userID); derr != nil {
+				log.Printf("Failed to delete task %s created from template: %v", id, derr)
+			}
+		}
+		return nil, err
+	}
+	return taskIDs, nil
+}
+
+// checkAccess は利用者がプロジェクトを閲覧できない場合に ErrNotFound を返します
+func (uc *TemplateUseCase) checkAccess(projectID, userID string) error {
+	ok, err := uc.access.CanAccessProject(userID, projectID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperrors.ErrNotFound
+	}
+	return nil
+}
+
+// parseStartDate は YYYY-MM-DD または RFC3339 の開始日を解析します
+func parseStartDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, fieldError("start_date", "start_date is required")
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fieldError("start_date", "start_date must be YYYY-MM-DD or RFC3339")
+}
+
+// prefixFields は ValidationError のフィールド名に prefix を付けます。それ以外のエラーはそのまま返します
+func prefixFields(err error, prefix string) error {
+	var verr *apperrors.ValidationError
+	if !errors.As(err, &verr) {
+		return err
+	}
+	prefixed := apperrors.NewValidationError()
+	for field, msg := range verr.Fields {
+		prefixed.Add(prefix+field, msg)
+	}
+	return prefixed
+}
+
+func fieldError(field, msg string) error {
+	verr := apperrors.NewValidationError()
+	verr.Add(field, msg)
+	return verr
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	attachmenthandler "todo-app/internal/attachment/handler"
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/common/utils"
+	projectpolicy "todo-app/internal/project/policy"
+	projectpostgres "todo-app/internal/project/repository/postgres"
+	taskhandler "todo-app/internal/task/handler"
+	taskpostgres "todo-app/internal/task/repository/postgres"
+	"todo-app/internal/trash/usecase"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// NewTrashUseCase は PostgreSQL の各リポジトリを使う TrashUseCase を返します
+func NewTrashUseCase(db *sql.DB) *usecase.TrashUseCase {
+	projectRepo := projectpostgres.NewProjectRepoPg(db)
+	return usecase.NewTrashUseCase(taskpostgres.NewTaskRepoPg(db), projectRepo, attachmenthandler.NewAttachmentUseCase(db, taskhandler.NewTaskUseCase(db)),
+		projectpolicy.NewPolicy(projectRepo), usecase.RetentionFromEnv())
+}
+
+func RegisterTrashRoutes(r chi.Router, db *sql.DB) {
+	uc := NewTrashUseCase(db)
+
+	r.Route("/trash", func(r chi.Router) {
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			log.Printf("Get trash request received")
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			trash, err := uc.List(userID)
+			if err != nil {
+				log.Printf("Failed to get trash: %v", err)
+				utils.JSONResponse(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+
+			utils.JSONResponse(w, http.StatusOK, trash)
+		})
+
+		r.Delete("/tasks/{taskID}", func(w http.ResponseWriter, r *http.Request) {
+			taskID := chi.URLParam(r, "taskID")
+			log.Printf("Purge task request received for taskID: %s", taskID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			if err := uc.PurgeTask(taskID, userID); err != nil {
+				log.Printf("Failed to purge task %s: %v", taskID, err)
+				writeTrashError(w, err, "task not found in trash")
+				return
+			}
+
+			log.Printf("Task purged successfully: %s", taskID)
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		r.Delete("/projects/{projectID}", func(w http.ResponseWriter, r *http.Request) {
+			projectID := chi.URLParam(r, "projectID")
+			log.Printf("Purge project request received for projectID: %s", projectID)
+
+			userID, ok := r.Context().Value("userID").(string)
+			if !ok {
+				log.Printf("Failed to get userID from context")
+				utils.JSONResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+
+			if err := uc.PurgeProject(projectID, userID); err != nil {
+				log.Printf("Failed to purge project %s: %v", projectID, err)
+				writeTrashError(w, err, "project not found in trash")
+				return
+			}
+
+			log.Printf("Project purged successfully: %s", projectID)
+			w.WriteHeader(http.StatusNoContent)
+		})
+	})
+}
+
+// writeTrashError はゴミ箱の操作のエラーを HTTP ステータスに変換して返します
+func writeTrashError(w http.ResponseWriter, err error, notFound string) {
+	switch {
+	case errors.Is(err, apperrors.ErrForbidden):
+		utils.JSONResponse(w, http.StatusForbidden, map[string]string{"error": err.Error()})
+	case errors.Is(err, apperrors.ErrNotFound):
+		utils.JSONResponse(w, http.StatusNotFound, map[string]string{"error": notFound})
+	default:
+		utils.JSONResponse(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+	}
+}
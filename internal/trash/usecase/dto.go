@@ -0,0 +1,19 @@
+package usecase
+
+import "time"
+
+// TrashItemDTO はゴミ箱内の1件（タスクまたはプロジェクト）です
+type TrashItemDTO struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	ProjectID string    `json:"project_id,omitempty"`
+	DeletedAt time.Time `json:"deleted_at"`
+	// PurgeAt はこの日時を過ぎると物理削除されることを表します
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+type TrashDTO struct {
+	Tasks    []*TrashItemDTO `json:"tasks"`
+	Projects []*TrashItemDTO `json:"projects"`
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	apperrors "todo-app/internal/common/errors"
+	"todo-app/internal/common/utils"
+	"todo-app/internal/infrastructure"
+	projectdomain "todo-app/internal/project/domain"
+	projectrepo "todo-app/internal/project/repository"
+	taskrepo "todo-app/internal/task/repository"
+)
+
+var solrClient, _ = infrastructure.NewSolrClient("todoapp")
+
+const (
+	// DefaultRetention はゴミ箱内のデータを保持する既定の期間です
+	DefaultRetention = 30 * 24 * time.Hour
+	// DefaultPurgeInterval は purger が実行される既定の間隔です
+	DefaultPurgeInterval = time.Hour
+)
+
+type TrashUseCase struct {
+	taskRepo    taskrepo.TaskRepository
+	projectRepo projectrepo.ProjectRepository
+	attachments BlobPurger
+	policy      ProjectPolicy
+	retention   time.Duration
+}
+
+// ProjectPolicy はプロジェクトのロールに基づいて操作を許可するかを判定します
+type ProjectPolicy interface {
+	Authorize(projectID, userID string, perm projectdomain.Permission) error
+}
+
+// BlobPurger は物理削除で参照が無くなった添付ファイルの本体をストレージから削除します
+type BlobPurger interface {
+	PurgeUnreferencedBlobs(ctx context.Context, now time.Time) (int, error)
+}
+
+func NewTrashUseCase(tr taskrepo.TaskRepository, pr projectrepo.ProjectRepository, bp BlobPurger, pp ProjectPolicy, retention time.Duration) *TrashUseCase {
+	return &TrashUseCase{taskRepo: tr, projectRepo: pr, attachments: bp, policy: pp, retention: retention}
+}
+
+// RetentionFromEnv は TRASH_RETENTION（例: 720h）から保持期間を読み込みます
+func RetentionFromEnv() time.Duration {
+	return utils.DurationFromEnv("TRASH_RETENTION", DefaultRetention)
+}
+
+// PurgeIntervalFromEnv は TRASH_PURGE_INTERVAL（例: 1h）から purger の実行間隔を読み込みます
+func PurgeIntervalFromEnv() time.Duration {
+	return utils.DurationFromEnv("TRASH_PURGE_INTERVAL", DefaultPurgeInterval)
+}
+
+// List は userID が復元・物理削除できるゴミ箱内のタスクとプロジェクトを返します
+// タスクはプロジェクトのタスクを編集できるもの（プロジェクトのないタスクは作成・担当しているもの）、プロジェクトは owner のものです
+func (uc *TrashUseCase) List(userID string) (*TrashDTO, error) {
+	tasks, err := uc.taskRepo.ListDeleted(userID, projectdomain.RolesAllowing(projectdomain.PermEditTasks))
+	if err != nil {
+		return nil, err
+	}
+	projects, err := uc.projectRepo.ListDeleted(userID, projectdomain.RolesAllowing(projectdomain.PermDeleteProject))
+	if err != nil {
+		return nil, err
+	}
+
+	trash := &TrashDTO{
+		Tasks:    make([]*TrashItemDTO, len(tasks)),
+		Projects: make([]*TrashItemDTO, len(projects)),
+	}
+	for i, task := range tasks {
+		trash.Tasks[i] = &TrashItemDTO{
+			ID:        task.ID,
+			Type:      "task",
+			Title:     task.Title,
+			ProjectID: task.ProjectID,
+			DeletedAt: *task.DeletedAt,
+			PurgeAt:   task.DeletedAt.Add(uc.retention),
+		}
+	}
+	for i, project := range projects {
+		trash.Projects[i] = &TrashItemDTO{
+			ID:        project.ID,
+			Type:      "project",
+			Title:     project.Name,
+			DeletedAt: *project.DeletedAt,
+			PurgeAt:   project.DeletedAt.Add(uc.retention),
+		}
+	}
+	return trash, nil
+}
+
+// PurgeTask はゴミ箱内のタスクを物理削除します。復元と同じく、タスクを編集できるユーザーのみ行えます
+func (uc *TrashUseCase) PurgeTask(id, userID string) error {
+	task, err := uc.taskRepo.FindDeleted(id)
+	if err != nil {
+		return apperrors.ErrNotFound
+	}
+	if task.ProjectID == "" {
+		if task.CreatedBy != userID && !task.HasAssignee(userID) {
+			return apperrors.ErrForbidden
+		}
+	} else if err := uc.policy.Authorize(task.ProjectID, userID, projectdomain.PermEditTasks); err != nil {
+		return err
+	}
+	if err := uc.taskRepo.PurgeDeleted(id); err != nil {
+		return apperrors.ErrNotFound
+	}
+	uc.removePurged([]string{id}, time.Now())
+	return nil
+}
+
+// PurgeProject はゴミ箱内のプロジェクトをタスクとともに物理削除します。owner のみ行えます
+func (uc *TrashUseCase) PurgeProject(id, userID string) error {
+	if err := uc.policy.Authorize(id, userID, projectdomain.PermDeleteProject); err != nil {
+		return err
+	}
+	taskIDs, err := uc.projectRepo.PurgeDeleted(id)
+	if err != nil {
+		return apperrors.ErrNotFound
+	}
+	uc.removePurged(append(taskIDs, id), time.Now())
+	return nil
+}
+
+// removePurged は物理削除したデータを Solr から削除し、参照が無くなった添付ファイルの本体を削除します
+func (uc *TrashUseCase) removePurged(ids []string, now time.Time) {
+	for _, id := range ids {
+		if err := solrClient.Delete(id); err != nil {
+			log.Printf("Failed to remove %s from Solr: %v", id, err)
+		}
+	}
+	if _, err := uc.attachments.PurgeUnreferencedBlobs(context.Background(), now); err != nil {
+		log.Printf("Failed to purge attachment blobs: %v", err)
+	}
+}
+
+// Purge は保持期間を過ぎたゴミ箱内のデータを物理削除し、Solr からも削除します
+// タスクを先に削除するのは、プロジェクトの削除で CASCADE されるタスクも Solr から消すためです
+// 添付ファイルは CASCADE で削除されるため、最後に参照が無くなった本体をストレージから削除します
+func (uc *TrashUseCase) Purge(now time.Time) (int, error) {
+	cutoff := now.Add(-uc.retention)
+
+	taskIDs, err := uc.taskRepo.PurgeDeletedBefore(cutoff)
+	if err != nil {
+		return 0, err
+	}
+	projectIDs, err := uc.projectRepo.PurgeDeletedBefore(cutoff)
+	if err != nil {
+		return len(taskIDs), err
+	}
+
+	uc.removePurged(append(taskIDs, projectIDs...), now)
+	return len(taskIDs) + len(projectIDs), nil
+}
+
+// RunPurger は ctx がキャンセルされるまで interval ごとに Purge を実行します
+func (uc *TrashUseCase) RunPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			n, err := uc.Purge(now)
+			if err != nil {
+				log.Printf("Failed to purge trash: %v", err)
+				continue
+			}
+			if n > 0 {
+				log.Printf("Purged %d items from trash", n)
+			}
+		}
+	}
+}
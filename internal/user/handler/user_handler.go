@@ -7,12 +7,14 @@ import (
 
     "github.com/go-chi/chi/v5"
     "todo-app/internal/common/utils"
+    taskhandler "todo-app/internal/task/handler"
     "todo-app/internal/user/repository/postgres"
     "todo-app/internal/user/usecase"
 )
 
 func RegisterUserRoutes(r chi.Router, db *sql.DB) {
     uc := usecase.NewUserUseCase(postgres.NewUserRepoPg(db), postgres.NewRoleRepoPg(db))
+    taskUC := taskhandler.NewTaskUseCase(db)
 
     r.Route("/users", func(r chi.Router) {
         r.Post("/register", func(w http.ResponseWriter, r *http.Request) {
@@ -76,6 +78,24 @@ func RegisterUserRoutes(r chi.Router, db *sql.DB) {
             utils.JSONResponse(w, http.StatusOK, user)
         })
 
+        // 自分が担当している未完了のタスクを期限で区分して返す
+        r.Get("/me/tasks", func(w http.ResponseWriter, r *http.Request) {
+            userID, ok := r.Context().Value("userID").(string)
+            if !ok {
+                log.Printf("UserID not found in context")
+                utils.JSONResponse(w, http.StatusUnauthorized, "unauthorized")
+                return
+            }
+
+            tasks, err := taskUC.MyTasks(r.Context(), userID)
+            if err != nil {
+                log.Printf("Failed to get tasks assigned to userID %s: %v", userID, err)
+                utils.JSONResponse(w, http.StatusInternalServerError, err.Error())
+                return
+            }
+            utils.JSONResponse(w, http.StatusOK, tasks)
+        })
+
         // 管理者専用エンドポイント
         r.Get("/", func(w http.ResponseWriter, r *http.Request) {
             log.Printf("Get all users request received")
@@ -33,16 +33,76 @@ CREATE TABLE IF NOT EXISTS projects (
     end_date TIMESTAMP,
     created_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    version INTEGER NOT NULL DEFAULT 1,
+    deleted_at TIMESTAMP,
+    auto_complete_tasks BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'archived'))
 );
 
 -- プロジェクトメンバーテーブルの作成
 CREATE TABLE IF NOT EXISTS project_members (
     project_id VARCHAR(255) REFERENCES projects(id) ON DELETE CASCADE,
     user_id VARCHAR(255) REFERENCES users(id) ON DELETE CASCADE,
+    -- プロジェクト内のロール。作成者は owner として登録される
+    role VARCHAR(20) NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'maintainer', 'member', 'viewer')),
     PRIMARY KEY (project_id, user_id)
 );
 
+-- 繰り返しタスクの系列テーブルの作成
+CREATE TABLE IF NOT EXISTS task_series (
+    id VARCHAR(255) PRIMARY KEY,
+    rule TEXT NOT NULL,
+    start_at TIMESTAMP NOT NULL,
+    last_due TIMESTAMP NOT NULL,
+    occurrences INTEGER NOT NULL DEFAULT 1,
+    ended BOOLEAN NOT NULL DEFAULT FALSE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    priority VARCHAR(20),
+    project_id VARCHAR(255) REFERENCES projects(id) ON DELETE CASCADE,
+    assignee_id VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- スプリントテーブルの作成（status は planned / active / closed。rolled_over は終了時に次へ移した未完了のタスク数）
+CREATE TABLE IF NOT EXISTS sprints (
+    id VARCHAR(255) PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    goal TEXT NOT NULL DEFAULT '',
+    start_date TIMESTAMP NOT NULL,
+    end_date TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'active', 'closed')),
+    started_at TIMESTAMP,
+    closed_at TIMESTAMP,
+    rolled_over INTEGER NOT NULL DEFAULT 0,
+    created_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 実行中のスプリントはプロジェクトごとに 1 つまで
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sprints_active ON sprints(project_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_sprints_project_id ON sprints(project_id, start_date);
+
+-- マイルストーンテーブルの作成（start_date は省略可能）
+CREATE TABLE IF NOT EXISTS milestones (
+    id VARCHAR(255) PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    start_date TIMESTAMP,
+    end_date TIMESTAMP NOT NULL,
+    created_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_milestones_project_id ON milestones(project_id, end_date);
+
 -- タスクテーブルの作成
 CREATE TABLE IF NOT EXISTS tasks (
     id VARCHAR(255) PRIMARY KEY,
@@ -55,7 +115,32 @@ CREATE TABLE IF NOT EXISTS tasks (
     assignee_id VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
     created_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    version INTEGER NOT NULL DEFAULT 1,
+    deleted_at TIMESTAMP,
+    series_id VARCHAR(255) REFERENCES task_series(id) ON DELETE SET NULL,
+    occurrence INTEGER,
+    estimate INTEGER NOT NULL DEFAULT 0,
+    sprint_id VARCHAR(255) REFERENCES sprints(id) ON DELETE SET NULL,
+    milestone_id VARCHAR(255) REFERENCES milestones(id) ON DELETE SET NULL,
+    start_date TIMESTAMP,
+    duration_days INTEGER NOT NULL DEFAULT 0 CHECK (duration_days >= 0),
+    rank VARCHAR(255) COLLATE "C" NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_tasks_sprint_id ON tasks(sprint_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_board ON tasks(project_id, status, rank);
+CREATE INDEX IF NOT EXISTS idx_tasks_milestone_id ON tasks(milestone_id);
+
+-- かんばんボードの列テーブルの作成（列はステータスに 1 対 1 で対応。設定のないステータスは既定の列になる）
+CREATE TABLE IF NOT EXISTS board_columns (
+    project_id VARCHAR(255) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    wip_limit INTEGER NOT NULL DEFAULT 0 CHECK (wip_limit >= 0),
+    wip_policy VARCHAR(10) NOT NULL DEFAULT 'warn' CHECK (wip_policy IN ('warn', 'reject')),
+    PRIMARY KEY (project_id, status)
 );
 
 -- サブタスクテーブルの作成
@@ -64,10 +149,110 @@ CREATE TABLE IF NOT EXISTS subtasks (
     title VARCHAR(255) NOT NULL,
     is_complete BOOLEAN DEFAULT FALSE,
     task_id VARCHAR(255) REFERENCES tasks(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- タスクの依存関係テーブルの作成（task_id は depends_on_id の完了を待つ）
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id VARCHAR(255) REFERENCES tasks(id) ON DELETE CASCADE,
+    depends_on_id VARCHAR(255) REFERENCES tasks(id) ON DELETE CASCADE,
+    created_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, depends_on_id),
+    CHECK (task_id <> depends_on_id)
+);
+
+-- ラベルテーブルの作成（プロジェクトごとのラベル定義）
+CREATE TABLE IF NOT EXISTS labels (
+    id VARCHAR(255) PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(7) NOT NULL DEFAULT '#9e9e9e',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ラベル名はプロジェクト内で一意（大文字・小文字を区別しない）
+CREATE UNIQUE INDEX IF NOT EXISTS idx_labels_project_name ON labels(project_id, LOWER(name));
+
+-- タスクとラベルの関連テーブルの作成
+CREATE TABLE IF NOT EXISTS task_labels (
+    task_id VARCHAR(255) REFERENCES tasks(id) ON DELETE CASCADE,
+    label_id VARCHAR(255) REFERENCES labels(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, label_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_labels_label_id ON task_labels(label_id);
+
+-- カスタムフィールドテーブルの作成（プロジェクトごとのタスクの追加の項目。key と type は作成後に変更しない）
+CREATE TABLE IF NOT EXISTS custom_fields (
+    id VARCHAR(255) PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    key VARCHAR(40) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('text', 'number', 'date', 'select', 'multi_select', 'user')),
+    options TEXT[] NOT NULL DEFAULT '{}',
     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- キーはプロジェクト内で一意
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custom_fields_project_key ON custom_fields(project_id, key);
+
+-- タスクのカスタムフィールドの値テーブルの作成（value は型に応じた JSON の文字列・数値・配列）
+CREATE TABLE IF NOT EXISTS task_custom_values (
+    task_id VARCHAR(255) REFERENCES tasks(id) ON DELETE CASCADE,
+    field_id VARCHAR(255) REFERENCES custom_fields(id) ON DELETE CASCADE,
+    value JSONB NOT NULL,
+    PRIMARY KEY (task_id, field_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_custom_values_field_id ON task_custom_values(field_id);
+
+-- タスクの担当者テーブルの作成（position は担当者の並び順。先頭が tasks.assignee_id の主担当者）
+CREATE TABLE IF NOT EXISTS task_assignees (
+    task_id VARCHAR(255) REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) REFERENCES users(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_assignees_user_id ON task_assignees(user_id);
+
+-- 作業時間の記録テーブルの作成（minutes は分。計測中のタイマーは ended_at が NULL）
+CREATE TABLE IF NOT EXISTS time_entries (
+    id VARCHAR(255) PRIMARY KEY,
+    task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    minutes INTEGER NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT '',
+    source VARCHAR(10) NOT NULL DEFAULT 'manual',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 計測中のタイマーはユーザーごとに1つ
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries(user_id) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_time_entries_task_id ON time_entries(task_id);
+CREATE INDEX IF NOT EXISTS idx_time_entries_user_started ON time_entries(user_id, started_at);
+
+-- タスクのステータス遷移履歴テーブルの作成
+CREATE TABLE IF NOT EXISTS task_transitions (
+    id VARCHAR(255) PRIMARY KEY,
+    task_id VARCHAR(255) REFERENCES tasks(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- レポートはタスクごとのステータス遷移を日時の順に読み出す
+CREATE INDEX IF NOT EXISTS idx_task_transitions_task_created ON task_transitions(task_id, created_at);
+
 -- コメントテーブルの作成
 CREATE TABLE IF NOT EXISTS comments (
     id VARCHAR(255) PRIMARY KEY,
@@ -78,6 +263,76 @@ CREATE TABLE IF NOT EXISTS comments (
     updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
 );
 
+-- 変更履歴テーブルの作成（追記専用の監査証跡。UPDATE / DELETE / TRUNCATE はトリガーで拒否する）
+-- 対象が物理削除された後も履歴を残すため、外部キーは設定しない。seq は追記の順序
+CREATE TABLE IF NOT EXISTS activity_log (
+    id VARCHAR(255) PRIMARY KEY,
+    seq BIGSERIAL NOT NULL UNIQUE,
+    entity_type VARCHAR(20) NOT NULL CHECK (entity_type IN ('task', 'subtask', 'project', 'comment')),
+    entity_id VARCHAR(255) NOT NULL,
+    task_id VARCHAR(255),
+    project_id VARCHAR(255),
+    action VARCHAR(20) NOT NULL CHECK (action IN ('created', 'updated', 'deleted', 'restored')),
+    actor_id VARCHAR(255),
+    changes JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_log_task_seq ON activity_log(task_id, seq);
+CREATE INDEX IF NOT EXISTS idx_activity_log_project_seq ON activity_log(project_id, seq);
+
+CREATE OR REPLACE FUNCTION reject_activity_log_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'activity_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS activity_log_append_only ON activity_log;
+CREATE TRIGGER activity_log_append_only BEFORE UPDATE OR DELETE ON activity_log
+    FOR EACH ROW EXECUTE FUNCTION reject_activity_log_change();
+DROP TRIGGER IF EXISTS activity_log_no_truncate ON activity_log;
+CREATE TRIGGER activity_log_no_truncate BEFORE TRUNCATE ON activity_log
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_activity_log_change();
+
+-- 添付ファイルの本体テーブルの作成（内容の SHA-256 ごとに1件。同じ内容のファイルは本体を共有）
+CREATE TABLE IF NOT EXISTS blobs (
+    hash VARCHAR(64) PRIMARY KEY,
+    size BIGINT NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 添付ファイルテーブルの作成（comment_id が NULL の場合はタスクへの添付）
+CREATE TABLE IF NOT EXISTS attachments (
+    id VARCHAR(255) PRIMARY KEY,
+    task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    comment_id VARCHAR(255) REFERENCES comments(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    blob_hash VARCHAR(64) NOT NULL REFERENCES blobs(hash),
+    uploaded_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_task_id ON attachments(task_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_comment_id ON attachments(comment_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_blob_hash ON attachments(blob_hash);
+
+-- テンプレートテーブルの作成（content は期限を開始日からの相対日数で保持する JSON）
+CREATE TABLE IF NOT EXISTS templates (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('project', 'task')),
+    content JSONB NOT NULL,
+    created_by VARCHAR(255) REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_templates_created_by ON templates(created_by);
+
 -- 通知テーブルの作成
 CREATE TABLE IF NOT EXISTS notifications (
     id VARCHAR(255) PRIMARY KEY,
@@ -130,8 +385,8 @@ VALUES (
     (SELECT id FROM users WHERE email = 'test@example.com')
 ) ON CONFLICT (name) DO NOTHING;
 
--- テスト用タスクの作成
-INSERT INTO tasks (id, title, description, due_date, priority, status, project_id, assignee_id, created_by)
+-- テスト用タスクの作成（プロジェクトのタスクはボードの順位を持つ）
+INSERT INTO tasks (id, title, description, due_date, priority, status, project_id, assignee_id, created_by, rank)
 VALUES (
     gen_random_uuid(),
     'Sample Task',
@@ -141,10 +396,11 @@ VALUES (
     'Open',
     (SELECT id FROM projects WHERE name = 'Sample Project'),
     (SELECT id FROM users WHERE email = 'test@example.com'),
-    (SELECT id FROM users WHERE email = 'test@example.com')
+    (SELECT id FROM users WHERE email = 'test@example.com'),
+    '000001V'
 ) ON CONFLICT (title) DO NOTHING;
 
 -- プロジェクトメンバーの追加
-INSERT INTO project_members (project_id, user_id)
-VALUES ((SELECT id FROM projects WHERE name = 'Sample Project'), (SELECT id FROM users WHERE email = 'test@example.com'))
+INSERT INTO project_members (project_id, user_id, role)
+VALUES ((SELECT id FROM projects WHERE name = 'Sample Project'), (SELECT id FROM users WHERE email = 'test@example.com'), 'owner')
 ON CONFLICT (project_id, user_id) DO NOTHING; 
\ No newline at end of file
@@ -0,0 +1,30 @@
+-- マイグレーション: タスク・サブタスク・プロジェクト・コメントの変更履歴（追記専用の監査証跡）の追加
+
+CREATE TABLE IF NOT EXISTS activity_log (
+    id VARCHAR(255) PRIMARY KEY,
+    seq BIGSERIAL NOT NULL UNIQUE,
+    entity_type VARCHAR(20) NOT NULL CHECK (entity_type IN ('task', 'subtask', 'project', 'comment')),
+    entity_id VARCHAR(255) NOT NULL,
+    task_id VARCHAR(255),
+    project_id VARCHAR(255),
+    action VARCHAR(20) NOT NULL CHECK (action IN ('created', 'updated', 'deleted', 'restored')),
+    actor_id VARCHAR(255),
+    changes JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_log_task_seq ON activity_log(task_id, seq);
+CREATE INDEX IF NOT EXISTS idx_activity_log_project_seq ON activity_log(project_id, seq);
+
+CREATE OR REPLACE FUNCTION reject_activity_log_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'activity_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS activity_log_append_only ON activity_log;
+CREATE TRIGGER activity_log_append_only BEFORE UPDATE OR DELETE ON activity_log
+    FOR EACH ROW EXECUTE FUNCTION reject_activity_log_change();
+DROP TRIGGER IF EXISTS activity_log_no_truncate ON activity_log;
+CREATE TRIGGER activity_log_no_truncate BEFORE TRUNCATE ON activity_log
+    FOR EACH STATEMENT EXECUTE FUNCTION reject_activity_log_change();
@@ -0,0 +1,26 @@
+-- マイグレーション: タスク・コメントの添付ファイルと、内容のハッシュごとの本体テーブルの追加
+
+-- 添付ファイルの本体テーブルの作成（内容の SHA-256 ごとに1件。同じ内容のファイルは本体を共有）
+CREATE TABLE IF NOT EXISTS blobs (
+    hash VARCHAR(64) PRIMARY KEY,
+    size BIGINT NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 添付ファイルテーブルの作成（comment_id が NULL の場合はタスクへの添付）
+CREATE TABLE IF NOT EXISTS attachments (
+    id VARCHAR(255) PRIMARY KEY,
+    task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    comment_id VARCHAR(255) REFERENCES comments(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(255) NOT NULL,
+    size BIGINT NOT NULL,
+    blob_hash VARCHAR(64) NOT NULL REFERENCES blobs(hash),
+    uploaded_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_task_id ON attachments(task_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_comment_id ON attachments(comment_id);
+CREATE INDEX IF NOT EXISTS idx_attachments_blob_hash ON attachments(blob_hash);
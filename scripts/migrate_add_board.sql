@@ -0,0 +1,25 @@
+-- マイグレーション: かんばんボードの列（WIP 制限）とタスクの列内の順位の追加
+
+-- 順位は辞書順（バイト順）で比較する
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS rank VARCHAR(255) COLLATE "C" NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS idx_tasks_board ON tasks(project_id, status, rank);
+
+-- 既存のタスクはプロジェクト・ステータスごとに作成順の順位を付ける（末尾が 0 にならないように V を付ける）
+UPDATE tasks t
+SET rank = r.rank
+FROM (
+    SELECT id, LPAD(ROW_NUMBER() OVER (PARTITION BY project_id, status ORDER BY created_at, id)::text, 6, '0') || 'V' AS rank
+    FROM tasks
+    WHERE project_id IS NOT NULL AND rank = ''
+) r
+WHERE t.id = r.id;
+
+CREATE TABLE IF NOT EXISTS board_columns (
+    project_id VARCHAR(255) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    wip_limit INTEGER NOT NULL DEFAULT 0 CHECK (wip_limit >= 0),
+    wip_policy VARCHAR(10) NOT NULL DEFAULT 'warn' CHECK (wip_policy IN ('warn', 'reject')),
+    PRIMARY KEY (project_id, status)
+);
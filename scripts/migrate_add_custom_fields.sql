@@ -0,0 +1,25 @@
+-- マイグレーション: プロジェクトごとのカスタムフィールド定義と、タスクのカスタムフィールドの値テーブルの追加
+
+CREATE TABLE IF NOT EXISTS custom_fields (
+    id VARCHAR(255) PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    key VARCHAR(40) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('text', 'number', 'date', 'select', 'multi_select', 'user')),
+    options TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- キーはプロジェクト内で一意
+CREATE UNIQUE INDEX IF NOT EXISTS idx_custom_fields_project_key ON custom_fields(project_id, key);
+
+-- タスクのカスタムフィールドの値テーブルの作成（value は型に応じた JSON の文字列・数値・配列）
+CREATE TABLE IF NOT EXISTS task_custom_values (
+    task_id VARCHAR(255) REFERENCES tasks(id) ON DELETE CASCADE,
+    field_id VARCHAR(255) REFERENCES custom_fields(id) ON DELETE CASCADE,
+    value JSONB NOT NULL,
+    PRIMARY KEY (task_id, field_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_custom_values_field_id ON task_custom_values(field_id);
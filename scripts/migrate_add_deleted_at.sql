@@ -0,0 +1,11 @@
+-- マイグレーション: 論理削除（ゴミ箱）用の deleted_at カラムの追加
+
+-- プロジェクトテーブルに deleted_at カラムを追加
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- タスクテーブルに deleted_at カラムを追加
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+
+-- ゴミ箱一覧と purger の検索用
+CREATE INDEX IF NOT EXISTS idx_projects_deleted_at ON projects(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks(deleted_at) WHERE deleted_at IS NOT NULL;
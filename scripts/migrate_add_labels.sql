@@ -0,0 +1,22 @@
+-- マイグレーション: プロジェクトごとのラベル定義と、タスクとラベルの関連テーブルの追加
+
+CREATE TABLE IF NOT EXISTS labels (
+    id VARCHAR(255) PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    color VARCHAR(7) NOT NULL DEFAULT '#9e9e9e',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- ラベル名はプロジェクト内で一意（大文字・小文字を区別しない）
+CREATE UNIQUE INDEX IF NOT EXISTS idx_labels_project_name ON labels(project_id, LOWER(name));
+
+CREATE TABLE IF NOT EXISTS task_labels (
+    task_id VARCHAR(255) REFERENCES tasks(id) ON DELETE CASCADE,
+    label_id VARCHAR(255) REFERENCES labels(id) ON DELETE CASCADE,
+    PRIMARY KEY (task_id, label_id)
+);
+
+-- ラベルでの絞り込み用
+CREATE INDEX IF NOT EXISTS idx_task_labels_label_id ON task_labels(label_id);
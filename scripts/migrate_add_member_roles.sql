@@ -0,0 +1,9 @@
+-- マイグレーション: プロジェクトメンバーのロール（owner / maintainer / member / viewer）の追加
+
+-- 既存のメンバーは member になる
+ALTER TABLE project_members ADD COLUMN IF NOT EXISTS role VARCHAR(20) NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'maintainer', 'member', 'viewer'));
+
+-- プロジェクトの作成者を owner にする（メンバーとして登録されていない場合は追加する）
+INSERT INTO project_members (project_id, user_id, role)
+SELECT id, created_by, 'owner' FROM projects WHERE created_by IS NOT NULL
+ON CONFLICT (project_id, user_id) DO UPDATE SET role = 'owner';
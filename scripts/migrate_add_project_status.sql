@@ -0,0 +1,4 @@
+-- マイグレーション: プロジェクトのステータス（active / archived）の追加
+
+-- 既存のプロジェクトは active になる
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'archived'));
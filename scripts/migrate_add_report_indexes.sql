@@ -0,0 +1,4 @@
+-- マイグレーション: レポート（バーンダウン・累積フロー等）のためのステータス遷移履歴のインデックスの追加
+
+CREATE INDEX IF NOT EXISTS idx_task_transitions_task_created ON task_transitions(task_id, created_at);
+DROP INDEX IF EXISTS idx_task_transitions_task_id;
@@ -0,0 +1,44 @@
+-- マイグレーション: プロジェクトのスプリント・マイルストーンと、タスクの予定先の追加
+
+-- スプリントテーブルの作成（status は planned / active / closed。rolled_over は終了時に次へ移した未完了のタスク数）
+CREATE TABLE IF NOT EXISTS sprints (
+    id VARCHAR(255) PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    goal TEXT NOT NULL DEFAULT '',
+    start_date TIMESTAMP NOT NULL,
+    end_date TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'planned' CHECK (status IN ('planned', 'active', 'closed')),
+    started_at TIMESTAMP,
+    closed_at TIMESTAMP,
+    rolled_over INTEGER NOT NULL DEFAULT 0,
+    created_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 実行中のスプリントはプロジェクトごとに 1 つまで
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sprints_active ON sprints(project_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_sprints_project_id ON sprints(project_id, start_date);
+
+-- マイルストーンテーブルの作成（start_date は省略可能）
+CREATE TABLE IF NOT EXISTS milestones (
+    id VARCHAR(255) PRIMARY KEY,
+    project_id VARCHAR(255) NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    start_date TIMESTAMP,
+    end_date TIMESTAMP NOT NULL,
+    created_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_milestones_project_id ON milestones(project_id, end_date);
+
+-- タスクテーブルにスプリント・マイルストーンへの参照を追加
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS sprint_id VARCHAR(255) REFERENCES sprints(id) ON DELETE SET NULL;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS milestone_id VARCHAR(255) REFERENCES milestones(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_sprint_id ON tasks(sprint_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_milestone_id ON tasks(milestone_id);
@@ -0,0 +1,16 @@
+-- マイグレーション: サブタスクの並び順とプロジェクトの自動完了設定の追加
+
+-- サブタスクテーブルに position カラムを追加
+ALTER TABLE subtasks ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+
+-- 既存のサブタスクは作成順に position を振る
+UPDATE subtasks s
+SET position = o.rn - 1
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY task_id ORDER BY created_at, id) AS rn
+    FROM subtasks
+) o
+WHERE s.id = o.id;
+
+-- プロジェクトテーブルに auto_complete_tasks カラムを追加
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS auto_complete_tasks BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,17 @@
+-- マイグレーション: タスクの複数担当者テーブルの追加と、既存の assignee_id からの移行
+
+CREATE TABLE IF NOT EXISTS task_assignees (
+    task_id VARCHAR(255) REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) REFERENCES users(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_assignees_user_id ON task_assignees(user_id);
+
+-- 空文字列の担当者は未割り当て（NULL）に揃える
+UPDATE tasks SET assignee_id = NULL WHERE assignee_id = '';
+
+INSERT INTO task_assignees (task_id, user_id, position)
+SELECT id, assignee_id, 0 FROM tasks WHERE assignee_id IS NOT NULL
+ON CONFLICT DO NOTHING;
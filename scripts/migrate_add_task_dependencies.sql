@@ -0,0 +1,13 @@
+-- マイグレーション: タスクの依存関係（blocks / blocked-by）テーブルの追加
+
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id VARCHAR(255) REFERENCES tasks(id) ON DELETE CASCADE,
+    depends_on_id VARCHAR(255) REFERENCES tasks(id) ON DELETE CASCADE,
+    created_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, depends_on_id),
+    CHECK (task_id <> depends_on_id)
+);
+
+-- 逆方向（blocks）の検索用
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_depends_on_id ON task_dependencies(depends_on_id);
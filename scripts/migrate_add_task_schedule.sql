@@ -0,0 +1,5 @@
+-- マイグレーション: タイムライン（ガントチャート）のためのタスクの開始日と日数の追加
+
+-- duration_days は 0 の場合未設定（開始日から期限まで、期限もなければ 1 日）
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS start_date TIMESTAMP;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS duration_days INTEGER NOT NULL DEFAULT 0 CHECK (duration_days >= 0);
@@ -0,0 +1,26 @@
+-- マイグレーション: 繰り返しタスク（RRULE）の系列テーブルの追加
+
+CREATE TABLE IF NOT EXISTS task_series (
+    id VARCHAR(255) PRIMARY KEY,
+    rule TEXT NOT NULL,
+    start_at TIMESTAMP NOT NULL,
+    last_due TIMESTAMP NOT NULL,
+    occurrences INTEGER NOT NULL DEFAULT 1,
+    ended BOOLEAN NOT NULL DEFAULT FALSE,
+    title VARCHAR(255) NOT NULL,
+    description TEXT,
+    priority VARCHAR(20),
+    project_id VARCHAR(255) REFERENCES projects(id) ON DELETE CASCADE,
+    assignee_id VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_by VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- タスクテーブルに系列への参照と発生回数を追加
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS series_id VARCHAR(255) REFERENCES task_series(id) ON DELETE SET NULL;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS occurrence INTEGER;
+
+-- スケジューラの検索用
+CREATE INDEX IF NOT EXISTS idx_task_series_last_due ON task_series(last_due) WHERE NOT ended;
+CREATE INDEX IF NOT EXISTS idx_tasks_series_id ON tasks(series_id);
@@ -0,0 +1,12 @@
+-- マイグレーション: タスクのステータス遷移履歴テーブルの追加
+
+CREATE TABLE IF NOT EXISTS task_transitions (
+    id VARCHAR(255) PRIMARY KEY,
+    task_id VARCHAR(255) REFERENCES tasks(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    actor_id VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_transitions_task_id ON task_transitions(task_id);
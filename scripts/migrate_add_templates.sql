@@ -0,0 +1,15 @@
+-- マイグレーション: プロジェクト・タスクのテンプレートテーブルの追加
+
+-- テンプレートテーブルの作成（content は期限を開始日からの相対日数で保持する JSON）
+CREATE TABLE IF NOT EXISTS templates (
+    id VARCHAR(255) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('project', 'task')),
+    content JSONB NOT NULL,
+    created_by VARCHAR(255) REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_templates_created_by ON templates(created_by);
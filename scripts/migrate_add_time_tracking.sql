@@ -0,0 +1,24 @@
+-- マイグレーション: タスクの見積もり時間と、作業時間の記録（タイマー・手入力）テーブルの追加
+
+-- タスクテーブルに見積もり時間（分）を追加
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS estimate INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS time_entries (
+    id VARCHAR(255) PRIMARY KEY,
+    task_id VARCHAR(255) NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) REFERENCES users(id) ON DELETE SET NULL,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    minutes INTEGER NOT NULL DEFAULT 0,
+    note TEXT NOT NULL DEFAULT '',
+    source VARCHAR(10) NOT NULL DEFAULT 'manual',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- 計測中のタイマーはユーザーごとに1つ
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries(user_id) WHERE ended_at IS NULL;
+
+-- タスク・ユーザーごとの集計用
+CREATE INDEX IF NOT EXISTS idx_time_entries_task_id ON time_entries(task_id);
+CREATE INDEX IF NOT EXISTS idx_time_entries_user_started ON time_entries(user_id, started_at);
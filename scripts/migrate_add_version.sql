@@ -0,0 +1,7 @@
+-- マイグレーション: 楽観的ロック用の version カラムの追加
+
+-- プロジェクトテーブルに version カラムを追加
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+-- タスクテーブルに version カラムを追加
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,21 @@
+-- マイグレーション: かんばんボードの順位のないプロジェクトのタスクに順位を付ける
+-- ボードの参照時には順位を補完しないため、migrate_add_board.sql の後に作成された順位のないタスクもここで補完する
+
+-- プロジェクト・ステータスごとに、既存の最後の順位の後ろへ作成順に並べる
+-- （最後の順位を接頭辞にすると既存の順位より後ろ、かつ末尾が 0 にならないように V を付ける）
+-- 変更履歴には記録しない（操作者のいないデータの移行のため）
+UPDATE tasks t
+SET rank = r.rank
+FROM (
+    SELECT u.id,
+        COALESCE(last.rank, '') || LPAD(ROW_NUMBER() OVER (PARTITION BY u.project_id, u.status ORDER BY u.created_at, u.id)::text, 6, '0') || 'V' AS rank
+    FROM tasks u
+    LEFT JOIN (
+        SELECT project_id, status, MAX(rank) AS rank
+        FROM tasks
+        WHERE project_id IS NOT NULL AND rank <> ''
+        GROUP BY project_id, status
+    ) last ON last.project_id = u.project_id AND last.status = u.status
+    WHERE u.project_id IS NOT NULL AND u.rank = ''
+) r
+WHERE t.id = r.id;
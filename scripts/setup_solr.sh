@@ -4,11 +4,13 @@
 docker exec todo-solr solr create_core -c todoapp
 
 # スキーマ定義
+# タスクのカスタムフィールドは既定の構成の動的フィールド（cf_<key>_s / cf_<key>_d / cf_<key>_ss）に登録する
 curl -X POST http://localhost:8983/solr/todoapp/schema -H 'Content-type:application/json' --data-binary '{
   "add-field": [
     {"name":"id", "type":"string", "stored":true, "required":true},
     {"name":"type", "type":"string", "stored":true},
     {"name":"title", "type":"text_ja", "stored":true},
-    {"name":"description", "type":"text_ja", "stored":true}
+    {"name":"description", "type":"text_ja", "stored":true},
+    {"name":"labels", "type":"string", "stored":true, "multiValued":true}
   ]
 }' 
\ No newline at end of file
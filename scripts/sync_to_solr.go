@@ -6,6 +6,7 @@ import (
 	"os"
 	"todo-app/internal/infrastructure"
 	projpg "todo-app/internal/project/repository/postgres"
+	taskdomain "todo-app/internal/task/domain"
 	taskpg "todo-app/internal/task/repository/postgres"
 	"todo-app/internal/infrastructure/db"
 )
@@ -40,11 +41,19 @@ func main() {
 	taskRepo := taskpg.NewTaskRepoPg(dbConn)
 	tasks, _ := taskRepo.GetAll()
 	for _, t := range tasks {
+		labels := make([]string, 0, len(t.Labels))
+		for _, l := range t.Labels {
+			labels = append(labels, l.Name)
+		}
 		doc := map[string]interface{}{
 			"id": t.ID,
 			"type": "task",
 			"title": t.Title,
 			"description": t.Description,
+			"labels": labels,
+		}
+		for key, value := range t.CustomFields {
+			doc[taskdomain.CustomFieldSolrName(key, value)] = value
 		}
 		err := solr.Add(doc)
 		if err != nil {